@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"log"
+	"math"
 	"time"
 
 	"github.com/optimized-sssp-benchmark/go/pkg/optimized_sssp"
@@ -54,7 +55,7 @@ func manualGraphExample() {
 
 	fmt.Printf("Shortest paths from node %d:\n", result.SourceNode)
 	for node, distance := range result.Distances {
-		if distance < 1e100 { // Reachable nodes
+		if !math.IsInf(float64(distance), 1) { // Reachable nodes
 			fmt.Printf("  Node %d: distance %.2f\n", node, distance)
 		}
 	}
@@ -80,7 +81,7 @@ func randomGraphExample() {
 
 	reachableCount := 0
 	for _, distance := range result.Distances {
-		if distance < 1e100 {
+		if !math.IsInf(float64(distance), 1) {
 			reachableCount++
 		}
 	}
@@ -0,0 +1,7 @@
+module github.com/your-org/optimized-sssp-go/examples/wasm
+
+go 1.22
+
+require github.com/your-org/optimized-sssp-go v0.0.0
+
+replace github.com/your-org/optimized-sssp-go => ../../wrappers/go
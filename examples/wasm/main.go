@@ -0,0 +1,61 @@
+//go:build js && wasm
+
+// Browser demo for the pure-Go build of the sssp package.
+//
+// Build (from this directory):
+//
+//	GOOS=js GOARCH=wasm go build -o sssp.wasm .
+//	cp "$(go env GOROOT)/lib/wasm/wasm_exec.js" .
+//
+// then serve the directory (e.g. `python -m http.server`) and open index.html.
+package main
+
+import (
+	"syscall/js"
+
+	sssp "github.com/your-org/optimized-sssp-go"
+)
+
+func u32s(v js.Value) []uint32 {
+	out := make([]uint32, v.Length())
+	for i := range out {
+		out[i] = uint32(v.Index(i).Int())
+	}
+	return out
+}
+
+func f32s(v js.Value) []float32 {
+	out := make([]float32, v.Length())
+	for i := range out {
+		out[i] = float32(v.Index(i).Float())
+	}
+	return out
+}
+
+// ssspRun(offsets, targets, weights, source, mode) -> {dist, pred, relaxations} | {error}
+func run(_ js.Value, args []js.Value) any {
+	if len(args) < 4 {
+		return map[string]any{"error": "usage: ssspRun(offsets, targets, weights, source, mode?)"}
+	}
+	offsets := u32s(args[0])
+	mode := sssp.ModeBaseline
+	if len(args) > 4 {
+		mode = args[4].Int()
+	}
+	res, err := sssp.Run(uint32(len(offsets)-1), offsets, u32s(args[1]), f32s(args[2]), uint32(args[3].Int()), mode)
+	if err != nil {
+		return map[string]any{"error": err.Error()}
+	}
+	dist := make([]any, len(res.Dist))
+	pred := make([]any, len(res.Pred))
+	for i, d := range res.Dist {
+		dist[i] = float64(d) // +Inf for unreachable nodes
+		pred[i] = int(res.Pred[i])
+	}
+	return map[string]any{"dist": dist, "pred": pred, "relaxations": float64(res.Stats.Relaxations)}
+}
+
+func main() {
+	js.Global().Set("ssspRun", js.FuncOf(run))
+	select {}
+}
@@ -20,7 +20,6 @@ pub struct SsspResultInfo {
 pub struct BaselineHeapStats { pub pushes: u64, pub pops: u64, pub max_size: u64 }
 impl Copy for BaselineHeapStats {}
 impl Clone for BaselineHeapStats { fn clone(&self) -> Self { *self } }
-static mut LAST_BASELINE_HEAP_STATS: BaselineHeapStats = BaselineHeapStats { pushes:0, pops:0, max_size:0 };
 
 // Additional global instrumentation for delta-stepping (light/heavy) to correlate scaling behavior.
 // Updated on each STOC / autotune final full run.
@@ -37,22 +36,70 @@ pub struct SsspBucketStats {
 impl Copy for SsspBucketStats {}
 impl Clone for SsspBucketStats { fn clone(&self) -> Self { *self } }
 
-static mut LAST_BUCKET_STATS: SsspBucketStats = SsspBucketStats { buckets_visited: 0, light_pass_repeats: 0, max_bucket_index: 0, restarts: 0, delta_x1000: 0, heavy_ratio_x1000: 0 };
-static mut LAST_DELTA: f32 = 0.0;
+// Operation counters reported the same way by every runner (and by the Go
+// engine), so implementations can be compared op for op:
+//  - edge_relaxations: edges whose tentative distance was computed and compared
+//  - improvements: edge relaxations that lowered a distance
+//  - heap_pushes / heap_pops: priority-queue traffic (Dijkstra only)
+//  - frontier_expansions: nodes taken off the heap or out of a bucket and scanned
+//  - bucket_scans: passes over a bucket (delta-stepping light-phase rounds)
+// Runs count all work done, including adaptive restarts and autotune trials.
+// The counters of the calling thread's last run are read back with
+// `sssp_get_last_op_counters`; batched runs report the sum over all rows.
+#[repr(C)]
+#[derive(Copy, Clone, Default)]
+pub struct SsspOpCounters {
+    pub edge_relaxations: u64,
+    pub improvements: u64,
+    pub heap_pushes: u64,
+    pub heap_pops: u64,
+    pub frontier_expansions: u64,
+    pub bucket_scans: u64,
+}
+
+impl SsspOpCounters {
+    fn add(&mut self, o: &SsspOpCounters) {
+        self.edge_relaxations += o.edge_relaxations; self.improvements += o.improvements;
+        self.heap_pushes += o.heap_pushes; self.heap_pops += o.heap_pops;
+        self.frontier_expansions += o.frontier_expansions; self.bucket_scans += o.bucket_scans;
+    }
+}
+
+// Per-thread like LAST_OPS, so concurrent callers never see each other's run
+// (and reading them is not a data race). Prefer the per-call out-structs
+// (`SsspStocParams`, `SsspAutotuneParams`) where they exist.
+thread_local! {
+    static LAST_OPS: core::cell::Cell<SsspOpCounters> = core::cell::Cell::new(SsspOpCounters::default());
+    static LAST_BASELINE_HEAP_STATS: core::cell::Cell<BaselineHeapStats> = core::cell::Cell::new(BaselineHeapStats { pushes:0, pops:0, max_size:0 });
+    static LAST_BUCKET_STATS: core::cell::Cell<SsspBucketStats> = core::cell::Cell::new(SsspBucketStats { buckets_visited: 0, light_pass_repeats: 0, max_bucket_index: 0, restarts: 0, delta_x1000: 0, heavy_ratio_x1000: 0 });
+    static LAST_DELTA: core::cell::Cell<f32> = core::cell::Cell::new(0.0);
+    static LAST_THREADS: core::cell::Cell<u32> = core::cell::Cell::new(0);
+}
+
+#[no_mangle]
+pub extern "C" fn sssp_get_last_op_counters(out: *mut SsspOpCounters) {
+    if out.is_null() { return; }
+    unsafe { *out = LAST_OPS.with(|c| c.get()); }
+}
+
 
 #[no_mangle]
 pub extern "C" fn sssp_get_bucket_stats(out: *mut SsspBucketStats) {
     if out.is_null() { return; }
-    unsafe { *out = LAST_BUCKET_STATS; }
+    unsafe { *out = LAST_BUCKET_STATS.with(|c| c.get()); }
 }
 
 #[no_mangle]
-pub extern "C" fn sssp_get_last_delta() -> f32 { unsafe { LAST_DELTA } }
+pub extern "C" fn sssp_get_last_delta() -> f32 { LAST_DELTA.with(|c| c.get()) }
+
+// Worker threads the last successful batch on this thread ran on.
+#[no_mangle]
+pub extern "C" fn sssp_get_last_threads() -> u32 { LAST_THREADS.with(|c| c.get()) }
 
 #[no_mangle]
 pub extern "C" fn sssp_get_baseline_heap_stats(out: *mut BaselineHeapStats) {
     if out.is_null() { return; }
-    unsafe { *out = LAST_BASELINE_HEAP_STATS; }
+    unsafe { *out = LAST_BASELINE_HEAP_STATS.with(|c| c.get()); }
 }
 
 #[inline(always)]
@@ -125,15 +172,31 @@ pub extern "C" fn sssp_run_baseline(
     let dist = as_mut_slice(out_dist, n_usize);
     let pred = as_mut_slice(out_pred, n_usize);
 
+    let mut ops = SsspOpCounters::default();
+    let (relaxations, heap_stats) = baseline_run_internal(off, tgt, wts, source, dist, Some(pred), &mut ops);
+    LAST_OPS.with(|c| c.set(ops));
+    let light_relaxations: u64 = 0; // unused in baseline
+    let heavy_relaxations: u64 = 0; // unused in baseline
+
+    if !info.is_null() { unsafe { *info = SsspResultInfo { relaxations, light_relaxations, heavy_relaxations, settled: n, error_code: 0 }; } }
+    LAST_BASELINE_HEAP_STATS.with(|c| c.set(heap_stats));
+    0
+}
+
+// Heap Dijkstra core shared by `sssp_run_baseline` and the batched entry point.
+// Touches no global instrumentation so it is safe to run on worker threads.
+// With `pred` None no predecessors are recorded (distances-only batches).
+fn baseline_run_internal(
+    off: &[u32], tgt: &[u32], wts: &[f32], source: u32,
+    dist: &mut [f32], mut pred: Option<&mut [i32]>, ops: &mut SsspOpCounters,
+) -> (u64, BaselineHeapStats) {
     // Init
     for d in dist.iter_mut() { *d = f32::INFINITY; }
-    for p in pred.iter_mut() { *p = -1; }
+    if let Some(p) = pred.as_deref_mut() { p.fill(-1); }
     dist[source as usize] = 0.0;
 
-    let mut heap = BinaryHeapSimple::new( (n as usize).min(1024) );
+    let mut heap = BinaryHeapSimple::new(dist.len().min(1024));
     let mut relaxations: u64 = 0;
-    let light_relaxations: u64 = 0; // unused in baseline
-    let heavy_relaxations: u64 = 0; // unused in baseline
     let mut heap_pushes: u64 = 0;
     let mut heap_pops: u64 = 0;
     let mut heap_max: u64 = 0;
@@ -144,6 +207,8 @@ pub extern "C" fn sssp_run_baseline(
         if item.dist > dist[item.node as usize] { continue; }
         let start = off[item.node as usize] as usize;
         let end = off[item.node as usize + 1] as usize;
+        ops.frontier_expansions += 1;
+        ops.edge_relaxations += (end - start) as u64;
         for e in start..end {
             let v = tgt[e] as usize;
             let w = wts[e];
@@ -151,22 +216,38 @@ pub extern "C" fn sssp_run_baseline(
             let cur = dist[v];
             if nd < cur {
                 dist[v] = nd;
-                pred[v] = item.node as i32;
+                if let Some(p) = pred.as_deref_mut() { p[v] = item.node as i32; }
                 heap.push(HeapItem { node: v as u32, dist: nd }, &mut heap_pushes);
                 if heap.data.len() as u64 > heap_max { heap_max = heap.data.len() as u64; }
                 relaxations += 1;
             }
         }
     }
-
-    if !info.is_null() { unsafe { *info = SsspResultInfo { relaxations, light_relaxations, heavy_relaxations, settled: n, error_code: 0 }; } }
-    unsafe { LAST_BASELINE_HEAP_STATS = BaselineHeapStats { pushes: heap_pushes, pops: heap_pops, max_size: heap_max }; }
-    0
+    ops.improvements += relaxations; ops.heap_pushes += heap_pushes; ops.heap_pops += heap_pops;
+    (relaxations, BaselineHeapStats { pushes: heap_pushes, pops: heap_pops, max_size: heap_max })
 }
 
 #[no_mangle]
 pub extern "C" fn sssp_version() -> u32 { 4 } // incremented due to SsspResultInfo breaking change
 
+// Feature bits reported by `sssp_capabilities`. Wrappers probe these at runtime
+// instead of inferring support from `sssp_version`. Bits are append-only.
+pub const SSSP_CAP_BASELINE: u64 = 1 << 0;
+pub const SSSP_CAP_STOC: u64 = 1 << 1;
+pub const SSSP_CAP_AUTOTUNE: u64 = 1 << 2;
+pub const SSSP_CAP_AUTOTUNE_PARAMS: u64 = 1 << 3; // sssp_run_stoc_autotune_params
+pub const SSSP_CAP_BATCH: u64 = 1 << 4;           // sssp_run_batch
+pub const SSSP_CAP_THREADS: u64 = 1 << 5;         // sssp_run_batch_threads
+pub const SSSP_CAP_F64: u64 = 1 << 6;             // reserved: f64 distance entry points
+pub const SSSP_CAP_GPU: u64 = 1 << 7;             // reserved: device offload backend
+pub const SSSP_CAP_OP_COUNTERS: u64 = 1 << 8;     // sssp_get_last_op_counters
+pub const SSSP_CAP_STOC_PARAMS: u64 = 1 << 9;     // sssp_run_stoc_params
+
+#[no_mangle]
+pub extern "C" fn sssp_capabilities() -> u64 {
+    SSSP_CAP_BASELINE | SSSP_CAP_STOC | SSSP_CAP_AUTOTUNE | SSSP_CAP_AUTOTUNE_PARAMS | SSSP_CAP_BATCH | SSSP_CAP_THREADS | SSSP_CAP_OP_COUNTERS | SSSP_CAP_STOC_PARAMS
+}
+
 // ---------------- STOC-inspired (delta-stepping style) variant ----------------
 // This implements a simplified delta-stepping algorithm (Meyer & Sanders) often
 // used as a practical foundation for layering / bucket approaches referenced in
@@ -191,6 +272,30 @@ pub extern "C" fn sssp_run_stoc(
     out_dist: *mut f32,
     out_pred: *mut i32,
     info: *mut SsspResultInfo,
+) -> i32 {
+    sssp_run_stoc_params(n, offsets, targets, weights, source, out_dist, out_pred, core::ptr::null_mut(), info)
+}
+
+// Parameters a delta-stepping run settled on (out), returned per call so
+// concurrent callers each get their own delta.
+#[repr(C)]
+pub struct SsspStocParams {
+    pub chosen_delta: f32,         // delta of the final run, after adaptive restarts
+    pub restarts: u32,             // adaptive restarts performed
+}
+
+// `sssp_run_stoc` reporting its chosen delta through `params` (may be null).
+#[no_mangle]
+pub extern "C" fn sssp_run_stoc_params(
+    n: u32,
+    offsets: *const u32,
+    targets: *const u32,
+    weights: *const f32,
+    source: u32,
+    out_dist: *mut f32,
+    out_pred: *mut i32,
+    params: *mut SsspStocParams,
+    info: *mut SsspResultInfo,
 ) -> i32 {
     if n == 0 { return -1; }
     if source >= n { return -2; }
@@ -257,6 +362,7 @@ pub extern "C" fn sssp_run_stoc(
     let adapt_trace = std::env::var("SSSP_STOC_ADAPT_TRACE").ok().map(|v| v=="1" || v.to_lowercase()=="true").unwrap_or(false);
     // Will hold (relax, light, heavy, settled, buckets_visited, light_repeat_total, bucket_cap)
     let final_stats: Option<(u64,u64,u64,u32,u32,u32,usize)>; // will be set before break
+    let mut ops = SsspOpCounters::default(); // summed over restarts
     let mut delta = choose_delta();
     loop {
         // Run with current delta
@@ -289,6 +395,7 @@ pub extern "C" fn sssp_run_stoc(
             let mut light_set: Vec<u32> = Vec::new();
             while request_light_repeat {
                 light_repeat_total += 1;
+                ops.bucket_scans += 1;
                 request_light_repeat = false;
                 let frontier: Vec<u32> = core::mem::take(&mut buckets[current_bucket]);
                 for &u_raw in &frontier { in_bucket[u_raw as usize] = false; }
@@ -298,12 +405,14 @@ pub extern "C" fn sssp_run_stoc(
                     if settled[u] { continue; }
                     settled[u] = true; settled_count += 1;
                     light_set.push(u_raw);
+                    ops.frontier_expansions += 1;
                     let start = off[u] as usize; let end = off[u+1] as usize;
                     let base = dist[u];
                     for e in start..end {
                         let v = unsafe { *tgt.get_unchecked(e) } as usize;
                         let w = unsafe { *wts.get_unchecked(e) };
                         if w <= delta { // light edge
+                            ops.edge_relaxations += 1;
                             let nd = base + w;
                             let cur = unsafe { *dist.get_unchecked(v) };
                             if nd < cur {
@@ -312,7 +421,7 @@ pub extern "C" fn sssp_run_stoc(
                                 if b > max_bucket_cap { return -5; }
                                 ensure_bucket(&mut buckets, b);
                                 if !in_bucket[v] && !settled[v] { buckets[b].push(v as u32); in_bucket[v] = true; request_light_repeat |= b == current_bucket; }
-                                relaxations += 1; light_relax += 1;
+                                relaxations += 1; light_relax += 1; ops.improvements += 1;
                             }
                         }
                     }
@@ -326,6 +435,7 @@ pub extern "C" fn sssp_run_stoc(
                     let v = unsafe { *tgt.get_unchecked(e) } as usize;
                     let w = unsafe { *wts.get_unchecked(e) };
                     if w > delta {
+                        ops.edge_relaxations += 1;
                         let nd = base + w; let cur = unsafe { *dist.get_unchecked(v) };
                         if nd < cur {
                             unsafe { *dist.get_unchecked_mut(v) = nd; *pred.get_unchecked_mut(v) = u as i32; }
@@ -333,7 +443,7 @@ pub extern "C" fn sssp_run_stoc(
                             if b > max_bucket_cap { return -5; }
                             ensure_bucket(&mut buckets, b);
                             if !in_bucket[v] && !settled[v] { buckets[b].push(v as u32); in_bucket[v] = true; }
-                            relaxations += 1; heavy_relax += 1;
+                            relaxations += 1; heavy_relax += 1; ops.improvements += 1;
                         }
                     }
                 }
@@ -368,14 +478,16 @@ pub extern "C" fn sssp_run_stoc(
             if restarts <= adaptive_max { continue; }
         }
         final_stats = Some((relaxations, light_relax, heavy_relax, settled_count, buckets_visited, light_repeat_total, buckets.len()));
-        unsafe { LAST_DELTA = delta; }
         break;
     }
+    LAST_OPS.with(|c| c.set(ops));
 
     let (relaxations, light_relax, heavy_relax, settled_count, buckets_visited, light_repeat_total, bucket_len) = final_stats.expect("final_stats must be set before loop break");
     if !info.is_null() { unsafe { *info = SsspResultInfo { relaxations, light_relaxations: light_relax, heavy_relaxations: heavy_relax, settled: settled_count, error_code: 0 }; } }
     let heavy_ratio_x1000 = if relaxations==0 {0} else { ((heavy_relax as f64 / relaxations as f64)*1000.0) as u32 };
-    unsafe { LAST_BUCKET_STATS = SsspBucketStats { buckets_visited, light_pass_repeats: light_repeat_total, max_bucket_index: (bucket_len.saturating_sub(1)) as u32, restarts, delta_x1000: (LAST_DELTA * 1000.0) as u32, heavy_ratio_x1000 }; }
+    LAST_DELTA.with(|c| c.set(delta));
+    LAST_BUCKET_STATS.with(|c| c.set(SsspBucketStats { buckets_visited, light_pass_repeats: light_repeat_total, max_bucket_index: (bucket_len.saturating_sub(1)) as u32, restarts, delta_x1000: (delta * 1000.0) as u32, heavy_ratio_x1000 }));
+    if !params.is_null() { unsafe { *params = SsspStocParams { chosen_delta: delta, restarts }; } }
     0
 }
 
@@ -415,12 +527,13 @@ fn stoc_run_internal(
     n: u32,
     off: &[u32], tgt: &[u32], wts: &[f32], source: u32,
     delta: f32,
-    dist: &mut [f32], pred: &mut [i32],
+    dist: &mut [f32], mut pred: Option<&mut [i32]>,
     truncate_after: Option<u32>,
+    ops: &mut SsspOpCounters,
 ) -> (u64,u64,u64,u32,i32) {
     let n_usize = n as usize;
     for d in dist.iter_mut() { *d = f32::INFINITY; }
-    for p in pred.iter_mut() { *p = -1; }
+    if let Some(p) = pred.as_deref_mut() { p.fill(-1); }
     dist[source as usize] = 0.0;
     let inv_delta = 1.0f32 / delta;
     let mut buckets: Vec<Vec<u32>> = Vec::new();
@@ -435,15 +548,15 @@ fn stoc_run_internal(
         if buckets[current_bucket].is_empty() { current_bucket += 1; continue; }
         let mut request_light_repeat = true; let mut light_set: Vec<u32> = Vec::new();
     while request_light_repeat {
-            request_light_repeat = false; let frontier: Vec<u32> = core::mem::take(&mut buckets[current_bucket]); for &u_raw in &frontier { in_bucket[u_raw as usize] = false; }
+            request_light_repeat = false; ops.bucket_scans += 1; let frontier: Vec<u32> = core::mem::take(&mut buckets[current_bucket]); for &u_raw in &frontier { in_bucket[u_raw as usize] = false; }
             if frontier.is_empty() { break; }
-            for &u_raw in &frontier { let u = u_raw as usize; if settled[u] { continue; } settled[u] = true; settled_count += 1; light_set.push(u_raw); let start = off[u] as usize; let end = off[u+1] as usize; let base = dist[u];
-                for e in start..end { let v = unsafe { *tgt.get_unchecked(e) } as usize; let w = unsafe { *wts.get_unchecked(e) }; if w <= delta { let nd = base + w; let cur = unsafe { *dist.get_unchecked(v) }; if nd < cur { unsafe { *dist.get_unchecked_mut(v) = nd; *pred.get_unchecked_mut(v) = u as i32; } let b = bucket_of(nd, inv_delta); if b > max_bucket_cap { return (relaxations, light_relax, heavy_relax, settled_count, -5); } ensure_bucket(&mut buckets,b); if !in_bucket[v] && !settled[v] { buckets[b].push(v as u32); in_bucket[v] = true; request_light_repeat |= b == current_bucket; } relaxations += 1; light_relax += 1; } } }
+            for &u_raw in &frontier { let u = u_raw as usize; if settled[u] { continue; } settled[u] = true; settled_count += 1; light_set.push(u_raw); ops.frontier_expansions += 1; let start = off[u] as usize; let end = off[u+1] as usize; let base = dist[u];
+                for e in start..end { let v = unsafe { *tgt.get_unchecked(e) } as usize; let w = unsafe { *wts.get_unchecked(e) }; if w <= delta { ops.edge_relaxations += 1; let nd = base + w; let cur = unsafe { *dist.get_unchecked(v) }; if nd < cur { unsafe { *dist.get_unchecked_mut(v) = nd; } if let Some(p) = pred.as_deref_mut() { unsafe { *p.get_unchecked_mut(v) = u as i32; } } let b = bucket_of(nd, inv_delta); if b > max_bucket_cap { return (relaxations, light_relax, heavy_relax, settled_count, -5); } ensure_bucket(&mut buckets,b); if !in_bucket[v] && !settled[v] { buckets[b].push(v as u32); in_bucket[v] = true; request_light_repeat |= b == current_bucket; } relaxations += 1; light_relax += 1; ops.improvements += 1; } } }
                 if let Some(limit) = truncate_after { if settled_count >= limit { break; } }
             }
             if let Some(limit) = truncate_after { if settled_count >= limit { break; } }
         }
-        for &u_raw in &light_set { let u = u_raw as usize; let start = off[u] as usize; let end = off[u+1] as usize; let base = dist[u]; for e in start..end { let v = unsafe { *tgt.get_unchecked(e) } as usize; let w = unsafe { *wts.get_unchecked(e) }; if w > delta { let nd = base + w; let cur = unsafe { *dist.get_unchecked(v) }; if nd < cur { unsafe { *dist.get_unchecked_mut(v) = nd; *pred.get_unchecked_mut(v) = u as i32; } let b = bucket_of(nd, inv_delta); if b > max_bucket_cap { return (relaxations, light_relax, heavy_relax, settled_count, -5); } ensure_bucket(&mut buckets,b); if !in_bucket[v] && !settled[v] { buckets[b].push(v as u32); in_bucket[v] = true; } relaxations += 1; heavy_relax += 1; } } } }
+        for &u_raw in &light_set { let u = u_raw as usize; let start = off[u] as usize; let end = off[u+1] as usize; let base = dist[u]; for e in start..end { let v = unsafe { *tgt.get_unchecked(e) } as usize; let w = unsafe { *wts.get_unchecked(e) }; if w > delta { ops.edge_relaxations += 1; let nd = base + w; let cur = unsafe { *dist.get_unchecked(v) }; if nd < cur { unsafe { *dist.get_unchecked_mut(v) = nd; } if let Some(p) = pred.as_deref_mut() { unsafe { *p.get_unchecked_mut(v) = u as i32; } } let b = bucket_of(nd, inv_delta); if b > max_bucket_cap { return (relaxations, light_relax, heavy_relax, settled_count, -5); } ensure_bucket(&mut buckets,b); if !in_bucket[v] && !settled[v] { buckets[b].push(v as u32); in_bucket[v] = true; } relaxations += 1; heavy_relax += 1; ops.improvements += 1; } } } }
         if let Some(limit) = truncate_after { if settled_count >= limit { break; } }
         current_bucket += 1;
     }
@@ -460,22 +573,69 @@ pub extern "C" fn sssp_run_stoc_autotune(
     out_dist: *mut f32,
     out_pred: *mut i32,
     info: *mut SsspResultInfo,
+) -> i32 {
+    sssp_run_stoc_autotune_params(n, offsets, targets, weights, source, out_dist, out_pred, core::ptr::null_mut(), info)
+}
+
+// Autotuner controls (in) and chosen parameters (out). Zeroed inputs fall back
+// to the env/default behaviour of `sssp_run_stoc_autotune`.
+#[repr(C)]
+pub struct SsspAutotuneParams {
+    // in
+    pub candidates: *const f32,    // delta multipliers to try (null => SSSP_STOC_AUTOTUNE_SET / default set)
+    pub candidate_count: u32,
+    pub trial_limit: u32,          // nodes settled per trial run (0 => SSSP_STOC_AUTOTUNE_LIMIT / 2048)
+    pub pinned_delta: f32,         // > 0 skips tuning entirely and runs with this delta
+    // out
+    pub chosen_delta: f32,
+    pub chosen_mult: f32,          // 0 when pinned_delta was used
+    pub avg_weight: f32,           // sampled average weight delta is scaled from
+    pub trials: u32,               // truncated trial runs executed
+    pub buckets: u32,              // bucket indices spanned by final distances
+}
+
+#[no_mangle]
+pub extern "C" fn sssp_run_stoc_autotune_params(
+    n: u32,
+    offsets: *const u32,
+    targets: *const u32,
+    weights: *const f32,
+    source: u32,
+    out_dist: *mut f32,
+    out_pred: *mut i32,
+    params: *mut SsspAutotuneParams,
+    info: *mut SsspResultInfo,
 ) -> i32 {
     if n == 0 { return -1; }
     if source >= n { return -2; }
     if offsets.is_null() || targets.is_null() || weights.is_null() || out_dist.is_null() || out_pred.is_null() { return -3; }
     let n_usize = n as usize; let off = as_slice(offsets, n_usize + 1); let m = match off.last() { Some(v) => *v as usize, None => return -4 }; let tgt = as_slice(targets, m); let wts = as_slice(weights, m);
     let dist = as_mut_slice(out_dist, n_usize); let pred = as_mut_slice(out_pred, n_usize);
+    let params = if params.is_null() { None } else { Some(unsafe { &mut *params }) };
     let sample = core::cmp::min(1000, m); let avg = derive_avg_weight(sample, wts);
-    let candidates = { let mut c = parse_autotune_set(); if c.is_empty() { c.push(3.0); } c };
-    let limit: u32 = std::env::var("SSSP_STOC_AUTOTUNE_LIMIT").ok().and_then(|v| v.parse().ok()).unwrap_or(2048).min(n);
-    let mut best_mult = candidates[0]; let mut best_time = f64::INFINITY;
-    let mut tmp_dist = vec![0f32; n_usize]; let mut tmp_pred = vec![0i32; n_usize];
-    for &mult in &candidates { let delta = (avg * mult).clamp(0.0001, 1e6); let start = Instant::now(); let (_r,_l,_h,_s,err) = stoc_run_internal(n, off, tgt, wts, source, delta, &mut tmp_dist, &mut tmp_pred, Some(limit)); if err != 0 { continue; } let elapsed = start.elapsed().as_secs_f64(); if elapsed < best_time { best_time = elapsed; best_mult = mult; } }
-    let final_delta = (avg * best_mult).clamp(0.0001, 1e6);
-    let (relax, light, heavy, settled, err) = stoc_run_internal(n, off, tgt, wts, source, final_delta, dist, pred, None);
+    let pinned = params.as_ref().map(|p| p.pinned_delta).unwrap_or(0.0);
+    let mut trials: u32 = 0;
+    let mut ops = SsspOpCounters::default();
+    let (final_delta, best_mult) = if pinned > 0.0 { (pinned.clamp(0.0001, 1e6), 0.0) } else {
+        let candidates = {
+            let mut c = match params.as_ref() { Some(p) if !p.candidates.is_null() && p.candidate_count > 0 => as_slice(p.candidates, p.candidate_count as usize).iter().copied().filter(|x| *x > 0.0).collect(), _ => parse_autotune_set() };
+            if c.is_empty() { c.push(3.0); } c
+        };
+        let limit: u32 = match params.as_ref() { Some(p) if p.trial_limit > 0 => p.trial_limit, _ => std::env::var("SSSP_STOC_AUTOTUNE_LIMIT").ok().and_then(|v| v.parse().ok()).unwrap_or(2048) }.min(n);
+        let mut best_mult = candidates[0]; let mut best_time = f64::INFINITY;
+        let mut tmp_dist = vec![0f32; n_usize];
+        for &mult in &candidates { let delta = (avg * mult).clamp(0.0001, 1e6); let start = Instant::now(); let (_r,_l,_h,_s,err) = stoc_run_internal(n, off, tgt, wts, source, delta, &mut tmp_dist, None, Some(limit), &mut ops); trials += 1; if err != 0 { continue; } let elapsed = start.elapsed().as_secs_f64(); if elapsed < best_time { best_time = elapsed; best_mult = mult; } }
+        ((avg * best_mult).clamp(0.0001, 1e6), best_mult)
+    };
+    let (relax, light, heavy, settled, err) = stoc_run_internal(n, off, tgt, wts, source, final_delta, dist, Some(pred), None, &mut ops);
     if err != 0 { return err; }
+    LAST_OPS.with(|c| c.set(ops));
     if !info.is_null() { unsafe { *info = SsspResultInfo { relaxations: relax, light_relaxations: light, heavy_relaxations: heavy, settled, error_code: 0 }; } }
+    if let Some(p) = params {
+        let max_finite = dist.iter().copied().filter(|d| d.is_finite()).fold(0.0f32, f32::max);
+        p.chosen_delta = final_delta; p.chosen_mult = best_mult; p.avg_weight = avg; p.trials = trials;
+        p.buckets = (max_finite / final_delta) as u32 + 1;
+    }
     // Autotune internal run does not update global stats; only final full run instrumentation performed via LAST_BUCKET_STATS in sssp_run_stoc.
     0
 }
@@ -515,11 +675,12 @@ pub extern "C" fn sssp_run_stoc_auto_adapt(
         };
         if samp.is_empty() { 1.0 } else { samp.sort_by(|a,b| a.partial_cmp(b).unwrap()); let q_index = ((samp.len()-1) as f32 * (1.0 - heavy_target)).round() as usize; samp[q_index].max(1e-4) }
     } else { 0.0 }; // unused in avg mode
-    let mut best_mult = candidates[0]; let mut best_time = f64::INFINITY; let mut tmp_dist = vec![0f32; n_usize]; let mut tmp_pred = vec![0i32; n_usize];
+    let mut best_mult = candidates[0]; let mut best_time = f64::INFINITY; let mut tmp_dist = vec![0f32; n_usize];
+    let mut trial_ops = SsspOpCounters::default();
     for &mult in &candidates {
         let delta = if mode == "quantile" { (base_quantile * mult).clamp(1e-4, 1e6) } else { (avg * mult).clamp(1e-4, 1e6) };
         let start = Instant::now();
-        let (_r,_l,_h,_s,err) = stoc_run_internal(n, off, tgt, wts, source, delta, &mut tmp_dist, &mut tmp_pred, Some(limit));
+        let (_r,_l,_h,_s,err) = stoc_run_internal(n, off, tgt, wts, source, delta, &mut tmp_dist, None, Some(limit), &mut trial_ops);
         if err != 0 { continue; }
         let elapsed = start.elapsed().as_secs_f64();
         if elapsed < best_time { best_time = elapsed; best_mult = mult; }
@@ -531,9 +692,129 @@ pub extern "C" fn sssp_run_stoc_auto_adapt(
     let rc = sssp_run_stoc(n, offsets, targets, weights, source, out_dist, out_pred, info);
     // Restore previous env state.
     if prev.is_none() { std::env::remove_var(env_key); }
+    if rc == 0 { LAST_OPS.with(|c| { let mut o = c.get(); o.add(&trial_ops); c.set(o); }); }
     rc
 }
 
+// ------------------- Batched multi-source (C ABI) -------------------
+// Runs one SSSP per entry of `sources` in a single FFI crossing and writes a
+// row-major distance matrix: out_dist[i*n + v] = dist(sources[i], v), len k*n.
+// out_pred is optional (null => no predecessors recorded), otherwise len k*n.
+// Modes: 0 baseline, 1 delta-stepping (fixed delta = avg * SSSP_STOC_DELTA_MULT,
+// no adaptive restarts), 2 autotune (multiplier tuned once on sources[0], then
+// reused for every row). Rows are split across worker threads; workers use the
+// internal runners only, so LAST_* instrumentation globals are not updated.
+// info (optional) receives counters summed over all rows.
+#[no_mangle]
+pub extern "C" fn sssp_run_batch(
+    n: u32,
+    offsets: *const u32,
+    targets: *const u32,
+    weights: *const f32,
+    sources: *const u32,
+    k: u32,
+    mode: i32,
+    out_dist: *mut f32,
+    out_pred: *mut i32,
+    info: *mut SsspResultInfo,
+) -> i32 {
+    sssp_run_batch_threads(n, offsets, targets, weights, sources, k, mode, 0, out_dist, out_pred, info)
+}
+
+// Worker thread cap for batched runs: explicit `threads` > 0 wins, then
+// SSSP_THREADS, then all available cores. Never more workers than rows.
+fn resolve_threads(threads: u32, rows: usize) -> usize {
+    let requested = if threads > 0 { threads as usize } else {
+        std::env::var("SSSP_THREADS").ok().and_then(|v| v.parse::<usize>().ok()).filter(|t| *t > 0)
+            .unwrap_or_else(|| std::thread::available_parallelism().map(|p| p.get()).unwrap_or(1))
+    };
+    requested.clamp(1, rows.max(1))
+}
+
+// Same as `sssp_run_batch` with an explicit per-call worker cap (0 => default).
+#[no_mangle]
+pub extern "C" fn sssp_run_batch_threads(
+    n: u32,
+    offsets: *const u32,
+    targets: *const u32,
+    weights: *const f32,
+    sources: *const u32,
+    k: u32,
+    mode: i32,
+    threads: u32,
+    out_dist: *mut f32,
+    out_pred: *mut i32,
+    info: *mut SsspResultInfo,
+) -> i32 {
+    if n == 0 { return -1; }
+    if offsets.is_null() || targets.is_null() || weights.is_null() || sources.is_null() || out_dist.is_null() { return -3; }
+    if !(0..=2).contains(&mode) { return -6; }
+    if k == 0 { return 0; }
+    let n_usize = n as usize; let k_usize = k as usize;
+    let off = as_slice(offsets, n_usize + 1); let m = match off.last() { Some(v) => *v as usize, None => return -4 };
+    let tgt = as_slice(targets, m); let wts = as_slice(weights, m);
+    let srcs = as_slice(sources, k_usize);
+    if srcs.iter().any(|&s| s >= n) { return -2; }
+    let dist = as_mut_slice(out_dist, k_usize * n_usize);
+    let mut pred_all = if out_pred.is_null() { None } else { Some(as_mut_slice(out_pred, k_usize * n_usize)) };
+
+    let avg = derive_avg_weight(core::cmp::min(1000, m), wts);
+    let mut ops = SsspOpCounters::default(); // tuning trials, then every row
+    let delta = match mode {
+        1 => { let mult: f32 = std::env::var("SSSP_STOC_DELTA_MULT").ok().and_then(|v| v.parse().ok()).unwrap_or(3.0); (avg * mult).clamp(1e-4, 1e6) }
+        2 => {
+            let candidates = { let mut c = parse_autotune_set(); if c.is_empty() { c.push(3.0); } c };
+            let limit: u32 = std::env::var("SSSP_STOC_AUTOTUNE_LIMIT").ok().and_then(|v| v.parse().ok()).unwrap_or(2048).min(n);
+            let mut best_mult = candidates[0]; let mut best_time = f64::INFINITY;
+            let mut tmp_dist = vec![0f32; n_usize];
+            for &mult in &candidates { let d = (avg * mult).clamp(0.0001, 1e6); let start = Instant::now(); let (_r,_l,_h,_s,err) = stoc_run_internal(n, off, tgt, wts, srcs[0], d, &mut tmp_dist, None, Some(limit), &mut ops); if err != 0 { continue; } let elapsed = start.elapsed().as_secs_f64(); if elapsed < best_time { best_time = elapsed; best_mult = mult; } }
+            (avg * best_mult).clamp(0.0001, 1e6)
+        }
+        _ => 0.0,
+    };
+
+    let threads = resolve_threads(threads, k_usize);
+    let rows_per = (k_usize + threads - 1) / threads;
+    // (relax, light, heavy, settled, err) and op counters per worker
+    let mut totals: Vec<((u64,u64,u64,u64,i32), SsspOpCounters)> = Vec::with_capacity(threads);
+    std::thread::scope(|scope| {
+        let mut handles = Vec::with_capacity(threads);
+        let mut pred_rest = pred_all.as_deref_mut();
+        for (chunk_idx, dist_chunk) in dist.chunks_mut(rows_per * n_usize).enumerate() {
+            let rows = dist_chunk.len() / n_usize;
+            let pred_chunk = match pred_rest.take() { Some(p) => { let (head, tail) = p.split_at_mut(rows * n_usize); pred_rest = Some(tail); Some(head) } None => None };
+            let chunk_srcs = &srcs[chunk_idx * rows_per..chunk_idx * rows_per + rows];
+            handles.push(scope.spawn(move || {
+                let mut acc = (0u64, 0u64, 0u64, 0u64, 0i32);
+                let mut ops = SsspOpCounters::default();
+                // Without out_pred rows record no predecessors at all.
+                let mut pred_chunk = pred_chunk;
+                for (row, &src) in chunk_srcs.iter().enumerate() {
+                    let d = &mut dist_chunk[row * n_usize..(row + 1) * n_usize];
+                    let p = pred_chunk.as_deref_mut().map(|pc| &mut pc[row * n_usize..(row + 1) * n_usize]);
+                    if mode == 0 {
+                        let (relax, _) = baseline_run_internal(off, tgt, wts, src, d, p, &mut ops);
+                        acc.0 += relax; acc.3 += n as u64;
+                    } else {
+                        let (relax, light, heavy, settled, err) = stoc_run_internal(n, off, tgt, wts, src, delta, d, p, None, &mut ops);
+                        if err != 0 { acc.4 = err; break; }
+                        acc.0 += relax; acc.1 += light; acc.2 += heavy; acc.3 += settled as u64;
+                    }
+                }
+                (acc, ops)
+            }));
+        }
+        for h in handles { totals.push(h.join().unwrap_or(((0,0,0,0,-7), SsspOpCounters::default()))); }
+    });
+    let mut agg = (0u64, 0u64, 0u64, 0u64, 0i32);
+    for (t, o) in &totals { agg.0 += t.0; agg.1 += t.1; agg.2 += t.2; agg.3 += t.3; if agg.4 == 0 { agg.4 = t.4; } ops.add(o); }
+    if agg.4 != 0 { return agg.4; }
+    LAST_OPS.with(|c| c.set(ops));
+    LAST_THREADS.with(|c| c.set(totals.len() as u32)); // workers spawned, not requested
+    if !info.is_null() { unsafe { *info = SsspResultInfo { relaxations: agg.0, light_relaxations: agg.1, heavy_relaxations: agg.2, settled: agg.3.min(u32::MAX as u64) as u32, error_code: 0 }; } }
+    0
+}
+
 mod spec_clean; // specification phased implementation module
 mod spec_future; // scaffolding for upcoming phases (no exported symbols yet)
 
@@ -0,0 +1,162 @@
+package sssp
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Node and edge attributes are stored column by column: one []T per name,
+// indexed by node ID or edge ID. Subgraph, SubgraphByEdges, Merge, Reverse
+// and the JSON format carry them over; AddEdges, RemoveEdges and
+// MergeDuplicates move edge values along with their edges, giving added
+// edges the zero value. Weight updates do not touch them.
+
+// attrColumn is one attribute column behind an interface, so a Graph can
+// hold columns of different types.
+type attrColumn interface {
+	len() int
+	// combine builds a column whose entry i is c[a[i]] if a[i] >= 0, else
+	// other[b[i]] if other is non-nil and b[i] >= 0, else the zero value.
+	// other must be nil or a column of the same type.
+	combine(a []int32, other attrColumn, b []int32) (attrColumn, error)
+	value(i int) any
+}
+
+type column[T any] []T
+
+func (c column[T]) len() int        { return len(c) }
+func (c column[T]) value(i int) any { return c[i] }
+
+func (c column[T]) combine(a []int32, other attrColumn, b []int32) (attrColumn, error) {
+	var o column[T]
+	if other != nil {
+		var ok bool
+		if o, ok = other.(column[T]); !ok {
+			return nil, fmt.Errorf("sssp: attribute columns of types %T and %T", c, other)
+		}
+	}
+	out := make(column[T], len(a))
+	for i, j := range a {
+		if j >= 0 {
+			out[i] = c[j]
+		} else if other != nil && b[i] >= 0 {
+			out[i] = o[b[i]]
+		}
+	}
+	return out, nil
+}
+
+// SetNodeAttr attaches values as the node attribute name, values[u] being
+// node u's. It needs one value per node; nil removes the attribute. The
+// slice is kept, not copied.
+func SetNodeAttr[T any](g *Graph, name string, values []T) error {
+	if values == nil {
+		delete(g.nodeAttrs, name)
+		g.dropReverse()
+		return nil
+	}
+	if len(values) != int(g.NodeCount()) {
+		return fmt.Errorf("sssp: attribute %q has %d values for %d nodes", name, len(values), g.NodeCount())
+	}
+	if g.nodeAttrs == nil {
+		g.nodeAttrs = make(map[string]attrColumn)
+	}
+	g.nodeAttrs[name] = column[T](values)
+	g.dropReverse()
+	return nil
+}
+
+// NodeAttr returns the node attribute name, and false if g has none by that
+// name or it does not hold T values.
+func NodeAttr[T any](g *Graph, name string) ([]T, bool) {
+	c, ok := g.nodeAttrs[name].(column[T])
+	return c, ok
+}
+
+// SetEdgeAttr attaches values as the edge attribute name, indexed by edge
+// ID (see EdgeIDs). It needs one value per edge; nil removes the
+// attribute. The slice is kept, not copied, until an edge change moves the
+// values.
+func SetEdgeAttr[T any](g *Graph, name string, values []T) error {
+	if values == nil {
+		delete(g.edgeAttrs, name)
+		g.dropReverse()
+		return nil
+	}
+	if len(values) != g.EdgeCount() {
+		return fmt.Errorf("sssp: attribute %q has %d values for %d edges", name, len(values), g.EdgeCount())
+	}
+	if g.edgeAttrs == nil {
+		g.edgeAttrs = make(map[string]attrColumn)
+	}
+	g.edgeAttrs[name] = column[T](values)
+	g.dropReverse()
+	return nil
+}
+
+// EdgeAttr returns the edge attribute name, indexed by edge ID, and false
+// if g has none by that name or it does not hold T values.
+func EdgeAttr[T any](g *Graph, name string) ([]T, bool) {
+	c, ok := g.edgeAttrs[name].(column[T])
+	return c, ok
+}
+
+// NodeAttrNames returns the names of the node attributes, sorted.
+func (g *Graph) NodeAttrNames() []string { return attrNames(g.nodeAttrs) }
+
+// EdgeAttrNames returns the names of the edge attributes, sorted.
+func (g *Graph) EdgeAttrNames() []string { return attrNames(g.edgeAttrs) }
+
+func attrNames(m map[string]attrColumn) []string {
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// gatherAttrs returns the columns of m rearranged by idx as in combine, or
+// nil if m is empty.
+func gatherAttrs(m map[string]attrColumn, idx []int32) map[string]attrColumn {
+	if len(m) == 0 {
+		return nil
+	}
+	out := make(map[string]attrColumn, len(m))
+	for k, c := range m {
+		out[k], _ = c.combine(idx, nil, nil)
+	}
+	return out
+}
+
+// mergeAttrs combines the columns of a and b by name, taking entry i from
+// a's column at ia[i], or else from b's at ib[i]. A name only one side has
+// is filled from that side. Columns of the same name must have the same
+// type.
+func mergeAttrs(a map[string]attrColumn, ia []int32, b map[string]attrColumn, ib []int32) (map[string]attrColumn, error) {
+	if len(a) == 0 && len(b) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]attrColumn, len(a)+len(b))
+	for k, c := range a {
+		merged, err := c.combine(ia, b[k], ib)
+		if err != nil {
+			return nil, fmt.Errorf("sssp: attribute %q: %w", k, err)
+		}
+		out[k] = merged
+	}
+	for k, c := range b {
+		if _, done := out[k]; !done {
+			out[k], _ = c.combine(ib, nil, nil)
+		}
+	}
+	return out, nil
+}
+
+// moveEdgeAttrs rearranges the edge attributes after an edge change; idx[e]
+// is the old ID of the edge now at e, -1 for a new edge.
+func (g *Graph) moveEdgeAttrs(idx []int32) {
+	if len(g.edgeAttrs) > 0 {
+		g.edgeAttrs = gatherAttrs(g.edgeAttrs, idx)
+	}
+}
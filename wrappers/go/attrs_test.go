@@ -0,0 +1,114 @@
+package sssp
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func attrGraph(t *testing.T) *Graph {
+	t.Helper()
+	g, err := FromEdges(3, []Edge{{0, 1, 1}, {0, 2, 5}, {1, 2, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SetNodeAttr(g, "name", []string{"a", "b", "c"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetEdgeAttr(g, "toll", []bool{false, true, false}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetEdgeAttr(g, "lanes", []int32{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestAttrsSetAndGet(t *testing.T) {
+	g := attrGraph(t)
+	if names, ok := NodeAttr[string](g, "name"); !ok || names[2] != "c" {
+		t.Fatalf("name %v %v", names, ok)
+	}
+	if _, ok := EdgeAttr[float64](g, "lanes"); ok {
+		t.Fatalf("lanes read with the wrong type")
+	}
+	if err := SetNodeAttr(g, "short", []int{1}); err == nil {
+		t.Fatalf("short column accepted")
+	}
+	if got := g.EdgeAttrNames(); !reflect.DeepEqual(got, []string{"lanes", "toll"}) {
+		t.Fatalf("edge attribute names %v", got)
+	}
+	if err := SetEdgeAttr[bool](g, "toll", nil); err != nil || len(g.EdgeAttrNames()) != 1 {
+		t.Fatalf("removal: %v %v", err, g.EdgeAttrNames())
+	}
+}
+
+func TestAttrsFollowEdgeChanges(t *testing.T) {
+	g := attrGraph(t)
+	if err := g.AddEdges([]Edge{{0, 0, 9}, {2, 0, 1}}); err != nil {
+		t.Fatal(err)
+	}
+	lanes, _ := EdgeAttr[int32](g, "lanes")
+	if want := []int32{1, 2, 0, 3, 0}; !reflect.DeepEqual(lanes, want) {
+		t.Fatalf("after AddEdges %v, want %v", lanes, want)
+	}
+	g.RemoveEdge(0, 2)
+	lanes, _ = EdgeAttr[int32](g, "lanes")
+	if want := []int32{1, 0, 3, 0}; !reflect.DeepEqual(lanes, want) {
+		t.Fatalf("after RemoveEdge %v, want %v", lanes, want)
+	}
+	rev := g.Reverse()
+	id := rev.EdgeIDs(2, 1)[0]
+	if lanes, _ := EdgeAttr[int32](rev, "lanes"); lanes[id] != 3 {
+		t.Fatalf("reverse lanes %v", lanes)
+	}
+}
+
+func TestAttrsSubgraphMergeAndJSON(t *testing.T) {
+	g := attrGraph(t)
+	sub, _, err := g.Subgraph([]uint32{2, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	names, _ := NodeAttr[string](sub, "name")
+	toll, _ := EdgeAttr[bool](sub, "toll")
+	if !reflect.DeepEqual(names, []string{"c", "a"}) || !reflect.DeepEqual(toll, []bool{true}) {
+		t.Fatalf("subgraph names %v toll %v", names, toll)
+	}
+
+	overlay, _ := FromEdges(4, []Edge{{0, 1, 3}, {2, 3, 1}})
+	if err := SetEdgeAttr(overlay, "lanes", []int32{7, 8}); err != nil {
+		t.Fatal(err)
+	}
+	m, err := Merge(g, overlay, PreferOverlay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lanes, _ := EdgeAttr[int32](m, "lanes")
+	names, _ = NodeAttr[string](m, "name")
+	if !reflect.DeepEqual(lanes, []int32{1, 2, 3, 8}) || !reflect.DeepEqual(names, []string{"a", "b", "c", ""}) {
+		t.Fatalf("merged lanes %v names %v", lanes, names)
+	}
+	if err := SetEdgeAttr(overlay, "lanes", []float64{7, 8}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Merge(g, overlay, PreferOverlay); err == nil {
+		t.Fatalf("merged columns of different types")
+	}
+
+	var buf bytes.Buffer
+	if err := g.EncodeJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	back, err := DecodeJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names, _ = NodeAttr[string](back, "name")
+	toll, _ = EdgeAttr[bool](back, "toll")
+	nums, ok := EdgeAttr[float64](back, "lanes") // JSON numbers come back as float64
+	if !reflect.DeepEqual(names, []string{"a", "b", "c"}) || !reflect.DeepEqual(toll, []bool{false, true, false}) ||
+		!ok || !reflect.DeepEqual(nums, []float64{1, 2, 3}) {
+		t.Fatalf("round trip names %v toll %v lanes %v", names, toll, nums)
+	}
+}
@@ -0,0 +1,150 @@
+package sssp
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// fingerprintSamples is how many edges Fingerprint digests at most.
+const fingerprintSamples = 4096
+
+// Fingerprint identifies a CSR graph for autotune caching: node and edge
+// counts plus an FNV-1a digest of up to 4096 edges spread evenly over the
+// edge array (source offset, target and weight of each), so it costs the
+// same on any size of graph. Graphs that differ only in unsampled edges
+// share a fingerprint; the tuned delta depends on the weight distribution,
+// which the sample tracks. Graph.Fingerprint caches it per graph.
+func Fingerprint(n uint32, offsets, targets []uint32, weights []float32) string {
+	h := fnv.New64a()
+	m := min(len(targets), len(weights))
+	step := max(m/fingerprintSamples, 1)
+	var buf [12]byte
+	for e, u := 0, 0; e < m; e += step {
+		for u+1 < len(offsets) && int(offsets[u+1]) <= e {
+			u++
+		}
+		binary.LittleEndian.PutUint32(buf[0:], uint32(u))
+		binary.LittleEndian.PutUint32(buf[4:], targets[e])
+		binary.LittleEndian.PutUint32(buf[8:], math.Float32bits(weights[e]))
+		h.Write(buf[:])
+	}
+	return fmt.Sprintf("n%d-m%d-%016x", n, len(targets), h.Sum64())
+}
+
+// fingerprintCache is Graph.Fingerprint for one version of the graph.
+type fingerprintCache struct {
+	fp      string
+	version uint64
+}
+
+// Fingerprint returns the autotune cache key of g, computing it once per
+// version of the graph: weight updates and edge changes invalidate it.
+func (g *Graph) Fingerprint() string {
+	if c := g.fp.Load(); c != nil && c.version == g.version {
+		return c.fp
+	}
+	c := &fingerprintCache{fp: Fingerprint(g.NodeCount(), g.offsets, g.targets, g.weights), version: g.version}
+	g.fp.Store(c)
+	return c.fp
+}
+
+// AutotuneEntry is the tuned state persisted per graph.
+type AutotuneEntry struct {
+	Delta      float32 `json:"delta"`
+	Multiplier float32 `json:"multiplier"`
+}
+
+// AutotuneCache persists autotuned deltas in a JSON file keyed by graph
+// Fingerprint, so later processes can skip the tuning trials.
+type AutotuneCache struct {
+	path    string
+	mu      sync.Mutex
+	entries map[string]AutotuneEntry
+}
+
+// OpenAutotuneCache loads the cache at path. A missing file yields an empty cache.
+func OpenAutotuneCache(path string) (*AutotuneCache, error) {
+	c := &AutotuneCache{path: path, entries: map[string]AutotuneEntry{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		return nil, fmt.Errorf("sssp: autotune cache %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Lookup returns the stored entry for a fingerprint.
+func (c *AutotuneCache) Lookup(fingerprint string) (AutotuneEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[fingerprint]
+	return e, ok
+}
+
+// Store records an entry and rewrites the cache file.
+func (c *AutotuneCache) Store(fingerprint string, e AutotuneEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[fingerprint] = e
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write-then-rename so a crash never leaves a truncated cache behind.
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), ".autotune-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
+
+// Run executes the autotuned variant, reusing a cached delta for this graph
+// when present and tuning (then storing the result) otherwise. It
+// fingerprints the arrays on every call; RunFingerprint and RunGraph take a
+// fingerprint computed once.
+func (c *AutotuneCache) Run(n uint32, offsets, targets []uint32, weights []float32, source uint32) (Result, error) {
+	return c.RunFingerprint(Fingerprint(n, offsets, targets, weights), n, offsets, targets, weights, source)
+}
+
+// RunGraph is Run on g, keyed by the cached g.Fingerprint.
+func (c *AutotuneCache) RunGraph(g *Graph, source uint32) (Result, error) {
+	res, err := c.RunFingerprint(g.Fingerprint(), g.NodeCount(), g.offsets, g.targets, g.weights, source)
+	res.graph = g
+	return res, err
+}
+
+// RunFingerprint is Run with the cache key fp supplied by the caller, for
+// callers that fingerprint a graph once and query it many times.
+func (c *AutotuneCache) RunFingerprint(fp string, n uint32, offsets, targets []uint32, weights []float32, source uint32) (Result, error) {
+	if e, ok := c.Lookup(fp); ok {
+		res, err := RunAutotune(n, offsets, targets, weights, source, AutotuneParams{PinnedDelta: e.Delta})
+		res.Stats.DeltaMultiplier = e.Multiplier
+		return res, err
+	}
+	res, err := RunAutotune(n, offsets, targets, weights, source, AutotuneParams{})
+	if err != nil {
+		return res, err
+	}
+	return res, c.Store(fp, AutotuneEntry{Delta: res.Stats.Delta, Multiplier: res.Stats.DeltaMultiplier})
+}
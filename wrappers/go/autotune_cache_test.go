@@ -0,0 +1,73 @@
+package sssp
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestAutotuneCacheSkipsTuningOnReopen(t *testing.T) {
+	off := []uint32{0, 2, 3, 4, 5}
+	tgt := []uint32{1, 2, 2, 3, 0}
+	wts := []float32{1, 4, 2, 1, 5}
+	path := filepath.Join(t.TempDir(), "autotune.json")
+
+	c, err := OpenAutotuneCache(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	first, err := c.Run(4, off, tgt, wts, 0)
+	if err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if first.Stats.AutotuneTrials == 0 {
+		t.Fatalf("expected tuning trials on a cold cache")
+	}
+
+	reopened, err := OpenAutotuneCache(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	second, err := reopened.Run(4, off, tgt, wts, 1)
+	if err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if second.Stats.AutotuneTrials != 0 || second.Stats.Delta != first.Stats.Delta {
+		t.Fatalf("cached delta not reused: first %+v second %+v", first.Stats, second.Stats)
+	}
+}
+
+func TestFingerprintSeesWeightChanges(t *testing.T) {
+	off := []uint32{0, 1, 1}
+	tgt := []uint32{1}
+	if Fingerprint(2, off, tgt, []float32{1}) == Fingerprint(2, off, tgt, []float32{2}) {
+		t.Fatalf("fingerprint ignored weight change")
+	}
+}
+
+func TestGraphFingerprintFollowsUpdates(t *testing.T) {
+	g, err := FromEdges(3, []Edge{{0, 1, 1}, {1, 2, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	off, tgt, wts := g.CSR()
+	fp := g.Fingerprint()
+	if fp != Fingerprint(3, off, tgt, wts) {
+		t.Fatalf("cached fingerprint %s differs from Fingerprint", fp)
+	}
+	c, err := OpenAutotuneCache(filepath.Join(t.TempDir(), "autotune.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.RunGraph(g, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Lookup(fp); !ok {
+		t.Fatalf("RunGraph did not store under %s", fp)
+	}
+	if err := g.UpdateEdgeWeights([]Edge{{From: 1, To: 2, Weight: 5}}); err != nil {
+		t.Fatal(err)
+	}
+	if g.Fingerprint() == fp {
+		t.Fatalf("fingerprint unchanged after a weight update")
+	}
+}
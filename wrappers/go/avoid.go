@@ -0,0 +1,66 @@
+package sssp
+
+import "fmt"
+
+// bitset is a fixed-size set of small integers.
+type bitset []uint64
+
+func newBitset(n int) bitset { return make(bitset, (n+63)/64) }
+
+func (b bitset) set(i uint64)      { b[i/64] |= 1 << (i % 64) }
+func (b bitset) has(i uint64) bool { return b[i/64]&(1<<(i%64)) != 0 }
+
+// AvoidSet is a set of nodes and edges a query must not use, such as a
+// bridge closed for the day. It is built once for a graph and can be
+// shared by any number of queries, including concurrent ones; a query
+// checks it with one bit test per relaxed edge.
+type AvoidSet struct {
+	nodes, edges   bitset
+	nodeCount      uint32
+	edgeCount      int
+	nNodes, nEdges int
+}
+
+// NewAvoidSet marks nodes, and every edge from p[0] to p[1] for each pair p
+// in edges, as off-limits. Nodes must be in range; a pair with no edge is
+// an error, so a stale list is noticed. The set refers to g's edge IDs:
+// adding or removing edges of g invalidates it, and RunQuery rejects it.
+func (g *Graph) NewAvoidSet(nodes []uint32, edges [][2]uint32) (*AvoidSet, error) {
+	a := &AvoidSet{nodeCount: g.NodeCount(), edgeCount: g.EdgeCount()}
+	if len(nodes) > 0 {
+		a.nodes = newBitset(int(g.NodeCount()))
+		for _, v := range nodes {
+			if v >= g.NodeCount() {
+				return nil, fmt.Errorf("sssp: avoided node %d out of range for %d nodes", v, g.NodeCount())
+			}
+			if !a.nodes.has(uint64(v)) {
+				a.nodes.set(uint64(v))
+				a.nNodes++
+			}
+		}
+	}
+	if len(edges) > 0 {
+		a.edges = newBitset(g.EdgeCount())
+		for _, p := range edges {
+			ids := g.EdgeIDs(p[0], p[1])
+			if len(ids) == 0 {
+				return nil, fmt.Errorf("sssp: avoided edge %d->%d does not exist", p[0], p[1])
+			}
+			for _, id := range ids {
+				if !a.edges.has(uint64(id)) {
+					a.edges.set(uint64(id))
+					a.nEdges++
+				}
+			}
+		}
+	}
+	return a, nil
+}
+
+// Len returns the number of avoided nodes and edges.
+func (a *AvoidSet) Len() (nodes, edges int) { return a.nNodes, a.nEdges }
+
+// blocks reports whether the edge e into v is avoided.
+func (a *AvoidSet) blocks(v uint32, e uint64) bool {
+	return (a.nodes != nil && a.nodes.has(uint64(v))) || (a.edges != nil && a.edges.has(e))
+}
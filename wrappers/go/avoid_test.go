@@ -0,0 +1,45 @@
+package sssp
+
+import "testing"
+
+func TestAvoidSet(t *testing.T) {
+	// Two bridges 1->3 and 2->3 over the river; 0->4->3 goes round.
+	g, err := FromEdges(5, []Edge{{0, 1, 1}, {1, 3, 1}, {1, 3, 2}, {0, 2, 1}, {2, 3, 2}, {0, 4, 5}, {4, 3, 5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bridge, err := g.NewAvoidSet(nil, [][2]uint32{{1, 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, e := bridge.Len(); n != 0 || e != 2 {
+		t.Fatalf("len %d nodes %d edges", n, e)
+	}
+	both, err := g.NewAvoidSet([]uint32{2}, [][2]uint32{{1, 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mode := range []int{ModeBaseline, ModeStoc, ModeAutotune} {
+		res, err := g.RunQuery(0, mode, QueryOptions{Avoid: bridge})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Dist[3] != 3 || res.Pred[3] != 2 {
+			t.Fatalf("mode %d, bridge avoided: dist %v pred %v", mode, res.Dist, res.Pred)
+		}
+		res, err = g.RunQuery(0, mode, QueryOptions{Avoid: both})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Dist[3] != 10 || res.Reachable(2) {
+			t.Fatalf("mode %d, both avoided: dist %v", mode, res.Dist)
+		}
+	}
+	if _, err := g.NewAvoidSet(nil, [][2]uint32{{3, 1}}); err == nil {
+		t.Fatalf("missing edge accepted")
+	}
+	g.RemoveEdge(0, 4)
+	if _, err := g.RunQuery(0, ModeBaseline, QueryOptions{Avoid: bridge}); err == nil {
+		t.Fatalf("stale avoid set accepted")
+	}
+}
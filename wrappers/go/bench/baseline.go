@@ -0,0 +1,114 @@
+package bench
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+// Save writes the report as JSON to path, e.g. to keep it as a baseline.
+func (rep *Report) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := rep.WriteJSON(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadReport reads a report saved with Save or WriteJSON.
+func LoadReport(path string) (*Report, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadReport(f)
+}
+
+// Change compares one algorithm's median run time against a baseline.
+type Change struct {
+	Name      string
+	Baseline  time.Duration // baseline p50; 0 if the algorithm is new
+	Current   time.Duration // current p50; 0 if the algorithm was dropped
+	Ratio     float64       // Current / Baseline; 0 unless both exist
+	Regressed bool          // Ratio exceeds 1 + threshold
+}
+
+// Compare matches cur's algorithms to base's by name and flags those whose
+// median (p50) run time grew by more than threshold, a fraction: 0.1 flags
+// anything over 10% slower. The median is used because a few preempted runs
+// move the mean but not the middle. Both reports must describe a graph of
+// the same size; timings on different graphs say nothing about a regression.
+func Compare(base, cur *Report, threshold float64) ([]Change, error) {
+	if threshold < 0 {
+		return nil, fmt.Errorf("bench: negative regression threshold %v", threshold)
+	}
+	if base.Graph.Nodes != cur.Graph.Nodes || base.Graph.Edges != cur.Graph.Edges {
+		return nil, fmt.Errorf("bench: baseline graph %q has %d nodes, %d edges; current %q has %d, %d",
+			base.Graph.Name, base.Graph.Nodes, base.Graph.Edges, cur.Graph.Name, cur.Graph.Nodes, cur.Graph.Edges)
+	}
+	old := make(map[string]time.Duration)
+	for _, s := range base.Results {
+		old[s.Name] = s.P50
+	}
+	var out []Change
+	for _, s := range cur.Results {
+		c := Change{Name: s.Name, Current: s.P50}
+		if b, ok := old[s.Name]; ok {
+			c.Baseline = b
+			delete(old, s.Name)
+			if b > 0 {
+				c.Ratio = float64(s.P50) / float64(b)
+				c.Regressed = c.Ratio > 1+threshold
+			}
+		}
+		out = append(out, c)
+	}
+	for _, s := range base.Results {
+		if _, dropped := old[s.Name]; dropped {
+			out = append(out, Change{Name: s.Name, Baseline: s.P50})
+		}
+	}
+	return out, nil
+}
+
+// Regressions counts the regressed entries of changes.
+func Regressions(changes []Change) int {
+	n := 0
+	for _, c := range changes {
+		if c.Regressed {
+			n++
+		}
+	}
+	return n
+}
+
+// WriteChanges prints changes as a table.
+func WriteChanges(w io.Writer, changes []Change) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "algorithm\tbaseline p50 ms\tcurrent p50 ms\tchange\t\t")
+	for _, c := range changes {
+		change, flag := "", ""
+		switch {
+		case c.Baseline == 0:
+			change = "new"
+		case c.Current == 0:
+			change = "dropped"
+		case c.Ratio > 0:
+			change = fmt.Sprintf("%+.1f%%", (c.Ratio-1)*100)
+		}
+		if c.Regressed {
+			flag = "REGRESSION"
+		}
+		fmt.Fprintf(tw, "%s\t%.3f\t%.3f\t%s\t%s\t\n", c.Name, ms(c.Baseline), ms(c.Current), change, flag)
+	}
+	return tw.Flush()
+}
+
+func ms(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
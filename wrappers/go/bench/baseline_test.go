@@ -0,0 +1,68 @@
+package bench
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompareFlagsRegressions(t *testing.T) {
+	rep := func(p50s map[string]time.Duration) *Report {
+		r := &Report{Graph: GraphInfo{Name: "g", Nodes: 10, Edges: 20}}
+		for _, name := range []string{"baseline", "stoc", "autotune", "gpu"} {
+			if d, ok := p50s[name]; ok {
+				r.Results = append(r.Results, Summary{Name: name, P50: d})
+			}
+		}
+		return r
+	}
+	base := rep(map[string]time.Duration{"baseline": 100, "stoc": 100, "gpu": 100})
+	cur := rep(map[string]time.Duration{"baseline": 109, "stoc": 125, "autotune": 50})
+
+	path := filepath.Join(t.TempDir(), "base.json")
+	if err := base.Save(path); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	loaded, err := LoadReport(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	changes, err := Compare(loaded, cur, 0.1)
+	if err != nil {
+		t.Fatalf("compare: %v", err)
+	}
+	want := []Change{
+		{Name: "baseline", Baseline: 100, Current: 109, Ratio: 1.09},
+		{Name: "stoc", Baseline: 100, Current: 125, Ratio: 1.25, Regressed: true},
+		{Name: "autotune", Current: 50},
+		{Name: "gpu", Baseline: 100},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("changes %+v", changes)
+	}
+	for i, c := range changes {
+		if c != want[i] {
+			t.Fatalf("change %d = %+v, want %+v", i, c, want[i])
+		}
+	}
+	if Regressions(changes) != 1 {
+		t.Fatalf("regressions = %d", Regressions(changes))
+	}
+	var buf bytes.Buffer
+	WriteChanges(&buf, changes)
+	for _, s := range []string{"+25.0%", "REGRESSION", "new", "dropped"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Fatalf("table lacks %q:\n%s", s, buf.String())
+		}
+	}
+
+	cur.Graph.Edges = 21
+	if _, err := Compare(base, cur, 0.1); err == nil {
+		t.Fatalf("expected an error for a different graph")
+	}
+	if _, err := Compare(base, base, -1); err == nil {
+		t.Fatalf("expected an error for a negative threshold")
+	}
+}
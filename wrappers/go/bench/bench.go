@@ -0,0 +1,338 @@
+// Package bench times shortest-path algorithms on a graph with enough
+// repetitions to tell real differences from noise.
+//
+// A Runner picks random sources once and runs every algorithm from the same
+// ones, after a few untimed warmup runs. Timed runs are interleaved across
+// algorithms (source 0 for each, then source 1, ...) so slow drift in the
+// machine's state, such as thermal throttling or a noisy neighbour, hits all
+// of them alike; Runner.Order can instead shuffle each round or time one
+// algorithm at a time. Each algorithm gets a Summary of its run times: mean,
+// standard deviation and the p50/p95/p99 percentiles.
+package bench
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"time"
+
+	sssp "github.com/your-org/optimized-sssp-go"
+)
+
+// Algorithm is one contender.
+type Algorithm struct {
+	Name string
+	Run  func(g *sssp.Graph, source uint32) (sssp.Result, error)
+}
+
+// Mode wraps Graph.Run with a fixed run mode.
+func Mode(name string, mode int) Algorithm {
+	return Algorithm{Name: name, Run: func(g *sssp.Graph, source uint32) (sssp.Result, error) {
+		return g.Run(source, mode)
+	}}
+}
+
+// Autotune runs delta-stepping with explicit autotune parameters, e.g. a
+// PinnedDelta to time one fixed delta, or a smaller TrialLimit.
+func Autotune(name string, params sssp.AutotuneParams) Algorithm {
+	return Algorithm{Name: name, Run: func(g *sssp.Graph, source uint32) (sssp.Result, error) {
+		off, tgt, wts := g.CSR()
+		return sssp.RunAutotune(g.NodeCount(), off, tgt, wts, source, params)
+	}}
+}
+
+// Modes returns the built-in run modes, named as in the ssspbench command.
+func Modes() []Algorithm {
+	return []Algorithm{
+		Mode("baseline", sssp.ModeBaseline),
+		Mode("stoc", sssp.ModeStoc),
+		Mode("autotune", sssp.ModeAutotune),
+		Mode("gpu", sssp.ModeGPU),
+	}
+}
+
+// Runner holds the benchmark settings. The zero value runs 10 timed
+// repetitions after 2 warmup runs, from sources drawn with seed 0.
+type Runner struct {
+	Warmup int // untimed runs per algorithm before timing; 0 means 2, < 0 none
+	Reps   int // timed runs per algorithm; 0 means 10
+	Seed   int64
+	// Sources overrides the random choice; timed run i starts from
+	// Sources[i % len(Sources)].
+	Sources []uint32
+	// Observe, if set, sees the result of every timed run, e.g. to check
+	// distances against a reference.
+	Observe func(alg string, source uint32, res *sssp.Result)
+	// Memory, if set, also records each algorithm's memory use in
+	// Summary.Mem. A goroutine samples the process every millisecond of
+	// each timed run, which costs a little time, so compare run times only
+	// between runs with the same setting.
+	Memory bool
+	// Perf, if set, also records hardware counters of each timed run in
+	// Summary.Perf (Linux only; see PerfCounters). Run then keeps its
+	// goroutine on one OS thread and fails if no counter can be opened.
+	Perf bool
+	// Order arranges the timed runs; the default interleaves them.
+	Order Order
+	// GC, if set, collects garbage before every timed run, untimed, so no
+	// run pays for the previous one's allocations.
+	GC bool
+}
+
+// Order is how a Runner arranges the timed runs of several algorithms.
+type Order int
+
+const (
+	// Interleaved warms up every algorithm, then times round i of all of
+	// them from source i, in the order given, so drift in the machine's
+	// state hits all alike.
+	Interleaved Order = iota
+	// Shuffled is Interleaved with the algorithms' order in each round
+	// drawn at random (from Seed), so none always runs right after another
+	// and inherits its cache state.
+	Shuffled
+	// Blocked warms up and times one algorithm at a time, each starting
+	// from its own warmed-up state; with GC set, the heap is also collected
+	// between algorithms. Slow drift then shows up as a difference between
+	// algorithms, so prefer it only when they disturb each other.
+	Blocked
+)
+
+var orderNames = []string{"interleaved", "shuffled", "blocked"}
+
+func (o Order) String() string {
+	if o >= 0 && int(o) < len(orderNames) {
+		return orderNames[o]
+	}
+	return fmt.Sprintf("Order(%d)", int(o))
+}
+
+// ParseOrder parses an Order's String form.
+func ParseOrder(s string) (Order, error) {
+	for i, name := range orderNames {
+		if s == name {
+			return Order(i), nil
+		}
+	}
+	return 0, fmt.Errorf("bench: unknown order %q", s)
+}
+
+// Summary describes the timed runs of one algorithm. Durations encode in
+// JSON as integer nanoseconds.
+type Summary struct {
+	Name    string          `json:"name"`
+	Runs    int             `json:"runs"`
+	Mean    time.Duration   `json:"mean_ns"`
+	StdDev  time.Duration   `json:"stddev_ns"`
+	Min     time.Duration   `json:"min_ns"`
+	Max     time.Duration   `json:"max_ns"`
+	P50     time.Duration   `json:"p50_ns"`
+	P95     time.Duration   `json:"p95_ns"`
+	P99     time.Duration   `json:"p99_ns"`
+	Samples []time.Duration `json:"samples_ns"` // in run order
+	// Counters averaged over the timed runs.
+	Relaxations float64         `json:"relaxations"`
+	Settled     float64         `json:"settled"`
+	Ops         sssp.OpCounters `json:"ops"`            // per-run mean, rounded down
+	Mem         *MemUsage       `json:"mem,omitempty"`  // set when Runner.Memory is
+	Perf        *PerfCounters   `json:"perf,omitempty"` // set when Runner.Perf is
+}
+
+// settings returns the warmup and repetition counts with defaults applied.
+func (r Runner) settings() (warmup, reps int) {
+	warmup, reps = r.Warmup, r.Reps
+	if reps <= 0 {
+		reps = 10
+	}
+	if warmup == 0 {
+		warmup = 2
+	}
+	return max(warmup, 0), reps
+}
+
+// Run benchmarks algs on g and returns their summaries in the same order.
+func (r Runner) Run(g *sssp.Graph, algs ...Algorithm) ([]Summary, error) {
+	if len(algs) == 0 {
+		return nil, fmt.Errorf("bench: no algorithms")
+	}
+	warmup, reps := r.settings()
+	sources := r.Sources
+	if len(sources) == 0 {
+		sources = RandomSources(g, reps, r.Seed)
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("bench: graph has no nodes")
+	}
+	var perf *perfSet
+	if r.Perf {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		var err error
+		if perf, err = openPerf(); err != nil {
+			return nil, err
+		}
+		defer perf.close()
+	}
+	warm := func(a Algorithm) error {
+		for i := 0; i < warmup; i++ {
+			if _, err := a.Run(g, sources[i%len(sources)]); err != nil {
+				return fmt.Errorf("bench: %s warmup from %d: %w", a.Name, sources[i%len(sources)], err)
+			}
+		}
+		return nil
+	}
+	out := make([]Summary, len(algs))
+	for i := range out {
+		out[i] = Summary{Name: algs[i].Name, Samples: make([]time.Duration, 0, reps)}
+		if r.Memory {
+			out[i].Mem = &MemUsage{}
+		}
+		if perf != nil {
+			out[i].Perf = &PerfCounters{}
+		}
+	}
+	timed := func(j int, s uint32) error {
+		a, sum := algs[j], &out[j]
+		if r.GC {
+			runtime.GC()
+		}
+		var probe *memProbe
+		if r.Memory {
+			probe = startProbe()
+		}
+		if perf != nil {
+			perf.start()
+		}
+		start := time.Now()
+		res, err := a.Run(g, s)
+		elapsed := time.Since(start)
+		if perf != nil {
+			perf.stop(sum.Perf)
+		}
+		if probe != nil {
+			probe.finish(sum.Mem)
+		}
+		if err != nil {
+			return fmt.Errorf("bench: %s from %d: %w", a.Name, s, err)
+		}
+		sum.Samples = append(sum.Samples, elapsed)
+		sum.Relaxations += float64(res.Stats.Relaxations)
+		sum.Settled += float64(res.Stats.Settled)
+		sum.Ops.Add(res.Stats.Ops)
+		if r.Observe != nil {
+			r.Observe(a.Name, s, &res)
+		}
+		return nil
+	}
+	switch r.Order {
+	case Blocked:
+		for j, a := range algs {
+			if r.GC {
+				runtime.GC()
+			}
+			if err := warm(a); err != nil {
+				return nil, err
+			}
+			for i := 0; i < reps; i++ {
+				if err := timed(j, sources[i%len(sources)]); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case Interleaved, Shuffled:
+		for _, a := range algs {
+			if err := warm(a); err != nil {
+				return nil, err
+			}
+		}
+		order := make([]int, len(algs))
+		for j := range order {
+			order[j] = j
+		}
+		rng := rand.New(rand.NewSource(r.Seed))
+		for i := 0; i < reps; i++ {
+			if r.Order == Shuffled {
+				rng.Shuffle(len(order), func(a, b int) { order[a], order[b] = order[b], order[a] })
+			}
+			for _, j := range order {
+				if err := timed(j, sources[i%len(sources)]); err != nil {
+					return nil, err
+				}
+			}
+		}
+	default:
+		return nil, fmt.Errorf("bench: unknown order %v", r.Order)
+	}
+	for i := range out {
+		out[i].summarize()
+	}
+	return out, nil
+}
+
+// summarize fills the statistics from Samples.
+func (s *Summary) summarize() {
+	k := len(s.Samples)
+	s.Runs = k
+	if k == 0 {
+		return
+	}
+	sorted := append([]time.Duration(nil), s.Samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var total float64
+	for _, d := range sorted {
+		total += float64(d)
+	}
+	mean := total / float64(k)
+	var sq float64
+	for _, d := range sorted {
+		sq += (float64(d) - mean) * (float64(d) - mean)
+	}
+	if k > 1 {
+		s.StdDev = time.Duration(math.Sqrt(sq / float64(k-1)))
+	}
+	s.Mean = time.Duration(mean)
+	s.Min, s.Max = sorted[0], sorted[k-1]
+	s.P50, s.P95, s.P99 = percentile(sorted, 50), percentile(sorted, 95), percentile(sorted, 99)
+	s.Relaxations /= float64(k)
+	s.Settled /= float64(k)
+	o := &s.Ops
+	for _, c := range []*uint64{&o.EdgeRelaxations, &o.Improvements, &o.HeapPushes, &o.HeapPops, &o.FrontierExpansions, &o.BucketScans} {
+		*c /= uint64(k)
+	}
+	if s.Mem != nil {
+		s.Mem.Allocs /= uint64(k)
+		s.Mem.AllocBytes /= uint64(k)
+	}
+	if s.Perf != nil {
+		for _, c := range s.Perf.fields() {
+			*c /= uint64(k)
+		}
+	}
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted samples.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+// RandomSources draws up to count distinct sources, preferring nodes with
+// out-edges so a run is not trivially empty.
+func RandomSources(g *sssp.Graph, count int, seed int64) []uint32 {
+	n := int(g.NodeCount())
+	offsets, _, _ := g.CSR()
+	r := rand.New(rand.NewSource(seed))
+	seen := make(map[uint32]bool)
+	var out []uint32
+	for tries := 0; len(out) < min(count, n) && tries < 100*count; tries++ {
+		v := uint32(r.Intn(n))
+		if seen[v] || (offsets[v] == offsets[v+1] && tries < 50*count) {
+			continue
+		}
+		seen[v] = true
+		out = append(out, v)
+	}
+	return out
+}
@@ -0,0 +1,122 @@
+package bench
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	sssp "github.com/your-org/optimized-sssp-go"
+)
+
+func TestSummarize(t *testing.T) {
+	s := Summary{Samples: make([]time.Duration, 100)}
+	for i := range s.Samples {
+		s.Samples[i] = time.Duration(100-i) * time.Millisecond // 100ms .. 1ms
+	}
+	s.Relaxations = 500
+	s.summarize()
+	if s.Runs != 100 || s.Min != time.Millisecond || s.Max != 100*time.Millisecond {
+		t.Fatalf("runs/min/max: %+v", s)
+	}
+	if s.P50 != 50*time.Millisecond || s.P95 != 95*time.Millisecond || s.P99 != 99*time.Millisecond {
+		t.Fatalf("percentiles %v %v %v", s.P50, s.P95, s.P99)
+	}
+	if s.Mean != 50500*time.Microsecond || s.Relaxations != 5 {
+		t.Fatalf("mean %v, relaxations %v", s.Mean, s.Relaxations)
+	}
+	// Sample standard deviation of 1..100 is sqrt(841.666...).
+	if d := s.StdDev - 29011*time.Microsecond; d < -time.Microsecond || d > time.Microsecond {
+		t.Fatalf("stddev %v", s.StdDev)
+	}
+	if p := percentile([]time.Duration{7}, 99); p != 7 {
+		t.Fatalf("single-sample percentile %v", p)
+	}
+}
+
+func TestRunnerSharesSources(t *testing.T) {
+	g, err := sssp.GenerateRandomGraph(200, 4, 3, sssp.RandomGraphOptions{})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	calls := map[string][]uint32{}
+	counting := func(name string) Algorithm {
+		return Algorithm{Name: name, Run: func(g *sssp.Graph, s uint32) (sssp.Result, error) {
+			calls[name] = append(calls[name], s)
+			return g.Run(s, sssp.ModeBaseline)
+		}}
+	}
+	observed := 0
+	r := Runner{Warmup: 3, Reps: 7, Seed: 9, Observe: func(string, uint32, *sssp.Result) { observed++ }}
+	sums, err := r.Run(g, counting("a"), counting("b"))
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(calls["a"]) != 10 || observed != 14 {
+		t.Fatalf("%d calls, %d observed; want 3 warmup + 7 timed each", len(calls["a"]), observed)
+	}
+	for i := range calls["a"] {
+		if calls["a"][i] != calls["b"][i] {
+			t.Fatalf("run %d: sources %d and %d differ", i, calls["a"][i], calls["b"][i])
+		}
+	}
+	seen := map[uint32]bool{}
+	for _, s := range calls["a"][3:] {
+		seen[s] = true
+	}
+	if len(seen) != 7 {
+		t.Fatalf("timed runs used %d distinct sources, want 7", len(seen))
+	}
+	for _, s := range sums {
+		if s.Runs != 7 || s.P50 <= 0 || s.Settled == 0 || len(s.Samples) != 7 {
+			t.Fatalf("summary %+v", s)
+		}
+	}
+	if _, err := (Runner{}).Run(g); err == nil {
+		t.Fatalf("expected an error without algorithms")
+	}
+}
+
+func TestRunnerOrders(t *testing.T) {
+	g, err := sssp.GenerateRandomGraph(100, 3, 1, sssp.RandomGraphOptions{})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	var seq []string
+	logged := func(name string) Algorithm {
+		return Algorithm{Name: name, Run: func(g *sssp.Graph, s uint32) (sssp.Result, error) {
+			seq = append(seq, name)
+			return g.Run(s, sssp.ModeBaseline)
+		}}
+	}
+	runs := func(o Order) string {
+		seq = nil
+		if _, err := (Runner{Warmup: 1, Reps: 20, Order: o, GC: true}).Run(g, logged("a"), logged("b")); err != nil {
+			t.Fatalf("%v: %v", o, err)
+		}
+		return strings.Join(seq, "")
+	}
+	if got := runs(Blocked); got != "a"+strings.Repeat("a", 20)+"b"+strings.Repeat("b", 20) {
+		t.Fatalf("blocked ran %s", got)
+	}
+	if got := runs(Interleaved); got != "ab"+strings.Repeat("ab", 20) {
+		t.Fatalf("interleaved ran %s", got)
+	}
+	got := runs(Shuffled)
+	timed := got[2:]
+	for i := 0; i < len(timed); i += 2 {
+		if timed[i] == timed[i+1] {
+			t.Fatalf("shuffled round %d ran %s", i/2, timed[i:i+2])
+		}
+	}
+	if !strings.Contains(timed, "ba") || timed == strings.Repeat("ba", 20) {
+		t.Fatalf("shuffled ran %s", got)
+	}
+	for _, o := range []Order{Interleaved, Shuffled, Blocked} {
+		if back, err := ParseOrder(o.String()); err != nil || back != o {
+			t.Fatalf("ParseOrder(%q) = %v, %v", o, back, err)
+		}
+	}
+	if _, err := (Runner{Order: 7}).Run(g, logged("a")); err == nil {
+		t.Fatalf("expected an unknown order to fail")
+	}
+}
@@ -0,0 +1,165 @@
+package bench
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MemUsage is an algorithm's memory use over its timed runs, recorded when
+// Runner.Memory is set. Go allocation counts cover only the Go heap; the
+// resident-set and cgroup figures also see memory the native library
+// allocates. Peaks are absolute, so they include the graph and anything else
+// the process holds; compare them between algorithms on the same graph.
+type MemUsage struct {
+	Allocs     uint64 `json:"allocs"`          // Go heap objects allocated, per-run mean
+	AllocBytes uint64 `json:"alloc_bytes"`     // Go heap bytes allocated, per-run mean
+	PeakHeap   uint64 `json:"peak_heap_bytes"` // largest Go heap in use seen during a run
+	// PeakRSS is the largest resident set of the process during a run. On
+	// Linux the kernel's high-water mark is reset before each run, so short
+	// spikes count; elsewhere it is sampled, and 0 where unavailable.
+	PeakRSS uint64 `json:"peak_rss_bytes"`
+	// CgroupPeak is the largest memory.current of the process's cgroup v2
+	// sampled during a run, or 0 without a readable cgroup.
+	CgroupPeak uint64 `json:"cgroup_peak_bytes"`
+}
+
+// memSampleInterval is how often a probe samples heap, RSS and cgroup use.
+const memSampleInterval = time.Millisecond
+
+// memProbe watches the process while one run executes.
+type memProbe struct {
+	before   runtime.MemStats
+	hwm      bool // the RSS high-water mark was reset for this run
+	stop     chan struct{}
+	done     chan struct{}
+	heap     uint64
+	rss      uint64
+	cgroup   uint64
+	cgroupAt string
+}
+
+var heapMetric = []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+
+// startProbe begins watching; call finish right after the run.
+func startProbe() *memProbe {
+	p := &memProbe{stop: make(chan struct{}), done: make(chan struct{}), cgroupAt: cgroupFile("memory.current")}
+	runtime.ReadMemStats(&p.before)
+	p.hwm = resetRSSPeak()
+	p.sample()
+	go func() {
+		defer close(p.done)
+		t := time.NewTicker(memSampleInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-t.C:
+				p.sample()
+			}
+		}
+	}()
+	return p
+}
+
+func (p *memProbe) sample() {
+	s := append([]metrics.Sample(nil), heapMetric...)
+	metrics.Read(s)
+	if s[0].Value.Kind() == metrics.KindUint64 {
+		p.heap = max(p.heap, s[0].Value.Uint64())
+	}
+	if !p.hwm {
+		p.rss = max(p.rss, currentRSS())
+	}
+	if p.cgroupAt != "" {
+		p.cgroup = max(p.cgroup, readUintFile(p.cgroupAt))
+	}
+}
+
+// finish stops the probe and adds the run to m: allocation counts are
+// summed (Summary.summarize turns them into means), peaks maxed.
+func (p *memProbe) finish(m *MemUsage) {
+	close(p.stop)
+	<-p.done
+	p.sample()
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+	m.Allocs += after.Mallocs - p.before.Mallocs
+	m.AllocBytes += after.TotalAlloc - p.before.TotalAlloc
+	if p.hwm {
+		p.rss = max(p.rss, statusField("VmHWM:"))
+	}
+	m.PeakHeap = max(m.PeakHeap, p.heap)
+	m.PeakRSS = max(m.PeakRSS, p.rss)
+	m.CgroupPeak = max(m.CgroupPeak, p.cgroup)
+}
+
+// resetRSSPeak resets the kernel's resident-set high-water mark (VmHWM) for
+// this process, which Linux allows through /proc/self/clear_refs.
+func resetRSSPeak() bool {
+	return os.WriteFile("/proc/self/clear_refs", []byte("5"), 0) == nil
+}
+
+// currentRSS returns the resident set size from /proc/self/statm, or 0.
+func currentRSS() uint64 {
+	b, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		return 0
+	}
+	f := strings.Fields(string(b))
+	if len(f) < 2 {
+		return 0
+	}
+	pages, _ := strconv.ParseUint(f[1], 10, 64)
+	return pages * uint64(os.Getpagesize())
+}
+
+// statusField returns a kB field of /proc/self/status in bytes, or 0.
+func statusField(key string) uint64 {
+	b, err := os.ReadFile("/proc/self/status")
+	if err != nil {
+		return 0
+	}
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	for sc.Scan() {
+		if rest, ok := strings.CutPrefix(sc.Text(), key); ok {
+			kb, _ := strconv.ParseUint(strings.TrimSuffix(strings.TrimSpace(rest), " kB"), 10, 64)
+			return kb * 1024
+		}
+	}
+	return 0
+}
+
+// cgroupFile returns the path of a file in this process's cgroup v2
+// directory, or "" if there is none or it is unreadable.
+func cgroupFile(name string) string {
+	b, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		if rel, ok := strings.CutPrefix(line, "0::"); ok {
+			path := filepath.Join("/sys/fs/cgroup", rel, name)
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+func readUintFile(path string) uint64 {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	v, _ := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+	return v
+}
@@ -0,0 +1,43 @@
+package bench
+
+import (
+	"runtime"
+	"testing"
+
+	sssp "github.com/your-org/optimized-sssp-go"
+)
+
+var sink []byte
+
+func TestRunnerRecordsMemory(t *testing.T) {
+	g, err := sssp.GenerateRandomGraph(200, 4, 3, sssp.RandomGraphOptions{})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	hungry := Algorithm{Name: "hungry", Run: func(g *sssp.Graph, s uint32) (sssp.Result, error) {
+		sink = make([]byte, 8<<20)
+		return g.Run(s, sssp.ModeBaseline)
+	}}
+	plain := Mode("baseline", sssp.ModeBaseline)
+	sums, err := Runner{Reps: 4, Memory: true}.Run(g, hungry, plain)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	h, p := sums[0].Mem, sums[1].Mem
+	if h == nil || p == nil {
+		t.Fatalf("memory not recorded: %+v", sums)
+	}
+	if h.AllocBytes < 8<<20 || h.AllocBytes <= p.AllocBytes || h.Allocs == 0 {
+		t.Fatalf("allocations: hungry %+v, baseline %+v", h, p)
+	}
+	if h.PeakHeap == 0 {
+		t.Fatalf("no heap peak: %+v", h)
+	}
+	if runtime.GOOS == "linux" && (h.PeakRSS == 0 || p.PeakRSS == 0) {
+		t.Fatalf("no RSS peak on linux: %+v, %+v", h, p)
+	}
+	sums, _ = Runner{Reps: 1}.Run(g, plain)
+	if sums[0].Mem != nil {
+		t.Fatalf("memory recorded without Runner.Memory")
+	}
+}
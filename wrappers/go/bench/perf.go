@@ -0,0 +1,38 @@
+package bench
+
+// PerfCounters are hardware performance counters of an algorithm's timed
+// runs, recorded when Runner.Perf is set: per-run means, rounded down. They
+// count the thread a run executes on, where the single-source runners do
+// their work, in user space only. A counter the CPU or kernel does not expose
+// stays 0; when the kernel multiplexes more events than the CPU has counters,
+// values are scaled up from the time each was actually counting.
+type PerfCounters struct {
+	Cycles          uint64 `json:"cycles"`
+	Instructions    uint64 `json:"instructions"`
+	CacheReferences uint64 `json:"cache_references"` // last-level cache accesses
+	CacheMisses     uint64 `json:"cache_misses"`     // last-level cache misses
+	Branches        uint64 `json:"branches"`
+	BranchMisses    uint64 `json:"branch_misses"`
+}
+
+// IPC returns instructions per cycle, or 0 without a cycle count.
+func (p *PerfCounters) IPC() float64 {
+	if p.Cycles == 0 {
+		return 0
+	}
+	return float64(p.Instructions) / float64(p.Cycles)
+}
+
+// CacheMissRate returns the fraction of last-level cache accesses that
+// missed, or 0 without a reference count.
+func (p *PerfCounters) CacheMissRate() float64 {
+	if p.CacheReferences == 0 {
+		return 0
+	}
+	return float64(p.CacheMisses) / float64(p.CacheReferences)
+}
+
+// fields lists the counters in perfEvents order.
+func (p *PerfCounters) fields() []*uint64 {
+	return []*uint64{&p.Cycles, &p.Instructions, &p.CacheReferences, &p.CacheMisses, &p.Branches, &p.BranchMisses}
+}
@@ -0,0 +1,127 @@
+package bench
+
+import (
+	"encoding/binary"
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// perfAttr is the first 64 bytes of struct perf_event_attr
+// (PERF_ATTR_SIZE_VER0), all a counting event needs.
+type perfAttr struct {
+	Type         uint32
+	Size         uint32
+	Config       uint64
+	SamplePeriod uint64
+	SampleType   uint64
+	ReadFormat   uint64
+	Flags        uint64
+	WakeupEvents uint32
+	BpType       uint32
+	Config1      uint64
+}
+
+const (
+	perfTypeHardware = 0
+	// perf_event_attr flag bits.
+	perfDisabled      = 1 << 0
+	perfExcludeKernel = 1 << 5
+	perfExcludeHV     = 1 << 6
+	// read_format: value, time enabled, time running.
+	perfFormatTimes = 1<<0 | 1<<1
+	// ioctls from linux/perf_event.h.
+	perfIocEnable  = 0x2400
+	perfIocDisable = 0x2401
+	perfIocReset   = 0x2403
+)
+
+// perfEvents are the PERF_COUNT_HW_* configs behind PerfCounters.fields.
+var perfEvents = []uint64{
+	0, // cpu cycles
+	1, // instructions
+	2, // cache references
+	3, // cache misses
+	4, // branch instructions
+	5, // branch misses
+}
+
+// perfSet is one open file descriptor per event, -1 where unsupported.
+type perfSet struct{ fds []int }
+
+// openPerf opens the counters for the calling thread, which must stay locked
+// to it while they are in use. It fails only if no event could be opened,
+// e.g. under a restrictive kernel.perf_event_paranoid or in a container
+// without perf access.
+func openPerf() (*perfSet, error) {
+	p := &perfSet{}
+	var firstErr error
+	opened := 0
+	for _, cfg := range perfEvents {
+		attr := perfAttr{
+			Type:       perfTypeHardware,
+			Size:       uint32(unsafe.Sizeof(perfAttr{})),
+			Config:     cfg,
+			ReadFormat: perfFormatTimes,
+			Flags:      perfDisabled | perfExcludeKernel | perfExcludeHV,
+		}
+		fd, _, errno := syscall.Syscall6(syscall.SYS_PERF_EVENT_OPEN, uintptr(unsafe.Pointer(&attr)), 0, ^uintptr(0), ^uintptr(0), 0, 0)
+		if errno != 0 {
+			if firstErr == nil {
+				firstErr = errno
+			}
+			p.fds = append(p.fds, -1)
+			continue
+		}
+		p.fds = append(p.fds, int(fd))
+		opened++
+	}
+	if opened == 0 {
+		return nil, fmt.Errorf("bench: perf counters unavailable: perf_event_open: %w", firstErr)
+	}
+	return p, nil
+}
+
+func (p *perfSet) ioctl(req uintptr) {
+	for _, fd := range p.fds {
+		if fd >= 0 {
+			syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), req, 0)
+		}
+	}
+}
+
+// start zeroes and enables the counters.
+func (p *perfSet) start() {
+	p.ioctl(perfIocReset)
+	p.ioctl(perfIocEnable)
+}
+
+// stop disables the counters and adds their scaled values to c.
+func (p *perfSet) stop(c *PerfCounters) {
+	p.ioctl(perfIocDisable)
+	var buf [24]byte
+	for i, dst := range c.fields() {
+		fd := p.fds[i]
+		if fd < 0 {
+			continue
+		}
+		if n, err := syscall.Read(fd, buf[:]); err != nil || n != len(buf) {
+			continue
+		}
+		v := binary.NativeEndian.Uint64(buf[0:])
+		enabled := binary.NativeEndian.Uint64(buf[8:])
+		running := binary.NativeEndian.Uint64(buf[16:])
+		if running > 0 && running < enabled {
+			v = uint64(float64(v) * float64(enabled) / float64(running))
+		}
+		*dst += v
+	}
+}
+
+func (p *perfSet) close() {
+	for _, fd := range p.fds {
+		if fd >= 0 {
+			syscall.Close(fd)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package bench
+
+import "fmt"
+
+type perfSet struct{}
+
+func openPerf() (*perfSet, error) {
+	return nil, fmt.Errorf("bench: perf counters need Linux perf_event")
+}
+
+func (p *perfSet) start()               {}
+func (p *perfSet) stop(c *PerfCounters) {}
+func (p *perfSet) close()               {}
@@ -0,0 +1,37 @@
+package bench
+
+import (
+	"testing"
+
+	sssp "github.com/your-org/optimized-sssp-go"
+)
+
+func TestPerfRatios(t *testing.T) {
+	p := PerfCounters{Cycles: 200, Instructions: 300, CacheReferences: 50, CacheMisses: 5}
+	if p.IPC() != 1.5 || p.CacheMissRate() != 0.1 {
+		t.Fatalf("IPC %v, miss rate %v", p.IPC(), p.CacheMissRate())
+	}
+	if z := (PerfCounters{}); z.IPC() != 0 || z.CacheMissRate() != 0 {
+		t.Fatalf("ratios of zero counters")
+	}
+}
+
+func TestRunnerRecordsPerf(t *testing.T) {
+	ps, err := openPerf()
+	if err != nil {
+		t.Skip(err)
+	}
+	ps.close()
+	g, err := sssp.GenerateRandomGraph(2000, 4, 3, sssp.RandomGraphOptions{})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	sums, err := Runner{Reps: 3, Perf: true}.Run(g, Mode("baseline", sssp.ModeBaseline))
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	p := sums[0].Perf
+	if p == nil || (p.Instructions == 0 && p.Cycles == 0) {
+		t.Fatalf("counters not recorded: %+v", p)
+	}
+}
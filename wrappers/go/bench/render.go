@@ -0,0 +1,186 @@
+package bench
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// RenderFormat selects the output of RenderComparison.
+type RenderFormat int
+
+const (
+	Markdown RenderFormat = iota // GitHub-flavoured tables, text bar charts
+	HTML                         // one self-contained page, no scripts or external assets
+)
+
+// ParseRenderFormat accepts "markdown" (or "md") and "html".
+func ParseRenderFormat(s string) (RenderFormat, error) {
+	switch strings.ToLower(s) {
+	case "markdown", "md":
+		return Markdown, nil
+	case "html", "htm":
+		return HTML, nil
+	}
+	return 0, fmt.Errorf("bench: unknown report format %q", s)
+}
+
+// comparison is the data both renderings show.
+type comparison struct {
+	Title   string
+	Rep     *Report
+	Base    string // algorithm speedups are relative to
+	Rows    []comparisonRow
+	MaxP50  float64
+	MaxOps  float64
+	Created string
+}
+
+type comparisonRow struct {
+	Summary
+	Speedup float64 // baseline mean / this mean
+	P50ms   float64
+	Edges   float64 // edge relaxations per run
+	HeapOps uint64
+	TimePct float64 // bar lengths, % of the largest
+	OpsPct  float64
+}
+
+// RenderComparison writes rep as a report to paste into documents: a
+// summary of the graph, settings and machine, a table of run times,
+// operation counters and speedups, and bar charts of median time and edge
+// relaxations. Speedups are over the algorithm named "baseline", or the
+// first one if there is none.
+func RenderComparison(w io.Writer, rep *Report, format RenderFormat) error {
+	if len(rep.Results) == 0 {
+		return fmt.Errorf("bench: report has no results")
+	}
+	c := comparison{Title: "Shortest-path comparison: " + rep.Graph.Name, Rep: rep, Created: rep.Time.Format(time.RFC3339)}
+	base := rep.Results[0]
+	for _, s := range rep.Results {
+		if s.Name == "baseline" {
+			base = s
+		}
+	}
+	c.Base = base.Name
+	for _, s := range rep.Results {
+		r := comparisonRow{Summary: s, P50ms: ms(s.P50), Edges: float64(s.Ops.EdgeRelaxations), HeapOps: s.Ops.HeapPushes + s.Ops.HeapPops}
+		if r.Edges == 0 {
+			r.Edges = s.Relaxations // engines without operation counters
+		}
+		if s.Mean > 0 {
+			r.Speedup = float64(base.Mean) / float64(s.Mean)
+		}
+		c.MaxP50 = math.Max(c.MaxP50, r.P50ms)
+		c.MaxOps = math.Max(c.MaxOps, r.Edges)
+		c.Rows = append(c.Rows, r)
+	}
+	for i := range c.Rows {
+		r := &c.Rows[i]
+		if c.MaxP50 > 0 {
+			r.TimePct = 100 * r.P50ms / c.MaxP50
+		}
+		if c.MaxOps > 0 {
+			r.OpsPct = 100 * r.Edges / c.MaxOps
+		}
+	}
+	if format == HTML {
+		return htmlReport.Execute(w, c)
+	}
+	if format != Markdown {
+		return fmt.Errorf("bench: unknown report format %d", format)
+	}
+	return c.markdown(w)
+}
+
+// barWidth is the length of a full Markdown bar, in characters.
+const barWidth = 40
+
+func (c comparison) markdown(w io.Writer) error {
+	var b strings.Builder
+	rep := c.Rep
+	fmt.Fprintf(&b, "## %s\n\n", mdEscape(c.Title))
+	fmt.Fprintf(&b, "%d nodes, %d edges (average degree %.2f). %d timed runs per algorithm after %d warmup, seed %d.\n\n",
+		rep.Graph.Nodes, rep.Graph.Edges, rep.Graph.AvgDegree, rep.Settings.Reps, rep.Settings.Warmup, rep.Settings.Seed)
+	fmt.Fprintf(&b, "Machine: %s, %d CPUs, %s %s/%s. Taken %s.\n\n",
+		mdEscape(orUnknown(rep.Env.CPUModel)), rep.Env.CPUs, rep.Env.GoVersion, rep.Env.GOOS, rep.Env.GOARCH, c.Created)
+	b.WriteString("| algorithm | runs | mean ms | stddev ms | p50 ms | p95 ms | p99 ms | speedup | edge relaxations | improvements | heap ops | bucket scans |\n")
+	b.WriteString("|---|--:|--:|--:|--:|--:|--:|--:|--:|--:|--:|--:|\n")
+	for _, r := range c.Rows {
+		fmt.Fprintf(&b, "| %s | %d | %.3f | %.3f | %.3f | %.3f | %.3f | %.2f× | %.0f | %d | %d | %d |\n",
+			mdEscape(r.Name), r.Runs, ms(r.Mean), ms(r.StdDev), r.P50ms, ms(r.P95), ms(r.P99), r.Speedup,
+			r.Edges, r.Ops.Improvements, r.HeapOps, r.Ops.BucketScans)
+	}
+	fmt.Fprintf(&b, "\nSpeedup is %s's mean time over each algorithm's.\n", mdEscape(c.Base))
+	width := 0
+	for _, r := range c.Rows {
+		width = max(width, len(r.Name))
+	}
+	chart := func(title string, pct func(comparisonRow) float64, label func(comparisonRow) string) {
+		fmt.Fprintf(&b, "\n%s\n\n```\n", title)
+		for _, r := range c.Rows {
+			n := int(math.Round(pct(r) / 100 * barWidth))
+			fmt.Fprintf(&b, "%-*s %s %s\n", width, r.Name, strings.Repeat("█", n)+strings.Repeat(" ", barWidth-n), label(r))
+		}
+		b.WriteString("```\n")
+	}
+	chart("Median run time:", func(r comparisonRow) float64 { return r.TimePct },
+		func(r comparisonRow) string { return fmt.Sprintf("%.3f ms", r.P50ms) })
+	chart("Edge relaxations per run:", func(r comparisonRow) float64 { return r.OpsPct },
+		func(r comparisonRow) string { return fmt.Sprintf("%.0f", r.Edges) })
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func mdEscape(s string) string { return strings.ReplaceAll(s, "|", `\|`) }
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown CPU"
+	}
+	return s
+}
+
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"ms":        ms,
+	"f":         func(prec int, x float64) string { return fmt.Sprintf("%.*f", prec, x) },
+	"orUnknown": orUnknown,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { padding: 0.3em 0.7em; border-bottom: 1px solid #ddd; }
+td.n, th.n { text-align: right; font-variant-numeric: tabular-nums; }
+.chart td { border: none; }
+.bar { height: 1em; background: #4a7fb5; }
+.bar.ops { background: #b5834a; }
+</style>
+</head>
+<body>
+<h2>{{.Title}}</h2>
+<p>{{.Rep.Graph.Nodes}} nodes, {{.Rep.Graph.Edges}} edges (average degree {{f 2 .Rep.Graph.AvgDegree}}).
+{{.Rep.Settings.Reps}} timed runs per algorithm after {{.Rep.Settings.Warmup}} warmup, seed {{.Rep.Settings.Seed}}.</p>
+<p>Machine: {{orUnknown .Rep.Env.CPUModel}}, {{.Rep.Env.CPUs}} CPUs, {{.Rep.Env.GoVersion}} {{.Rep.Env.GOOS}}/{{.Rep.Env.GOARCH}}. Taken {{.Created}}.</p>
+<table>
+<tr><th>algorithm</th><th class="n">runs</th><th class="n">mean ms</th><th class="n">stddev ms</th><th class="n">p50 ms</th><th class="n">p95 ms</th><th class="n">p99 ms</th><th class="n">speedup</th><th class="n">edge relaxations</th><th class="n">improvements</th><th class="n">heap ops</th><th class="n">bucket scans</th></tr>
+{{range .Rows}}<tr><td>{{.Name}}</td><td class="n">{{.Runs}}</td><td class="n">{{f 3 (ms .Mean)}}</td><td class="n">{{f 3 (ms .StdDev)}}</td><td class="n">{{f 3 .P50ms}}</td><td class="n">{{f 3 (ms .P95)}}</td><td class="n">{{f 3 (ms .P99)}}</td><td class="n">{{f 2 .Speedup}}×</td><td class="n">{{f 0 .Edges}}</td><td class="n">{{.Ops.Improvements}}</td><td class="n">{{.HeapOps}}</td><td class="n">{{.Ops.BucketScans}}</td></tr>
+{{end}}</table>
+<p>Speedup is {{.Base}}'s mean time over each algorithm's.</p>
+<h3>Median run time</h3>
+<table class="chart">
+{{range .Rows}}<tr><td>{{.Name}}</td><td style="width: 30em"><div class="bar" style="width: {{f 1 .TimePct}}%"></div></td><td class="n">{{f 3 .P50ms}} ms</td></tr>
+{{end}}</table>
+<h3>Edge relaxations per run</h3>
+<table class="chart">
+{{range .Rows}}<tr><td>{{.Name}}</td><td style="width: 30em"><div class="bar ops" style="width: {{f 1 .OpsPct}}%"></div></td><td class="n">{{f 0 .Edges}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
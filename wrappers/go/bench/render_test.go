@@ -0,0 +1,64 @@
+package bench
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	sssp "github.com/your-org/optimized-sssp-go"
+)
+
+func renderFixture() *Report {
+	return &Report{
+		Time:  time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Graph: GraphInfo{Name: "road|a", Nodes: 1000, Edges: 4000, AvgDegree: 4},
+		Results: []Summary{
+			{Name: "stoc", Runs: 5, Mean: 5 * time.Millisecond, P50: 5 * time.Millisecond, Ops: sssp.OpCounters{EdgeRelaxations: 6000}},
+			{Name: "baseline", Runs: 5, Mean: 10 * time.Millisecond, P50: 10 * time.Millisecond, Ops: sssp.OpCounters{EdgeRelaxations: 3000, HeapPushes: 2, HeapPops: 3}},
+		},
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	var b bytes.Buffer
+	if err := RenderComparison(&b, renderFixture(), Markdown); err != nil {
+		t.Fatal(err)
+	}
+	md := b.String()
+	for _, want := range []string{
+		"## Shortest-path comparison: road\\|a",
+		"| stoc | 5 | 5.000 |", "| 2.00× | 6000 | 0 | 0 | 0 |", "| 1.00× | 3000 | 0 | 5 | 0 |",
+		"stoc     " + strings.Repeat("█", 20) + strings.Repeat(" ", 20) + " 5.000 ms",
+		"baseline " + strings.Repeat("█", 40) + " 10.000 ms",
+		"stoc     " + strings.Repeat("█", 40) + " 6000",
+	} {
+		if !strings.Contains(md, want) {
+			t.Fatalf("missing %q in\n%s", want, md)
+		}
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	rep := renderFixture()
+	rep.Results[0].Name = "<stoc>"
+	var b bytes.Buffer
+	if err := RenderComparison(&b, rep, HTML); err != nil {
+		t.Fatal(err)
+	}
+	page := b.String()
+	for _, want := range []string{"<!DOCTYPE html>", "&lt;stoc&gt;", `style="width: 50.0%"`, `style="width: 100.0%"`, "2.00×"} {
+		if !strings.Contains(page, want) {
+			t.Fatalf("missing %q in\n%s", want, page)
+		}
+	}
+	if strings.Contains(page, "<stoc>") || strings.Contains(page, "<script") || strings.Contains(page, "ZgotmplZ") {
+		t.Fatalf("unsafe or rejected output:\n%s", page)
+	}
+	if _, err := ParseRenderFormat("pdf"); err == nil {
+		t.Fatal("unknown format accepted")
+	}
+	if err := RenderComparison(&b, &Report{}, HTML); err == nil {
+		t.Fatal("empty report accepted")
+	}
+}
@@ -0,0 +1,191 @@
+package bench
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	sssp "github.com/your-org/optimized-sssp-go"
+)
+
+// Report is a self-describing record of one benchmark run, meant to be
+// stored and charted over time.
+type Report struct {
+	Time     time.Time   `json:"time"`
+	Graph    GraphInfo   `json:"graph"`
+	Settings Settings    `json:"settings"`
+	Env      Environment `json:"env"`
+	Results  []Summary   `json:"results"`
+}
+
+// GraphInfo identifies the benchmarked graph.
+type GraphInfo struct {
+	Name      string  `json:"name"` // file name or generator spec
+	Nodes     uint32  `json:"nodes"`
+	Edges     int     `json:"edges"`
+	AvgDegree float64 `json:"avg_degree"`
+}
+
+// Settings are the Runner parameters the results were taken with.
+type Settings struct {
+	Warmup  int    `json:"warmup"`
+	Reps    int    `json:"reps"`
+	Seed    int64  `json:"seed"`
+	Sources int    `json:"sources"` // distinct sources; 0 when drawn by the Runner
+	Memory  bool   `json:"memory,omitempty"`
+	Perf    bool   `json:"perf,omitempty"`
+	Order   string `json:"order"`
+	GC      bool   `json:"gc,omitempty"`
+}
+
+// Environment describes the machine and build.
+type Environment struct {
+	GoVersion    string `json:"go_version"`
+	GOOS         string `json:"goos"`
+	GOARCH       string `json:"goarch"`
+	CPUs         int    `json:"cpus"`
+	GOMAXPROCS   int    `json:"gomaxprocs"`
+	CPUModel     string `json:"cpu_model,omitempty"`
+	Hostname     string `json:"hostname,omitempty"`
+	Revision     string `json:"revision,omitempty"` // VCS revision of the binary, if stamped
+	Capabilities uint64 `json:"capabilities"`       // sssp.Capabilities of the loaded engine
+}
+
+// NewReport wraps the summaries of r.Run on g, stamping the current time and
+// environment. name identifies the graph in the report.
+func NewReport(name string, g *sssp.Graph, r Runner, sums []Summary) *Report {
+	warmup, reps := r.settings()
+	info := GraphInfo{Name: name, Nodes: g.NodeCount(), Edges: g.EdgeCount()}
+	if info.Nodes > 0 {
+		info.AvgDegree = float64(info.Edges) / float64(info.Nodes)
+	}
+	return &Report{
+		Time:  time.Now().UTC(),
+		Graph: info,
+		Settings: Settings{Warmup: warmup, Reps: reps, Seed: r.Seed, Sources: len(r.Sources), Memory: r.Memory, Perf: r.Perf,
+			Order: r.Order.String(), GC: r.GC},
+		Env:     CurrentEnvironment(),
+		Results: sums,
+	}
+}
+
+// CurrentEnvironment describes the running process.
+func CurrentEnvironment() Environment {
+	env := Environment{
+		GoVersion:    runtime.Version(),
+		GOOS:         runtime.GOOS,
+		GOARCH:       runtime.GOARCH,
+		CPUs:         runtime.NumCPU(),
+		GOMAXPROCS:   runtime.GOMAXPROCS(0),
+		CPUModel:     cpuModel(),
+		Capabilities: uint64(sssp.Capabilities()),
+	}
+	env.Hostname, _ = os.Hostname()
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			if s.Key == "vcs.revision" {
+				env.Revision = s.Value
+			}
+		}
+	}
+	return env
+}
+
+// cpuModel reads the processor name on Linux; elsewhere it is empty.
+func cpuModel() string {
+	f, err := os.Open("/proc/cpuinfo")
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if key, val, ok := strings.Cut(sc.Text(), ":"); ok && strings.TrimSpace(key) == "model name" {
+			return strings.TrimSpace(val)
+		}
+	}
+	return ""
+}
+
+// WriteJSON writes the report as indented JSON.
+func (rep *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rep)
+}
+
+// ReadReport decodes a report written by WriteJSON.
+func ReadReport(r io.Reader) (*Report, error) {
+	var rep Report
+	if err := json.NewDecoder(r).Decode(&rep); err != nil {
+		return nil, fmt.Errorf("bench: report: %w", err)
+	}
+	return &rep, nil
+}
+
+// csvHeader lists the WriteCSV columns. Times are in milliseconds; the
+// memory and hardware counter columns are empty unless the Runner recorded
+// them.
+var csvHeader = []string{
+	"time", "graph", "nodes", "edges", "avg_degree", "algorithm", "runs",
+	"mean_ms", "stddev_ms", "min_ms", "max_ms", "p50_ms", "p95_ms", "p99_ms",
+	"relaxations", "settled",
+	"edge_relaxations", "improvements", "heap_pushes", "heap_pops", "frontier_expansions", "bucket_scans",
+	"allocs", "alloc_bytes", "peak_heap_bytes", "peak_rss_bytes", "cgroup_peak_bytes",
+	"cycles", "instructions", "cache_references", "cache_misses", "branches", "branch_misses",
+	"warmup", "seed",
+	"go_version", "goos", "goarch", "cpus", "gomaxprocs", "cpu_model", "revision",
+}
+
+// WriteCSV writes one row per algorithm, repeating the graph and
+// environment columns on each so rows from many reports can be concatenated
+// into one table. header controls whether the column names come first; leave
+// it off when appending to an existing file.
+func (rep *Report) WriteCSV(w io.Writer, header bool) error {
+	cw := csv.NewWriter(w)
+	if header {
+		cw.Write(csvHeader)
+	}
+	f := func(x float64, prec int) string { return strconv.FormatFloat(x, 'f', prec, 64) }
+	msf := func(d time.Duration) string { return f(ms(d), 3) }
+	u := func(x uint64) string { return strconv.FormatUint(x, 10) }
+	for _, s := range rep.Results {
+		mem := make([]string, 5)
+		if m := s.Mem; m != nil {
+			mem = []string{u(m.Allocs), u(m.AllocBytes), u(m.PeakHeap), u(m.PeakRSS), u(m.CgroupPeak)}
+		}
+		row := []string{
+			rep.Time.Format(time.RFC3339), rep.Graph.Name,
+			strconv.FormatUint(uint64(rep.Graph.Nodes), 10), strconv.Itoa(rep.Graph.Edges), f(rep.Graph.AvgDegree, 3),
+			s.Name, strconv.Itoa(s.Runs),
+			msf(s.Mean), msf(s.StdDev), msf(s.Min), msf(s.Max), msf(s.P50), msf(s.P95), msf(s.P99),
+			f(s.Relaxations, 0), f(s.Settled, 0),
+			u(s.Ops.EdgeRelaxations), u(s.Ops.Improvements), u(s.Ops.HeapPushes), u(s.Ops.HeapPops),
+			u(s.Ops.FrontierExpansions), u(s.Ops.BucketScans),
+		}
+		perf := make([]string, 6)
+		if p := s.Perf; p != nil {
+			for i, c := range p.fields() {
+				perf[i] = u(*c)
+			}
+		}
+		row = append(row, mem...)
+		row = append(row, perf...)
+		row = append(row,
+			strconv.Itoa(rep.Settings.Warmup), strconv.FormatInt(rep.Settings.Seed, 10),
+			rep.Env.GoVersion, rep.Env.GOOS, rep.Env.GOARCH,
+			strconv.Itoa(rep.Env.CPUs), strconv.Itoa(rep.Env.GOMAXPROCS), rep.Env.CPUModel, rep.Env.Revision,
+		)
+		cw.Write(row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
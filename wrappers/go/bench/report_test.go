@@ -0,0 +1,59 @@
+package bench
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	sssp "github.com/your-org/optimized-sssp-go"
+)
+
+func TestReportRoundTrip(t *testing.T) {
+	g, err := sssp.GenerateRandomGraph(300, 4, 1, sssp.RandomGraphOptions{})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	r := Runner{Reps: 4, Seed: 2}
+	sums, err := r.Run(g, Modes()[:2]...)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	rep := NewReport("random:300:4", g, r, sums)
+	if rep.Graph.Nodes != 300 || rep.Graph.AvgDegree != float64(g.EdgeCount())/300 || rep.Settings.Warmup != 2 || rep.Env.GoVersion == "" {
+		t.Fatalf("report %+v", rep)
+	}
+
+	var buf bytes.Buffer
+	if err := rep.WriteJSON(&buf); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"p99_ns"`)) {
+		t.Fatalf("json lacks percentiles:\n%s", buf.String())
+	}
+	back, err := ReadReport(&buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !back.Time.Equal(rep.Time) || back.Results[1].Name != "stoc" || back.Results[0].P50 != sums[0].P50 || len(back.Results[0].Samples) != 4 {
+		t.Fatalf("round trip %+v", back)
+	}
+
+	buf.Reset()
+	if err := rep.WriteCSV(&buf, true); err != nil {
+		t.Fatalf("csv: %v", err)
+	}
+	if err := rep.WriteCSV(&buf, false); err != nil {
+		t.Fatalf("csv: %v", err)
+	}
+	recs, err := csv.NewReader(&buf).ReadAll()
+	if err != nil || len(recs) != 5 || len(recs[0]) != len(csvHeader) || recs[2][5] != "stoc" || recs[3][1] != "random:300:4" {
+		t.Fatalf("csv %v: %v", recs, err)
+	}
+	if _, err := time.Parse(time.RFC3339, recs[1][0]); err != nil {
+		t.Fatalf("time column: %v", err)
+	}
+	if _, err := ReadReport(bytes.NewReader([]byte("{"))); err == nil {
+		t.Fatalf("expected a decode error")
+	}
+}
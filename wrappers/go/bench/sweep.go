@@ -0,0 +1,167 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	sssp "github.com/your-org/optimized-sssp-go"
+)
+
+// Sweep benchmarks algorithms over a grid of graph sizes and degrees and fits
+// how their run times grow, which is how a claimed complexity is checked in
+// practice: an algorithm that really beats Dijkstra asymptotically shows a
+// smaller exponent as the graphs grow.
+type Sweep struct {
+	Nodes   []uint32  // e.g. 10_000 to 10_000_000
+	Degrees []float64 // average out-degrees, e.g. 2, 4, 8, 16
+	// Runner times each configuration. Its Sources must be empty: every
+	// graph draws its own.
+	Runner Runner
+	// Generate builds one configuration's graph; nil means
+	// GenerateRandomGraph with Reachable set, so runs reach every node.
+	Generate func(n uint32, degree float64, seed int64) (*sssp.Graph, error)
+	// Progress, if set, sees each configuration's report as it completes.
+	Progress func(rep *Report)
+}
+
+// Fit is a least-squares fit of log(p50 time) against log(edges) over the
+// sweep: time ≈ Coef · edges^Exponent. Exponents near 1 mean near-linear
+// growth; log factors show up as a small excess over 1 that shrinks as the
+// graphs grow. R2 is the coefficient of determination of the fit.
+type Fit struct {
+	Name     string  `json:"name"`
+	Exponent float64 `json:"exponent"`
+	Coef     float64 `json:"coef_ns"`
+	R2       float64 `json:"r2"`
+	Points   int     `json:"points"`
+}
+
+// SweepResult holds one report per configuration and the fits across them.
+type SweepResult struct {
+	Reports []*Report `json:"reports"`
+	Fits    []Fit     `json:"fits"`
+}
+
+// Run benchmarks algs on every (node count, degree) pair, smallest first,
+// and fits their scaling. Each graph is released before the next is built.
+func (s Sweep) Run(algs ...Algorithm) (*SweepResult, error) {
+	if len(s.Nodes) == 0 || len(s.Degrees) == 0 {
+		return nil, fmt.Errorf("bench: sweep needs node counts and degrees")
+	}
+	if len(s.Runner.Sources) > 0 {
+		return nil, fmt.Errorf("bench: sweep graphs draw their own sources")
+	}
+	gen := s.Generate
+	if gen == nil {
+		gen = func(n uint32, degree float64, seed int64) (*sssp.Graph, error) {
+			return sssp.GenerateRandomGraph(n, degree, seed, sssp.RandomGraphOptions{Reachable: true})
+		}
+	}
+	out := &SweepResult{}
+	for _, n := range s.Nodes {
+		for _, d := range s.Degrees {
+			g, err := gen(n, d, s.Runner.Seed)
+			if err != nil {
+				return nil, err
+			}
+			sums, err := s.Runner.Run(g, algs...)
+			if err != nil {
+				return nil, fmt.Errorf("bench: sweep n=%d degree=%v: %w", n, d, err)
+			}
+			rep := NewReport(fmt.Sprintf("sweep:%d:%s", n, strconv.FormatFloat(d, 'g', -1, 64)), g, s.Runner, sums)
+			out.Reports = append(out.Reports, rep)
+			if s.Progress != nil {
+				s.Progress(rep)
+			}
+		}
+	}
+	out.Fits = FitScaling(out.Reports)
+	return out, nil
+}
+
+// FitScaling fits each algorithm's p50 time against edge count across
+// reports. Algorithms are listed in order of first appearance; those with
+// fewer than two usable points get no fit.
+func FitScaling(reports []*Report) []Fit {
+	type pts struct{ x, y []float64 }
+	var order []string
+	byName := make(map[string]*pts)
+	for _, rep := range reports {
+		for _, s := range rep.Results {
+			if rep.Graph.Edges <= 0 || s.P50 <= 0 {
+				continue
+			}
+			p := byName[s.Name]
+			if p == nil {
+				p = &pts{}
+				byName[s.Name] = p
+				order = append(order, s.Name)
+			}
+			p.x = append(p.x, math.Log(float64(rep.Graph.Edges)))
+			p.y = append(p.y, math.Log(float64(s.P50)))
+		}
+	}
+	var fits []Fit
+	for _, name := range order {
+		p := byName[name]
+		slope, icept, r2, ok := linearFit(p.x, p.y)
+		if !ok {
+			continue
+		}
+		fits = append(fits, Fit{Name: name, Exponent: slope, Coef: math.Exp(icept), R2: r2, Points: len(p.x)})
+	}
+	return fits
+}
+
+// linearFit returns the least-squares line y = slope·x + icept and its R².
+// It fails when x does not vary.
+func linearFit(x, y []float64) (slope, icept, r2 float64, ok bool) {
+	n := float64(len(x))
+	if len(x) < 2 {
+		return 0, 0, 0, false
+	}
+	var sx, sy float64
+	for i := range x {
+		sx += x[i]
+		sy += y[i]
+	}
+	mx, my := sx/n, sy/n
+	var sxx, sxy, syy float64
+	for i := range x {
+		dx, dy := x[i]-mx, y[i]-my
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+	if sxx == 0 {
+		return 0, 0, 0, false
+	}
+	slope = sxy / sxx
+	icept = my - slope*mx
+	r2 = 1.0
+	if syy > 0 {
+		r2 = sxy * sxy / (sxx * syy)
+	}
+	return slope, icept, r2, true
+}
+
+// WriteJSON writes the sweep as indented JSON.
+func (r *SweepResult) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteCSV writes every configuration's rows as one table (see
+// Report.WriteCSV), with the header first when header is set.
+func (r *SweepResult) WriteCSV(w io.Writer, header bool) error {
+	for i, rep := range r.Reports {
+		if err := rep.WriteCSV(w, header && i == 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package bench
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"testing"
+	"time"
+
+	sssp "github.com/your-org/optimized-sssp-go"
+)
+
+func TestFitScalingRecoversExponent(t *testing.T) {
+	var reps []*Report
+	for _, m := range []int{1000, 4000, 16000, 64000} {
+		reps = append(reps, &Report{
+			Graph: GraphInfo{Edges: m},
+			Results: []Summary{
+				{Name: "linear", P50: time.Duration(3 * m)},
+				{Name: "superlinear", P50: time.Duration(math.Pow(float64(m), 1.5))},
+			},
+		})
+	}
+	fits := FitScaling(reps)
+	if len(fits) != 2 || fits[0].Name != "linear" || fits[1].Name != "superlinear" {
+		t.Fatalf("fits %+v", fits)
+	}
+	for i, want := range []float64{1, 1.5} {
+		f := fits[i]
+		if math.Abs(f.Exponent-want) > 1e-3 || f.R2 < 0.9999 || f.Points != 4 {
+			t.Fatalf("%s: %+v, want exponent %v", f.Name, f, want)
+		}
+	}
+	if math.Abs(fits[0].Coef-3) > 0.01 {
+		t.Fatalf("linear coefficient %v, want 3", fits[0].Coef)
+	}
+	if got := FitScaling(reps[:1]); len(got) != 0 {
+		t.Fatalf("one point should not fit: %+v", got)
+	}
+}
+
+func TestSweepRunsEveryConfiguration(t *testing.T) {
+	var built [][2]float64
+	var seen int
+	s := Sweep{
+		Nodes:   []uint32{100, 400},
+		Degrees: []float64{2, 4},
+		Runner:  Runner{Reps: 2, Warmup: -1},
+		Generate: func(n uint32, d float64, seed int64) (*sssp.Graph, error) {
+			built = append(built, [2]float64{float64(n), d})
+			return sssp.GenerateRandomGraph(n, d, seed, sssp.RandomGraphOptions{Reachable: true})
+		},
+		Progress: func(*Report) { seen++ },
+	}
+	res, err := s.Run(Modes()[:2]...)
+	if err != nil {
+		t.Fatalf("sweep: %v", err)
+	}
+	if len(res.Reports) != 4 || seen != 4 || built[1] != [2]float64{100, 4} || built[3] != [2]float64{400, 4} {
+		t.Fatalf("built %v, %d reports, %d progress calls", built, len(res.Reports), seen)
+	}
+	if res.Reports[2].Graph.Name != "sweep:400:2" || len(res.Fits) != 2 {
+		t.Fatalf("reports/fits: %q %+v", res.Reports[2].Graph.Name, res.Fits)
+	}
+	var buf bytes.Buffer
+	if err := res.WriteCSV(&buf, true); err != nil {
+		t.Fatalf("csv: %v", err)
+	}
+	recs, err := csv.NewReader(&buf).ReadAll()
+	if err != nil || len(recs) != 1+4*2 {
+		t.Fatalf("csv has %d records: %v", len(recs), err)
+	}
+	if _, err := (Sweep{Nodes: []uint32{10}}).Run(Modes()[0]); err == nil {
+		t.Fatalf("expected an error without degrees")
+	}
+	if _, err := (Sweep{Nodes: []uint32{10}, Degrees: []float64{2}, Runner: Runner{Sources: []uint32{0}}}).Run(Modes()[0]); err == nil {
+		t.Fatalf("expected an error for fixed sources")
+	}
+}
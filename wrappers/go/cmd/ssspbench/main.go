@@ -0,0 +1,572 @@
+// Command ssspbench compares the shortest-path modes on one graph.
+//
+// Usage:
+//
+//	ssspbench -graph road.gr.gz -modes baseline,stoc,autotune -sources 20
+//	ssspbench -gen rmat:18 -out history.csv -json run.json
+//
+// The graph is read with sssp.LoadGraphFile (any supported format, optionally
+// compressed) or generated from a spec:
+//
+//	random:N:DEG     GenerateRandomGraph with N nodes and average degree DEG
+//	rmat:SCALE[:EF]  GenerateRMAT with 2^SCALE nodes and edge factor EF
+//	grid:WxH[xD]     GenerateGrid with random weights
+//	road:N           GenerateRoadNetwork with about N nodes
+//
+// Every mode runs from the same randomly chosen sources, after -warmup
+// untimed runs, using the bench package. The table reports the mean, spread
+// and percentiles of the wall-clock times, the sssp.OpCounters averaged over
+// the runs, and how far each mode's distances stray from the baseline's.
+// -out appends
+// bench.Report CSV rows to a file, so repeated runs build one history table;
+// -json writes the full report. A later run given it as -baseline prints the
+// change in each mode's median time and exits with status 1 if any slowed
+// down by more than -threshold.
+//
+// With -sweep-nodes the command instead times random graphs of every listed
+// size and -sweep-degrees degree and fits, per mode, the exponent k in
+// time ≈ c·m^k over the edge counts m:
+//
+//	ssspbench -sweep-nodes 10k,100k,1M,10M -sweep-degrees 2,4,8,16 -json sweep.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	sssp "github.com/your-org/optimized-sssp-go"
+	"github.com/your-org/optimized-sssp-go/bench"
+)
+
+var modeNames = map[string]int{
+	"baseline": sssp.ModeBaseline,
+	"stoc":     sssp.ModeStoc,
+	"autotune": sssp.ModeAutotune,
+	"gpu":      sssp.ModeGPU,
+}
+
+// config holds the command-line settings.
+type config struct {
+	graph, gen, modes string
+	sources           int
+	reps, warmup      int
+	seed              int64
+	out, json, report string
+	baseline          string
+	threshold         float64
+	sweepNodes        string
+	sweepDegrees      string
+	mem, perf         bool
+	order             string
+	gc                bool
+	dups              string
+	relabel           string
+}
+
+// row is one line of the comparison table.
+type row struct {
+	bench.Summary
+	maxDrift float64 // largest |dist - reference dist| over nodes both reach, over all runs
+	// Nodes failing sssp.Verify, summed over runs.
+	drift, reach, badPred int
+}
+
+func main() {
+	var c config
+	flag.StringVar(&c.graph, "graph", "", "graph file in any format LoadGraphFile detects")
+	flag.StringVar(&c.gen, "gen", "", "generate the graph instead: random:N:DEG, rmat:SCALE[:EF], grid:WxH[xD], road:N")
+	flag.StringVar(&c.modes, "modes", "baseline,stoc,autotune", "comma-separated modes: baseline, stoc, autotune, gpu; settings follow a colon, e.g. stoc:delta=2, autotune:limit=1024:cands=1/4")
+	flag.IntVar(&c.sources, "sources", 10, "number of random sources")
+	flag.IntVar(&c.reps, "reps", 0, "timed runs per mode, cycling through the sources; 0 means one per source")
+	flag.IntVar(&c.warmup, "warmup", 2, "untimed runs per mode before timing")
+	flag.Int64Var(&c.seed, "seed", 1, "seed for source selection and generators")
+	flag.StringVar(&c.out, "out", "", "append the results as CSV rows to this file, for tracking over time")
+	flag.StringVar(&c.json, "json", "", "write the results as a JSON report to this file, e.g. to keep as a baseline")
+	flag.StringVar(&c.report, "report", "", "write the comparison as a Markdown (.md) or HTML (.html) report to this file")
+	flag.StringVar(&c.baseline, "baseline", "", "compare against a JSON report from -json and fail on regressions")
+	flag.Float64Var(&c.threshold, "threshold", 0.10, "with -baseline, the p50 slowdown (fraction) counted as a regression")
+	flag.StringVar(&c.sweepNodes, "sweep-nodes", "", "sweep random graphs of these node counts (e.g. 10k,100k,1M) instead of one graph")
+	flag.StringVar(&c.sweepDegrees, "sweep-degrees", "2,4,8,16", "average degrees for -sweep-nodes")
+	flag.BoolVar(&c.mem, "mem", false, "also record allocations and peak heap, RSS and cgroup memory per mode")
+	flag.BoolVar(&c.perf, "perf", false, "also record hardware counters (cycles, instructions, cache and branch misses) per mode; Linux only")
+	flag.StringVar(&c.order, "order", "interleaved", "timed run order: interleaved, shuffled (each round), or blocked (one mode at a time)")
+	flag.BoolVar(&c.gc, "gc", false, "collect garbage before every timed run")
+	flag.StringVar(&c.dups, "dups", "multi", "parallel edges of the graph: multi (keep all), min, last or sum")
+	flag.StringVar(&c.relabel, "relabel", "", "renumber the graph's nodes for locality before timing: bfs, rcm, degree or gorder")
+	flag.Parse()
+
+	if err := run(c, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "ssspbench:", err)
+		os.Exit(1)
+	}
+}
+
+func run(c config, stdout io.Writer) error {
+	if c.sweepNodes != "" {
+		return sweep(c, stdout)
+	}
+	var g *sssp.Graph
+	var err error
+	switch {
+	case c.graph != "" && c.gen != "":
+		return fmt.Errorf("use either -graph or -gen")
+	case c.graph != "":
+		g, err = sssp.LoadGraphFile(c.graph)
+	case c.gen != "":
+		g, err = generate(c.gen, c.seed)
+	default:
+		return fmt.Errorf("no graph: pass -graph or -gen")
+	}
+	if err != nil {
+		return err
+	}
+	if c.dups != "" {
+		policy, err := sssp.ParseDuplicatePolicy(c.dups)
+		if err != nil {
+			return err
+		}
+		if _, err := g.MergeDuplicates(policy); err != nil {
+			return err
+		}
+	}
+	if c.relabel != "" {
+		st, err := sssp.ParseReorderStrategy(c.relabel)
+		if err != nil {
+			return err
+		}
+		if g, _, err = g.Reorder(st); err != nil {
+			return err
+		}
+	}
+	algs, err := parseModes(c.modes)
+	if err != nil {
+		return err
+	}
+	srcs := bench.RandomSources(g, c.sources, c.seed)
+	if len(srcs) == 0 {
+		return fmt.Errorf("no sources to run from")
+	}
+	reps := c.reps
+	if reps <= 0 {
+		reps = len(srcs)
+	}
+	warmup := c.warmup
+	if warmup == 0 {
+		warmup = -1 // bench.Runner treats 0 as its default
+	}
+	comps := g.Components()
+	_, largest := comps.Largest()
+	fmt.Fprintf(stdout, "graph: %d nodes, %d edges, %d strongly connected components (largest %d nodes); %d sources, %d runs per mode\n\n",
+		g.NodeCount(), g.EdgeCount(), comps.Count(), largest, len(srcs), reps)
+
+	order, err := parseOrder(c.order)
+	if err != nil {
+		return err
+	}
+	runner := bench.Runner{Warmup: warmup, Reps: reps, Seed: c.seed, Sources: srcs, Memory: c.mem, Perf: c.perf, Order: order, GC: c.gc}
+	rows, err := compare(g, algs, runner)
+	if err != nil {
+		return err
+	}
+	writeTable(stdout, rows)
+
+	name := c.gen
+	if c.graph != "" {
+		name = filepath.Base(c.graph)
+	}
+	sums := make([]bench.Summary, len(rows))
+	for i, r := range rows {
+		sums[i] = r.Summary
+	}
+	rep := bench.NewReport(name, g, runner, sums)
+	if c.json != "" {
+		if err := rep.Save(c.json); err != nil {
+			return err
+		}
+	}
+	if c.out != "" {
+		if err := appendCSV(c.out, rep.WriteCSV); err != nil {
+			return err
+		}
+	}
+	if c.report != "" {
+		if err := writeReport(c.report, rep); err != nil {
+			return err
+		}
+	}
+	if c.baseline == "" {
+		return nil
+	}
+	base, err := bench.LoadReport(c.baseline)
+	if err != nil {
+		return err
+	}
+	changes, err := bench.Compare(base, rep, c.threshold)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "\nagainst %s (%s):\n", c.baseline, base.Time.Format(time.RFC3339))
+	bench.WriteChanges(stdout, changes)
+	if n := bench.Regressions(changes); n > 0 {
+		return fmt.Errorf("%d mode(s) more than %.0f%% slower than the baseline", n, c.threshold*100)
+	}
+	return nil
+}
+
+// writeReport renders rep to path, as HTML if the name ends in .html or
+// .htm and as Markdown otherwise.
+func writeReport(path string, rep *bench.Report) error {
+	format := bench.Markdown
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".html" || ext == ".htm" {
+		format = bench.HTML
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := bench.RenderComparison(f, rep, format); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// appendCSV appends rows to path with write, asking for a header if the file
+// is new or empty.
+func appendCSV(path string, write func(w io.Writer, header bool) error) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	st, err := f.Stat()
+	if err == nil {
+		err = write(f, st.Size() == 0)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// sweep runs the modes over random graphs of every -sweep-nodes and
+// -sweep-degrees combination and fits how their times scale.
+func sweep(c config, stdout io.Writer) error {
+	if c.graph != "" || c.gen != "" {
+		return fmt.Errorf("-sweep-nodes generates its own graphs; drop -graph and -gen")
+	}
+	algs, err := parseModes(c.modes)
+	if err != nil {
+		return err
+	}
+	var nodes []uint32
+	for _, f := range strings.Split(c.sweepNodes, ",") {
+		n, err := parseCount(strings.TrimSpace(f))
+		if err != nil {
+			return err
+		}
+		nodes = append(nodes, n)
+	}
+	var degrees []float64
+	for _, f := range strings.Split(c.sweepDegrees, ",") {
+		d, err := strconv.ParseFloat(strings.TrimSpace(f), 64)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("bad degree %q in -sweep-degrees", f)
+		}
+		degrees = append(degrees, d)
+	}
+	reps := c.reps
+	if reps <= 0 {
+		reps = c.sources
+	}
+	order, err := parseOrder(c.order)
+	if err != nil {
+		return err
+	}
+	warmup := c.warmup
+	if warmup == 0 {
+		warmup = -1
+	}
+	fmt.Fprintf(stdout, "%10s %6s %12s  %-10s %10s %10s\n", "nodes", "degree", "edges", "mode", "p50 ms", "p95 ms")
+	s := bench.Sweep{
+		Nodes: nodes, Degrees: degrees,
+		Runner: bench.Runner{Warmup: warmup, Reps: reps, Seed: c.seed, Order: order, GC: c.gc},
+		Progress: func(rep *bench.Report) {
+			for _, r := range rep.Results {
+				fmt.Fprintf(stdout, "%10d %6.3g %12d  %-10s %10.3f %10.3f\n",
+					rep.Graph.Nodes, rep.Graph.AvgDegree, rep.Graph.Edges, r.Name, ms(r.P50), ms(r.P95))
+			}
+		},
+	}
+	res, err := s.Run(algs...)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "\nscaling of p50 time with edges (time ≈ c·m^k):\n")
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "mode\tk\tR²\tpoints\t")
+	for _, f := range res.Fits {
+		fmt.Fprintf(tw, "%s\t%.3f\t%.3f\t%d\t\n", f.Name, f.Exponent, f.R2, f.Points)
+	}
+	tw.Flush()
+	if c.json != "" {
+		f, err := os.Create(c.json)
+		if err != nil {
+			return err
+		}
+		if err := res.WriteJSON(f); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	if c.out != "" {
+		return appendCSV(c.out, res.WriteCSV)
+	}
+	return nil
+}
+
+// parseOrder parses -order; empty means interleaved.
+func parseOrder(s string) (bench.Order, error) {
+	if s == "" {
+		return bench.Interleaved, nil
+	}
+	return bench.ParseOrder(s)
+}
+
+// parseCount parses a node count with an optional k or M suffix.
+func parseCount(s string) (uint32, error) {
+	mult := 1.0
+	switch {
+	case strings.HasSuffix(s, "k"), strings.HasSuffix(s, "K"):
+		mult, s = 1e3, s[:len(s)-1]
+	case strings.HasSuffix(s, "M"):
+		mult, s = 1e6, s[:len(s)-1]
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v*mult < 1 || v*mult > math.MaxUint32 {
+		return 0, fmt.Errorf("bad node count %q", s)
+	}
+	return uint32(v * mult), nil
+}
+
+func parseModes(list string) ([]bench.Algorithm, error) {
+	var algs []bench.Algorithm
+	seen := make(map[string]bool)
+	for _, spec := range strings.Split(list, ",") {
+		spec = strings.TrimSpace(spec)
+		if seen[spec] {
+			return nil, fmt.Errorf("mode %q listed twice", spec)
+		}
+		seen[spec] = true
+		a, err := parseMode(spec)
+		if err != nil {
+			return nil, err
+		}
+		algs = append(algs, a)
+	}
+	return algs, nil
+}
+
+// parseMode parses one -modes entry, a mode name with optional settings:
+// stoc:delta=D runs delta-stepping with a fixed delta D, and autotune takes
+// delta=D (pin), limit=N (nodes per trial) and cands=M1/M2/... (delta
+// multipliers to try). The entry as written names the mode in the output,
+// so one mode can be listed with several settings.
+func parseMode(spec string) (bench.Algorithm, error) {
+	name, opts, hasOpts := strings.Cut(spec, ":")
+	mode, ok := modeNames[name]
+	if !ok {
+		return bench.Algorithm{}, fmt.Errorf("unknown mode %q", name)
+	}
+	if !hasOpts {
+		return bench.Mode(spec, mode), nil
+	}
+	var p sssp.AutotuneParams
+	for _, opt := range strings.Split(opts, ":") {
+		key, val, _ := strings.Cut(opt, "=")
+		var err error
+		switch {
+		case key == "delta" && (mode == sssp.ModeStoc || mode == sssp.ModeAutotune):
+			var d float64
+			d, err = strconv.ParseFloat(val, 32)
+			if err == nil && !(d > 0) {
+				err = fmt.Errorf("delta must be positive")
+			}
+			p.PinnedDelta = float32(d)
+		case key == "limit" && mode == sssp.ModeAutotune:
+			var n uint64
+			n, err = strconv.ParseUint(val, 10, 32)
+			p.TrialLimit = uint32(n)
+		case key == "cands" && mode == sssp.ModeAutotune:
+			for _, f := range strings.Split(val, "/") {
+				var m float64
+				if m, err = strconv.ParseFloat(f, 32); err != nil || !(m > 0) {
+					err = fmt.Errorf("bad multiplier %q", f)
+					break
+				}
+				p.Candidates = append(p.Candidates, float32(m))
+			}
+		default:
+			return bench.Algorithm{}, fmt.Errorf("mode %q: unknown setting %q", spec, key)
+		}
+		if err != nil {
+			return bench.Algorithm{}, fmt.Errorf("mode %q: bad %s: %v", spec, key, err)
+		}
+	}
+	if mode == sssp.ModeStoc && p.PinnedDelta == 0 {
+		return bench.Algorithm{}, fmt.Errorf("mode %q: stoc settings need delta", spec)
+	}
+	return bench.Autotune(spec, p), nil
+}
+
+// generate builds a graph from a -gen spec.
+func generate(spec string, seed int64) (*sssp.Graph, error) {
+	kind, args, _ := strings.Cut(spec, ":")
+	fields := strings.FieldsFunc(args, func(r rune) bool { return r == ':' || r == 'x' })
+	nums := make([]float64, len(fields))
+	for i, f := range fields {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil || v < 0 {
+			return nil, fmt.Errorf("bad number %q in -gen %q", f, spec)
+		}
+		nums[i] = v
+	}
+	want := func(lo, hi int) error {
+		if len(nums) < lo || len(nums) > hi {
+			return fmt.Errorf("-gen %s takes %d to %d numbers, got %q", kind, lo, hi, spec)
+		}
+		return nil
+	}
+	switch kind {
+	case "random":
+		if err := want(2, 2); err != nil {
+			return nil, err
+		}
+		return sssp.GenerateRandomGraph(uint32(nums[0]), nums[1], seed, sssp.RandomGraphOptions{})
+	case "rmat":
+		if err := want(1, 2); err != nil {
+			return nil, err
+		}
+		p := sssp.RMATParams{Scale: uint32(nums[0])}
+		if len(nums) == 2 {
+			p.EdgeFactor = uint32(nums[1])
+		}
+		return sssp.GenerateRMAT(p, seed)
+	case "grid":
+		if err := want(2, 3); err != nil {
+			return nil, err
+		}
+		dims := make([]uint32, len(nums))
+		for i, v := range nums {
+			dims[i] = uint32(v)
+		}
+		return sssp.GenerateGrid(sssp.GridParams{Dims: dims, RandomWeights: true}, seed)
+	case "road":
+		if err := want(1, 1); err != nil {
+			return nil, err
+		}
+		return sssp.GenerateRoadNetwork(sssp.RoadParams{Nodes: uint32(nums[0])}, seed)
+	}
+	return nil, fmt.Errorf("unknown generator %q", kind)
+}
+
+// compare benchmarks every mode and checks its distances against baseline's,
+// or the first listed mode's when baseline is not among them. The reference
+// distances are computed up front, untimed, so the check does not depend on
+// the order the Runner runs the modes in. Rows come back in the order listed.
+func compare(g *sssp.Graph, algs []bench.Algorithm, r bench.Runner) ([]row, error) {
+	ref := algs[0]
+	for _, a := range algs {
+		if a.Name == "baseline" {
+			ref = a
+		}
+	}
+	want := make(map[uint32][]float32)
+	for _, s := range r.Sources {
+		res, err := ref.Run(g, s)
+		if err != nil {
+			return nil, fmt.Errorf("%s from %d: %w", ref.Name, s, err)
+		}
+		want[s] = res.Dist
+	}
+	diffs := make(map[string]*row)
+	for _, a := range algs {
+		diffs[a.Name] = &row{}
+	}
+	r.Observe = func(name string, source uint32, res *sssp.Result) {
+		var expect []float32
+		if name != ref.Name {
+			expect = want[source]
+		}
+		v, err := g.Verify(source, res, expect, sssp.DefaultTolerance)
+		if err != nil { // a result of the wrong size disagrees everywhere
+			v = &sssp.Verification{Reachability: int(g.NodeCount())}
+		}
+		rw := diffs[name]
+		rw.maxDrift = math.Max(rw.maxDrift, v.MaxDrift)
+		rw.drift += v.Drift
+		rw.reach += v.Reachability
+		rw.badPred += v.Predecessor
+	}
+	sums, err := r.Run(g, algs...)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range sums {
+		diffs[s.Name].Summary = s
+	}
+	out := make([]row, 0, len(algs))
+	for _, a := range algs {
+		out = append(out, *diffs[a.Name])
+	}
+	return out, nil
+}
+
+func writeTable(w io.Writer, rows []row) {
+	base := rows[0].Mean
+	for _, r := range rows {
+		if r.Name == "baseline" {
+			base = r.Mean
+		}
+	}
+	mem, perf := rows[0].Mem != nil, rows[0].Perf != nil
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(tw, "mode\truns\tmean ms\tstddev\tp50 ms\tp95 ms\tp99 ms\tspeedup\tedge relax\timproved\texpansions\theap ops\tbucket scans\tmax drift\tdrift\treach\tbad pred\t")
+	if mem {
+		fmt.Fprint(tw, "allocs\talloc MB\tpeak heap MB\tpeak RSS MB\tcgroup MB\t")
+	}
+	if perf {
+		fmt.Fprint(tw, "instructions\tIPC\tLLC misses\tmiss rate\tbranch misses\t")
+	}
+	fmt.Fprintln(tw)
+	for _, r := range rows {
+		speedup := float64(base) / float64(r.Mean)
+		o := r.Ops
+		fmt.Fprintf(tw, "%s\t%d\t%.3f\t%.3f\t%.3f\t%.3f\t%.3f\t%.2fx\t%d\t%d\t%d\t%d\t%d\t%g\t%d\t%d\t%d\t",
+			r.Name, r.Runs, ms(r.Mean), ms(r.StdDev), ms(r.P50), ms(r.P95), ms(r.P99), speedup,
+			o.EdgeRelaxations, o.Improvements, o.FrontierExpansions, o.HeapPushes+o.HeapPops, o.BucketScans,
+			r.maxDrift, r.drift, r.reach, r.badPred)
+		if m := r.Mem; mem && m != nil {
+			fmt.Fprintf(tw, "%d\t%.1f\t%.1f\t%.1f\t%.1f\t", m.Allocs, mb(m.AllocBytes), mb(m.PeakHeap), mb(m.PeakRSS), mb(m.CgroupPeak))
+		}
+		if p := r.Perf; perf && p != nil {
+			fmt.Fprintf(tw, "%d\t%.2f\t%d\t%.1f%%\t%d\t", p.Instructions, p.IPC(), p.CacheMisses, 100*p.CacheMissRate(), p.BranchMisses)
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+}
+
+func ms(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
+
+func mb(b uint64) float64 { return float64(b) / (1 << 20) }
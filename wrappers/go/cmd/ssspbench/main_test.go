@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/your-org/optimized-sssp-go/bench"
+)
+
+func TestRunWritesComparison(t *testing.T) {
+	dir := t.TempDir()
+	out, js := filepath.Join(dir, "history.csv"), filepath.Join(dir, "run.json")
+	for i := 0; i < 2; i++ {
+		var stdout bytes.Buffer
+		c := config{gen: "grid:20x20", modes: "stoc,baseline", sources: 3, reps: 5, warmup: 1, seed: 1, out: out, json: js}
+		if err := run(c, &stdout); err != nil {
+			t.Fatalf("run: %v", err)
+		}
+		if !strings.Contains(stdout.String(), "400 nodes") || !strings.Contains(stdout.String(), "p95") {
+			t.Fatalf("table:\n%s", stdout.String())
+		}
+	}
+	f, err := os.Open(out)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+	recs, err := csv.NewReader(f).ReadAll()
+	// One header, then two rows per run: the second run appended.
+	if err != nil || len(recs) != 5 || recs[0][0] != "time" || recs[1][5] != "stoc" || recs[4][5] != "baseline" || recs[1][6] != "5" {
+		t.Fatalf("csv %v: %v", recs, err)
+	}
+	jf, err := os.Open(js)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer jf.Close()
+	rep, err := bench.ReadReport(jf)
+	if err != nil || rep.Graph.Name != "grid:20x20" || len(rep.Results) != 2 || rep.Settings.Sources != 3 {
+		t.Fatalf("report %+v: %v", rep, err)
+	}
+
+	// A baseline claiming every mode once ran in a nanosecond must fail.
+	for i := range rep.Results {
+		rep.Results[i].P50 = 1
+	}
+	fast := filepath.Join(dir, "fast.json")
+	if err := rep.Save(fast); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	var stdout bytes.Buffer
+	err = run(config{gen: "grid:20x20", modes: "baseline", sources: 2, seed: 1, baseline: fast, threshold: 0.1}, &stdout)
+	if err == nil || !strings.Contains(stdout.String(), "REGRESSION") {
+		t.Fatalf("expected a regression, got %v:\n%s", err, stdout.String())
+	}
+	if err := run(config{gen: "grid:20x20", modes: "baseline", sources: 2, seed: 1, baseline: js, threshold: 1e6}, &stdout); err != nil {
+		t.Fatalf("generous threshold: %v", err)
+	}
+}
+
+func TestGenerateSpecs(t *testing.T) {
+	for spec, nodes := range map[string]uint32{"random:100:3": 100, "rmat:8": 256, "grid:4x5x6": 120, "road:50": 64} {
+		g, err := generate(spec, 1)
+		if err != nil || g.NodeCount() != nodes {
+			t.Fatalf("%s: %v", spec, err)
+		}
+	}
+	for _, bad := range []string{"rmat", "grid:3", "random:x:1", "cube:3"} {
+		if _, err := generate(bad, 1); err == nil {
+			t.Fatalf("%s: expected an error", bad)
+		}
+	}
+	if _, err := parseModes("baseline,fast"); err == nil {
+		t.Fatalf("expected an unknown mode to fail")
+	}
+}
+
+func TestParseModeSettings(t *testing.T) {
+	algs, err := parseModes("baseline, stoc:delta=2,autotune:limit=64:cands=1/4,stoc:delta=8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(algs) != 4 || algs[1].Name != "stoc:delta=2" || algs[3].Name != "stoc:delta=8" {
+		t.Fatalf("parsed %v", algs)
+	}
+	g, err := generate("grid:10x10", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := algs[1].Run(g, 0)
+	if err != nil || res.Stats.Delta != 2 {
+		t.Fatalf("stoc:delta=2 ran with delta %v, %v", res.Stats.Delta, err)
+	}
+	for _, bad := range []string{"stoc:", "stoc:delta=0", "stoc:limit=3", "baseline:delta=1", "autotune:cands=1/x", "stoc:delta=1,stoc:delta=1"} {
+		if _, err := parseModes(bad); err == nil {
+			t.Fatalf("%s: expected an error", bad)
+		}
+	}
+	var stdout bytes.Buffer
+	md := filepath.Join(t.TempDir(), "cmp.md")
+	if err := run(config{gen: "grid:10x10", modes: "stoc:delta=1,baseline", sources: 2, seed: 1, report: md}, &stdout); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(stdout.String(), "stoc:delta=1") {
+		t.Fatalf("missing mode row:\n%s", stdout.String())
+	}
+	if b, err := os.ReadFile(md); err != nil || !strings.Contains(string(b), "| stoc:delta=1 |") {
+		t.Fatalf("report %s: %v", b, err)
+	}
+}
+
+func TestSweepFitsEveryMode(t *testing.T) {
+	js := filepath.Join(t.TempDir(), "sweep.json")
+	var stdout bytes.Buffer
+	c := config{modes: "baseline,stoc", sources: 2, warmup: 1, seed: 1, json: js, sweepNodes: "0.2k,400", sweepDegrees: "2,3"}
+	if err := run(c, &stdout); err != nil {
+		t.Fatalf("sweep: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "m^k") || strings.Count(stdout.String(), "stoc") != 5 {
+		t.Fatalf("output:\n%s", stdout.String())
+	}
+	if _, err := os.Stat(js); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	if err := run(config{modes: "baseline", gen: "grid:3x3", sweepNodes: "10"}, &stdout); err == nil {
+		t.Fatalf("expected -gen with -sweep-nodes to fail")
+	}
+	if _, err := parseCount("1.5M"); err != nil {
+		t.Fatalf("parseCount: %v", err)
+	}
+	if n, _ := parseCount("10k"); n != 10000 {
+		t.Fatalf("parseCount(10k) = %d", n)
+	}
+}
+
+func TestRelabel(t *testing.T) {
+	var stdout bytes.Buffer
+	if err := run(config{gen: "grid:10x10", modes: "baseline", sources: 2, seed: 1, relabel: "rcm"}, &stdout); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(config{gen: "grid:10x10", modes: "baseline", sources: 2, seed: 1, relabel: "zigzag"}, &stdout); err == nil {
+		t.Fatalf("unknown -relabel accepted")
+	}
+}
@@ -0,0 +1,358 @@
+package sssp
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Contracted is a graph with its chains of degree-2 nodes replaced by
+// shortcut edges, as built by Graph.ContractChains. Queries run on the
+// smaller Core and are expanded back to every node of the original.
+type Contracted struct {
+	// Core holds the nodes that are not inside a chain, renumbered in
+	// increasing order of their original IDs, with the edges between them
+	// and one shortcut per chain and direction. It can be run in any mode.
+	Core *Graph
+	// Removed is the number of nodes contracted away.
+	Removed int
+
+	orig    *Graph
+	coreID  []int32  // core ID of each original node, -1 inside a chain
+	nodes   []uint32 // original ID of each core node
+	chains  []chain
+	chainOf []int32 // chain of each original node, -1 for core nodes
+	posOf   []int32 // index in its chain's interior
+	// via[e] is the chain behind core edge e, as 2*chain for the forward
+	// shortcut and 2*chain+1 for the backward one, or -1 for an original
+	// edge.
+	via []int32
+}
+
+// chain is a run of contracted nodes between two core nodes, which may be
+// the same node. fwd[i] is the weight of the hop into interior[i] from
+// from's side (fwd[len(interior)] the hop into to); bwd[i] the weight of
+// the hop out of interior[i] towards from (bwd[0] into from). A nil slice
+// means the chain cannot be travelled that way.
+type chain struct {
+	from, to uint32
+	interior []uint32
+	fwd, bwd []float32
+}
+
+// ContractChains collapses chains of degree-2 nodes into shortcut edges. A
+// node is contracted when its only neighbours are two other nodes a and b
+// and it passes traffic through: edges a->v->b, or b->v->a, or all four,
+// and no others. Road networks are largely made of such nodes, between
+// junctions, so the core is much smaller and every algorithm on it faster.
+// Rings with no junction keep one node in the core. Shortcut weights are
+// the sums of their chains' weights, so core distances can differ from
+// the original graph's in the last bits of the float32 result; coordinates
+// are carried to the core, attributes are not.
+func (g *Graph) ContractChains() *Contracted {
+	n := g.NodeCount()
+	rev := g.inEdges()
+	// nbr[v] holds a contractible node's two neighbours.
+	nbr := make([][2]uint32, n)
+	inner := make([]bool, n)
+	for v := uint32(0); v < n; v++ {
+		inner[v] = g.passThrough(rev, v, &nbr[v])
+	}
+	c := &Contracted{orig: g, chainOf: make([]int32, n), posOf: make([]int32, n)}
+	for v := range c.chainOf {
+		c.chainOf[v] = -1
+	}
+	walk := func(s uint32) {
+		for _, first := range g.chainStarts(rev, s) {
+			if !inner[first] || c.chainOf[first] >= 0 {
+				continue
+			}
+			ch := chain{from: s}
+			prev, cur := s, first
+			for inner[cur] && c.chainOf[cur] < 0 {
+				c.chainOf[cur], c.posOf[cur] = int32(len(c.chains)), int32(len(ch.interior))
+				ch.interior = append(ch.interior, cur)
+				next := nbr[cur][0]
+				if next == prev {
+					next = nbr[cur][1]
+				}
+				prev, cur = cur, next
+			}
+			ch.to = cur
+			path := append(append([]uint32{s}, ch.interior...), cur)
+			ch.fwd, ch.bwd = g.hopWeights(path, false), g.hopWeights(path, true)
+			c.chains = append(c.chains, ch)
+		}
+	}
+	for v := uint32(0); v < n; v++ {
+		if !inner[v] {
+			walk(v)
+		}
+	}
+	// What is left are rings of contractible nodes; keep one of each.
+	for v := uint32(0); v < n; v++ {
+		if inner[v] && c.chainOf[v] < 0 {
+			inner[v] = false
+			walk(v)
+		}
+	}
+	c.coreID = make([]int32, n)
+	for v := uint32(0); v < n; v++ {
+		c.coreID[v] = -1
+		if !inner[v] {
+			c.coreID[v] = int32(len(c.nodes))
+			c.nodes = append(c.nodes, v)
+		}
+	}
+	c.Removed = int(n) - len(c.nodes)
+	c.buildCore()
+	return c
+}
+
+// passThrough reports whether v's edges are exactly a->v->b, b->v->a or
+// both for two distinct other nodes a and b, storing them in ab.
+func (g *Graph) passThrough(rev *Graph, v uint32, ab *[2]uint32) bool {
+	outs, ins := g.targets[g.offsets[v]:g.offsets[v+1]], rev.targets[rev.offsets[v]:rev.offsets[v+1]]
+	if len(outs) == 0 || len(outs) > 2 || len(ins) == 0 || len(ins) > 2 || len(outs) != len(ins) {
+		return false
+	}
+	if len(outs) == 1 { // one way: a->v->b
+		a, b := ins[0], outs[0]
+		*ab = [2]uint32{a, b}
+		return a != b && a != v && b != v
+	}
+	a, b := outs[0], outs[1]
+	*ab = [2]uint32{a, b}
+	return a != b && a != v && b != v && ((ins[0] == a && ins[1] == b) || (ins[0] == b && ins[1] == a))
+}
+
+// chainStarts lists the neighbours of s in either direction, each once.
+func (g *Graph) chainStarts(rev *Graph, s uint32) []uint32 {
+	var nb []uint32
+	for _, adj := range []*Graph{g, rev} {
+		for e := adj.offsets[s]; e < adj.offsets[s+1]; e++ {
+			if v := adj.targets[e]; !slices.Contains(nb, v) {
+				nb = append(nb, v)
+			}
+		}
+	}
+	return nb
+}
+
+// hopWeights returns the weights of the edges path[i]->path[i+1], or with
+// back set path[i+1]->path[i], or nil if one is missing. Within a chain
+// either all exist or none.
+func (g *Graph) hopWeights(path []uint32, back bool) []float32 {
+	w := make([]float32, len(path)-1)
+	for i := range w {
+		u, v := path[i], path[i+1]
+		if back {
+			u, v = v, u
+		}
+		found := false
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			if g.targets[e] == v && (!found || g.weights[e] < w[i]) {
+				w[i], found = g.weights[e], true
+			}
+		}
+		if !found {
+			return nil
+		}
+	}
+	return w
+}
+
+// buildCore assembles Core from the original edges between core nodes and
+// the chains' shortcuts.
+func (c *Contracted) buildCore() {
+	g := c.orig
+	type tagged struct {
+		Edge
+		via int32
+	}
+	var edges []tagged
+	for i, u := range c.nodes {
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			if v := c.coreID[g.targets[e]]; v >= 0 {
+				edges = append(edges, tagged{Edge{uint32(i), uint32(v), g.weights[e]}, -1})
+			}
+		}
+	}
+	for i, ch := range c.chains {
+		if ch.from == ch.to {
+			continue // a loop back to its start never shortens a path
+		}
+		from, to := uint32(c.coreID[ch.from]), uint32(c.coreID[ch.to])
+		if ch.fwd != nil {
+			edges = append(edges, tagged{Edge{from, to, sum32(ch.fwd)}, int32(2 * i)})
+		}
+		if ch.bwd != nil {
+			edges = append(edges, tagged{Edge{to, from, sum32(ch.bwd)}, int32(2*i + 1)})
+		}
+	}
+	// FromEdges keeps input order per source node, so sorting by source
+	// first makes the slice index the edge ID.
+	slices.SortStableFunc(edges, func(a, b tagged) int { return cmp.Compare(a.From, b.From) })
+	plain := make([]Edge, len(edges))
+	c.via = make([]int32, len(edges))
+	for i, e := range edges {
+		plain[i], c.via[i] = e.Edge, e.via
+	}
+	// Every endpoint is a core ID, so FromEdges cannot fail.
+	c.Core, _ = FromEdges(uint32(len(c.nodes)), plain)
+	if g.coords != nil {
+		c.Core.coords = make([]LatLon, len(c.nodes))
+		for i, u := range c.nodes {
+			c.Core.coords[i] = g.coords[u]
+		}
+	}
+}
+
+func sum32(w []float32) float32 {
+	var s float32
+	for _, x := range w {
+		s += x
+	}
+	return s
+}
+
+// CoreNode returns the core ID of original node v, or false if v was
+// contracted.
+func (c *Contracted) CoreNode(v uint32) (uint32, bool) {
+	if int(v) >= len(c.coreID) || c.coreID[v] < 0 {
+		return 0, false
+	}
+	return uint32(c.coreID[v]), true
+}
+
+// Run answers a query from original node source by running mode on Core
+// and expanding the result: each contracted node gets the better of its
+// distances along its chain from either end, and predecessors are
+// rewritten so that Path walks the original edges. A source inside a
+// chain leaves it through one of the chain's ends, so Core is run from
+// each end it can reach and the two results are combined. Stats are those
+// of the core runs, with relaxations summed over both.
+func (c *Contracted) Run(source uint32, mode int) (Result, error) {
+	n := c.orig.NodeCount()
+	if err := checkGoRun(n, source, mode); err != nil {
+		return Result{}, err
+	}
+	res := Result{Dist: make([]float32, n), Pred: make([]int32, n), graph: c.orig}
+	for v := range res.Dist {
+		res.Dist[v], res.Pred[v] = Unreachable, -1
+	}
+	// Entry points into Core: the source itself, or the ends of its chain
+	// with their distances and the last node before each.
+	type entry struct {
+		node uint32 // original ID of a core node
+		dist float32
+		pred int32
+	}
+	var entries []entry
+	ci := c.chainOf[source]
+	if ci < 0 {
+		entries = append(entries, entry{source, 0, -1})
+	} else {
+		ch, pos := c.chains[ci], int(c.posOf[source])
+		res.Dist[source] = 0
+		if ch.fwd != nil { // onwards to ch.to
+			d, prev := float32(0), source
+			for i := pos + 1; i < len(ch.interior); i++ {
+				d += ch.fwd[i]
+				v := ch.interior[i]
+				res.Dist[v], res.Pred[v], prev = d, int32(prev), v
+			}
+			entries = append(entries, entry{ch.to, d + ch.fwd[len(ch.interior)], int32(prev)})
+		}
+		if ch.bwd != nil { // back to ch.from
+			d, prev := float32(0), source
+			for i := pos - 1; i >= 0; i-- {
+				d += ch.bwd[i+1]
+				v := ch.interior[i]
+				res.Dist[v], res.Pred[v], prev = d, int32(prev), v
+			}
+			entries = append(entries, entry{ch.from, d + ch.bwd[0], int32(prev)})
+		}
+	}
+	first := true
+	for _, en := range entries {
+		r, err := c.Core.Run(uint32(c.coreID[en.node]), mode)
+		if err != nil {
+			return Result{}, err
+		}
+		if first {
+			res.Stats, first = r.Stats, false
+		} else {
+			res.Stats.Relaxations += r.Stats.Relaxations
+			res.Stats.LightRelaxations += r.Stats.LightRelaxations
+			res.Stats.HeavyRelaxations += r.Stats.HeavyRelaxations
+			res.Stats.Ops.Add(r.Stats.Ops)
+		}
+		for i, d := range r.Dist {
+			v := c.nodes[i]
+			if d == Unreachable || en.dist+d >= res.Dist[v] {
+				continue
+			}
+			res.Dist[v] = en.dist + d
+			if r.Pred[i] < 0 {
+				res.Pred[v] = en.pred
+			} else {
+				res.Pred[v] = c.corePred(r, uint32(r.Pred[i]), uint32(i))
+			}
+		}
+	}
+	for _, ch := range c.chains {
+		c.expand(&res, ch)
+	}
+	return res, nil
+}
+
+// corePred returns the original predecessor of core node v reached from
+// core node u: u itself over an original edge, or the last node of the
+// chain whose shortcut the run took.
+func (c *Contracted) corePred(r Result, u, v uint32) int32 {
+	core := c.Core
+	best, via := Unreachable, int32(-1)
+	for e := core.offsets[u]; e < core.offsets[u+1]; e++ {
+		if core.targets[e] == v && (via == -1 || r.Dist[u]+core.weights[e] < best) {
+			best, via = r.Dist[u]+core.weights[e], c.via[e]
+			if via < 0 {
+				via = -2 // original edge; -1 means none seen yet
+			}
+		}
+	}
+	if via < 0 {
+		return int32(c.nodes[u])
+	}
+	ch := c.chains[via/2]
+	if via%2 == 0 {
+		return int32(ch.interior[len(ch.interior)-1])
+	}
+	return int32(ch.interior[0])
+}
+
+// expand fills in the contracted nodes of ch from the distances of its
+// ends. When the source lies in ch, passing through it never beats the
+// distances walked from it, so those are kept.
+func (c *Contracted) expand(res *Result, ch chain) {
+	if ch.fwd != nil && res.Dist[ch.from] != Unreachable {
+		d, prev := res.Dist[ch.from], ch.from
+		for i, v := range ch.interior {
+			d += ch.fwd[i]
+			if d < res.Dist[v] {
+				res.Dist[v], res.Pred[v] = d, int32(prev)
+			}
+			prev = v
+		}
+	}
+	if ch.bwd != nil && res.Dist[ch.to] != Unreachable {
+		d, prev := res.Dist[ch.to], ch.to
+		for i := len(ch.interior) - 1; i >= 0; i-- {
+			v := ch.interior[i]
+			d += ch.bwd[i+1]
+			if d < res.Dist[v] {
+				res.Dist[v], res.Pred[v] = d, int32(prev)
+			}
+			prev = v
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package sssp
+
+import (
+	"math"
+	"testing"
+)
+
+// chainGraph has junctions 0, 1 and 2 joined by a two-way chain with
+// different weights each way (3, 4), a one-way chain (5, 6), a chain
+// looping back to its start (7, 8), and a ring with no junction (9-11).
+// Weights are binary fractions, so sums are exact.
+func chainGraph(t *testing.T) *Graph {
+	t.Helper()
+	g, err := FromEdges(12, []Edge{
+		{0, 3, 1}, {3, 4, 1}, {4, 1, 1}, {1, 4, 0.25}, {4, 3, 0.25}, {3, 0, 0.25},
+		{1, 5, 2}, {5, 6, 2}, {6, 2, 2},
+		{2, 7, 1}, {7, 8, 1}, {8, 2, 1},
+		{0, 2, 1}, {2, 0, 1}, {2, 1, 0.5}, {0, 1, 5},
+		{9, 10, 1}, {10, 9, 1}, {10, 11, 1}, {11, 10, 1}, {11, 9, 1}, {9, 11, 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+// checkPaths verifies that every path in r follows edges of g and adds up
+// to its distance, within tol.
+func checkPaths(t *testing.T, g *Graph, r Result, source uint32, tol float64) {
+	t.Helper()
+	for v, d := range r.Dist {
+		if d == Unreachable {
+			continue
+		}
+		path := r.Path(uint32(v))
+		if len(path) == 0 || path[0] != source {
+			t.Fatalf("source %d: path to %d = %v", source, v, path)
+		}
+		var sum float32
+		for i := 1; i < len(path); i++ {
+			w, ok := g.EdgeWeight(path[i-1], path[i])
+			if !ok {
+				t.Fatalf("source %d: path to %d uses missing edge %d->%d", source, v, path[i-1], path[i])
+			}
+			sum += w
+		}
+		if math.Abs(float64(sum-d)) > tol*float64(max(1, d)) {
+			t.Fatalf("source %d: path to %d sums to %v, dist %v", source, v, sum, d)
+		}
+	}
+}
+
+func TestContractChains(t *testing.T) {
+	g := chainGraph(t)
+	c := g.ContractChains()
+	// 0, 1, 2 stay, and one node of the ring.
+	if c.Core.NodeCount() != 4 || c.Removed != 8 {
+		t.Fatalf("core has %d nodes, %d removed; want 4 and 8", c.Core.NodeCount(), c.Removed)
+	}
+	if _, ok := c.CoreNode(3); ok {
+		t.Error("node 3 is in the core")
+	}
+	if id, ok := c.CoreNode(2); !ok || id != 2 {
+		t.Errorf("CoreNode(2) = %d, %v", id, ok)
+	}
+	for s := uint32(0); s < g.NodeCount(); s++ {
+		want, err := g.RunSettled(s, ModeBaseline, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := c.Run(s, ModeBaseline)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for v := range want.Dist {
+			if got.Dist[v] != want.Dist[v] {
+				t.Fatalf("source %d: dist[%d] = %v, want %v", s, v, got.Dist[v], want.Dist[v])
+			}
+		}
+		if got.Pred[s] != -1 {
+			t.Errorf("source %d has predecessor %d", s, got.Pred[s])
+		}
+		checkPaths(t, g, got, s, 0)
+	}
+	if _, err := c.Run(12, ModeBaseline); err == nil {
+		t.Error("out-of-range source accepted")
+	}
+}
+
+func TestContractRoadNetwork(t *testing.T) {
+	g, err := GenerateRoadNetwork(RoadParams{Nodes: 900}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := g.ContractChains()
+	if c.Removed == 0 || c.Core.NodeCount()+uint32(c.Removed) != g.NodeCount() {
+		t.Fatalf("removed %d of %d nodes, core %d", c.Removed, g.NodeCount(), c.Core.NodeCount())
+	}
+	for _, s := range []uint32{0, 57, 450, g.NodeCount() - 1} {
+		want, err := g.RunSettled(s, ModeBaseline, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := c.Run(s, ModeBaseline)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for v := range want.Dist {
+			if d := math.Abs(float64(got.Dist[v] - want.Dist[v])); d > 1e-4*float64(max(1, want.Dist[v])) {
+				t.Fatalf("source %d: dist[%d] = %v, want %v", s, v, got.Dist[v], want.Dist[v])
+			}
+		}
+		checkPaths(t, g, got, s, 1e-4)
+	}
+}
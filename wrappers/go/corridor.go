@@ -0,0 +1,60 @@
+package sssp
+
+import "fmt"
+
+// Corridor is the set of near-optimal routes between two nodes, as
+// returned by Graph.Corridor.
+type Corridor struct {
+	// Dist is the shortest distance from source to target, Unreachable if
+	// there is no path (and then the corridor is empty).
+	Dist float32
+	// Graph is the subgraph of the corridor's edges and the nodes they
+	// touch, renumbered; NodeMap maps the original node IDs to it, -1 for
+	// nodes outside.
+	Graph   *Graph
+	NodeMap []int32
+	// Edges are the IDs, in the original graph, of the corridor's edges.
+	Edges []int
+}
+
+// Corridor returns every edge u->v lying on a source-target route within
+// (1+alpha) of the shortest distance: those with dist(source, u) + w +
+// dist(v, target) <= (1+alpha) * dist(source, target). It costs two runs in
+// mode, forward from source and backward from target on ReverseView (built
+// on first use and kept). alpha = 0 gives the union of all shortest paths.
+// The bound holds for the best route through each edge, which may revisit
+// nodes; constraint solvers working on the corridor enforce simplicity.
+func (g *Graph) Corridor(source, target uint32, alpha float32, mode int) (*Corridor, error) {
+	if alpha < 0 {
+		return nil, fmt.Errorf("sssp: corridor slack %v is negative", alpha)
+	}
+	if target >= g.NodeCount() {
+		return nil, fmt.Errorf("sssp: target %d out of range for %d nodes", target, g.NodeCount())
+	}
+	fwd, err := g.Run(source, mode)
+	if err != nil {
+		return nil, err
+	}
+	bwd, err := g.ReverseView().Run(target, mode)
+	if err != nil {
+		return nil, err
+	}
+	c := &Corridor{Dist: fwd.Dist[target]}
+	kept := make([]bool, len(g.targets))
+	if c.Dist != Unreachable {
+		bound := (1 + alpha) * c.Dist
+		for u := uint32(0); u < g.NodeCount(); u++ {
+			if fwd.Dist[u] > bound {
+				continue
+			}
+			for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+				if fwd.Dist[u]+g.weights[e]+bwd.Dist[g.targets[e]] <= bound {
+					kept[e] = true
+					c.Edges = append(c.Edges, int(e))
+				}
+			}
+		}
+	}
+	c.Graph, c.NodeMap = g.keepEdges(kept)
+	return c, nil
+}
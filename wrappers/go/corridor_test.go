@@ -0,0 +1,33 @@
+package sssp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCorridor(t *testing.T) {
+	// Routes 0->3: via 1 (length 2), via 2 (length 2.5), and a detour
+	// 0->4->3 of length 10. Edge IDs run 0->1, 0->2, 0->4, 1->3, 2->3, 4->3.
+	g, _ := FromEdges(5, []Edge{{0, 1, 1}, {1, 3, 1}, {0, 2, 1}, {2, 3, 1.5}, {0, 4, 5}, {4, 3, 5}})
+	c, err := g.Corridor(0, 3, 0, ModeBaseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Dist != 2 || !reflect.DeepEqual(c.Edges, []int{0, 3}) {
+		t.Fatalf("shortest corridor %v %v", c.Dist, c.Edges)
+	}
+	if c.Graph.NodeCount() != 3 || c.NodeMap[2] != -1 {
+		t.Fatalf("corridor graph %d nodes, map %v", c.Graph.NodeCount(), c.NodeMap)
+	}
+	c, _ = g.Corridor(0, 3, 0.25, ModeStoc)
+	if !reflect.DeepEqual(c.Edges, []int{0, 1, 3, 4}) {
+		t.Fatalf("25%% corridor %v", c.Edges)
+	}
+	c, _ = g.Corridor(3, 0, 1, ModeBaseline)
+	if c.Dist != Unreachable || c.Edges != nil || c.Graph.NodeCount() != 0 {
+		t.Fatalf("unreachable corridor %+v", c)
+	}
+	if _, err := g.Corridor(0, 3, -1, ModeBaseline); err == nil {
+		t.Fatalf("negative slack accepted")
+	}
+}
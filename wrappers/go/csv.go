@@ -0,0 +1,91 @@
+package sssp
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// CSVOptions controls Result.WriteCSV.
+type CSVOptions struct {
+	// Unreachable fills the distance, predecessor and hops columns of
+	// unreachable nodes. The default "" reads as missing in spreadsheets and
+	// pandas; "inf" is another common choice.
+	Unreachable string
+}
+
+// WriteCSV writes one row per node with a header line:
+//
+//	node,distance,predecessor,hops
+//
+// hops counts the edges on the shortest path; the source has predecessor -1
+// and 0 hops.
+func (r *Result) WriteCSV(w io.Writer, opts CSVOptions) error {
+	n := len(r.Dist)
+	if len(r.Pred) != n {
+		return fmt.Errorf("sssp: csv: %d distances but %d predecessors", n, len(r.Pred))
+	}
+	hops := r.hops()
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"node", "distance", "predecessor", "hops"})
+	row := make([]string, 4)
+	for v := 0; v < n; v++ {
+		row[0] = strconv.Itoa(v)
+		if hops[v] < 0 {
+			row[1], row[2], row[3] = opts.Unreachable, opts.Unreachable, opts.Unreachable
+		} else {
+			row[1] = formatWeight(r.Dist[v])
+			row[2] = strconv.Itoa(int(r.Pred[v]))
+			row[3] = strconv.Itoa(int(hops[v]))
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// hops returns the edge count of every node's shortest path, or -1 when the
+// node is unreachable or its predecessor chain does not end at a root.
+func (r *Result) hops() []int32 {
+	const unknown, visiting = -2, -3
+	n := len(r.Dist)
+	hops := make([]int32, n)
+	for v := range hops {
+		hops[v] = unknown
+	}
+	var stack []int32
+	for v := 0; v < n; v++ {
+		for u := int32(v); hops[u] == unknown; {
+			if math.IsInf(float64(r.Dist[u]), 1) {
+				hops[u] = -1
+				break
+			}
+			p := r.Pred[u]
+			if p < 0 {
+				hops[u] = 0
+				break
+			}
+			if int(p) >= n || hops[p] == visiting {
+				hops[u] = -1 // corrupt chain
+				break
+			}
+			hops[u] = visiting
+			stack = append(stack, u)
+			u = p
+		}
+		for len(stack) > 0 {
+			u := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if h := hops[r.Pred[u]]; h >= 0 {
+				hops[u] = h + 1
+			} else {
+				hops[u] = -1
+			}
+		}
+	}
+	return hops
+}
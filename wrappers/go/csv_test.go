@@ -0,0 +1,28 @@
+package sssp
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestResultWriteCSV(t *testing.T) {
+	g, _ := FromEdges(4, []Edge{{0, 1, 1.5}, {1, 2, 2}, {0, 2, 4}})
+	res, err := g.Run(0, ModeBaseline)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := res.WriteCSV(&buf, CSVOptions{Unreachable: "inf"}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	want := "node,distance,predecessor,hops\n0,0,-1,0\n1,1.5,0,1\n2,3.5,1,2\n3,inf,inf,inf\n"
+	if buf.String() != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	// A predecessor cycle must not hang or count hops.
+	bad := Result{Dist: []float32{0, 1, 2}, Pred: []int32{-1, 2, 1}}
+	if h := bad.hops(); h[0] != 0 || h[1] != -1 || h[2] != -1 {
+		t.Fatalf("hops = %v", h)
+	}
+}
@@ -0,0 +1,129 @@
+package sssp
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// GenerateRandomDAG returns a directed acyclic graph with n nodes and about
+// density*n edges, together with a topological order of it. Node ids are
+// shuffled, so the order is not simply 0..n-1. Duplicate edges are avoided
+// only by chance.
+func GenerateRandomDAG(n uint32, density float64, seed int64, weights WeightDist) (*Graph, []uint32, error) {
+	if density < 0 || (n < 2 && density > 0) {
+		return nil, nil, fmt.Errorf("sssp: generate: cannot place density %v edges on %d nodes", density, n)
+	}
+	if err := weights.validate(); err != nil {
+		return nil, nil, err
+	}
+	r := rand.New(rand.NewSource(seed))
+	order := make([]uint32, n)
+	for i := range order {
+		order[i] = uint32(i)
+	}
+	r.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+	m := int(density*float64(n) + 0.5)
+	edges := make([]Edge, m)
+	for i := range edges {
+		a := r.Int63n(int64(n))
+		b := r.Int63n(int64(n - 1))
+		if b >= a {
+			b++
+		}
+		a, b = min(a, b), max(a, b)
+		edges[i] = Edge{From: order[a], To: order[b], Weight: weights.sample(r)}
+	}
+	g, err := FromEdges(n, edges)
+	if err != nil {
+		return nil, nil, err
+	}
+	return g, order, nil
+}
+
+// TopologicalOrder returns the nodes of g so that every edge points forward,
+// or an error naming a node on a cycle.
+func (g *Graph) TopologicalOrder() ([]uint32, error) {
+	n := g.NodeCount()
+	indeg := make([]uint32, n)
+	for _, v := range g.targets {
+		indeg[v]++
+	}
+	order := make([]uint32, 0, n)
+	for u := uint32(0); u < n; u++ {
+		if indeg[u] == 0 {
+			order = append(order, u)
+		}
+	}
+	for i := 0; i < len(order); i++ {
+		u := order[i]
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			v := g.targets[e]
+			if indeg[v]--; indeg[v] == 0 {
+				order = append(order, v)
+			}
+		}
+	}
+	if len(order) < int(n) {
+		for u := uint32(0); u < n; u++ {
+			if indeg[u] > 0 {
+				return nil, fmt.Errorf("sssp: graph has a cycle through node %d", u)
+			}
+		}
+	}
+	return order, nil
+}
+
+// RunDAG computes shortest paths from source on an acyclic graph in
+// O(n + m) by relaxing edges in topological order. order may come from
+// GenerateRandomDAG or TopologicalOrder; nil computes it. Unlike the other
+// runners it accepts negative weights, so negating them turns it into a
+// longest-path (critical path) solver.
+func (g *Graph) RunDAG(source uint32, order []uint32) (Result, error) {
+	n := g.NodeCount()
+	if source >= n {
+		return Result{}, fmt.Errorf("sssp: source %d out of range for %d nodes", source, n)
+	}
+	if order == nil {
+		var err error
+		if order, err = g.TopologicalOrder(); err != nil {
+			return Result{}, err
+		}
+	}
+	if len(order) != int(n) {
+		return Result{}, fmt.Errorf("sssp: topological order has %d nodes, graph has %d", len(order), n)
+	}
+	pos := make([]uint32, n)
+	for i := range pos {
+		pos[i] = n
+	}
+	for i, u := range order {
+		if u >= n || pos[u] != n {
+			return Result{}, fmt.Errorf("sssp: topological order is not a permutation (entry %d is %d)", i, u)
+		}
+		pos[u] = uint32(i)
+	}
+	dist := make([]float32, n)
+	pred := make([]int32, n)
+	for i := range dist {
+		dist[i], pred[i] = inf32, -1
+	}
+	dist[source] = 0
+	var stats Stats
+	for _, u := range order[pos[source]:] {
+		if dist[u] == inf32 {
+			continue
+		}
+		stats.Settled++
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			v := g.targets[e]
+			if pos[v] <= pos[u] {
+				return Result{}, fmt.Errorf("sssp: edge %d->%d points backwards in the topological order", u, v)
+			}
+			stats.Relaxations++
+			if d := dist[u] + g.weights[e]; d < dist[v] {
+				dist[v], pred[v] = d, int32(u)
+			}
+		}
+	}
+	return Result{Dist: dist, Pred: pred, Stats: stats, graph: g}, nil
+}
@@ -0,0 +1,54 @@
+package sssp
+
+import "testing"
+
+func TestRunDAGMatchesDijkstra(t *testing.T) {
+	g, order, err := GenerateRandomDAG(2000, 4, 3, WeightDist{})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	pos := make([]int, len(order))
+	for i, u := range order {
+		pos[u] = i
+	}
+	for _, e := range g.Edges() {
+		if pos[e.From] >= pos[e.To] {
+			t.Fatalf("edge %+v goes against the order", e)
+		}
+	}
+	src := order[0]
+	want, err := g.Run(src, ModeBaseline)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	for _, o := range [][]uint32{order, nil} {
+		got, err := g.RunDAG(src, o)
+		if err != nil {
+			t.Fatalf("dag: %v", err)
+		}
+		for v := range want.Dist {
+			if got.Dist[v] != want.Dist[v] {
+				t.Fatalf("node %d: %v, want %v", v, got.Dist[v], want.Dist[v])
+			}
+		}
+	}
+}
+
+func TestRunDAGLongestPathAndCycles(t *testing.T) {
+	// Critical path 0->1->3 (5+4) beats 0->2->3 (2+3); negate to find it.
+	g, _ := FromEdges(4, []Edge{{0, 1, -5}, {0, 2, -2}, {1, 3, -4}, {2, 3, -3}})
+	res, err := g.RunDAG(0, nil)
+	if err != nil {
+		t.Fatalf("dag: %v", err)
+	}
+	if res.Dist[3] != -9 || res.Pred[3] != 1 {
+		t.Fatalf("dist %v pred %v", res.Dist[3], res.Pred[3])
+	}
+	if _, err := g.RunDAG(0, []uint32{0, 2, 3, 1}); err == nil {
+		t.Fatalf("expected a wrong order to fail")
+	}
+	cyc, _ := FromEdges(3, []Edge{{0, 1, 1}, {1, 2, 1}, {2, 1, 1}})
+	if _, err := cyc.TopologicalOrder(); err == nil {
+		t.Fatalf("expected a cycle to be reported")
+	}
+}
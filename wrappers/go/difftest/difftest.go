@@ -0,0 +1,339 @@
+// Package difftest cross-checks shortest-path algorithms on many small
+// random graphs.
+//
+// Run generates a graph per seed, runs a reference algorithm and the
+// contenders from the same source, and compares their distances. On the
+// first disagreement it shrinks the graph, dropping edges, then unused
+// nodes, then simplifying weights for as long as the disagreement persists,
+// and writes the minimal counterexample as a DIMACS .gr/.ss pair that the
+// loaders and the ssspbench command read back.
+package difftest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"time"
+
+	sssp "github.com/your-org/optimized-sssp-go"
+	"github.com/your-org/optimized-sssp-go/bench"
+)
+
+// Config controls the generated cases. The zero value tries 200 graphs of
+// up to 64 nodes and average out-degree up to 4.
+type Config struct {
+	Cases     int     // graphs to try; 0 means 200
+	Seed      int64   // case i uses seed Seed+i
+	MaxNodes  uint32  // 0 means 64; at least 2
+	MaxDegree float64 // 0 means 4
+	// Weights are cycled across cases; nil means a mix of the default
+	// uniform weights, small integers (many ties), exponential weights
+	// (many light edges) and weights below 1.
+	Weights []sssp.WeightDist
+	// Tolerance is the relative distance error still accepted; 0 means
+	// 1e-5. Algorithms summing the same path in a different order can
+	// round differently.
+	Tolerance float64
+	// Timeout bounds every run; 0 means 10s, < 0 none. A run that times out
+	// is reported without shrinking, and its goroutine is abandoned.
+	Timeout time.Duration
+	// Dir, if set, receives the counterexample files.
+	Dir string
+}
+
+// Failure is a counterexample: Algorithm disagrees with the reference on
+// Graph from Source.
+type Failure struct {
+	Seed      int64 // case that first failed
+	Algorithm string
+	Graph     *sssp.Graph // shrunk graph
+	Source    uint32
+	// Node is the first node whose distances differ, Want the reference
+	// distance and Got the algorithm's (+Inf when unreachable). They are
+	// unset when Err is.
+	Node      uint32
+	Want, Got float32
+	Err       error // the algorithm's error, if it failed or timed out
+	// OriginalNodes and OriginalEdges give the size before shrinking.
+	OriginalNodes uint32
+	OriginalEdges int
+	Path          string // the .gr file written, if Config.Dir is set
+}
+
+func (f *Failure) String() string {
+	s := fmt.Sprintf("seed %d: %s from %d on %d nodes, %d edges (shrunk from %d, %d)",
+		f.Seed, f.Algorithm, f.Source, f.Graph.NodeCount(), f.Graph.EdgeCount(), f.OriginalNodes, f.OriginalEdges)
+	if f.Err != nil {
+		return s + ": " + f.Err.Error()
+	}
+	return s + fmt.Sprintf(": node %d has distance %g, want %g", f.Node, f.Got, f.Want)
+}
+
+var errTimeout = errors.New("timed out")
+
+func (c Config) withDefaults() Config {
+	if c.Cases <= 0 {
+		c.Cases = 200
+	}
+	if c.MaxNodes == 0 {
+		c.MaxNodes = 64
+	}
+	c.MaxNodes = max(c.MaxNodes, 2)
+	if c.MaxDegree <= 0 {
+		c.MaxDegree = 4
+	}
+	if len(c.Weights) == 0 {
+		c.Weights = []sssp.WeightDist{
+			{}, sssp.IntWeights(1, 3), sssp.ExponentialWeights(1), sssp.UniformWeights(0, 1),
+		}
+	}
+	if c.Tolerance <= 0 {
+		c.Tolerance = 1e-5
+	}
+	if c.Timeout == 0 {
+		c.Timeout = 10 * time.Second
+	}
+	return c
+}
+
+// Run checks algs against ref on cfg.Cases random graphs and returns the
+// first failure, shrunk, or nil if all agree. The error reports a problem
+// with the harness itself: a graph that could not be generated, a failing
+// reference or a counterexample that could not be written.
+func Run(cfg Config, ref bench.Algorithm, algs ...bench.Algorithm) (*Failure, error) {
+	if len(algs) == 0 {
+		return nil, fmt.Errorf("difftest: no algorithms")
+	}
+	cfg = cfg.withDefaults()
+	for i := 0; i < cfg.Cases; i++ {
+		seed := cfg.Seed + int64(i)
+		g, source, err := cfg.generate(i, seed)
+		if err != nil {
+			return nil, err
+		}
+		want, err := run(ref, g, source, cfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("difftest: seed %d: reference %s: %w", seed, ref.Name, err)
+		}
+		for _, a := range algs {
+			f := check(cfg, a, g, source, want.Dist)
+			if f == nil {
+				continue
+			}
+			f.Seed, f.OriginalNodes, f.OriginalEdges = seed, g.NodeCount(), g.EdgeCount()
+			if !errors.Is(f.Err, errTimeout) {
+				fails := func(h *sssp.Graph, s uint32) bool {
+					r, err := run(ref, h, s, cfg.Timeout)
+					return err == nil && check(cfg, a, h, s, r.Dist) != nil
+				}
+				small, s := Shrink(g, source, fails)
+				r, _ := run(ref, small, s, cfg.Timeout)
+				if shrunk := check(cfg, a, small, s, r.Dist); shrunk != nil {
+					shrunk.Seed, shrunk.OriginalNodes, shrunk.OriginalEdges = f.Seed, f.OriginalNodes, f.OriginalEdges
+					f = shrunk
+				}
+			}
+			if cfg.Dir != "" {
+				if err := f.write(cfg.Dir); err != nil {
+					return f, err
+				}
+			}
+			return f, nil
+		}
+	}
+	return nil, nil
+}
+
+// generate builds case i: about half the graphs guarantee every node is
+// reachable from source 0, the rest start from a random node.
+func (c Config) generate(i int, seed int64) (*sssp.Graph, uint32, error) {
+	r := rand.New(rand.NewSource(seed))
+	n := 2 + uint32(r.Int63n(int64(c.MaxNodes-1)))
+	degree := c.MaxDegree * (0.25 + 0.75*r.Float64())
+	reachable := i%2 == 0
+	g, err := sssp.GenerateRandomGraph(n, degree, seed, sssp.RandomGraphOptions{
+		Weights: c.Weights[i%len(c.Weights)], Reachable: reachable, Workers: 1,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("difftest: seed %d: %w", seed, err)
+	}
+	source := uint32(0)
+	if !reachable {
+		source = uint32(r.Int63n(int64(n)))
+	}
+	return g, source, nil
+}
+
+// check runs a on g and compares it to the reference distances want.
+func check(c Config, a bench.Algorithm, g *sssp.Graph, source uint32, want []float32) *Failure {
+	res, err := run(a, g, source, c.Timeout)
+	f := &Failure{Algorithm: a.Name, Graph: g, Source: source}
+	if err != nil {
+		f.Err = err
+		return f
+	}
+	if len(res.Dist) != len(want) {
+		f.Err = fmt.Errorf("returned %d distances for %d nodes", len(res.Dist), len(want))
+		return f
+	}
+	for v := range want {
+		if !agree(want[v], res.Dist[v], c.Tolerance) {
+			f.Node, f.Want, f.Got = uint32(v), want[v], res.Dist[v]
+			return f
+		}
+	}
+	return nil
+}
+
+// agree reports whether got is within a relative tolerance of want, treating
+// every infinite or NaN distance as unreachable.
+func agree(want, got float32, tol float64) bool {
+	w, g := float64(want), float64(got)
+	wInf, gInf := math.IsInf(w, 0) || math.IsNaN(w), math.IsInf(g, 0) || math.IsNaN(g)
+	if wInf || gInf {
+		return wInf == gInf
+	}
+	return math.Abs(w-g) <= tol*max(1, math.Abs(w))
+}
+
+// run calls a, giving up after timeout when it is positive.
+func run(a bench.Algorithm, g *sssp.Graph, source uint32, timeout time.Duration) (sssp.Result, error) {
+	if timeout < 0 {
+		return a.Run(g, source)
+	}
+	type outcome struct {
+		res sssp.Result
+		err error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		res, err := a.Run(g, source)
+		done <- outcome{res, err}
+	}()
+	select {
+	case o := <-done:
+		return o.res, o.err
+	case <-time.After(timeout):
+		return sssp.Result{}, fmt.Errorf("%s from %d %w after %v", a.Name, source, errTimeout, timeout)
+	}
+}
+
+// Shrink returns a smaller graph and source on which fails still holds,
+// assuming it holds for g and source. It removes edges in halving chunks
+// down to single edges, drops nodes no edge touches (relabelling the rest),
+// and tries replacing each weight by 1 or by its rounded value, repeating
+// until nothing more can be removed. fails must be deterministic.
+func Shrink(g *sssp.Graph, source uint32, fails func(g *sssp.Graph, source uint32) bool) (*sssp.Graph, uint32) {
+	n, edges := g.NodeCount(), g.Edges()
+	changed := false
+	try := func(n2 uint32, e2 []sssp.Edge, s2 uint32) bool {
+		h, err := sssp.FromEdges(n2, e2)
+		if err != nil || !fails(h, s2) {
+			return false
+		}
+		n, edges, source, changed = n2, e2, s2, true
+		return true
+	}
+	for {
+		changed = false
+		for chunk := max(len(edges)/2, 1); ; chunk /= 2 {
+			for i := 0; i < len(edges); {
+				end := min(i+chunk, len(edges))
+				cand := append(append([]sssp.Edge(nil), edges[:i]...), edges[end:]...)
+				if !try(n, cand, source) {
+					i = end
+				}
+			}
+			if chunk <= 1 {
+				break
+			}
+		}
+		if n2, e2, s2 := compact(n, edges, source); n2 < n {
+			try(n2, e2, s2)
+		}
+		for i := range edges {
+			for _, w := range []float32{1, float32(math.Round(float64(edges[i].Weight)))} {
+				if edges[i].Weight == w {
+					break
+				}
+				cand := append([]sssp.Edge(nil), edges...)
+				cand[i].Weight = w
+				if try(n, cand, source) {
+					break
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	h, _ := sssp.FromEdges(n, edges)
+	return h, source
+}
+
+// compact drops the nodes that are neither the source nor an edge endpoint,
+// keeping the order of the rest.
+func compact(n uint32, edges []sssp.Edge, source uint32) (uint32, []sssp.Edge, uint32) {
+	used := make([]bool, n)
+	used[source] = true
+	for _, e := range edges {
+		used[e.From], used[e.To] = true, true
+	}
+	id := make([]uint32, n)
+	var m uint32
+	for v := range used {
+		if used[v] {
+			id[v] = m
+			m++
+		}
+	}
+	out := make([]sssp.Edge, len(edges))
+	for i, e := range edges {
+		out[i] = sssp.Edge{From: id[e.From], To: id[e.To], Weight: e.Weight}
+	}
+	return m, out, id[source]
+}
+
+// write saves the counterexample as <dir>/seed-<seed>-<algorithm>.gr with the
+// source alongside in a .ss file, and records the .gr path in f.Path. The
+// failure is described in comment lines; node numbers there are 1-based, as
+// in the file.
+func (f *Failure) write(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("difftest: %w", err)
+	}
+	base := filepath.Join(dir, fmt.Sprintf("seed-%d-%s", f.Seed, f.Algorithm))
+	gr, err := os.Create(base + ".gr")
+	if err != nil {
+		return fmt.Errorf("difftest: %w", err)
+	}
+	fmt.Fprintf(gr, "c difftest seed %d: %s from %d\n", f.Seed, f.Algorithm, f.Source+1)
+	if f.Err != nil {
+		fmt.Fprintf(gr, "c error: %v\n", f.Err)
+	} else {
+		fmt.Fprintf(gr, "c node %d: got %g, want %g\n", f.Node+1, f.Got, f.Want)
+	}
+	err = f.Graph.WriteDIMACS(gr)
+	if cerr := gr.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("difftest: %w", err)
+	}
+	ss, err := os.Create(base + ".ss")
+	if err != nil {
+		return fmt.Errorf("difftest: %w", err)
+	}
+	err = sssp.WriteDIMACSSources(ss, []uint32{f.Source})
+	if cerr := ss.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("difftest: %w", err)
+	}
+	f.Path = base + ".gr"
+	return nil
+}
@@ -0,0 +1,90 @@
+package difftest
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	sssp "github.com/your-org/optimized-sssp-go"
+	"github.com/your-org/optimized-sssp-go/bench"
+)
+
+// dropHeavy is a broken algorithm: it ignores edges heavier than 5.
+var dropHeavy = bench.Algorithm{Name: "dropheavy", Run: func(g *sssp.Graph, source uint32) (sssp.Result, error) {
+	var light []sssp.Edge
+	for _, e := range g.Edges() {
+		if e.Weight <= 5 {
+			light = append(light, e)
+		}
+	}
+	h, err := sssp.FromEdges(g.NodeCount(), light)
+	if err != nil {
+		return sssp.Result{}, err
+	}
+	return h.Run(source, sssp.ModeBaseline)
+}}
+
+func TestRunShrinksCounterexample(t *testing.T) {
+	dir := t.TempDir()
+	ref := bench.Mode("baseline", sssp.ModeBaseline)
+	f, err := Run(Config{Cases: 20, Dir: dir}, ref, dropHeavy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f == nil {
+		t.Fatal("broken algorithm passed")
+	}
+	if f.Graph.NodeCount() != 2 || f.Graph.EdgeCount() != 1 {
+		t.Fatalf("shrunk to %d nodes, %d edges, want 2, 1", f.Graph.NodeCount(), f.Graph.EdgeCount())
+	}
+	if w := f.Graph.Edges()[0].Weight; w <= 5 || w != float32(int(w)) {
+		t.Fatalf("weight %v not simplified to a heavy integer", w)
+	}
+	if f.OriginalEdges <= 1 || f.Err != nil {
+		t.Fatalf("failure %v", f)
+	}
+	g, err := sssp.LoadDIMACSFile(f.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.EdgeCount() != 1 {
+		t.Fatalf("written graph has %d edges", g.EdgeCount())
+	}
+	ss, err := os.ReadFile(strings.TrimSuffix(f.Path, ".gr") + ".ss")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources, err := sssp.LoadDIMACSSources(strings.NewReader(string(ss)))
+	if err != nil || len(sources) != 1 || sources[0] != f.Source {
+		t.Fatalf("sources %v, %v; want [%d]", sources, err, f.Source)
+	}
+}
+
+func TestRunAgreeingAlgorithms(t *testing.T) {
+	ref := bench.Mode("baseline", sssp.ModeBaseline)
+	f, err := Run(Config{Cases: 20}, ref, bench.Mode("again", sssp.ModeBaseline))
+	if err != nil || f != nil {
+		t.Fatalf("Run = %v, %v", f, err)
+	}
+}
+
+func TestShrinkKeepsSource(t *testing.T) {
+	g, err := sssp.FromEdges(6, []sssp.Edge{
+		{From: 3, To: 4, Weight: 2.5}, {From: 4, To: 5, Weight: 7.25}, {From: 0, To: 1, Weight: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Fails while the source still has an out-edge.
+	fails := func(h *sssp.Graph, s uint32) bool {
+		off, _, _ := h.CSR()
+		return off[s+1] > off[s]
+	}
+	h, s := Shrink(g, 3, fails)
+	if h.NodeCount() != 2 || h.EdgeCount() != 1 || s != 0 {
+		t.Fatalf("shrunk to %d nodes, %d edges, source %d", h.NodeCount(), h.EdgeCount(), s)
+	}
+	if e := h.Edges()[0]; e.From != 0 || e.To != 1 || e.Weight != 1 {
+		t.Fatalf("edge %+v", e)
+	}
+}
@@ -0,0 +1,177 @@
+package sssp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/your-org/optimized-sssp-go/internal/decompress"
+)
+
+// DIMACS 9th Implementation Challenge (shortest paths) formats. Node ids are
+// 1-based in files and 0-based in Graph.
+//
+//	.gr:  c <comment> | p sp <n> <m> | a <u> <v> <w>
+//	.ss:  c <comment> | p aux sp ss <k> | s <u>
+
+// LoadDIMACS reads a .gr file. Weights may be integers (as the challenge
+// specifies) or decimals.
+func LoadDIMACS(r io.Reader) (*Graph, error) {
+	var (
+		n     uint32
+		edges []Edge
+	)
+	err := scanDIMACS(r, func(nodes uint32, arcs uint64) {
+		n, edges = nodes, make([]Edge, 0, min(arcs, maxPrealloc))
+	}, func(e Edge) error {
+		edges = append(edges, e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return FromEdges(n, edges)
+}
+
+// LoadDIMACSFile reads a .gr file in two passes with a StreamBuilder, so the
+// arcs are never held as an edge list. Use it for graphs near the memory
+// limit; it reads (and decompresses) the file twice. The node count of the
+// problem line is allocated only after the arcs have been counted.
+func LoadDIMACSFile(path string) (*Graph, error) {
+	var b *StreamBuilder
+	pass := func(emit func(Edge) error) error {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return scanDIMACS(f, func(n uint32, _ uint64) {
+			if b == nil {
+				b = NewStreamBuilder(n)
+			}
+		}, emit)
+	}
+	if err := pass(func(e Edge) error { return b.Count(e) }); err != nil {
+		return nil, err
+	}
+	if err := pass(func(e Edge) error { return b.Place(e) }); err != nil {
+		return nil, err
+	}
+	return b.Graph()
+}
+
+// scanDIMACS parses a .gr stream, calling header for the problem line and arc
+// for every arc with 0-based endpoints.
+func scanDIMACS(r io.Reader, header func(n uint32, m uint64), arc func(Edge) error) error {
+	r, err := decompress.Reader(r)
+	if err != nil {
+		return err
+	}
+	sc := bufio.NewScanner(r)
+	var (
+		n, m, arcs uint64
+		seen       bool
+	)
+	for line := 1; sc.Scan(); line++ {
+		f := strings.Fields(sc.Text())
+		if len(f) == 0 || f[0] == "c" {
+			continue
+		}
+		switch f[0] {
+		case "p":
+			if seen || len(f) != 4 || f[1] != "sp" {
+				return fmt.Errorf("sssp: dimacs line %d: expected a single 'p sp <n> <m>'", line)
+			}
+			var err1, err2 error
+			n, err1 = strconv.ParseUint(f[2], 10, 32)
+			m, err2 = strconv.ParseUint(f[3], 10, 64)
+			if err1 != nil || err2 != nil {
+				return fmt.Errorf("sssp: dimacs line %d: bad problem line", line)
+			}
+			seen = true
+			header(uint32(n), m)
+		case "a":
+			if !seen {
+				return fmt.Errorf("sssp: dimacs line %d: arc before problem line", line)
+			}
+			if len(f) != 4 {
+				return fmt.Errorf("sssp: dimacs line %d: expected 'a <u> <v> <w>'", line)
+			}
+			u, err1 := strconv.ParseUint(f[1], 10, 32)
+			v, err2 := strconv.ParseUint(f[2], 10, 32)
+			w, err3 := strconv.ParseFloat(f[3], 32)
+			if err1 != nil || err2 != nil || err3 != nil {
+				return fmt.Errorf("sssp: dimacs line %d: bad arc", line)
+			}
+			if u < 1 || u > n || v < 1 || v > n {
+				return fmt.Errorf("sssp: dimacs line %d: node out of range 1..%d", line, n)
+			}
+			if err := arc(Edge{From: uint32(u - 1), To: uint32(v - 1), Weight: float32(w)}); err != nil {
+				return fmt.Errorf("sssp: dimacs line %d: %w", line, err)
+			}
+			arcs++
+		default:
+			return fmt.Errorf("sssp: dimacs line %d: unknown line type %q", line, f[0])
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	if !seen {
+		return fmt.Errorf("sssp: dimacs: missing problem line")
+	}
+	if arcs != m {
+		return fmt.Errorf("sssp: dimacs: problem line declares %d arcs, found %d", m, arcs)
+	}
+	return nil
+}
+
+// WriteDIMACS writes g as a .gr file.
+func (g *Graph) WriteDIMACS(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "p sp %d %d\n", g.NodeCount(), g.EdgeCount())
+	for u := uint32(0); u < g.NodeCount(); u++ {
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			fmt.Fprintf(bw, "a %d %d %s\n", u+1, g.targets[e]+1, strconv.FormatFloat(float64(g.weights[e]), 'g', -1, 32))
+		}
+	}
+	return bw.Flush()
+}
+
+// LoadDIMACSSources reads a .ss file and returns 0-based source nodes.
+func LoadDIMACSSources(r io.Reader) ([]uint32, error) {
+	r, err := decompress.Reader(r)
+	if err != nil {
+		return nil, err
+	}
+	sc := bufio.NewScanner(r)
+	var sources []uint32
+	for line := 1; sc.Scan(); line++ {
+		f := strings.Fields(sc.Text())
+		if len(f) == 0 || f[0] == "c" || f[0] == "p" {
+			continue
+		}
+		if f[0] != "s" || len(f) != 2 {
+			return nil, fmt.Errorf("sssp: dimacs line %d: expected 's <u>'", line)
+		}
+		u, err := strconv.ParseUint(f[1], 10, 32)
+		if err != nil || u < 1 {
+			return nil, fmt.Errorf("sssp: dimacs line %d: bad source", line)
+		}
+		sources = append(sources, uint32(u-1))
+	}
+	return sources, sc.Err()
+}
+
+// WriteDIMACSSources writes 0-based sources as a .ss file.
+func WriteDIMACSSources(w io.Writer, sources []uint32) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "p aux sp ss %d\n", len(sources))
+	for _, s := range sources {
+		fmt.Fprintf(bw, "s %d\n", s+1)
+	}
+	return bw.Flush()
+}
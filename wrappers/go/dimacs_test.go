@@ -0,0 +1,86 @@
+package sssp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+const sampleGR = `c 4-node sample
+p sp 4 5
+a 1 2 1
+a 1 3 4
+a 2 3 2
+a 3 4 1
+a 4 1 5
+`
+
+func TestDIMACSRoundTrip(t *testing.T) {
+	g, err := LoadDIMACS(strings.NewReader(sampleGR))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if g.NodeCount() != 4 || g.EdgeCount() != 5 {
+		t.Fatalf("got %d nodes %d edges", g.NodeCount(), g.EdgeCount())
+	}
+	res, err := g.Run(0, ModeBaseline)
+	if err != nil || res.Dist[3] != 4 {
+		t.Fatalf("run: %v dist %v", err, res.Dist)
+	}
+	var buf bytes.Buffer
+	if err := g.WriteDIMACS(&buf); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	again, err := LoadDIMACS(&buf)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if got, want := again.Edges(), g.Edges(); len(got) != len(want) || got[4] != want[4] {
+		t.Fatalf("round trip changed edges: %v vs %v", got, want)
+	}
+}
+
+func TestDIMACSSourcesAndErrors(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteDIMACSSources(&buf, []uint32{0, 3}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	src, err := LoadDIMACSSources(&buf)
+	if err != nil || len(src) != 2 || src[1] != 3 {
+		t.Fatalf("sources %v err %v", src, err)
+	}
+	if _, err := LoadDIMACS(strings.NewReader("p sp 2 1\na 1 3 1\n")); err == nil {
+		t.Fatalf("expected out-of-range error")
+	}
+	if _, err := LoadDIMACS(strings.NewReader("p sp 2 2\na 1 2 1\n")); err == nil {
+		t.Fatalf("expected arc count mismatch error")
+	}
+	if _, err := LoadDIMACS(strings.NewReader("p sp 2 99999999999999\na 1 2 1\n")); err == nil {
+		t.Fatalf("expected error for an inflated arc count")
+	}
+}
+
+func TestLoadDIMACSDecompresses(t *testing.T) {
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte(sampleGR))
+	zw.Close()
+	g, err := LoadDIMACS(&gz)
+	if err != nil || g.EdgeCount() != 5 {
+		t.Fatalf("gzip: %v", err)
+	}
+
+	// "p sp 3 2\na 1 2 5\na 2 3 7\n" as two zstd frames around a skippable one.
+	zst, _ := hex.DecodeString("28b52ffd240949000070207370203320320a0140c5ca502a4d180300000078797a" +
+		"28b52ffd0000810000612031203220350a612032203320370a")
+	g, err = LoadDIMACS(bytes.NewReader(zst))
+	if err != nil || g.NodeCount() != 3 || g.EdgeCount() != 2 {
+		t.Fatalf("zstd: %v", err)
+	}
+	zst[len(zst)-3] ^= 0xff // corrupt the second frame's payload
+	if _, err := LoadDIMACS(bytes.NewReader(zst)); err == nil {
+		t.Fatalf("expected corrupt stream to fail")
+	}
+}
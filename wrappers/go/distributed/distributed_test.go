@@ -0,0 +1,126 @@
+package distributed
+
+import (
+	"math/rand"
+	"net"
+	"slices"
+	"testing"
+
+	sssp "github.com/your-org/optimized-sssp-go"
+)
+
+func randomGraph(t *testing.T, n uint32, m int, seed int64) *sssp.Graph {
+	t.Helper()
+	rng := rand.New(rand.NewSource(seed))
+	edges := make([]sssp.Edge, m)
+	for i := range edges {
+		edges[i] = sssp.Edge{From: uint32(rng.Intn(int(n))), To: uint32(rng.Intn(int(n))), Weight: float32(rng.Intn(100)) / 10}
+	}
+	g, err := sssp.FromEdges(n, edges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestRunMatchesDijkstra(t *testing.T) {
+	g := randomGraph(t, 2000, 8000, 1)
+	want, err := g.RunSettled(3, sssp.ModeBaseline, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rng := rand.New(rand.NewSource(2))
+	scattered := make([]int32, g.NodeCount())
+	for v := range scattered {
+		scattered[v] = int32(rng.Intn(5))
+	}
+	for _, tc := range []struct {
+		name  string
+		owner []int32
+		parts int
+		opts  Options
+	}{
+		{"range", PartitionByRange(g, 4), 4, Options{}},
+		{"scattered", scattered, 5, Options{Delta: 2}},
+		{"one shard", make([]int32, g.NodeCount()), 1, Options{}},
+		{"tiny delta", PartitionByRange(g, 3), 3, Options{Delta: 0.05}},
+	} {
+		shards, err := Split(g, tc.owner, tc.parts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := Run(Local(shards...), 3, tc.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if !slices.Equal(res.Dist, want.Dist) {
+			t.Fatalf("%s: distances differ", tc.name)
+		}
+		for v, p := range res.Pred {
+			if _, ok := g.EdgeWeight(uint32(p), uint32(v)); p >= 0 && !ok {
+				t.Fatalf("%s: pred %d of %d is not a neighbour", tc.name, p, v)
+			}
+		}
+		if reached := uint32(len(want.ReachableNodes())); res.Stats.Settled != reached {
+			t.Fatalf("%s: settled %d, want %d", tc.name, res.Stats.Settled, reached)
+		}
+		if tc.parts > 1 && res.Messages == 0 {
+			t.Fatalf("%s: no updates exchanged", tc.name)
+		}
+	}
+}
+
+func TestRunOverRPC(t *testing.T) {
+	g := randomGraph(t, 500, 2000, 3)
+	shards, err := Split(g, PartitionByRange(g, 3), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var addrs []string
+	for _, s := range shards {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Skip("no loopback network:", err)
+		}
+		defer l.Close()
+		go Serve(l, s)
+		addrs = append(addrs, l.Addr().String())
+	}
+	c, err := Dial(addrs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	res, err := Run(c, 0, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := g.RunSettled(0, sssp.ModeBaseline, nil)
+	if !slices.Equal(res.Dist, want.Dist) {
+		t.Fatalf("distances over RPC differ")
+	}
+	if p := res.Path(res.ReachableNodes()[len(res.ReachableNodes())-1]); len(p) == 0 || p[0] != 0 {
+		t.Fatalf("path %v does not start at the source", p)
+	}
+	if _, err := Run(c, 500, Options{}); err == nil {
+		t.Fatalf("out-of-range source accepted over RPC")
+	}
+}
+
+func TestSplitErrors(t *testing.T) {
+	g := randomGraph(t, 10, 20, 4)
+	if _, err := Split(g, make([]int32, 9), 2); err == nil {
+		t.Fatalf("short owner slice accepted")
+	}
+	owner := make([]int32, 10)
+	owner[4] = 2
+	if _, err := Split(g, owner, 2); err == nil {
+		t.Fatalf("owner beyond parts accepted")
+	}
+	if _, err := NewShard(0, make([]int32, 10), []sssp.Edge{{From: 1, To: 2, Weight: -1}}); err == nil {
+		t.Fatalf("negative weight accepted")
+	}
+	if _, err := NewShard(1, make([]int32, 10), []sssp.Edge{{From: 1, To: 2, Weight: 1}}); err == nil {
+		t.Fatalf("edge of another shard accepted")
+	}
+}
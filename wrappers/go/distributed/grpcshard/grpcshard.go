@@ -0,0 +1,161 @@
+// Package grpcshard serves distributed shards over gRPC and provides the
+// matching distributed.Transport. It is a module of its own so that the
+// sssp module, and distributed's net/rpc transport, stay free of
+// dependencies.
+//
+// The service is sssp.distributed.Shard with the unary methods Start, Step
+// and Collect. Messages are the distributed package's request and reply
+// types in gob encoding, under the gRPC content subtype "gob", so no
+// protoc-generated code is involved; both ends must use this package.
+package grpcshard
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/your-org/optimized-sssp-go/distributed"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+)
+
+// codecName is the content subtype requests carry: application/grpc+gob.
+const codecName = "gob"
+
+func init() { encoding.RegisterCodec(gobCodec{}) }
+
+// gobCodec marshals messages with encoding/gob, one value per message.
+type gobCodec struct{}
+
+func (gobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+func (gobCodec) Name() string { return codecName }
+
+const serviceName = "sssp.distributed.Shard"
+
+// serviceDesc describes the Shard service by hand, in place of generated
+// code; each handler decodes its request and calls the shard.
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Start", Handler: unary("Start", func(s *distributed.Shard, source *uint32) (distributed.StartReply, error) {
+			return s.Start(*source)
+		})},
+		{MethodName: "Step", Handler: unary("Step", func(s *distributed.Shard, req *distributed.StepRequest) (distributed.StepReply, error) {
+			return s.Step(*req)
+		})},
+		{MethodName: "Collect", Handler: unary("Collect", func(s *distributed.Shard, _ *int) (distributed.CollectReply, error) {
+			return s.Collect()
+		})},
+	},
+	Metadata: "grpcshard",
+}
+
+// unary adapts call to the handler of method, for the service registered
+// with a *distributed.Shard.
+func unary[Req, Reply any](method string, call func(*distributed.Shard, *Req) (Reply, error)) func(any, context.Context, func(any) error, grpc.UnaryServerInterceptor) (any, error) {
+	return func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+		req := new(Req)
+		if err := dec(req); err != nil {
+			return nil, err
+		}
+		s := srv.(*distributed.Shard)
+		if interceptor == nil {
+			return call(s, req)
+		}
+		info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/" + method}
+		return interceptor(ctx, req, info, func(ctx context.Context, r any) (any, error) {
+			return call(s, r.(*Req))
+		})
+	}
+}
+
+// Register adds the Shard service for s to a gRPC server the caller runs,
+// with its own credentials and interceptors.
+func Register(srv *grpc.Server, s *distributed.Shard) {
+	srv.RegisterService(&serviceDesc, s)
+}
+
+// Serve answers Client requests for s on connections accepted from l,
+// without transport security. It returns nil once l is closed.
+func Serve(l net.Listener, s *distributed.Shard, opts ...grpc.ServerOption) error {
+	srv := grpc.NewServer(opts...)
+	Register(srv, s)
+	err := srv.Serve(l)
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+// Client is a distributed.Transport to shards served by Serve or Register;
+// shard i is at the i-th address.
+type Client struct {
+	conns []*grpc.ClientConn
+}
+
+var _ distributed.Transport = (*Client)(nil)
+
+// Dial connects to the shards. Without options the connections are
+// plaintext; pass grpc.WithTransportCredentials to secure them. gRPC
+// connects lazily, so an unreachable shard shows up as an error from the
+// first call to it.
+func Dial(addrs []string, opts ...grpc.DialOption) (*Client, error) {
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+	opts = append(opts, grpc.WithDefaultCallOptions(grpc.CallContentSubtype(codecName)))
+	c := &Client{}
+	for _, a := range addrs {
+		conn, err := grpc.NewClient(a, opts...)
+		if err != nil {
+			c.Close()
+			return nil, fmt.Errorf("grpcshard: dial shard %d at %s: %w", len(c.conns), a, err)
+		}
+		c.conns = append(c.conns, conn)
+	}
+	return c, nil
+}
+
+// Close closes the connections.
+func (c *Client) Close() error {
+	var first error
+	for _, conn := range c.conns {
+		if err := conn.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+func (c *Client) Shards() int { return len(c.conns) }
+
+func (c *Client) Start(i int, source uint32) (r distributed.StartReply, err error) {
+	err = c.conns[i].Invoke(context.Background(), "/"+serviceName+"/Start", &source, &r)
+	return r, err
+}
+
+func (c *Client) Step(i int, req distributed.StepRequest) (r distributed.StepReply, err error) {
+	err = c.conns[i].Invoke(context.Background(), "/"+serviceName+"/Step", &req, &r)
+	return r, err
+}
+
+func (c *Client) Collect(i int) (r distributed.CollectReply, err error) {
+	err = c.conns[i].Invoke(context.Background(), "/"+serviceName+"/Collect", new(int), &r)
+	return r, err
+}
@@ -0,0 +1,53 @@
+package grpcshard
+
+import (
+	"math/rand"
+	"net"
+	"slices"
+	"testing"
+
+	sssp "github.com/your-org/optimized-sssp-go"
+	"github.com/your-org/optimized-sssp-go/distributed"
+)
+
+func TestRunOverGRPC(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	edges := make([]sssp.Edge, 2000)
+	for i := range edges {
+		edges[i] = sssp.Edge{From: uint32(rng.Intn(500)), To: uint32(rng.Intn(500)), Weight: float32(rng.Intn(100)) / 10}
+	}
+	g, err := sssp.FromEdges(500, edges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shards, err := distributed.Split(g, distributed.PartitionByRange(g, 3), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var addrs []string
+	for _, s := range shards {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Skip("no loopback network:", err)
+		}
+		defer l.Close()
+		go Serve(l, s)
+		addrs = append(addrs, l.Addr().String())
+	}
+	c, err := Dial(addrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	res, err := distributed.Run(c, 0, distributed.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := g.RunSettled(0, sssp.ModeBaseline, nil)
+	if !slices.Equal(res.Dist, want.Dist) {
+		t.Fatalf("distances over gRPC differ")
+	}
+	if res.Messages == 0 {
+		t.Fatalf("no updates exchanged")
+	}
+}
@@ -0,0 +1,176 @@
+package distributed
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	sssp "github.com/your-org/optimized-sssp-go"
+)
+
+// Transport reaches the shards of a graph, numbered from 0.
+type Transport interface {
+	Shards() int
+	Start(shard int, source uint32) (StartReply, error)
+	Step(shard int, req StepRequest) (StepReply, error)
+	Collect(shard int) (CollectReply, error)
+}
+
+// Options configure Run.
+type Options struct {
+	// Delta is how far the bound rises per bucket; 0 means three times the
+	// average edge weight, as ModeStoc uses. Smaller values mean more
+	// supersteps and fewer repeated expansions.
+	Delta float32
+	// MaxSupersteps stops a runaway query with an error; 0 means no limit.
+	MaxSupersteps int
+}
+
+// Result is a distributed query's outcome.
+type Result struct {
+	// Result holds the distances and predecessors of every node, gathered
+	// from the shards. Stats.Relaxations counts edge relaxations and
+	// Stats.Settled reachable nodes.
+	sssp.Result
+	Supersteps int
+	// Messages counts the updates exchanged between shards.
+	Messages uint64
+	// Expanded counts node expansions; above the reachable node count it
+	// shows the repeated work a smaller Delta would avoid.
+	Expanded uint64
+}
+
+// Run computes distances from source over the shards behind t. Shards
+// run each superstep concurrently; the calling goroutine coordinates.
+func Run(t Transport, source uint32, opts Options) (*Result, error) {
+	k := t.Shards()
+	if k == 0 {
+		return nil, fmt.Errorf("distributed: no shards")
+	}
+	starts := make([]StartReply, k)
+	if err := each(k, func(i int) (err error) {
+		starts[i], err = t.Start(i, source)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	var owned, edges uint64
+	var wsum float64
+	for _, s := range starts {
+		if s.Nodes != starts[0].Nodes {
+			return nil, fmt.Errorf("distributed: shards disagree on the node count (%d, %d)", starts[0].Nodes, s.Nodes)
+		}
+		owned += uint64(s.Owned)
+		edges += s.Edges
+		wsum += s.WeightSum
+	}
+	n := starts[0].Nodes
+	if owned != uint64(n) {
+		return nil, fmt.Errorf("distributed: shards own %d of %d nodes", owned, n)
+	}
+	delta := opts.Delta
+	if delta <= 0 {
+		delta = 1
+		if edges > 0 && wsum > 0 {
+			delta = float32(3 * wsum / float64(edges))
+		}
+	}
+
+	res := &Result{}
+	inbox := make([][]Update, k)
+	bound := delta
+	for {
+		if opts.MaxSupersteps > 0 && res.Supersteps >= opts.MaxSupersteps {
+			return nil, fmt.Errorf("distributed: no convergence after %d supersteps", res.Supersteps)
+		}
+		replies := make([]StepReply, k)
+		if err := each(k, func(i int) (err error) {
+			replies[i], err = t.Step(i, StepRequest{Bound: bound, Updates: inbox[i]})
+			return err
+		}); err != nil {
+			return nil, err
+		}
+		res.Supersteps++
+		next := float32(math.Inf(1))
+		for i := range inbox {
+			inbox[i] = inbox[i][:0]
+		}
+		for _, r := range replies {
+			res.Expanded += r.Expanded
+			res.Stats.Relaxations += r.Relaxations
+			next = min(next, r.Next)
+			for to, ups := range r.Outbox {
+				if to >= k {
+					return nil, fmt.Errorf("distributed: update for shard %d of %d", to, k)
+				}
+				inbox[to] = append(inbox[to], ups...)
+				res.Messages += uint64(len(ups))
+				for _, u := range ups {
+					next = min(next, u.Dist)
+				}
+			}
+		}
+		if math.IsInf(float64(next), 1) {
+			break
+		}
+		if next >= bound {
+			bound = (float32(math.Floor(float64(next/delta))) + 1) * delta
+			if bound <= next { // float rounding at huge distances
+				bound = math.Nextafter32(next, float32(math.Inf(1)))
+			}
+		}
+	}
+
+	res.Dist, res.Pred = make([]float32, n), make([]int32, n)
+	for v := range res.Dist {
+		res.Dist[v], res.Pred[v] = sssp.Unreachable, -1
+	}
+	var mu sync.Mutex
+	if err := each(k, func(i int) error {
+		c, err := t.Collect(i)
+		if err != nil {
+			return err
+		}
+		if len(c.Dist) != len(c.Nodes) || len(c.Pred) != len(c.Nodes) {
+			return fmt.Errorf("distributed: shard %d returned %d nodes, %d distances", i, len(c.Nodes), len(c.Dist))
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for j, v := range c.Nodes {
+			if v >= n {
+				return fmt.Errorf("distributed: shard %d returned node %d of %d", i, v, n)
+			}
+			res.Dist[v], res.Pred[v] = c.Dist[j], c.Pred[j]
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	for _, d := range res.Dist {
+		if d != sssp.Unreachable {
+			res.Stats.Settled++
+		}
+	}
+	res.Stats.Delta = delta
+	return res, nil
+}
+
+// each runs fn for 0..k-1 concurrently and returns the first error.
+func each(k int, fn func(i int) error) error {
+	errs := make([]error, k)
+	var wg sync.WaitGroup
+	for i := 0; i < k; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,285 @@
+// Package distributed runs single-source shortest paths on a graph split
+// into shards, each holding the edges out of its own nodes, so the graph
+// never has to fit on one machine.
+//
+// A query is a sequence of bulk-synchronous supersteps in the manner of
+// delta-stepping. In each, every shard takes the distance updates sent to
+// it, expands its nodes closer than the current bound, and returns the
+// updates its edges produce for nodes other shards own. The coordinator
+// routes those updates to their owners and raises the bound by delta once
+// no shard has work below it. The search ends when no updates are in
+// flight and no shard has work left.
+//
+// Shards are reached through a Transport: Local for shards in this
+// process, Serve and Dial for shards behind net/rpc on other machines, or
+// the gRPC transport of the distributed/grpcshard module, which holds the
+// gRPC dependency so this module keeps none.
+package distributed
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sync"
+
+	sssp "github.com/your-org/optimized-sssp-go"
+)
+
+// Update is a tentative distance for a node, found through predecessor
+// Pred (a global node ID).
+type Update struct {
+	Node uint32
+	Dist float32
+	Pred int32
+}
+
+// Shard holds one part of a graph: the out-edges of the nodes it owns,
+// with targets as global node IDs, and their distances during a query.
+// Besides its edges it keeps two arrays with one entry per graph node,
+// the owner of each node and its local index. A Shard serves one query
+// at a time.
+type Shard struct {
+	id    int
+	parts int // shards in the graph
+	owner []int32
+	local []uint32 // local index of each owned node
+	nodes []uint32 // global ID of each local node
+	off   []uint32
+	tgt   []uint32
+	wts   []float32
+	wsum  float64
+
+	mu   sync.Mutex
+	dist []float32
+	pred []int32
+	work nodeHeap
+	// sent is the best distance this query has sent for each remote node,
+	// so updates that cannot help are not sent again.
+	sent map[uint32]float32
+}
+
+// NewShard builds shard id of a graph whose node v belongs to shard
+// owner[v]. edges are the edges out of the shard's nodes, in any order;
+// their weights must be non-negative.
+func NewShard(id int, owner []int32, edges []sssp.Edge) (*Shard, error) {
+	n := uint32(len(owner))
+	s := &Shard{id: id, owner: owner, local: make([]uint32, n)}
+	for v, o := range owner {
+		s.parts = max(s.parts, int(o)+1)
+		if o == int32(id) {
+			s.local[v] = uint32(len(s.nodes))
+			s.nodes = append(s.nodes, uint32(v))
+		}
+	}
+	s.off = make([]uint32, len(s.nodes)+1)
+	for _, e := range edges {
+		switch {
+		case e.From >= n || e.To >= n:
+			return nil, fmt.Errorf("distributed: edge %d->%d out of range for %d nodes", e.From, e.To, n)
+		case owner[e.From] != int32(id):
+			return nil, fmt.Errorf("distributed: edge %d->%d leaves node %d of shard %d, not %d", e.From, e.To, e.From, owner[e.From], id)
+		case !(e.Weight >= 0):
+			return nil, fmt.Errorf("distributed: edge %d->%d has weight %v; weights must be non-negative", e.From, e.To, e.Weight)
+		}
+		s.off[s.local[e.From]+1]++
+	}
+	for i := range s.nodes {
+		s.off[i+1] += s.off[i]
+	}
+	s.tgt, s.wts = make([]uint32, len(edges)), make([]float32, len(edges))
+	next := append([]uint32(nil), s.off[:len(s.nodes)]...)
+	for _, e := range edges {
+		l := s.local[e.From]
+		s.tgt[next[l]], s.wts[next[l]] = e.To, e.Weight
+		next[l]++
+		if !math.IsInf(float64(e.Weight), 1) {
+			s.wsum += float64(e.Weight)
+		}
+	}
+	return s, nil
+}
+
+// Split cuts g into shards by owner, which assigns each node to one of
+// parts shards, as PartitionByRange does or a METIS partition would.
+func Split(g *sssp.Graph, owner []int32, parts int) ([]*Shard, error) {
+	if len(owner) != int(g.NodeCount()) {
+		return nil, fmt.Errorf("distributed: %d owners for %d nodes", len(owner), g.NodeCount())
+	}
+	edges := make([][]sssp.Edge, parts)
+	for u, o := range owner {
+		if o < 0 || int(o) >= parts {
+			return nil, fmt.Errorf("distributed: node %d assigned to shard %d of %d", u, o, parts)
+		}
+		g.ForEachNeighbor(uint32(u), func(v uint32, w float32) bool {
+			edges[o] = append(edges[o], sssp.Edge{From: uint32(u), To: v, Weight: w})
+			return true
+		})
+	}
+	shards := make([]*Shard, parts)
+	for i := range shards {
+		s, err := NewShard(i, owner, edges[i])
+		if err != nil {
+			return nil, err
+		}
+		shards[i] = s
+	}
+	return shards, nil
+}
+
+// PartitionByRange assigns nodes to parts shards in contiguous ranges of
+// about equal nodes plus edges.
+func PartitionByRange(g *sssp.Graph, parts int) []int32 {
+	n := g.NodeCount()
+	total := uint64(n) + uint64(g.EdgeCount())
+	owner := make([]int32, n)
+	var seen uint64
+	for u := uint32(0); u < n; u++ {
+		owner[u] = int32(min(seen*uint64(parts)/max(total, 1), uint64(parts-1)))
+		seen += 1 + uint64(g.Degree(u))
+	}
+	return owner
+}
+
+// StartReply describes a shard when a query starts.
+type StartReply struct {
+	Nodes     uint32  // nodes in the whole graph
+	Owned     uint32  // nodes the shard owns
+	Edges     uint64  // edges the shard holds
+	WeightSum float64 // sum of their finite weights
+}
+
+// Start resets the shard for a query from source.
+func (s *Shard) Start(source uint32) (StartReply, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := uint32(len(s.owner))
+	if source >= n {
+		return StartReply{}, fmt.Errorf("distributed: source %d out of range for %d nodes", source, n)
+	}
+	s.dist, s.pred = make([]float32, len(s.nodes)), make([]int32, len(s.nodes))
+	for i := range s.dist {
+		s.dist[i], s.pred[i] = sssp.Unreachable, -1
+	}
+	s.work, s.sent = s.work[:0], make(map[uint32]float32)
+	if s.owner[source] == int32(s.id) {
+		l := s.local[source]
+		s.dist[l] = 0
+		heap.Push(&s.work, nodeItem{l, 0})
+	}
+	return StartReply{Nodes: n, Owned: uint32(len(s.nodes)), Edges: uint64(len(s.tgt)), WeightSum: s.wsum}, nil
+}
+
+// StepRequest is one superstep's input to a shard.
+type StepRequest struct {
+	// Bound is the distance below which nodes are expanded this step.
+	Bound float32
+	// Updates are the updates other shards sent to this one.
+	Updates []Update
+}
+
+// StepReply is one superstep's output from a shard.
+type StepReply struct {
+	// Outbox[i] holds the updates for shard i, at most one per node.
+	Outbox [][]Update
+	// Next is the least distance of a node waiting to be expanded, or
+	// +Inf if none is.
+	Next float32
+	// Expanded and Relaxations count the step's work.
+	Expanded, Relaxations uint64
+}
+
+// Step runs one superstep: it applies req.Updates, then expands nodes
+// below req.Bound in distance order, local edges feeding back into the
+// step and remote ones into the outbox. A node whose distance drops later
+// is expanded again.
+func (s *Shard) Step(req StepRequest) (StepReply, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.dist == nil {
+		return StepReply{}, fmt.Errorf("distributed: shard %d stepped before Start", s.id)
+	}
+	for _, u := range req.Updates {
+		if int(u.Node) >= len(s.owner) || s.owner[u.Node] != int32(s.id) {
+			return StepReply{}, fmt.Errorf("distributed: shard %d sent update for node %d it does not own", s.id, u.Node)
+		}
+		if l := s.local[u.Node]; u.Dist < s.dist[l] {
+			s.dist[l], s.pred[l] = u.Dist, u.Pred
+			heap.Push(&s.work, nodeItem{l, u.Dist})
+		}
+	}
+	var r StepReply
+	out := make(map[uint32]Update)
+	for len(s.work) > 0 {
+		it := s.work[0]
+		if it.dist > s.dist[it.node] {
+			heap.Pop(&s.work)
+			continue
+		}
+		if it.dist >= req.Bound {
+			break
+		}
+		heap.Pop(&s.work)
+		r.Expanded++
+		u := s.nodes[it.node]
+		for e := s.off[it.node]; e < s.off[it.node+1]; e++ {
+			v, nd := s.tgt[e], it.dist+s.wts[e]
+			r.Relaxations++
+			if s.owner[v] != int32(s.id) {
+				if best, ok := s.sent[v]; (!ok || nd < best) && !math.IsInf(float64(nd), 1) {
+					s.sent[v] = nd
+					out[v] = Update{Node: v, Dist: nd, Pred: int32(u)}
+				}
+				continue
+			}
+			if l := s.local[v]; nd < s.dist[l] {
+				s.dist[l], s.pred[l] = nd, int32(u)
+				heap.Push(&s.work, nodeItem{l, nd})
+			}
+		}
+	}
+	r.Next = sssp.Unreachable
+	if len(s.work) > 0 {
+		r.Next = s.work[0].dist
+	}
+	r.Outbox = make([][]Update, s.parts)
+	for v, u := range out {
+		r.Outbox[s.owner[v]] = append(r.Outbox[s.owner[v]], u)
+	}
+	return r, nil
+}
+
+// CollectReply holds a shard's final distances.
+type CollectReply struct {
+	Nodes []uint32 // global IDs of the shard's nodes
+	Dist  []float32
+	Pred  []int32
+}
+
+// Collect returns the distances of the shard's nodes after a query.
+func (s *Shard) Collect() (CollectReply, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.dist == nil {
+		return CollectReply{}, fmt.Errorf("distributed: shard %d collected before Start", s.id)
+	}
+	return CollectReply{Nodes: s.nodes, Dist: s.dist, Pred: s.pred}, nil
+}
+
+// nodeItem is a local node waiting for expansion at dist.
+type nodeItem struct {
+	node uint32
+	dist float32
+}
+
+type nodeHeap []nodeItem
+
+func (h nodeHeap) Len() int           { return len(h) }
+func (h nodeHeap) Less(i, j int) bool { return h[i].dist < h[j].dist }
+func (h nodeHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *nodeHeap) Push(x any)        { *h = append(*h, x.(nodeItem)) }
+func (h *nodeHeap) Pop() any {
+	old := *h
+	it := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return it
+}
@@ -0,0 +1,106 @@
+package distributed
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/rpc"
+)
+
+// Local returns a Transport calling shards in this process directly.
+func Local(shards ...*Shard) Transport { return local(shards) }
+
+type local []*Shard
+
+func (l local) Shards() int { return len(l) }
+
+func (l local) Start(i int, source uint32) (StartReply, error) { return l[i].Start(source) }
+
+func (l local) Step(i int, req StepRequest) (StepReply, error) { return l[i].Step(req) }
+
+func (l local) Collect(i int) (CollectReply, error) { return l[i].Collect() }
+
+// Serve answers Client requests for s on connections accepted from l. It
+// returns nil once l is closed. The protocol is net/rpc with gob encoding.
+func Serve(l net.Listener, s *Shard) error {
+	srv := rpc.NewServer()
+	if err := srv.RegisterName("Shard", &rpcShard{s}); err != nil {
+		return err
+	}
+	for {
+		conn, err := l.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("distributed: accept: %w", err)
+		}
+		go srv.ServeConn(conn)
+	}
+}
+
+// rpcShard adapts Shard to net/rpc's method signatures.
+type rpcShard struct{ s *Shard }
+
+func (r *rpcShard) Start(source *uint32, reply *StartReply) (err error) {
+	*reply, err = r.s.Start(*source)
+	return err
+}
+
+func (r *rpcShard) Step(req *StepRequest, reply *StepReply) (err error) {
+	*reply, err = r.s.Step(*req)
+	return err
+}
+
+func (r *rpcShard) Collect(_ *int, reply *CollectReply) (err error) {
+	*reply, err = r.s.Collect()
+	return err
+}
+
+// Client is a Transport to shards served by Serve; shard i is at the i-th
+// address dialled.
+type Client struct {
+	conns []*rpc.Client
+}
+
+// Dial connects to the shards over TCP.
+func Dial(addrs ...string) (*Client, error) {
+	c := &Client{}
+	for _, a := range addrs {
+		conn, err := rpc.Dial("tcp", a)
+		if err != nil {
+			c.Close()
+			return nil, fmt.Errorf("distributed: dial shard %d at %s: %w", len(c.conns), a, err)
+		}
+		c.conns = append(c.conns, conn)
+	}
+	return c, nil
+}
+
+// Close closes the connections.
+func (c *Client) Close() error {
+	var first error
+	for _, conn := range c.conns {
+		if err := conn.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+func (c *Client) Shards() int { return len(c.conns) }
+
+func (c *Client) Start(i int, source uint32) (r StartReply, err error) {
+	err = c.conns[i].Call("Shard.Start", &source, &r)
+	return r, err
+}
+
+func (c *Client) Step(i int, req StepRequest) (r StepReply, err error) {
+	err = c.conns[i].Call("Shard.Step", &req, &r)
+	return r, err
+}
+
+func (c *Client) Collect(i int) (r CollectReply, err error) {
+	err = c.conns[i].Call("Shard.Collect", new(int), &r)
+	return r, err
+}
@@ -0,0 +1,74 @@
+package sssp
+
+import (
+	"math"
+	"slices"
+)
+
+// DistanceStats summarizes the distances of a result. Distances cover the
+// reachable nodes other than the source; with none of them, Min, Mean and
+// Eccentricity are 0.
+type DistanceStats struct {
+	Reachable   int // nodes with a finite distance, the source included
+	Unreachable int
+
+	Min, Mean float32
+	// Eccentricity is the largest finite distance: how far the farthest
+	// reachable node is from the source.
+	Eccentricity float32
+
+	// HopCounts[h] is the number of nodes whose shortest path, following
+	// Pred, has h edges; HopCounts[0] is the source. Its length is the hop
+	// eccentricity plus one.
+	HopCounts []int
+
+	sorted []float32
+}
+
+// DistanceStats computes the reachable count, distance and hop-count
+// distributions of r in one pass over Dist and Pred.
+func (r *Result) DistanceStats() DistanceStats {
+	var s DistanceStats
+	var sum float64
+	for v, d := range r.Dist {
+		switch {
+		case math.IsInf(float64(d), 1):
+			s.Unreachable++
+			continue
+		case v < len(r.Pred) && r.Pred[v] < 0:
+			s.Reachable++ // the source
+			continue
+		}
+		s.Reachable++
+		s.sorted = append(s.sorted, d)
+		sum += float64(d)
+	}
+	if len(s.sorted) > 0 {
+		slices.Sort(s.sorted)
+		s.Min = s.sorted[0]
+		s.Eccentricity = s.sorted[len(s.sorted)-1]
+		s.Mean = float32(sum / float64(len(s.sorted)))
+	}
+	if len(r.Pred) == len(r.Dist) {
+		for _, h := range r.hops() {
+			if h < 0 {
+				continue
+			}
+			for int(h) >= len(s.HopCounts) {
+				s.HopCounts = append(s.HopCounts, 0)
+			}
+			s.HopCounts[h]++
+		}
+	}
+	return s
+}
+
+// Percentile returns the nearest-rank p-th percentile (0-100) of the
+// distances, or 0 if no node besides the source is reachable.
+func (s DistanceStats) Percentile(p float64) float32 {
+	if len(s.sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(s.sorted))))
+	return s.sorted[min(max(rank, 1), len(s.sorted))-1]
+}
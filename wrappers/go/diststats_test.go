@@ -0,0 +1,37 @@
+package sssp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistanceStats(t *testing.T) {
+	// 0->1 (1), 1->2 (2), 0->3 (4), 3->4 (1); 5 unreachable.
+	g, err := FromEdges(6, []Edge{{0, 1, 1}, {1, 2, 2}, {0, 3, 4}, {3, 4, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := g.Run(0, ModeBaseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := res.DistanceStats()
+	if s.Reachable != 5 || s.Unreachable != 1 {
+		t.Fatalf("reachable %d, unreachable %d", s.Reachable, s.Unreachable)
+	}
+	if s.Min != 1 || s.Mean != 3.25 || s.Eccentricity != 5 {
+		t.Fatalf("min %v, mean %v, eccentricity %v", s.Min, s.Mean, s.Eccentricity)
+	}
+	if p := []float32{s.Percentile(0), s.Percentile(50), s.Percentile(75), s.Percentile(100)}; p[0] != 1 || p[1] != 3 || p[2] != 4 || p[3] != 5 {
+		t.Fatalf("percentiles %v", p)
+	}
+	if h := s.HopCounts; len(h) != 3 || h[0] != 1 || h[1] != 2 || h[2] != 2 {
+		t.Fatalf("hop counts %v", h)
+	}
+
+	inf := float32(math.Inf(1))
+	empty := (&Result{Dist: []float32{0, inf}, Pred: []int32{-1, -1}}).DistanceStats()
+	if empty.Reachable != 1 || empty.Eccentricity != 0 || empty.Percentile(50) != 0 || len(empty.HopCounts) != 1 {
+		t.Fatalf("source only: %+v", empty)
+	}
+}
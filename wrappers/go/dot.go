@@ -0,0 +1,146 @@
+package sssp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DOTOptions controls Graphviz output. Meant for small graphs such as
+// counterexamples; nothing is elided for large ones.
+type DOTOptions struct {
+	// Names labels nodes; nil uses node ids.
+	Names []string
+	// Result adds distances to node labels and draws the shortest-path tree
+	// edges bold (Graph.WriteDOT only; Result.WriteDOT draws its own tree).
+	Result *Result
+	// Highlight lists targets whose shortest path is drawn in red.
+	Highlight []uint32
+}
+
+// WriteDOT writes g as a Graphviz digraph with weights as edge labels.
+func (g *Graph) WriteDOT(w io.Writer, opts DOTOptions) error {
+	n := g.NodeCount()
+	if opts.Names != nil && len(opts.Names) != int(n) {
+		return fmt.Errorf("sssp: dot: %d names for %d nodes", len(opts.Names), n)
+	}
+	r := opts.Result
+	if r == nil && len(opts.Highlight) > 0 {
+		return fmt.Errorf("sssp: dot: Highlight needs a Result")
+	}
+	if r != nil && (len(r.Dist) != int(n) || len(r.Pred) != int(n)) {
+		return fmt.Errorf("sssp: dot: result covers %d nodes, graph has %d", len(r.Dist), n)
+	}
+	hot := highlighted(r, opts.Highlight)
+	bw := bufio.NewWriter(w)
+	bw.WriteString("digraph sssp {\n  node [shape=circle];\n")
+	for u := uint32(0); u < n; u++ {
+		writeDOTNode(bw, u, opts.Names, r, hot)
+	}
+	for u := uint32(0); u < n; u++ {
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			v, wt := g.targets[e], g.weights[e]
+			attrs := `label="` + formatWeight(wt) + `"`
+			if r != nil && r.Pred[v] == int32(u) && r.Dist[u]+wt == r.Dist[v] {
+				attrs += ", style=bold"
+				if hot[v] {
+					attrs += ", color=red, fontcolor=red"
+				}
+			}
+			fmt.Fprintf(bw, "  n%d -> n%d [%s];\n", u, v, attrs)
+		}
+	}
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
+
+// WriteDOT writes only the shortest-path tree of r: reachable nodes labelled
+// with their distances, and an edge from each node's predecessor labelled
+// with the distance it adds. opts.Result is ignored.
+func (r *Result) WriteDOT(w io.Writer, opts DOTOptions) error {
+	n := len(r.Dist)
+	if len(r.Pred) != n {
+		return fmt.Errorf("sssp: dot: %d distances but %d predecessors", n, len(r.Pred))
+	}
+	if opts.Names != nil && len(opts.Names) != n {
+		return fmt.Errorf("sssp: dot: %d names for %d nodes", len(opts.Names), n)
+	}
+	hot := highlighted(r, opts.Highlight)
+	bw := bufio.NewWriter(w)
+	bw.WriteString("digraph sssp_tree {\n  node [shape=circle];\n")
+	for v := 0; v < n; v++ {
+		if !math.IsInf(float64(r.Dist[v]), 1) {
+			writeDOTNode(bw, uint32(v), opts.Names, r, hot)
+		}
+	}
+	for v := 0; v < n; v++ {
+		p := r.Pred[v]
+		if p < 0 || int(p) >= n {
+			continue
+		}
+		attrs := `label="` + formatWeight(r.Dist[v]-r.Dist[p]) + `"`
+		if hot[uint32(v)] {
+			attrs += ", color=red, fontcolor=red"
+		}
+		fmt.Fprintf(bw, "  n%d -> n%d [%s];\n", p, v, attrs)
+	}
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
+
+// highlighted marks every node on the paths to targets.
+func highlighted(r *Result, targets []uint32) map[uint32]bool {
+	hot := make(map[uint32]bool)
+	for _, t := range targets {
+		for _, v := range r.Path(t) {
+			hot[v] = true
+		}
+	}
+	return hot
+}
+
+func writeDOTNode(bw *bufio.Writer, u uint32, names []string, r *Result, hot map[uint32]bool) {
+	label := strconv.FormatUint(uint64(u), 10)
+	if names != nil {
+		label = names[u]
+	}
+	if r != nil {
+		d := "inf"
+		if !math.IsInf(float64(r.Dist[u]), 1) {
+			d = formatWeight(r.Dist[u])
+		}
+		label += "\nd=" + d
+	}
+	attrs := "label=" + dotQuote(label)
+	if hot[u] {
+		attrs += ", color=red, fontcolor=red, penwidth=2"
+	}
+	fmt.Fprintf(bw, "  n%d [%s];\n", u, attrs)
+}
+
+func formatWeight(w float32) string {
+	return strconv.FormatFloat(float64(w), 'g', -1, 32)
+}
+
+// dotQuote renders s as a DOT string literal.
+func dotQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, c := range s {
+		switch c {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(c)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+		default:
+			b.WriteRune(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package sssp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteDOTHighlightsPath(t *testing.T) {
+	g, err := LoadDIMACS(strings.NewReader(sampleGR))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	res, err := g.Run(0, ModeBaseline)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if p := res.Path(3); len(p) != 4 || p[0] != 0 || p[1] != 1 || p[2] != 2 || p[3] != 3 {
+		t.Fatalf("path = %v", p)
+	}
+
+	var buf bytes.Buffer
+	names := []string{"a", `b"q`, "c", "d"}
+	if err := g.WriteDOT(&buf, DOTOptions{Names: names, Result: &res, Highlight: []uint32{3}}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`n1 [label="b\"q\nd=1", color=red`,
+		`n2 -> n3 [label="1", style=bold, color=red`,
+		`n0 -> n2 [label="4"];`, // not on the tree
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	if err := res.WriteDOT(&buf, DOTOptions{}); err != nil {
+		t.Fatalf("tree: %v", err)
+	}
+	if got := strings.Count(buf.String(), "->"); got != 3 {
+		t.Fatalf("tree has %d edges, want 3:\n%s", got, buf.String())
+	}
+	if err := g.WriteDOT(&buf, DOTOptions{Highlight: []uint32{1}}); err == nil {
+		t.Fatalf("expected Highlight without Result to fail")
+	}
+}
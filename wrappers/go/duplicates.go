@@ -0,0 +1,136 @@
+package sssp
+
+import "fmt"
+
+// DuplicatePolicy says what to do with parallel edges, several edges with
+// the same endpoints. Builders such as FromEdges keep them all, in input
+// order; MergeDuplicates applies one of the other policies afterwards, so
+// results do not depend on which duplicate an algorithm happens to relax.
+type DuplicatePolicy uint8
+
+const (
+	KeepMultiEdges DuplicatePolicy = iota // keep every edge, each addressed by its edge ID
+	KeepMin                               // one edge with the smallest weight
+	KeepLast                              // one edge with the weight of the last in CSR order
+	SumWeights                            // one edge with the sum of the weights
+)
+
+func (p DuplicatePolicy) String() string {
+	switch p {
+	case KeepMultiEdges:
+		return "multi"
+	case KeepMin:
+		return "min"
+	case KeepLast:
+		return "last"
+	case SumWeights:
+		return "sum"
+	}
+	return fmt.Sprintf("DuplicatePolicy(%d)", uint8(p))
+}
+
+// ParseDuplicatePolicy accepts the names String returns.
+func ParseDuplicatePolicy(s string) (DuplicatePolicy, error) {
+	for p := KeepMultiEdges; p <= SumWeights; p++ {
+		if s == p.String() {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("sssp: unknown duplicate policy %q (want multi, min, last or sum)", s)
+}
+
+// MergeDuplicates collapses parallel edges by policy in one pass, keeping
+// each surviving edge where the first of its group was, and reports how
+// many edges it removed. KeepMultiEdges leaves g unchanged. Arrays passed
+// to NewGraphCSR are copied rather than modified, and edge IDs change.
+func (g *Graph) MergeDuplicates(policy DuplicatePolicy) (int, error) {
+	if policy > SumWeights {
+		return 0, fmt.Errorf("sssp: unknown duplicate policy %d", policy)
+	}
+	if policy == KeepMultiEdges {
+		return 0, nil
+	}
+	n := g.NodeCount()
+	stamp := make([]uint32, n) // stamp[v] == u+1: slot[v] holds u's edge to v
+	slot := make([]uint32, n)
+	var idx []int32 // old ID of each kept edge
+	if g.keepsEdgeIDs() {
+		idx = make([]int32, 0, len(g.targets))
+	}
+	kept, removed := uint32(0), 0
+	for u := uint32(0); u < n; u++ {
+		lo, hi := g.offsets[u], g.offsets[u+1]
+		if removed > 0 {
+			g.offsets[u] = kept
+		}
+		for e := lo; e < hi; e++ {
+			v, w := g.targets[e], g.weights[e]
+			if stamp[v] != u+1 {
+				stamp[v], slot[v] = u+1, kept
+				if removed > 0 {
+					g.targets[kept], g.weights[kept] = v, w
+				}
+				if idx != nil {
+					idx = append(idx, int32(e))
+				}
+				kept++
+				continue
+			}
+			if removed == 0 {
+				g.own()
+			}
+			removed++
+			switch k := slot[v]; policy {
+			case KeepMin:
+				if w < g.weights[k] {
+					g.weights[k] = w
+				}
+			case KeepLast:
+				g.weights[k] = w
+			case SumWeights:
+				g.weights[k] += w
+			}
+		}
+	}
+	if removed > 0 {
+		g.offsets[n] = kept
+		g.targets, g.weights = g.targets[:kept], g.weights[:kept]
+		g.moveEdges(idx)
+		g.dropReverse()
+	}
+	return removed, nil
+}
+
+// EdgeIDs returns the IDs of the edges from u to v in CSR order. An edge's
+// ID is its index in the CSR targets and weights, stable until g changes;
+// with KeepMultiEdges it tells parallel edges apart.
+func (g *Graph) EdgeIDs(u, v uint32) []int {
+	if u >= g.NodeCount() {
+		return nil
+	}
+	var ids []int
+	for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+		if g.targets[e] == v {
+			ids = append(ids, int(e))
+		}
+	}
+	return ids
+}
+
+// EdgeByID returns the edge with the given ID, and false if there is none.
+func (g *Graph) EdgeByID(id int) (Edge, bool) {
+	if id < 0 || id >= len(g.targets) {
+		return Edge{}, false
+	}
+	// The source is the last node whose edges start at or before id.
+	lo, hi := uint32(0), g.NodeCount()
+	for lo+1 < hi {
+		mid := lo + (hi-lo)/2
+		if int(g.offsets[mid]) <= id {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return Edge{From: lo, To: g.targets[id], Weight: g.weights[id]}, true
+}
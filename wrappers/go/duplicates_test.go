@@ -0,0 +1,57 @@
+package sssp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeDuplicates(t *testing.T) {
+	edges := []Edge{{0, 1, 4}, {0, 2, 1}, {0, 1, 2}, {2, 1, 1}, {0, 1, 3}, {2, 1, 5}}
+	for _, tc := range []struct {
+		policy  DuplicatePolicy
+		removed int
+		want    []Edge
+	}{
+		{KeepMultiEdges, 0, []Edge{{0, 1, 4}, {0, 2, 1}, {0, 1, 2}, {0, 1, 3}, {2, 1, 1}, {2, 1, 5}}},
+		{KeepMin, 3, []Edge{{0, 1, 2}, {0, 2, 1}, {2, 1, 1}}},
+		{KeepLast, 3, []Edge{{0, 1, 3}, {0, 2, 1}, {2, 1, 5}}},
+		{SumWeights, 3, []Edge{{0, 1, 9}, {0, 2, 1}, {2, 1, 6}}},
+	} {
+		g, err := FromEdges(3, edges)
+		if err != nil {
+			t.Fatal(err)
+		}
+		removed, err := g.MergeDuplicates(tc.policy)
+		if err != nil || removed != tc.removed || !reflect.DeepEqual(g.Edges(), tc.want) {
+			t.Fatalf("%v: removed %d, edges %v, err %v", tc.policy, removed, g.Edges(), err)
+		}
+		if r := g.Validate(); tc.policy != KeepMultiEdges && r.Counts[DuplicateEdge] != 0 {
+			t.Fatalf("%v: duplicates left", tc.policy)
+		}
+		if p, err := ParseDuplicatePolicy(tc.policy.String()); err != nil || p != tc.policy {
+			t.Fatalf("parse %v: %v, %v", tc.policy, p, err)
+		}
+	}
+	if _, err := ParseDuplicatePolicy("max"); err == nil {
+		t.Fatalf("unknown policy accepted")
+	}
+}
+
+func TestEdgeIDs(t *testing.T) {
+	g, err := FromEdges(4, []Edge{{0, 1, 4}, {2, 1, 1}, {0, 1, 2}, {2, 3, 7}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := g.EdgeIDs(0, 1)
+	if !reflect.DeepEqual(ids, []int{0, 1}) || g.EdgeIDs(1, 0) != nil {
+		t.Fatalf("ids %v", ids)
+	}
+	for id, want := range g.Edges() {
+		if e, ok := g.EdgeByID(id); !ok || e != want {
+			t.Fatalf("edge %d: %v, want %v", id, e, want)
+		}
+	}
+	if _, ok := g.EdgeByID(4); ok {
+		t.Fatalf("edge ID past the end accepted")
+	}
+}
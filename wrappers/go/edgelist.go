@@ -0,0 +1,169 @@
+package sssp
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/your-org/optimized-sssp-go/internal/decompress"
+)
+
+// EdgeListOptions describes an ad-hoc delimited edge list. The zero value reads
+// "u v [w]" lines split on whitespace with 0-based node ids.
+type EdgeListOptions struct {
+	// Delimiter separates fields; 0 splits on runs of whitespace. Any other
+	// rune (',' or '\t' typically) is parsed as CSV, so quoted fields work.
+	Delimiter rune
+	// SourceCol, TargetCol and WeightCol are 1-based column numbers. Zero
+	// means the defaults 1, 2 and 3; a WeightCol of -1 ignores weights.
+	SourceCol, TargetCol, WeightCol int
+	// SkipHeader drops that many leading non-comment rows.
+	SkipHeader int
+	// Comment starts a comment line; empty means "#". Lines beginning with
+	// it (after leading spaces) are skipped.
+	Comment string
+	// DefaultWeight applies when the weight column is absent or empty. Zero
+	// means 1.
+	DefaultWeight float32
+	// OneBased subtracts 1 from every node id.
+	OneBased bool
+	// NodeCount fixes the number of nodes; zero means max id + 1.
+	NodeCount uint32
+	// IDs, if set, reads the node columns as arbitrary keys ("paris",
+	// "osm:123") and numbers them through it; OneBased is ignored and a zero
+	// NodeCount means IDs.Len(). Reuse one mapper to load several files over
+	// the same nodes.
+	IDs *IDMapper[string]
+	// SparseIDs accepts a largest id far beyond what the edge count
+	// justifies (see CheckIDSpan) when NodeCount is zero; LoadEdgeList
+	// otherwise rejects it. Use IDs to number sparse ids densely instead.
+	SparseIDs bool
+}
+
+// LoadEdgeList reads a delimited edge list into a directed graph.
+func LoadEdgeList(r io.Reader, opts EdgeListOptions) (*Graph, error) {
+	r, err := decompress.Reader(r)
+	if err != nil {
+		return nil, err
+	}
+	sc, tc, wc := opts.SourceCol, opts.TargetCol, opts.WeightCol
+	if sc == 0 {
+		sc = 1
+	}
+	if tc == 0 {
+		tc = 2
+	}
+	if wc == 0 {
+		wc = 3
+	}
+	if sc < 1 || tc < 1 || wc < -1 {
+		return nil, fmt.Errorf("sssp: edgelist: bad column numbers %d/%d/%d", sc, tc, wc)
+	}
+	comment := opts.Comment
+	if comment == "" {
+		comment = "#"
+	}
+	def := opts.DefaultWeight
+	if def == 0 {
+		def = 1
+	}
+	var (
+		edges   []Edge
+		maxID   uint64
+		skipped int
+	)
+	record := func(line int, f []string) error {
+		if skipped < opts.SkipHeader {
+			skipped++
+			return nil
+		}
+		if len(f) < sc || len(f) < tc {
+			return fmt.Errorf("sssp: edgelist line %d: %d columns, need %d", line, len(f), max(sc, tc))
+		}
+		var u, v uint64
+		if opts.IDs != nil {
+			u, v = uint64(opts.IDs.ID(strings.TrimSpace(f[sc-1]))), uint64(opts.IDs.ID(strings.TrimSpace(f[tc-1])))
+		} else {
+			var err1, err2 error
+			u, err1 = strconv.ParseUint(strings.TrimSpace(f[sc-1]), 10, 32)
+			v, err2 = strconv.ParseUint(strings.TrimSpace(f[tc-1]), 10, 32)
+			if err1 != nil || err2 != nil {
+				return fmt.Errorf("sssp: edgelist line %d: bad node id", line)
+			}
+		}
+		if opts.OneBased && opts.IDs == nil {
+			if u == 0 || v == 0 {
+				return fmt.Errorf("sssp: edgelist line %d: node id 0 in a 1-based file", line)
+			}
+			u, v = u-1, v-1
+		}
+		w := def
+		if wc > 0 && wc <= len(f) && strings.TrimSpace(f[wc-1]) != "" {
+			x, err := strconv.ParseFloat(strings.TrimSpace(f[wc-1]), 32)
+			if err != nil {
+				return fmt.Errorf("sssp: edgelist line %d: bad weight %q", line, f[wc-1])
+			}
+			w = float32(x)
+		}
+		if u >= math.MaxUint32 || v >= math.MaxUint32 {
+			return fmt.Errorf("sssp: edgelist line %d: node id too large", line)
+		}
+		maxID = max(maxID, u, v)
+		edges = append(edges, Edge{From: uint32(u), To: uint32(v), Weight: w})
+		return nil
+	}
+	isComment := func(s string) bool { return strings.HasPrefix(strings.TrimSpace(s), comment) }
+	if opts.Delimiter == 0 {
+		s := bufio.NewScanner(r)
+		s.Buffer(make([]byte, 64*1024), 1<<20)
+		for line := 1; s.Scan(); line++ {
+			f := strings.Fields(s.Text())
+			if len(f) == 0 || isComment(s.Text()) {
+				continue
+			}
+			if err := record(line, f); err != nil {
+				return nil, err
+			}
+		}
+		if err := s.Err(); err != nil {
+			return nil, err
+		}
+	} else {
+		cr := csv.NewReader(r)
+		cr.Comma = opts.Delimiter
+		cr.FieldsPerRecord = -1
+		cr.LazyQuotes = true
+		cr.ReuseRecord = true
+		for {
+			f, err := cr.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("sssp: edgelist: %w", err)
+			}
+			line, _ := cr.FieldPos(0)
+			if len(f) == 0 || (len(f) == 1 && strings.TrimSpace(f[0]) == "") || isComment(f[0]) {
+				continue
+			}
+			if err := record(line, f); err != nil {
+				return nil, err
+			}
+		}
+	}
+	n := opts.NodeCount
+	if n == 0 && opts.IDs != nil {
+		n = opts.IDs.Len()
+	} else if n == 0 && len(edges) > 0 {
+		if err := CheckIDSpan(maxID, len(edges)); err != nil && !opts.SparseIDs {
+			return nil, fmt.Errorf("%w; set EdgeListOptions.IDs to number the ids densely, SparseIDs to keep them, or NodeCount", err)
+		}
+		n = uint32(maxID + 1)
+	}
+	return FromEdges(n, edges)
+}
@@ -0,0 +1,46 @@
+package sssp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadEdgeListWhitespaceDefaults(t *testing.T) {
+	src := "# SNAP-style dump\n0 1 2.5\n\n1\t2\n"
+	g, err := LoadEdgeList(strings.NewReader(src), EdgeListOptions{DefaultWeight: 4})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if g.NodeCount() != 3 || g.Edges()[1] != (Edge{1, 2, 4}) {
+		t.Fatalf("got %v", g.Edges())
+	}
+}
+
+func TestLoadEdgeListCSVColumnsAndHeader(t *testing.T) {
+	src := "weight,from,to\n\"1.5\",1,2\n,2,3\n# trailing comment\n"
+	g, err := LoadEdgeList(strings.NewReader(src), EdgeListOptions{
+		Delimiter: ',', SourceCol: 2, TargetCol: 3, WeightCol: 1, SkipHeader: 1, OneBased: true, NodeCount: 4,
+	})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if g.NodeCount() != 4 || g.EdgeCount() != 2 || g.Edges()[0] != (Edge{0, 1, 1.5}) || g.Edges()[1].Weight != 1 {
+		t.Fatalf("got %v", g.Edges())
+	}
+	if _, err := LoadEdgeList(strings.NewReader("% header\n0 1\n"), EdgeListOptions{}); err == nil {
+		t.Fatalf("expected '%%' line to be data with the default comment prefix")
+	}
+	if _, err := LoadEdgeList(strings.NewReader("a\tb\n"), EdgeListOptions{Delimiter: '\t'}); err == nil {
+		t.Fatalf("expected bad node id error")
+	}
+}
+
+func TestLoadEdgeListRejectsStrayLargeID(t *testing.T) {
+	if _, err := LoadEdgeList(strings.NewReader("0 2000000000\n"), EdgeListOptions{}); err == nil {
+		t.Fatal("id 2e9 on one edge accepted")
+	}
+	g, err := LoadEdgeList(strings.NewReader("0 5000000\n"), EdgeListOptions{SparseIDs: true})
+	if err != nil || g.NodeCount() != 5000001 {
+		t.Fatalf("sparse ids: %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package sssp
+
+import "fmt"
+
+// ElevationAttr is the node attribute holding elevations in metres, as
+// float32. Being an attribute, it follows the nodes through Subgraph, Merge,
+// Reverse and the JSON format.
+const ElevationAttr = "elevation"
+
+// SetElevations attaches the elevation of every node in metres, for
+// example sampled from a terrain model at each node's coordinate. nil
+// detaches them. The slice is kept, not copied.
+func (g *Graph) SetElevations(metres []float32) error {
+	return SetNodeAttr(g, ElevationAttr, metres)
+}
+
+// Elevations returns the node elevations in metres, or nil.
+func (g *Graph) Elevations() []float32 {
+	e, _ := NodeAttr[float32](g, ElevationAttr)
+	return e
+}
+
+// GradeCost makes an edge's weight depend on the height it climbs or
+// descends, for cyclists and walkers on hilly ground. The zero value
+// leaves weights unchanged.
+type GradeCost struct {
+	// Climb is added to the weight per metre climbed.
+	Climb float32
+	// Descent is subtracted from the weight per metre descended.
+	Descent float32
+	// MinFactor is the lowest fraction of its flat weight a descent can
+	// bring an edge to; weights never go below zero.
+	MinFactor float32
+}
+
+// Apply returns the weight of an edge of flat weight w that rises by rise
+// metres (negative for a descent).
+func (c GradeCost) Apply(w, rise float32) float32 {
+	if rise > 0 {
+		return w + c.Climb*rise
+	}
+	return max(w+c.Descent*rise, c.MinFactor*w, 0)
+}
+
+// GradeWeight returns a WeightFunc applying c with g's elevations, for
+// RunQuery, Reweighted or a routing profile. It fails if g has none.
+func (g *Graph) GradeWeight(c GradeCost) (WeightFunc, error) {
+	elev := g.Elevations()
+	if elev == nil {
+		return nil, fmt.Errorf("sssp: grade cost needs node elevations")
+	}
+	return func(u, v uint32, w float32) float32 { return c.Apply(w, elev[v]-elev[u]) }, nil
+}
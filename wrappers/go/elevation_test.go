@@ -0,0 +1,43 @@
+package sssp
+
+import "testing"
+
+func TestGradeCost(t *testing.T) {
+	c := GradeCost{Climb: 10, Descent: 4, MinFactor: 0.5}
+	for _, tc := range []struct{ w, rise, want float32 }{
+		{100, 0, 100}, {100, 3, 130}, {100, -5, 80}, {100, -50, 50},
+	} {
+		if got := c.Apply(tc.w, tc.rise); got != tc.want {
+			t.Errorf("Apply(%v, %v) = %v, want %v", tc.w, tc.rise, got, tc.want)
+		}
+	}
+	if got := (GradeCost{Descent: 10}).Apply(10, -5); got != 0 {
+		t.Errorf("descent below zero: %v", got)
+	}
+}
+
+func TestGradeWeightQuery(t *testing.T) {
+	// 0 -> 1 -> 2 over a hill, or 0 -> 2 around it.
+	g, _ := FromEdges(3, []Edge{{0, 1, 10}, {1, 2, 10}, {0, 2, 25}})
+	if _, err := g.GradeWeight(GradeCost{Climb: 1}); err == nil {
+		t.Fatalf("grade cost without elevations accepted")
+	}
+	if err := g.SetElevations([]float32{0, 20, 0}); err != nil {
+		t.Fatal(err)
+	}
+	fn, err := g.GradeWeight(GradeCost{Climb: 1, Descent: 0.5, MinFactor: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := g.RunQuery(0, ModeBaseline, QueryOptions{Weight: fn})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Dist[2] != 25 || res.Dist[1] != 30 {
+		t.Fatalf("dist %v, want the flat detour", res.Dist)
+	}
+	sub, _, _ := g.Subgraph([]uint32{1, 2})
+	if e := sub.Elevations(); len(e) != 2 || e[0] != 20 {
+		t.Fatalf("subgraph elevations %v", e)
+	}
+}
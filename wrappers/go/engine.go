@@ -0,0 +1,582 @@
+package sssp
+
+import (
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Pure-Go counterparts of the Rust runners. They back the !cgo build (js/wasm,
+// wasip1, CGO_ENABLED=0) and follow the same CSR layout and conventions:
+// unreachable nodes keep +Inf distance and -1 predecessor.
+
+var inf32 = float32(math.Inf(1))
+
+// csrOffset is the type of CSR offsets and edge indices: uint32 for Graph,
+// uint64 for Graph64.
+type csrOffset interface{ ~uint32 | ~uint64 }
+
+type heapItem[W Weight] struct {
+	node uint32
+	dist W
+}
+
+// minHeap is a binary min-heap on dist, mirroring BinaryHeapSimple in lib.rs.
+type minHeap[W Weight] struct{ data []heapItem[W] }
+
+func (h *minHeap[W]) push(it heapItem[W]) {
+	h.data = append(h.data, it)
+	i := len(h.data) - 1
+	for i > 0 {
+		p := (i - 1) / 2
+		if h.data[i].dist >= h.data[p].dist {
+			break
+		}
+		h.data[i], h.data[p] = h.data[p], h.data[i]
+		i = p
+	}
+}
+
+func (h *minHeap[W]) pop() (heapItem[W], bool) {
+	n := len(h.data)
+	if n == 0 {
+		return heapItem[W]{}, false
+	}
+	out := h.data[0]
+	h.data[0] = h.data[n-1]
+	h.data = h.data[:n-1]
+	n--
+	i := 0
+	for {
+		l := 2*i + 1
+		if l >= n {
+			break
+		}
+		b := l
+		if r := l + 1; r < n && h.data[r].dist < h.data[l].dist {
+			b = r
+		}
+		if h.data[b].dist >= h.data[i].dist {
+			break
+		}
+		h.data[i], h.data[b] = h.data[b], h.data[i]
+		i = b
+	}
+	return out, true
+}
+
+// live returns the nodes of the heap's current entries, skipping stale ones.
+func (h *minHeap[W]) live(dist []W) []uint32 {
+	var nodes []uint32
+	for _, it := range h.data {
+		if it.dist == dist[it.node] {
+			nodes = append(nodes, it.node)
+		}
+	}
+	return nodes
+}
+
+func resetDistPred[W Weight](source uint32, dist []W, pred []int32) {
+	unreached := unreachableOf[W]()
+	for i := range dist {
+		dist[i] = unreached
+	}
+	for i := range pred {
+		pred[i] = -1
+	}
+	dist[source] = 0
+}
+
+// runHooks are the optional observers of a pure-Go run; a nil *runHooks
+// observes nothing.
+type runHooks struct {
+	visit func(uint32) // sees each node once its distance is final
+	trace *phaseTrace
+
+	// frontier sees the nodes each light round scans, with its bucket, or
+	// for Dijkstra the live heap entries every stride settled nodes, with
+	// bucket -1. members is only valid during the call.
+	frontier func(bucket int, members []uint32)
+	stride   uint64
+
+	// avoid and edgeOK, if set, are asked before each relaxation of edge e
+	// from u to v; a rejected edge is skipped as if absent and not counted.
+	avoid  *AvoidSet
+	edgeOK func(u uint32, e uint64) bool
+}
+
+func (h *runHooks) skips(u, v uint32, e uint64) bool {
+	return h != nil && ((h.avoid != nil && h.avoid.blocks(v, e)) || (h.edgeOK != nil && !h.edgeOK(u, e)))
+}
+
+// trialHooks keeps only what changes the result, for autotune trials.
+func (h *runHooks) trialHooks() *runHooks {
+	if h == nil || (h.avoid == nil && h.edgeOK == nil) {
+		return nil
+	}
+	return &runHooks{avoid: h.avoid, edgeOK: h.edgeOK}
+}
+
+func (h *runHooks) settle(u uint32) {
+	if h != nil && h.visit != nil {
+		h.visit(u)
+	}
+}
+
+func (h *runHooks) tracing() bool { return h != nil && h.trace != nil }
+
+// goDijkstra is the pure-Go sssp_run_baseline. Its Improvements are the
+// Stats.Relaxations count. A nil pred records no predecessors; the same
+// holds for the other runners. hooks see each node as it is settled, in
+// nondecreasing distance order, and the run as one PhaseHeap. With integer
+// weights a sum that overflows W is not a path: it never improves a
+// distance.
+func goDijkstra[O csrOffset, W Weight](off []O, tgt []uint32, wts []W, source uint32, dist []W, pred []int32, hooks *runHooks) OpCounters {
+	pool := poolOf[heapItem[W]]()
+	hp := pool.get(min(len(dist), 1024))
+	h := minHeap[W]{data: *hp}
+	var vec *simdRelaxer
+	if hooks == nil || (hooks.avoid == nil && hooks.edgeOK == nil) {
+		vec = newSIMDRelaxer(tgt, wts, dist)
+	}
+	ops := dijkstraWith(off, tgt, wts, source, dist, pred, hooks, &h, vec)
+	*hp = h.data
+	pool.put(hp)
+	vec.release()
+	return ops
+}
+
+// dijkstraWith is goDijkstra on a caller-owned heap, emptied first, and
+// vector relaxer for dist, or nil, so a PathFinder can reuse both.
+func dijkstraWith[O csrOffset, W Weight](off []O, tgt []uint32, wts []W, source uint32, dist []W, pred []int32, hooks *runHooks, h *minHeap[W], vec *simdRelaxer) OpCounters {
+	resetDistPred(source, dist, pred)
+	wraps := !isFloatWeight[W]()
+	h.data = h.data[:0]
+	h.push(heapItem[W]{node: source})
+	ops := OpCounters{HeapPushes: 1}
+	peak := 1
+	for {
+		it, ok := h.pop()
+		if !ok {
+			break
+		}
+		ops.HeapPops++
+		if it.dist > dist[it.node] {
+			continue
+		}
+		ops.FrontierExpansions++
+		hooks.settle(it.node)
+		ops.EdgeRelaxations += uint64(off[it.node+1] - off[it.node])
+		// With the vector kernel, walk only the edges it found improving;
+		// cand is non-nil exactly then.
+		lo, hi, cand := off[it.node], off[it.node+1], []int(nil)
+		if vec != nil && hi-lo >= simdMinDegree {
+			cand = vec.candidates(float32(it.dist), int(lo), int(hi))
+			lo, hi = 0, O(len(cand))
+		}
+		for i := lo; i < hi; i++ {
+			e := i
+			if cand != nil {
+				e = O(cand[i])
+			}
+			v := tgt[e]
+			if hooks.skips(it.node, v, uint64(e)) {
+				ops.EdgeRelaxations--
+				continue
+			}
+			nd := it.dist + wts[e]
+			if nd < dist[v] && !(wraps && nd < it.dist) {
+				dist[v] = nd
+				if pred != nil {
+					pred[v] = int32(it.node)
+				}
+				h.push(heapItem[W]{node: v, dist: nd})
+				ops.HeapPushes++
+				ops.Improvements++
+			}
+		}
+		peak = max(peak, len(h.data))
+		if hooks != nil && hooks.frontier != nil && ops.FrontierExpansions%hooks.stride == 0 {
+			hooks.frontier(-1, h.live(dist))
+		}
+	}
+	if hooks.tracing() {
+		hooks.trace.end(Phase{Kind: PhaseHeap, Bucket: -1, Frontier: peak, Settled: int(ops.FrontierExpansions), Relaxations: ops.EdgeRelaxations})
+	}
+	return ops
+}
+
+// stepCounters are the delta-stepping counters reported through Stats.
+type stepCounters struct {
+	relax, light, heavy uint64
+	settled             uint32
+	ops                 OpCounters
+	overflow            bool // a distance fell past maxBucket; see goDeltaStepping
+}
+
+// maxBucket is the highest bucket index delta-stepping accepts on n nodes,
+// max_bucket_cap in lib.rs, where the native runner returns -5 past it
+// instead of growing the bucket array without bound.
+func maxBucket(n int) int { return 4*n + 1024 }
+
+// bucketIndex returns the bucket of distance nd, at least cur, for width
+// delta (inv is 1/delta for float weights), or false if nd lies past
+// maxBucket(n) or is NaN.
+func bucketIndex[W Weight](nd, delta, inv W, isFloat bool, cur, n int) (int, bool) {
+	if isFloat {
+		q := float64(nd * inv)
+		if !(q <= float64(maxBucket(n))) {
+			return 0, false
+		}
+		return max(int(q), cur), true
+	}
+	q := nd / delta
+	if uint64(q) > uint64(maxBucket(n)) {
+		return 0, false
+	}
+	return max(int(q), cur), true
+}
+
+// goDeltaStepping uses the bucket scheme of stoc_run_internal: per bucket,
+// relax light edges (w <= delta) until the bucket stays empty, then relax heavy
+// edges of every node removed from it. Nodes improved after leaving the current
+// bucket are reinserted, keeping distances exact. limit > 0 stops after that
+// many settled nodes. hooks see the nodes of each bucket once its light
+// phase ends, when their distances are final: heavy edges only reach later
+// buckets. Each light round and heavy pass is traced as a phase.
+//
+// A distance past maxBucket (a huge or infinite weight against a small
+// delta) ends delta-stepping with overflow set, as -5 does in lib.rs. A
+// full run then redoes the query with goDijkstra so the result stays exact,
+// and callers report Stats.Fallback; hooks see the nodes they were not yet
+// given, in Dijkstra's order. A truncated trial run just stops.
+func goDeltaStepping[O csrOffset, W Weight](off []O, tgt []uint32, wts []W, source uint32, delta W, dist []W, pred []int32, limit uint32, hooks *runHooks) stepCounters {
+	resetDistPred(source, dist, pred)
+	n := len(dist)
+	// Float buckets multiply by 1/delta like lib.rs; integer ones divide, so
+	// they never touch floating point.
+	isFloat := isFloatWeight[W]()
+	var inv W
+	if isFloat {
+		inv = 1 / delta
+	}
+	// bucketOf is the bucket a node waits in, or -1. A node whose distance
+	// drops into an earlier bucket moves there, leaving a stale entry that
+	// the later bucket's scan skips.
+	sc := getStepScratch(n)
+	defer sc.release()
+	bucketOf, settled := sc.bucketOf, sc.settled
+	for i := range bucketOf {
+		bucketOf[i] = -1
+	}
+	buckets := append(sc.buckets, append(sc.list(), source))
+	bucketOf[source] = 0
+	var c stepCounters
+	// relax reports whether dist[v] improved and whether v (re)entered bucket cur.
+	relax := func(u uint32, e O, cur int) (improved, sameBucket bool) {
+		v := tgt[e]
+		if hooks.skips(u, v, uint64(e)) {
+			return false, false
+		}
+		nd := dist[u] + wts[e]
+		c.ops.EdgeRelaxations++
+		if nd >= dist[v] || (!isFloat && nd < dist[u]) {
+			return false, false
+		}
+		b, ok := bucketIndex(nd, delta, inv, isFloat, cur, n)
+		if !ok {
+			c.overflow = true
+			return false, false
+		}
+		dist[v] = nd
+		if pred != nil {
+			pred[v] = int32(u)
+		}
+		for b >= len(buckets) {
+			buckets = append(buckets, nil)
+		}
+		c.relax++
+		c.ops.Improvements++
+		if bucketOf[v] != int32(b) {
+			if buckets[b] == nil {
+				buckets[b] = sc.list()
+			}
+			buckets[b] = append(buckets[b], v)
+			bucketOf[v] = int32(b)
+			return true, b == cur
+		}
+		return true, false
+	}
+	done := func() bool { return c.overflow || limit > 0 && c.settled >= limit }
+	for cur := 0; cur < len(buckets) && !done(); cur++ {
+		lightSet := sc.light[:0]
+		for round, repeat := 0, true; repeat && !done(); round++ {
+			repeat = false
+			frontier := buckets[cur][:0]
+			for _, u := range buckets[cur] {
+				if bucketOf[u] == int32(cur) {
+					bucketOf[u] = -1
+					frontier = append(frontier, u)
+				}
+			}
+			buckets[cur] = nil
+			c.ops.BucketScans++
+			if hooks != nil && hooks.frontier != nil {
+				hooks.frontier(cur, frontier)
+			}
+			relaxed, settledBefore := c.ops.EdgeRelaxations, c.settled
+			for _, u := range frontier {
+				c.ops.FrontierExpansions++
+				if !settled[u] {
+					settled[u] = true
+					c.settled++
+					lightSet = append(lightSet, u)
+				}
+				for e := off[u]; e < off[u+1]; e++ {
+					if wts[e] > delta {
+						continue
+					}
+					if improved, same := relax(u, e, cur); improved {
+						c.light++
+						repeat = repeat || same
+					}
+				}
+				if done() {
+					break
+				}
+			}
+			if hooks.tracing() {
+				hooks.trace.end(Phase{Kind: PhaseLight, Bucket: cur, Round: round, Delta: float32(delta), Frontier: len(frontier),
+					Settled: int(c.settled - settledBefore), Relaxations: c.ops.EdgeRelaxations - relaxed})
+			}
+			sc.spare = append(sc.spare, frontier)
+		}
+		sc.light = lightSet
+		if c.overflow {
+			// Not reported yet: leave them to the fallback.
+			for _, u := range lightSet {
+				settled[u] = false
+			}
+			break
+		}
+		for _, u := range lightSet {
+			hooks.settle(u)
+		}
+		relaxed := c.ops.EdgeRelaxations
+		for _, u := range lightSet {
+			if c.overflow {
+				break
+			}
+			for e := off[u]; e < off[u+1]; e++ {
+				if wts[e] > delta {
+					if improved, _ := relax(u, e, cur); improved {
+						c.heavy++
+					}
+				}
+			}
+		}
+		if hooks.tracing() {
+			hooks.trace.end(Phase{Kind: PhaseHeavy, Bucket: cur, Delta: float32(delta), Frontier: len(lightSet),
+				Relaxations: c.ops.EdgeRelaxations - relaxed})
+		}
+	}
+	sc.buckets = buckets
+	if c.overflow && limit == 0 {
+		// settled now marks exactly the nodes hooks were given.
+		var fh *runHooks
+		if hooks != nil {
+			h := *hooks
+			if visit := h.visit; visit != nil {
+				h.visit = func(u uint32) {
+					if !settled[u] {
+						visit(u)
+					}
+				}
+			}
+			fh = &h
+		}
+		ops := goDijkstra(off, tgt, wts, source, dist, pred, fh)
+		c.ops.Add(ops)
+		c.relax += ops.Improvements
+		c.settled = uint32(n)
+	}
+	return c
+}
+
+// stepScratch is the working memory of goDeltaStepping: per-node state,
+// the buckets and the light set, with emptied bucket lists kept for reuse.
+type stepScratch struct {
+	bucketOf []int32
+	settled  []bool
+	buckets  [][]uint32
+	spare    [][]uint32
+	light    []uint32
+}
+
+var stepPool sync.Pool
+
+// getStepScratch returns scratch for n nodes with settled cleared and no
+// buckets.
+func getStepScratch(n int) *stepScratch {
+	sc, _ := stepPool.Get().(*stepScratch)
+	if sc == nil || !pooling.Load() {
+		sc = new(stepScratch)
+	}
+	if cap(sc.bucketOf) < n {
+		sc.bucketOf, sc.settled = make([]int32, n), make([]bool, n)
+	}
+	sc.bucketOf, sc.settled = sc.bucketOf[:n], sc.settled[:n]
+	clear(sc.settled)
+	return sc
+}
+
+// list returns an empty bucket list, reusing an emptied one if any.
+func (sc *stepScratch) list() []uint32 {
+	if k := len(sc.spare); k > 0 {
+		l := sc.spare[k-1][:0]
+		sc.spare = sc.spare[:k-1]
+		return l
+	}
+	return nil
+}
+
+// release returns the scratch to the pool, its lists to the spares.
+func (sc *stepScratch) release() {
+	if !pooling.Load() {
+		return
+	}
+	for i, l := range sc.buckets {
+		if l != nil {
+			sc.spare = append(sc.spare, l)
+		}
+		sc.buckets[i] = nil
+	}
+	sc.buckets = sc.buckets[:0]
+	stepPool.Put(sc)
+}
+
+// avgWeight samples the first 1000 weights like derive_avg_weight in lib.rs.
+func avgWeight[W Weight](wts []W) float32 {
+	sample := min(len(wts), 1000)
+	if sample == 0 {
+		return 1
+	}
+	var s float32
+	for _, w := range wts[:sample] {
+		s += float32(w)
+	}
+	avg := s / float32(sample)
+	if avg <= 0 {
+		return 1
+	}
+	return avg
+}
+
+func clampDelta(d float32) float32 {
+	return float32(math.Min(math.Max(float64(d), 1e-4), 1e6))
+}
+
+// deltaOf converts a bucket width computed in float32 to W: clamped like
+// clampDelta for floats, rounded to at least 1 for integers.
+func deltaOf[W Weight](d float32) W {
+	if isFloatWeight[W]() {
+		return W(clampDelta(d))
+	}
+	return W(max(1, math.Round(math.Min(float64(d), 1<<30))))
+}
+
+func envFloat(key string, def float32) float32 {
+	if v, err := strconv.ParseFloat(os.Getenv(key), 32); err == nil {
+		return float32(v)
+	}
+	return def
+}
+
+func envUint(key string, def uint32) uint32 {
+	if v, err := strconv.ParseUint(os.Getenv(key), 10, 32); err == nil {
+		return uint32(v)
+	}
+	return def
+}
+
+func autotuneCandidates(params AutotuneParams) []float32 {
+	var c []float32
+	if len(params.Candidates) > 0 {
+		for _, m := range params.Candidates {
+			if m > 0 {
+				c = append(c, m)
+			}
+		}
+	} else if env := os.Getenv("SSSP_STOC_AUTOTUNE_SET"); env != "" {
+		for _, f := range strings.Split(env, ",") {
+			if m, err := strconv.ParseFloat(strings.TrimSpace(f), 32); err == nil && m > 0 {
+				c = append(c, float32(m))
+			}
+		}
+	} else {
+		c = []float32{1.5, 2, 3, 4, 6}
+	}
+	if len(c) == 0 {
+		c = []float32{3}
+	}
+	return c
+}
+
+// goAutotune mirrors sssp_run_stoc_autotune_params: time truncated trial runs
+// per candidate multiplier, then run the fastest (or the pinned delta) in full.
+// hooks see each trial as a PhaseTrial, then the final run as
+// goDeltaStepping reports it.
+func goAutotune[O csrOffset, W Weight](off []O, tgt []uint32, wts []W, source uint32, params AutotuneParams, dist []W, pred []int32, hooks *runHooks) Stats {
+	avg := avgWeight(wts)
+	var delta W
+	var mult float32
+	var trials uint32
+	var ops OpCounters
+	if params.PinnedDelta > 0 {
+		delta = deltaOf[W](params.PinnedDelta)
+	} else {
+		n := uint32(len(dist))
+		limit := params.TrialLimit
+		if limit == 0 {
+			limit = envUint("SSSP_STOC_AUTOTUNE_LIMIT", 2048)
+		}
+		limit = min(limit, n)
+		// Trials only time the search, so they record no predecessors.
+		distRow, tmpDist := getRow[W](int(n))
+		defer putRow(distRow)
+		best := time.Duration(math.MaxInt64)
+		candidates := autotuneCandidates(params)
+		mult = candidates[0]
+		for i, m := range candidates {
+			start := time.Now()
+			trial := goDeltaStepping(off, tgt, wts, source, deltaOf[W](avg*m), tmpDist, nil, limit, hooks.trialHooks())
+			ops.Add(trial.ops)
+			trials++
+			if el := time.Since(start); el < best && !trial.overflow {
+				best, mult = el, m
+			}
+			if hooks.tracing() {
+				hooks.trace.end(Phase{Kind: PhaseTrial, Bucket: -1, Round: i, Delta: float32(deltaOf[W](avg * m)),
+					Settled: int(trial.settled), Relaxations: trial.ops.EdgeRelaxations})
+			}
+		}
+		delta = deltaOf[W](avg * mult)
+	}
+	c := goDeltaStepping(off, tgt, wts, source, delta, dist, pred, 0, hooks)
+	ops.Add(c.ops)
+	var maxFinite W
+	unreached := unreachableOf[W]()
+	for _, d := range dist {
+		if d != unreached && d > maxFinite {
+			maxFinite = d
+		}
+	}
+	return Stats{
+		Relaxations: c.relax, LightRelaxations: c.light, HeavyRelaxations: c.heavy, Settled: c.settled,
+		Delta: float32(delta), DeltaMultiplier: mult, Buckets: uint32(maxFinite/delta) + 1, AutotuneTrials: trials,
+		Fallback: c.overflow, Ops: ops,
+	}
+}
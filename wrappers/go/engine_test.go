@@ -0,0 +1,113 @@
+package sssp
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// randomCSR builds a reproducible random digraph with avgDeg out-edges per node.
+func randomCSR(n, avgDeg int, seed int64) (off, tgt []uint32, wts []float32) {
+	r := rand.New(rand.NewSource(seed))
+	off = make([]uint32, n+1)
+	for u := 0; u < n; u++ {
+		deg := r.Intn(2*avgDeg + 1)
+		for i := 0; i < deg; i++ {
+			tgt = append(tgt, uint32(r.Intn(n)))
+			wts = append(wts, 1+r.Float32()*9)
+		}
+		off[u+1] = uint32(len(tgt))
+	}
+	return off, tgt, wts
+}
+
+func TestGoDeltaSteppingMatchesGoDijkstra(t *testing.T) {
+	off, tgt, wts := randomCSR(500, 4, 7)
+	want := make([]float32, 500)
+	goDijkstra(off, tgt, wts, 0, want, make([]int32, 500), nil)
+	for _, delta := range []float32{0.5, 3, 50} {
+		got := make([]float32, 500)
+		pred := make([]int32, 500)
+		goDeltaStepping(off, tgt, wts, 0, delta, got, pred, 0, nil)
+		for v := range got {
+			if got[v] != want[v] {
+				t.Fatalf("delta %v node %d: got %v want %v", delta, v, got[v], want[v])
+			}
+			if p := pred[v]; p >= 0 && got[p] >= got[v] {
+				t.Fatalf("delta %v node %d: predecessor %d not closer", delta, v, p)
+			}
+		}
+	}
+}
+
+func TestGoAutotunePinnedSkipsTrials(t *testing.T) {
+	off, tgt, wts := randomCSR(200, 3, 11)
+	stats := goAutotune(off, tgt, wts, 0, AutotuneParams{PinnedDelta: 2}, make([]float32, 200), make([]int32, 200), nil)
+	if stats.AutotuneTrials != 0 || stats.Delta != 2 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestGoDeltaSteppingFallsBackPastBucketCap(t *testing.T) {
+	// A chain of unit edges, then one edge far past 4n+1024 buckets of
+	// width 1, and an infinite one.
+	const n = 64
+	var edges []Edge
+	for u := uint32(0); u+3 < n; u++ {
+		edges = append(edges, Edge{u, u + 1, 1})
+	}
+	edges = append(edges, Edge{n - 3, n - 2, 1e20}, Edge{n - 2, n - 1, float32(math.Inf(1))})
+	g, err := FromEdges(n, edges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := g.RunSettled(0, ModeBaseline, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make([]int, n)
+	for _, mode := range []int{ModeStoc, ModeAutotune} {
+		clear(seen)
+		res, err := g.RunSettled(0, mode, func(v uint32, _ float32, _ int32) { seen[v]++ })
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Stats.Fallback {
+			t.Fatalf("mode %d: Fallback not set past the bucket cap", mode)
+		}
+		for v := range want.Dist {
+			if res.Dist[v] != want.Dist[v] {
+				t.Fatalf("mode %d node %d: got %v want %v", mode, v, res.Dist[v], want.Dist[v])
+			}
+			if reached := res.Reachable(uint32(v)); (seen[v] == 1) != reached || seen[v] > 1 {
+				t.Fatalf("mode %d node %d reported %d times", mode, v, seen[v])
+			}
+		}
+	}
+	off, tgt, wts := g.CSR()
+	ints := make([]int64, len(wts))
+	for i, w := range wts {
+		ints[i] = int64(min(float64(w), 1<<60))
+	}
+	gi, err := NewGraphCSROf(off, tgt, ints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res, err := gi.Run(0, ModeStoc); err != nil || !res.Stats.Fallback || res.Dist[n-1] != 1<<61+int64(n-3) {
+		t.Fatalf("int64 weights: %v, fallback %v, dist %v", err, res.Stats.Fallback, res.Dist[n-1])
+	}
+	res, err := RunSource(g, 0, ModeStoc)
+	if err != nil || !res.Stats.Fallback || res.Dist[n-2] != want.Dist[n-2] {
+		t.Fatalf("RunSource: %v, fallback %v, dist %v", err, res.Stats.Fallback, res.Dist[n-2])
+	}
+	// Batches agree on the rows or, natively, fail the whole batch.
+	batch, err := RunBatchThreads(n, off, tgt, wts, []uint32{0, 1}, ModeStoc, 2)
+	if err != nil {
+		if !strings.Contains(err.Error(), "code -5") {
+			t.Fatalf("batch: %v", err)
+		}
+	} else if !batch.Stats.Fallback || batch.Row(0)[n-2] != want.Dist[n-2] {
+		t.Fatalf("batch: fallback %v, dist %v", batch.Stats.Fallback, batch.Row(0)[n-2])
+	}
+}
@@ -0,0 +1,204 @@
+package sssp
+
+import (
+	"fmt"
+	"math"
+)
+
+// EnergyModel estimates the battery energy an electric vehicle uses on
+// each edge, in any unit (kWh is usual) matching EVOptions.Capacity.
+type EnergyModel struct {
+	// PerMetre is the energy used per metre on the flat.
+	PerMetre float32
+	// PerMetreClimbed is the extra energy per metre of height gained.
+	PerMetreClimbed float32
+	// Recuperation is the fraction of PerMetreClimbed won back per metre
+	// descended, so a long descent can have negative consumption.
+	Recuperation float32
+}
+
+// EdgeEnergy returns the energy of every edge of g under m, indexed by
+// edge ID. lengths gives each edge's length in metres; if nil, the
+// great-circle distance between its endpoints is used, which needs
+// coordinates. Height changes come from the elevations, if g has them.
+func (g *Graph) EdgeEnergy(m EnergyModel, lengths []float32) ([]float32, error) {
+	if lengths == nil && g.coords == nil {
+		return nil, fmt.Errorf("sssp: edge energy needs lengths or coordinates")
+	}
+	if lengths != nil && len(lengths) != g.EdgeCount() {
+		return nil, fmt.Errorf("sssp: %d lengths for %d edges", len(lengths), g.EdgeCount())
+	}
+	elev := g.Elevations()
+	energy := make([]float32, g.EdgeCount())
+	for u := uint32(0); u < g.NodeCount(); u++ {
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			v := g.targets[e]
+			var length float32
+			if lengths != nil {
+				length = lengths[e]
+			} else {
+				length = float32(Haversine(g.coords[u], g.coords[v]))
+			}
+			energy[e] = m.PerMetre * length
+			if elev != nil {
+				if rise := elev[v] - elev[u]; rise > 0 {
+					energy[e] += m.PerMetreClimbed * rise
+				} else {
+					energy[e] += m.Recuperation * m.PerMetreClimbed * rise
+				}
+			}
+		}
+	}
+	return energy, nil
+}
+
+// Charger describes a charging station.
+type Charger struct {
+	// Rate is the energy added per unit of weight (per second, if weights
+	// are travel times).
+	Rate float32
+	// Setup is the weight of stopping, whatever the charge.
+	Setup float32
+}
+
+// EVOptions configure EVRoute.
+type EVOptions struct {
+	// Energy is the consumption of each edge, indexed by edge ID; negative
+	// values recharge the battery. See EdgeEnergy.
+	Energy []float32
+	// Capacity is the battery size; the charge never exceeds it.
+	Capacity float32
+	// Initial is the charge at the source.
+	Initial float32
+	// Stations are the nodes where the vehicle may charge. A stop always
+	// fills the battery, which keeps the search small but can miss a faster
+	// route with a partial charge.
+	Stations map[uint32]Charger
+	// MaxLabels bounds the search, which keeps one label per useful
+	// (node, charge) pair; 0 means 16 per node and edge.
+	MaxLabels int
+}
+
+// ChargeStop is one charging stop of an EV route.
+type ChargeStop struct {
+	Node   uint32
+	Added  float32 // energy charged
+	Weight float32 // weight of the stop, setup included
+}
+
+// EVRoute is a route found by Graph.EVRoute.
+type EVRoute struct {
+	// Weight is the total weight, charging stops included.
+	Weight float32
+	Path   []uint32
+	// Charge[i] is the charge on leaving Path[i], and on arrival for the
+	// last node.
+	Charge []float32
+	Stops  []ChargeStop
+}
+
+// evLabel is one state of the EV search: at node with charge soc after
+// weight time, reached from label parent.
+type evLabel struct {
+	node   uint32
+	time   float32
+	soc    float32
+	parent int32
+}
+
+// EVRoute finds the route from source to target of least weight on which
+// the battery never runs below zero, charging at stations as needed. The
+// charge after an edge is the charge before minus its energy, capped at
+// Capacity, so recuperation cannot overfill the battery. It reports false if
+// no such route exists. Weights must be non-negative.
+//
+// The search is label-setting over (node, charge) states in weight order; a
+// state is dropped when the node was already left with at least as much
+// charge, so each node keeps a short Pareto front of weight against charge.
+func (g *Graph) EVRoute(source, target uint32, opts EVOptions) (EVRoute, bool, error) {
+	n := g.NodeCount()
+	switch {
+	case source >= n || target >= n:
+		return EVRoute{}, false, fmt.Errorf("sssp: route %d->%d out of range for %d nodes", source, target, n)
+	case len(opts.Energy) != g.EdgeCount():
+		return EVRoute{}, false, fmt.Errorf("sssp: %d edge energies for %d edges", len(opts.Energy), g.EdgeCount())
+	case opts.Capacity <= 0 || opts.Initial < 0 || opts.Initial > opts.Capacity:
+		return EVRoute{}, false, fmt.Errorf("sssp: initial charge %v outside battery capacity %v", opts.Initial, opts.Capacity)
+	}
+	for v, c := range opts.Stations {
+		if v >= n || c.Rate <= 0 || c.Setup < 0 {
+			return EVRoute{}, false, fmt.Errorf("sssp: bad charging station at node %d", v)
+		}
+	}
+	limit := opts.MaxLabels
+	if limit <= 0 {
+		limit = 16 * (int(n) + g.EdgeCount())
+	}
+	best := make([]float32, n) // highest charge each node was settled with
+	for i := range best {
+		best[i] = -1
+	}
+	labels := []evLabel{{node: source, soc: opts.Initial, parent: -1}}
+	q := minHeap[float32]{}
+	q.push(heapItem[float32]{node: 0})
+	push := func(l evLabel) error {
+		if len(labels) >= limit {
+			return fmt.Errorf("sssp: EV search exceeded %d labels", limit)
+		}
+		labels = append(labels, l)
+		q.push(heapItem[float32]{node: uint32(len(labels) - 1), dist: l.time})
+		return nil
+	}
+	for {
+		it, ok := q.pop()
+		if !ok {
+			return EVRoute{}, false, nil
+		}
+		i := int32(it.node)
+		l := labels[i]
+		if l.soc <= best[l.node] {
+			continue
+		}
+		best[l.node] = l.soc
+		if l.node == target {
+			return evRoute(labels, i), true, nil
+		}
+		if c, ok := opts.Stations[l.node]; ok && l.soc < opts.Capacity {
+			t := l.time + c.Setup + (opts.Capacity-l.soc)/c.Rate
+			if err := push(evLabel{node: l.node, time: t, soc: opts.Capacity, parent: i}); err != nil {
+				return EVRoute{}, false, err
+			}
+		}
+		for e := g.offsets[l.node]; e < g.offsets[l.node+1]; e++ {
+			v := g.targets[e]
+			soc := min(l.soc-opts.Energy[e], opts.Capacity)
+			if soc < 0 || soc <= best[v] || math.IsInf(float64(g.weights[e]), 1) {
+				continue
+			}
+			if err := push(evLabel{node: v, time: l.time + g.weights[e], soc: soc, parent: i}); err != nil {
+				return EVRoute{}, false, err
+			}
+		}
+	}
+}
+
+// evRoute unwinds the labels ending at last into a route.
+func evRoute(labels []evLabel, last int32) EVRoute {
+	var chain []evLabel
+	for i := last; i >= 0; i = labels[i].parent {
+		chain = append(chain, labels[i])
+	}
+	r := EVRoute{Weight: labels[last].time}
+	for i := len(chain) - 1; i >= 0; i-- {
+		l := chain[i]
+		if k := len(r.Path); k > 0 && r.Path[k-1] == l.node {
+			prev := chain[i+1]
+			r.Stops = append(r.Stops, ChargeStop{Node: l.node, Added: l.soc - prev.soc, Weight: l.time - prev.time})
+			r.Charge[k-1] = l.soc
+			continue
+		}
+		r.Path = append(r.Path, l.node)
+		r.Charge = append(r.Charge, l.soc)
+	}
+	return r
+}
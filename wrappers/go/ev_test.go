@@ -0,0 +1,70 @@
+package sssp
+
+import (
+	"reflect"
+	"testing"
+)
+
+// evGraph: a fast road 0->1->3 that needs more charge than a full battery
+// holds unless the car stops at station 1, and a slow road 0->2->3. Edge IDs
+// run 0->1, 0->2, 1->3, 2->3.
+func evGraph(t *testing.T) *Graph {
+	t.Helper()
+	g, err := FromEdges(4, []Edge{{0, 1, 10}, {1, 3, 10}, {0, 2, 30}, {2, 3, 30}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestEVRouteChargesWhenNeeded(t *testing.T) {
+	g := evGraph(t)
+	opts := EVOptions{Energy: []float32{6, 4, 6, 4}, Capacity: 10, Initial: 10}
+	r, ok, err := g.EVRoute(0, 3, opts)
+	if err != nil || !ok {
+		t.Fatalf("%v %v", ok, err)
+	}
+	if r.Weight != 60 || !reflect.DeepEqual(r.Path, []uint32{0, 2, 3}) || len(r.Stops) != 0 {
+		t.Fatalf("without stations got %+v", r)
+	}
+
+	opts.Stations = map[uint32]Charger{1: {Rate: 1, Setup: 5}}
+	r, ok, _ = g.EVRoute(0, 3, opts)
+	// 10 to the station, 5 setup + 6 charging, 10 more.
+	if !ok || r.Weight != 31 || !reflect.DeepEqual(r.Path, []uint32{0, 1, 3}) {
+		t.Fatalf("with a station got %+v", r)
+	}
+	if want := []ChargeStop{{Node: 1, Added: 6, Weight: 11}}; !reflect.DeepEqual(r.Stops, want) {
+		t.Fatalf("stops %+v", r.Stops)
+	}
+	if want := []float32{10, 10, 4}; !reflect.DeepEqual(r.Charge, want) {
+		t.Fatalf("charge %v", r.Charge)
+	}
+
+	opts.Initial = 3
+	if _, ok, _ := g.EVRoute(0, 3, opts); ok {
+		t.Fatalf("route found on an empty battery")
+	}
+	if _, _, err := g.EVRoute(0, 3, EVOptions{Energy: opts.Energy[:2], Capacity: 1}); err == nil {
+		t.Fatalf("short energy slice accepted")
+	}
+}
+
+func TestEVRouteRecuperationIsCapped(t *testing.T) {
+	g, _ := FromEdges(3, []Edge{{0, 1, 1}, {1, 2, 1}})
+	g.SetElevations([]float32{500, 0, 0})
+	energy, err := g.EdgeEnergy(EnergyModel{PerMetre: 0.001, PerMetreClimbed: 0.01, Recuperation: 0.5}, []float32{1000, 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if energy[0] != 1-2.5 || energy[1] != 1 {
+		t.Fatalf("energy %v", energy)
+	}
+	r, ok, err := g.EVRoute(0, 2, EVOptions{Energy: energy, Capacity: 2, Initial: 1})
+	if err != nil || !ok {
+		t.Fatalf("%v %v", ok, err)
+	}
+	if want := []float32{1, 2, 1}; !reflect.DeepEqual(r.Charge, want) {
+		t.Fatalf("charge %v, want the descent capped at capacity", r.Charge)
+	}
+}
@@ -0,0 +1,109 @@
+package sssp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"slices"
+)
+
+// FrontierOptions configures Graph.RecordFrontier.
+type FrontierOptions struct {
+	// MaxMembers caps the members kept per frame; a larger frontier keeps
+	// a uniform sample of that many, sorted. 0 keeps every member and a
+	// negative value keeps only frame sizes.
+	MaxMembers int
+	// Seed drives the sampling, so recordings are reproducible.
+	Seed int64
+	// Stride is, for ModeBaseline, the number of settled nodes between
+	// frames; the default takes about 100 frames per run.
+	Stride int
+	// MaxFrames stops recording after that many frames; the run itself
+	// still completes. 0 means no limit.
+	MaxFrames int
+}
+
+// FrontierFrame is the frontier at one step of a run.
+type FrontierFrame struct {
+	// Bucket is the delta-stepping bucket being scanned, -1 for
+	// ModeBaseline, whose frames show the live heap entries.
+	Bucket  int      `json:"bucket"`
+	Size    int      `json:"size"`
+	Settled int      `json:"settled"` // nodes with final distances when the frame was taken
+	Members []uint32 `json:"members,omitempty"`
+	Sampled bool     `json:"sampled,omitempty"` // Members is a sample of Size nodes
+}
+
+// FrontierRecording is the sequence of frontiers of one run, for animating
+// how the algorithm explores the graph.
+type FrontierRecording struct {
+	Source    uint32          `json:"source"`
+	Mode      int             `json:"mode"`
+	NodeCount uint32          `json:"node_count"`
+	Frames    []FrontierFrame `json:"frames"`
+	Truncated bool            `json:"truncated,omitempty"` // MaxFrames cut the recording short
+}
+
+// WriteJSON writes the recording as one JSON document, frames in order.
+func (fr *FrontierRecording) WriteJSON(w io.Writer) error {
+	if err := json.NewEncoder(w).Encode(fr); err != nil {
+		return fmt.Errorf("sssp: frontier: %w", err)
+	}
+	return nil
+}
+
+// RecordFrontier runs mode from source and records a frame per step: the
+// nodes each light round of a delta-stepping bucket scans (for ModeStoc,
+// ModeGPU and the final run of ModeAutotune), or the live heap entries
+// every Stride settled nodes for ModeBaseline. It runs the pure-Go engine,
+// like RunSettled, and returns the run's result with the recording.
+func (g *Graph) RecordFrontier(source uint32, mode int, opts FrontierOptions) (*FrontierRecording, Result, error) {
+	rec := &FrontierRecording{Source: source, Mode: mode, NodeCount: g.NodeCount(), Frames: []FrontierFrame{}}
+	stride := opts.Stride
+	if stride <= 0 {
+		stride = max(1, int(g.NodeCount())/100)
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+	settled := 0
+	hooks := &runHooks{
+		visit:  func(uint32) { settled++ },
+		stride: uint64(stride),
+		frontier: func(bucket int, members []uint32) {
+			if opts.MaxFrames > 0 && len(rec.Frames) >= opts.MaxFrames {
+				rec.Truncated = true
+				return
+			}
+			f := FrontierFrame{Bucket: bucket, Size: len(members), Settled: settled}
+			switch {
+			case opts.MaxMembers < 0:
+			case opts.MaxMembers == 0 || len(members) <= opts.MaxMembers:
+				f.Members = slices.Clone(members)
+			default:
+				f.Members, f.Sampled = sampleNodes(rng, members, opts.MaxMembers), true
+			}
+			if f.Members != nil {
+				slices.Sort(f.Members)
+			}
+			rec.Frames = append(rec.Frames, f)
+		},
+	}
+	var res Result
+	if err := g.runGo(source, mode, &res, hooks); err != nil {
+		return nil, Result{}, err
+	}
+	return rec, res, nil
+}
+
+// sampleNodes draws k of nodes uniformly without replacement, leaving nodes
+// untouched.
+func sampleNodes(rng *rand.Rand, nodes []uint32, k int) []uint32 {
+	out := make([]uint32, k)
+	copy(out, nodes[:k])
+	for i := k; i < len(nodes); i++ { // reservoir sampling
+		if j := rng.Intn(i + 1); j < k {
+			out[j] = nodes[i]
+		}
+	}
+	return out
+}
@@ -0,0 +1,57 @@
+package sssp
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestRecordFrontier(t *testing.T) {
+	g, err := GenerateRandomGraph(2000, 4, 13, RandomGraphOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, res, err := g.RecordFrontier(3, ModeStoc, FrontierOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.Frames) == 0 || rec.Frames[0].Size != 1 || rec.Frames[0].Members[0] != 3 {
+		t.Fatalf("first frame %+v", rec.Frames)
+	}
+	scanned := make([]bool, g.NodeCount())
+	for _, f := range rec.Frames {
+		if len(f.Members) != f.Size || f.Sampled {
+			t.Fatalf("unsampled frame %+v", f)
+		}
+		for _, v := range f.Members {
+			scanned[v] = true
+		}
+	}
+	for v := range scanned {
+		if scanned[v] != res.Reachable(uint32(v)) {
+			t.Fatalf("node %d scanned %v, reachable %v", v, scanned[v], res.Reachable(uint32(v)))
+		}
+	}
+
+	rec, _, err = g.RecordFrontier(3, ModeBaseline, FrontierOptions{MaxMembers: 5, Stride: 50, MaxFrames: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.Frames) != 10 || !rec.Truncated {
+		t.Fatalf("%d frames, truncated %v", len(rec.Frames), rec.Truncated)
+	}
+	for i, f := range rec.Frames {
+		if f.Bucket != -1 || f.Settled != 50*(i+1) || len(f.Members) > 5 || f.Sampled != (f.Size > 5) {
+			t.Fatalf("frame %d: %+v", i, f)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := rec.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var back FrontierRecording
+	if err := json.Unmarshal(buf.Bytes(), &back); err != nil || len(back.Frames) != 10 || back.Frames[3].Size != rec.Frames[3].Size {
+		t.Fatalf("round trip: %v, %+v", err, back)
+	}
+}
@@ -0,0 +1,169 @@
+package sssp
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// RandomGraphOptions configures GenerateRandomGraph.
+type RandomGraphOptions struct {
+	Weights WeightDist
+	// Reachable guarantees that every node is reachable from node 0: the
+	// first n-1 edges form a random spanning arborescence rooted at 0 (each
+	// node hangs off a random earlier node of a shuffled order), and the
+	// rest are drawn as usual. There are then at least n-1 edges.
+	Reachable bool
+	// Workers generating edges in parallel; 0 means GOMAXPROCS. The graph
+	// does not depend on it.
+	Workers int
+}
+
+// GenerateRandomGraph returns a directed graph with n nodes and about
+// avgDegree*n edges whose endpoints are drawn uniformly, without self-loops.
+// The same seed gives the same graph. Each node's edges are sorted by
+// target.
+func GenerateRandomGraph(n uint32, avgDegree float64, seed int64, opts RandomGraphOptions) (*Graph, error) {
+	gen, err := randomGen(n, avgDegree, seed, opts)
+	if err != nil {
+		return nil, err
+	}
+	return gen.graph(opts.Workers), nil
+}
+
+// WriteRandomGraphSnapshot is GenerateRandomGraph writing straight into a
+// snapshot file, as WriteRMATSnapshot does.
+func WriteRandomGraphSnapshot(path string, n uint32, avgDegree float64, seed int64, opts RandomGraphOptions) error {
+	gen, err := randomGen(n, avgDegree, seed, opts)
+	if err != nil {
+		return err
+	}
+	return gen.writeSnapshot(path, opts.Workers)
+}
+
+func randomGen(n uint32, avgDegree float64, seed int64, opts RandomGraphOptions) (*edgeGen, error) {
+	if n < 2 && avgDegree > 0 {
+		return nil, fmt.Errorf("sssp: generate: %d nodes cannot have edges without self-loops", n)
+	}
+	if !(avgDegree >= 0) {
+		return nil, fmt.Errorf("sssp: generate: invalid average degree %v", avgDegree)
+	}
+	if err := opts.Weights.validate(); err != nil {
+		return nil, err
+	}
+	m := uint64(avgDegree*float64(n) + 0.5)
+	var order []uint32
+	if opts.Reachable && n > 1 {
+		m = max(m, uint64(n-1))
+		order = make([]uint32, n)
+		for i := range order {
+			order[i] = uint32(i)
+		}
+		r := rand.New(rand.NewSource(seed))
+		r.Shuffle(len(order)-1, func(i, j int) { order[i+1], order[j+1] = order[j+1], order[i+1] })
+	}
+	if err := checkEdgeCount(m); err != nil {
+		return nil, err
+	}
+	return &edgeGen{n: n, m: m, seed: seed, edge: func(r *rand.Rand, i uint64) Edge {
+		if i+1 < uint64(len(order)) {
+			parent := order[r.Int63n(int64(i+1))]
+			return Edge{From: parent, To: order[i+1], Weight: opts.Weights.sample(r)}
+		}
+		u := uint32(r.Int63n(int64(n)))
+		v := uint32(r.Int63n(int64(n - 1)))
+		if v >= u {
+			v++
+		}
+		return Edge{From: u, To: v, Weight: opts.Weights.sample(r)}
+	}}, nil
+}
+
+// GenerateBarabasiAlbert returns an undirected scale-free graph (each edge
+// stored in both directions with one weight) grown by preferential
+// attachment: it starts from a clique of m+1 nodes, and every further node
+// links to m distinct earlier nodes chosen with probability proportional to
+// their degree. Hubs reach degrees around sqrt(n).
+func GenerateBarabasiAlbert(n, m uint32, seed int64, weights WeightDist) (*Graph, error) {
+	if m == 0 || n <= m {
+		return nil, fmt.Errorf("sssp: generate: Barabási–Albert needs 0 < m < n, got n=%d m=%d", n, m)
+	}
+	if err := weights.validate(); err != nil {
+		return nil, err
+	}
+	r := rand.New(rand.NewSource(seed))
+	total := uint64(m)*uint64(m+1)/2 + uint64(n-m-1)*uint64(m)
+	edges := make([]Edge, 0, 2*total)
+	// ends lists both endpoints of every edge so far; a uniform pick from it
+	// is a degree-proportional pick of a node.
+	ends := make([]uint32, 0, 2*total)
+	link := func(u, v uint32) {
+		w := weights.sample(r)
+		edges = append(edges, Edge{From: u, To: v, Weight: w}, Edge{From: v, To: u, Weight: w})
+		ends = append(ends, u, v)
+	}
+	for u := uint32(0); u <= m; u++ {
+		for v := u + 1; v <= m; v++ {
+			link(u, v)
+		}
+	}
+	chosen := make(map[uint32]bool, m)
+	for u := m + 1; u < n; u++ {
+		clear(chosen)
+		picks := ends // new edges must not shift the distribution mid-node
+		for uint32(len(chosen)) < m {
+			v := picks[r.Intn(len(picks))]
+			if !chosen[v] {
+				chosen[v] = true
+				link(u, v)
+			}
+		}
+	}
+	return FromEdges(n, edges)
+}
+
+// GenerateWattsStrogatz returns an undirected small-world graph: a ring where
+// each node links to its k/2 nearest neighbours on either side, after which
+// each link's far end is rewired with probability beta to a uniformly chosen
+// node, avoiding self-loops and duplicate links. beta 0 keeps the ring (long
+// diameter); a few percent already gives logarithmic diameter. k must be even.
+func GenerateWattsStrogatz(n, k uint32, beta float64, seed int64, weights WeightDist) (*Graph, error) {
+	if k == 0 || k%2 != 0 || k >= n {
+		return nil, fmt.Errorf("sssp: generate: Watts–Strogatz needs an even 0 < k < n, got n=%d k=%d", n, k)
+	}
+	if !(beta >= 0 && beta <= 1) {
+		return nil, fmt.Errorf("sssp: generate: rewiring probability %v outside [0, 1]", beta)
+	}
+	if err := weights.validate(); err != nil {
+		return nil, err
+	}
+	r := rand.New(rand.NewSource(seed))
+	type link struct{ u, v uint32 }
+	key := func(u, v uint32) link { return link{min(u, v), max(u, v)} }
+	links := make([]link, 0, uint64(n)*uint64(k/2))
+	present := make(map[link]bool, cap(links))
+	for j := uint32(1); j <= k/2; j++ {
+		for u := uint32(0); u < n; u++ {
+			l := link{u, (u + j) % n}
+			links = append(links, l)
+			present[key(l.u, l.v)] = true
+		}
+	}
+	for i, l := range links {
+		if r.Float64() >= beta {
+			continue
+		}
+		v := uint32(r.Int63n(int64(n)))
+		if v == l.u || present[key(l.u, v)] {
+			continue // as in the original model, a blocked rewiring keeps the link
+		}
+		delete(present, key(l.u, l.v))
+		present[key(l.u, v)] = true
+		links[i].v = v
+	}
+	edges := make([]Edge, 0, 2*len(links))
+	for _, l := range links {
+		w := weights.sample(r)
+		edges = append(edges, Edge{From: l.u, To: l.v, Weight: w}, Edge{From: l.v, To: l.u, Weight: w})
+	}
+	return FromEdges(n, edges)
+}
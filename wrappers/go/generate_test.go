@@ -0,0 +1,112 @@
+package sssp
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestGenerateRandomGraph(t *testing.T) {
+	g, err := GenerateRandomGraph(1000, 2.5, 1, RandomGraphOptions{})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if g.NodeCount() != 1000 || g.EdgeCount() != 2500 {
+		t.Fatalf("%d nodes %d edges", g.NodeCount(), g.EdgeCount())
+	}
+	for _, e := range g.Edges() {
+		if e.From == e.To || e.Weight < 1 || e.Weight >= 10 {
+			t.Fatalf("bad edge %+v", e)
+		}
+	}
+	again, _ := GenerateRandomGraph(1000, 2.5, 1, RandomGraphOptions{})
+	if !reflect.DeepEqual(g.Edges(), again.Edges()) {
+		t.Fatalf("same seed gave different graphs")
+	}
+}
+
+func TestGenerateRandomGraphReachable(t *testing.T) {
+	// Sparse enough that most nodes would be unreachable without the option.
+	for _, avg := range []float64{0.5, 1.5} {
+		g, err := GenerateRandomGraph(3000, avg, 4, RandomGraphOptions{Reachable: true, Weights: ConstantWeights(1)})
+		if err != nil {
+			t.Fatalf("generate: %v", err)
+		}
+		if want := max(2999, int(avg*3000)); g.EdgeCount() != want {
+			t.Fatalf("avg %v: %d edges, want %d", avg, g.EdgeCount(), want)
+		}
+		res, err := g.Run(0, ModeBaseline)
+		if err != nil {
+			t.Fatalf("run: %v", err)
+		}
+		for v, d := range res.Dist {
+			if math.IsInf(float64(d), 1) {
+				t.Fatalf("avg %v: node %d unreachable", avg, v)
+			}
+		}
+	}
+}
+
+func TestGenerateBarabasiAlbert(t *testing.T) {
+	const n, m = 5000, 3
+	g, err := GenerateBarabasiAlbert(n, m, 7, UniformWeights(2, 3))
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if want := 2 * (m*(m+1)/2 + (n-m-1)*m); g.EdgeCount() != want {
+		t.Fatalf("%d edges, want %d", g.EdgeCount(), want)
+	}
+	off, _, _ := g.CSR()
+	maxDeg := uint32(0)
+	for u := 0; u < n; u++ {
+		d := off[u+1] - off[u]
+		if d < m {
+			t.Fatalf("node %d has degree %d < m", u, d)
+		}
+		maxDeg = max(maxDeg, d)
+	}
+	if maxDeg < 10*m {
+		t.Fatalf("max degree %d: no hubs", maxDeg)
+	}
+	res, err := g.Run(n-1, ModeBaseline)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	for v, d := range res.Dist {
+		if d < 0 || d > 3*n {
+			t.Fatalf("node %d unreachable (%v)", v, d)
+		}
+	}
+	if _, err := GenerateBarabasiAlbert(3, 3, 1, WeightDist{}); err == nil {
+		t.Fatalf("expected n <= m to fail")
+	}
+}
+
+func TestGenerateWattsStrogatzShrinksDiameter(t *testing.T) {
+	const n = 2000
+	eccentricity := func(beta float64) float32 {
+		g, err := GenerateWattsStrogatz(n, 4, beta, 3, WeightDist{})
+		if err != nil {
+			t.Fatalf("generate: %v", err)
+		}
+		if g.EdgeCount() != 2*n*2 {
+			t.Fatalf("beta %v: %d edges", beta, g.EdgeCount())
+		}
+		res, err := g.Run(0, ModeBaseline)
+		if err != nil {
+			t.Fatalf("run: %v", err)
+		}
+		var far float32
+		for _, d := range res.Dist {
+			far = max(far, d)
+		}
+		return far
+	}
+	ring, small := eccentricity(0), eccentricity(0.1)
+	if ring < 10*small {
+		t.Fatalf("eccentricity %v on the ring vs %v rewired: no small-world effect", ring, small)
+	}
+	if _, err := GenerateWattsStrogatz(10, 3, 0.1, 1, WeightDist{}); err == nil {
+		t.Fatalf("expected odd k to fail")
+	}
+}
@@ -0,0 +1,177 @@
+package sssp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// edgeGen describes a generator whose edges come in fixed-size chunks, each
+// drawn from its own seeded random stream. Any number of workers can then
+// produce them, and every pass over them yields the same edges, so the CSR
+// is built in two passes (count, then place) without an edge list.
+type edgeGen struct {
+	n    uint32
+	m    uint64
+	seed int64
+	// edge returns edge i, drawing from r, the stream of i's chunk.
+	edge func(r *rand.Rand, i uint64) Edge
+}
+
+// genChunk is the number of edges drawn from one random stream.
+const genChunk = 1 << 16
+
+// each calls fn for every edge, from up to workers goroutines at once.
+func (g *edgeGen) each(workers int, fn func(Edge)) {
+	chunks := int((g.m + genChunk - 1) / genChunk)
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = max(1, min(workers, chunks))
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for c := w; c < chunks; c += workers {
+				r := rand.New(rand.NewSource(streamSeed(g.seed, uint64(c))))
+				lo := uint64(c) * genChunk
+				for i := lo; i < min(lo+genChunk, g.m); i++ {
+					fn(g.edge(r, i))
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+// build fills offsets (n+1 entries, zeroed), targets and weights (m each).
+// Workers place edges in whatever order they finish, so each node's edges
+// are then sorted by target (and weight) to make the result deterministic.
+func (g *edgeGen) build(workers int, offsets, targets []uint32, weights []float32) {
+	g.each(workers, func(e Edge) { atomic.AddUint32(&offsets[e.From+1], 1) })
+	for u := uint32(0); u < g.n; u++ {
+		offsets[u+1] += offsets[u]
+	}
+	cursor := make([]uint32, g.n)
+	copy(cursor, offsets[:g.n])
+	g.each(workers, func(e Edge) {
+		i := atomic.AddUint32(&cursor[e.From], 1) - 1
+		targets[i], weights[i] = e.To, e.Weight
+	})
+	cursor = nil
+
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	const block = 1 << 12
+	var next atomic.Uint64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				lo := next.Add(block) - block
+				if lo >= uint64(g.n) {
+					return
+				}
+				for u := lo; u < min(lo+block, uint64(g.n)); u++ {
+					a, b := offsets[u], offsets[u+1]
+					sort.Sort(adjacency{targets[a:b], weights[a:b]})
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// graph builds the generated graph in memory.
+func (g *edgeGen) graph(workers int) *Graph {
+	offsets := make([]uint32, g.n+1)
+	targets := make([]uint32, g.m)
+	weights := make([]float32, g.m)
+	g.build(workers, offsets, targets, weights)
+	return &Graph{offsets: offsets, targets: targets, weights: weights}
+}
+
+// writeSnapshot builds the graph directly in a memory-mapped snapshot file
+// at path, so the arrays are backed by the page cache rather than the heap.
+func (g *edgeGen) writeSnapshot(path string, workers int) (err error) {
+	n, m := uint64(g.n), g.m
+	offAt, tgtAt, wtsAt, end := snapshotSections(n, m)
+	data, done, err := createMapped(path, int64(end))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if derr := done(); err == nil {
+			err = derr
+		}
+	}()
+	copy(data, snapshotMagic)
+	binary.LittleEndian.PutUint32(data[8:], snapshotVersion)
+	binary.LittleEndian.PutUint32(data[12:], snapshotWeightF32)
+	binary.LittleEndian.PutUint64(data[16:], n)
+	binary.LittleEndian.PutUint64(data[24:], m)
+	if !nativeLittleEndian {
+		// Views would be copies; build in memory and encode.
+		gr := g.graph(workers)
+		putUint32s(data[offAt:], gr.offsets)
+		putUint32s(data[tgtAt:], gr.targets)
+		for i, w := range gr.weights {
+			binary.LittleEndian.PutUint32(data[wtsAt+4*uint64(i):], math.Float32bits(w))
+		}
+		return nil
+	}
+	g.build(workers,
+		viewUint32(data[offAt:offAt+4*(n+1)]),
+		viewUint32(data[tgtAt:tgtAt+4*m]),
+		viewFloat32(data[wtsAt:wtsAt+4*m]))
+	return nil
+}
+
+// streamSeed derives the seed of independent random stream i (splitmix64).
+func streamSeed(seed int64, i uint64) int64 {
+	z := uint64(seed) + (i+1)*0x9e3779b97f4a7c15
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return int64(z ^ z>>31)
+}
+
+func putUint32s(b []byte, vs []uint32) {
+	for i, v := range vs {
+		binary.LittleEndian.PutUint32(b[4*i:], v)
+	}
+}
+
+// adjacency sorts one node's targets and weights together.
+type adjacency struct {
+	targets []uint32
+	weights []float32
+}
+
+func (a adjacency) Len() int { return len(a.targets) }
+func (a adjacency) Less(i, j int) bool {
+	if a.targets[i] != a.targets[j] {
+		return a.targets[i] < a.targets[j]
+	}
+	return a.weights[i] < a.weights[j]
+}
+func (a adjacency) Swap(i, j int) {
+	a.targets[i], a.targets[j] = a.targets[j], a.targets[i]
+	a.weights[i], a.weights[j] = a.weights[j], a.weights[i]
+}
+
+// checkEdgeCount rejects graphs whose offsets would overflow uint32.
+func checkEdgeCount(m uint64) error {
+	if m > math.MaxUint32 {
+		return fmt.Errorf("sssp: generate: %d edges exceed the 32-bit CSR offsets", m)
+	}
+	return nil
+}
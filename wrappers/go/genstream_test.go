@@ -0,0 +1,47 @@
+package sssp
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWriteSnapshotGeneratorsMatchInMemory(t *testing.T) {
+	dir := t.TempDir()
+	p := RMATParams{Scale: 11, EdgeFactor: 8, Weights: IntWeights(1, 100), Workers: 3}
+	want, err := GenerateRMAT(p, 21)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	path := filepath.Join(dir, "rmat.snap")
+	if err := WriteRMATSnapshot(path, p, 21); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	mg, err := OpenMapped(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer mg.Close()
+	if !reflect.DeepEqual(mg.Edges(), want.Edges()) {
+		t.Fatalf("snapshot differs from the in-memory graph")
+	}
+	off, tgt, wts := mg.CSR()
+	if _, err := NewGraphCSR(off, tgt, wts); err != nil {
+		t.Fatalf("invalid CSR: %v", err)
+	}
+
+	opts := RandomGraphOptions{Reachable: true}
+	path = filepath.Join(dir, "random.snap")
+	if err := WriteRandomGraphSnapshot(path, 5000, 3, 4, opts); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	rg, err := OpenSnapshot(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	opts.Workers = 1
+	serial, _ := GenerateRandomGraph(5000, 3, 4, opts)
+	if !reflect.DeepEqual(rg.Edges(), serial.Edges()) {
+		t.Fatalf("random snapshot differs from a single-worker build")
+	}
+}
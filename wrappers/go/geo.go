@@ -0,0 +1,106 @@
+package sssp
+
+import (
+	"fmt"
+	"math"
+)
+
+// Haversine returns the great-circle distance between a and b in metres on
+// a spherical Earth of mean radius.
+func Haversine(a, b LatLon) float64 {
+	const r = 6371008.8
+	rad := math.Pi / 180
+	dLat, dLon := (b.Lat-a.Lat)*rad, (b.Lon-a.Lon)*rad
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(a.Lat*rad)*math.Cos(b.Lat*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * r * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Coord returns the coordinate of node v, if g has coordinates.
+func (g *Graph) Coord(v uint32) (LatLon, bool) {
+	if g.coords == nil || v >= g.NodeCount() {
+		return LatLon{}, false
+	}
+	return g.coords[v], true
+}
+
+// BBox is a latitude/longitude rectangle in degrees, bounds included. A
+// MinLon greater than MaxLon wraps across the antimeridian.
+type BBox struct {
+	MinLat, MinLon, MaxLat, MaxLon float64
+}
+
+// Contains reports whether c lies in b.
+func (b BBox) Contains(c LatLon) bool {
+	if c.Lat < b.MinLat || c.Lat > b.MaxLat {
+		return false
+	}
+	if b.MinLon <= b.MaxLon {
+		return c.Lon >= b.MinLon && c.Lon <= b.MaxLon
+	}
+	return c.Lon >= b.MinLon || c.Lon <= b.MaxLon
+}
+
+// NodesIn returns the nodes whose coordinates lie in box, in ID order, for
+// picking sources and targets by location. It scans every node, and returns
+// nil if g has no coordinates.
+func (g *Graph) NodesIn(box BBox) []uint32 {
+	var out []uint32
+	for v, c := range g.coords {
+		if box.Contains(c) {
+			out = append(out, uint32(v))
+		}
+	}
+	return out
+}
+
+// ShortestPath returns the distance from source to target and the path
+// between them, or Unreachable and nil. It runs a pure-Go search that stops
+// at target. If g has coordinates the search is A*, guided by the
+// great-circle distance to target times the lowest cost per metre of any
+// edge; that bound never overestimates, so the result is exact, and it
+// prunes well when weights track distance, as travel times do. Without
+// coordinates it is Dijkstra's algorithm. Weights must be non-negative.
+// After a call to Components, and until g changes, the search also prunes
+// by strongly connected component: it returns Unreachable at once when
+// target's component comes before source's, and never enters components
+// after target's, from which target cannot be reached.
+func (g *Graph) ShortestPath(source, target uint32) (float32, []uint32, error) {
+	n := g.NodeCount()
+	if source >= n || target >= n {
+		return Unreachable, nil, fmt.Errorf("sssp: path %d->%d out of range for %d nodes", source, target, n)
+	}
+	return g.NewPathFinder().ShortestPath(source, target)
+}
+
+// geoCache is the A* scale of a graph at one version.
+type geoCache struct {
+	scale   float64
+	version uint64
+}
+
+// geoScale returns the A* scale: the lowest weight per metre of
+// great-circle distance over all edges, shaded down against rounding, or 0
+// if g has no coordinates or some edge is free. It is computed once per
+// change of g; queries racing to fill the cache compute the same value.
+func (g *Graph) geoScale() float64 {
+	if g.coords == nil {
+		return 0
+	}
+	if c := g.geo.Load(); c != nil && c.version == g.version {
+		return c.scale
+	}
+	k := math.Inf(1)
+	for u := uint32(0); u < g.NodeCount() && k > 0; u++ {
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			if m := Haversine(g.coords[u], g.coords[g.targets[e]]); m > 0 {
+				k = min(k, float64(g.weights[e])/m)
+			}
+		}
+	}
+	if math.IsInf(k, 1) || math.IsNaN(k) || k < 0 {
+		k = 0
+	}
+	c := &geoCache{scale: k * (1 - 1e-4), version: g.version}
+	g.geo.Store(c)
+	return c.scale
+}
@@ -0,0 +1,116 @@
+package sssp
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestHaversine(t *testing.T) {
+	// One degree of longitude on the equator.
+	if d := Haversine(LatLon{0, 0}, LatLon{0, 1}); math.Abs(d-111195) > 1 {
+		t.Fatalf("got %v m", d)
+	}
+	if d := Haversine(LatLon{52.5, 13.4}, LatLon{52.5, 13.4}); d != 0 {
+		t.Fatalf("same point %v m", d)
+	}
+}
+
+func TestNodesIn(t *testing.T) {
+	g, _ := FromEdges(4, nil)
+	if g.NodesIn(BBox{-90, -180, 90, 180}) != nil {
+		t.Fatalf("nodes without coordinates")
+	}
+	g.SetCoords([]LatLon{{0, 0}, {1, 179.5}, {1, -179.5}, {5, 5}})
+	if got := g.NodesIn(BBox{-1, -1, 2, 6}); !reflect.DeepEqual(got, []uint32{0}) {
+		t.Fatalf("box %v", got)
+	}
+	if got := g.NodesIn(BBox{0, 179, 2, -179}); !reflect.DeepEqual(got, []uint32{1, 2}) {
+		t.Fatalf("antimeridian box %v", got)
+	}
+}
+
+func TestShortestPathMatchesRun(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	const side = 12
+	coords := make([]LatLon, side*side)
+	var edges []Edge
+	for r := 0; r < side; r++ {
+		for c := 0; c < side; c++ {
+			v := uint32(r*side + c)
+			coords[v] = LatLon{Lat: 50 + float64(r)*0.01, Lon: 8 + float64(c)*0.01}
+			for _, d := range [][2]int{{0, 1}, {1, 0}, {0, -1}, {-1, 0}} {
+				rr, cc := r+d[0], c+d[1]
+				if rr < 0 || cc < 0 || rr >= side || cc >= side {
+					continue
+				}
+				w := uint32(rr*side + cc)
+				secs := float32(Haversine(coords[v], LatLon{50 + float64(rr)*0.01, 8 + float64(cc)*0.01}) / (5 + 25*rng.Float64()))
+				edges = append(edges, Edge{From: v, To: w, Weight: secs})
+			}
+		}
+	}
+	g, _ := FromEdges(side*side, edges)
+	plain, _ := FromEdges(side*side, edges)
+	g.SetCoords(coords)
+	if g.geoScale() <= 0 {
+		t.Fatalf("no A* scale with coordinates")
+	}
+	res, err := g.Run(0, ModeBaseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, target := range []uint32{0, 13, side*side - 1, 77} {
+		for _, gr := range []*Graph{g, plain} {
+			d, path, err := gr.ShortestPath(0, target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(float64(d-res.Dist[target])) > 1e-3 || path[0] != 0 || path[len(path)-1] != target {
+				t.Fatalf("target %d: %v %v, Run says %v", target, d, path, res.Dist[target])
+			}
+		}
+	}
+	if _, _, err := g.ShortestPath(0, side*side); err == nil {
+		t.Fatalf("out-of-range target accepted")
+	}
+	g.AddEdges([]Edge{{0, side*side - 1, 0}})
+	if d, _, _ := g.ShortestPath(0, side*side-1); d != 0 {
+		t.Fatalf("free edge missed after change: %v", d)
+	}
+}
+
+func TestShortestPathConcurrent(t *testing.T) {
+	// Queries sharing a graph fill its A* cache together; run with -race.
+	g, err := GenerateGrid(GridParams{Dims: []uint32{20, 20}}, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coords := make([]LatLon, g.NodeCount())
+	for v := range coords {
+		coords[v] = LatLon{Lat: float64(v/20) * 1e-3, Lon: float64(v%20) * 1e-3}
+	}
+	g.SetCoords(coords)
+	want, _ := g.Run(0, ModeBaseline)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(target uint32) {
+			defer wg.Done()
+			if d, _, err := g.ShortestPath(0, target); err != nil || d != want.Dist[target] {
+				t.Errorf("target %d: %v, %v, want %v", target, d, err, want.Dist[target])
+			}
+		}(uint32(100*i + 99))
+	}
+	wg.Wait()
+}
+
+func TestShortestPathUnreachable(t *testing.T) {
+	g, _ := FromEdges(3, []Edge{{0, 1, 1}})
+	d, path, err := g.ShortestPath(0, 2)
+	if err != nil || d != Unreachable || path != nil {
+		t.Fatalf("got %v %v %v", d, path, err)
+	}
+}
@@ -0,0 +1,156 @@
+package sssp
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// GeoJSON (RFC 7946) exports for results of Graph.Run on a graph with
+// coordinates attached (Graph.SetCoords). Positions are [lon, lat].
+
+type geoCollection struct {
+	Type     string       `json:"type"`
+	Features []geoFeature `json:"features"`
+}
+
+type geoFeature struct {
+	Type       string         `json:"type"`
+	Geometry   geoGeometry    `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+type geoGeometry struct {
+	Type        string `json:"type"`
+	Coordinates any    `json:"coordinates"`
+}
+
+func geoPosition(c LatLon) [2]float64 { return [2]float64{c.Lon, c.Lat} }
+
+// geoCoords returns the coordinates of the graph r was computed on.
+func (r *Result) geoCoords() ([]LatLon, error) {
+	if r.graph == nil || r.graph.coords == nil {
+		return nil, fmt.Errorf("sssp: geojson: no coordinates attached to the result's graph")
+	}
+	if len(r.graph.coords) != len(r.Dist) {
+		return nil, fmt.Errorf("sssp: geojson: result covers %d nodes, graph has %d", len(r.Dist), len(r.graph.coords))
+	}
+	return r.graph.coords, nil
+}
+
+// PathGeoJSON returns a FeatureCollection holding the shortest path to target
+// as one LineString feature, with "source", "target", "distance" and "nodes"
+// (the node ids along the path) as properties.
+func (r *Result) PathGeoJSON(target uint32) ([]byte, error) {
+	coords, err := r.geoCoords()
+	if err != nil {
+		return nil, err
+	}
+	path := r.Path(target)
+	if path == nil {
+		return nil, fmt.Errorf("sssp: geojson: node %d is unreachable", target)
+	}
+	line := make([][2]float64, len(path))
+	for i, v := range path {
+		line[i] = geoPosition(coords[v])
+	}
+	if len(line) == 1 {
+		line = append(line, line[0]) // a LineString needs two positions
+	}
+	return json.Marshal(geoCollection{Type: "FeatureCollection", Features: []geoFeature{{
+		Type:     "Feature",
+		Geometry: geoGeometry{Type: "LineString", Coordinates: line},
+		Properties: map[string]any{
+			"source":   path[0],
+			"target":   target,
+			"distance": r.Dist[target],
+			"nodes":    path,
+		},
+	}}})
+}
+
+// IsochroneGeoJSON returns a FeatureCollection with one feature per limit,
+// in ascending order, outlining the area reachable within that distance. The
+// outline is the convex hull of the nodes within the limit and of the points
+// part way along edges leaving them where the limit runs out, interpolated
+// linearly between the endpoint coordinates. It is a Polygon with "limit" and
+// "reached" (how many nodes are within the limit) as properties, or a
+// MultiPoint when the reachable points are fewer than three or collinear.
+func (r *Result) IsochroneGeoJSON(limits ...float32) ([]byte, error) {
+	coords, err := r.geoCoords()
+	if err != nil {
+		return nil, err
+	}
+	limits = append([]float32(nil), limits...)
+	sort.Slice(limits, func(i, j int) bool { return limits[i] < limits[j] })
+	g := r.graph
+	fc := geoCollection{Type: "FeatureCollection", Features: []geoFeature{}}
+	for _, limit := range limits {
+		if math.IsNaN(float64(limit)) || limit < 0 {
+			return nil, fmt.Errorf("sssp: geojson: invalid isochrone limit %v", limit)
+		}
+		var pts [][2]float64
+		inside := 0
+		for u, d := range r.Dist {
+			if !(d <= limit) {
+				continue
+			}
+			inside++
+			pts = append(pts, geoPosition(coords[u]))
+			for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+				v, w := g.targets[e], g.weights[e]
+				if d+w <= limit || w <= 0 {
+					continue // the whole edge is inside
+				}
+				f := float64((limit - d) / w)
+				a, b := coords[u], coords[v]
+				pts = append(pts, [2]float64{a.Lon + f*(b.Lon-a.Lon), a.Lat + f*(b.Lat-a.Lat)})
+			}
+		}
+		props := map[string]any{"limit": limit, "reached": inside}
+		hull := convexHull(pts)
+		geom := geoGeometry{Type: "MultiPoint", Coordinates: hull}
+		if len(hull) >= 3 {
+			geom = geoGeometry{Type: "Polygon", Coordinates: [][][2]float64{append(hull, hull[0])}}
+		}
+		fc.Features = append(fc.Features, geoFeature{Type: "Feature", Geometry: geom, Properties: props})
+	}
+	return json.Marshal(fc)
+}
+
+// convexHull returns the hull of pts counter-clockwise (the RFC 7946 winding
+// for exterior rings), without repeating the first point.
+func convexHull(pts [][2]float64) [][2]float64 {
+	sort.Slice(pts, func(i, j int) bool {
+		return pts[i][0] < pts[j][0] || pts[i][0] == pts[j][0] && pts[i][1] < pts[j][1]
+	})
+	uniq := pts[:0]
+	for _, p := range pts {
+		if len(uniq) == 0 || p != uniq[len(uniq)-1] {
+			uniq = append(uniq, p)
+		}
+	}
+	pts = uniq
+	if len(pts) < 3 {
+		return append([][2]float64{}, pts...)
+	}
+	cross := func(o, a, b [2]float64) float64 {
+		return (a[0]-o[0])*(b[1]-o[1]) - (a[1]-o[1])*(b[0]-o[0])
+	}
+	hull := make([][2]float64, 0, 2*len(pts))
+	for _, p := range pts { // lower hull
+		for len(hull) >= 2 && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	for i, lower := len(pts)-2, len(hull)+1; i >= 0; i-- { // upper hull
+		p := pts[i]
+		for len(hull) >= lower && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	return hull[:len(hull)-1]
+}
@@ -0,0 +1,85 @@
+package sssp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type testFeatureCollection struct {
+	Type     string
+	Features []struct {
+		Geometry struct {
+			Type        string
+			Coordinates json.RawMessage
+		}
+		Properties map[string]any
+	}
+}
+
+func TestPathGeoJSON(t *testing.T) {
+	g, err := LoadDIMACS(strings.NewReader(sampleGR))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	res, err := g.Run(0, ModeBaseline)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if _, err := res.PathGeoJSON(3); err == nil {
+		t.Fatalf("expected an error without coordinates")
+	}
+	if err := g.SetCoords([]LatLon{{0, 0}}); err == nil {
+		t.Fatalf("expected a length mismatch to fail")
+	}
+	g.SetCoords([]LatLon{{50, 8}, {50, 8.1}, {50.1, 8.1}, {50.1, 8}})
+
+	b, err := res.PathGeoJSON(3)
+	if err != nil {
+		t.Fatalf("path: %v", err)
+	}
+	var fc testFeatureCollection
+	if err := json.Unmarshal(b, &fc); err != nil {
+		t.Fatalf("decode %s: %v", b, err)
+	}
+	f := fc.Features[0]
+	if fc.Type != "FeatureCollection" || f.Geometry.Type != "LineString" || f.Properties["distance"] != 4.0 {
+		t.Fatalf("feature %s", b)
+	}
+	if got := string(f.Geometry.Coordinates); got != "[[8,50],[8.1,50],[8.1,50.1],[8,50.1]]" {
+		t.Fatalf("coordinates %s", got)
+	}
+}
+
+func TestIsochroneGeoJSON(t *testing.T) {
+	// A star on a unit grid: 1 east, 2 north, 3 west, 4 south of the centre.
+	g, _ := FromEdges(5, []Edge{{0, 1, 2}, {0, 2, 2}, {0, 3, 2}, {0, 4, 6}})
+	g.SetCoords([]LatLon{{0, 0}, {0, 1}, {1, 0}, {0, -1}, {-1, 0}})
+	res, err := g.Run(0, ModeBaseline)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	b, err := res.IsochroneGeoJSON(3, 0)
+	if err != nil {
+		t.Fatalf("isochrone: %v", err)
+	}
+	var fc testFeatureCollection
+	if err := json.Unmarshal(b, &fc); err != nil {
+		t.Fatalf("decode %s: %v", b, err)
+	}
+	if len(fc.Features) != 2 {
+		t.Fatalf("features %s", b)
+	}
+	// Limit 0: only the source, part way along no edge.
+	if f := fc.Features[0]; f.Geometry.Type != "MultiPoint" || f.Properties["reached"] != 1.0 {
+		t.Fatalf("limit 0: %s", b)
+	}
+	// Limit 3: the three near leaves plus half of the edge south, CCW.
+	f := fc.Features[1]
+	if f.Geometry.Type != "Polygon" || f.Properties["reached"] != 4.0 {
+		t.Fatalf("limit 3: %s", b)
+	}
+	if got, want := string(f.Geometry.Coordinates), "[[[-1,0],[0,-0.5],[1,0],[0,1],[-1,0]]]"; got != want {
+		t.Fatalf("ring %s, want %s", got, want)
+	}
+}
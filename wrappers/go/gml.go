@@ -0,0 +1,277 @@
+package sssp
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/your-org/optimized-sssp-go/internal/decompress"
+)
+
+// GML ("Graph Modelling Language", as written by NetworkX and igraph) is a
+// tree of "key value" pairs where a value is a number, a quoted string or a
+// [ ... ] list:
+//
+//	graph [
+//	  directed 1
+//	  node [ id 0 label "depot" ]
+//	  edge [ source 0 target 1 weight 2.5 ]
+//	]
+//
+// Graphs are undirected unless "directed 1" is given. Strings carry HTML
+// entities (&quot;, &amp;, &#NNN;) instead of backslash escapes.
+
+// GMLOptions selects which edge attribute holds the weight.
+type GMLOptions struct {
+	// WeightKey names the edge attribute holding the weight. Empty means
+	// unweighted (every edge gets DefaultWeight).
+	WeightKey string
+	// DefaultWeight applies to edges without the weight attribute. Zero means 1.
+	DefaultWeight float32
+}
+
+type gmlPair struct {
+	key string
+	val any // string, float64 or []gmlPair
+}
+
+type gmlLexer struct {
+	r    *bufio.Reader
+	line int
+}
+
+// next returns the next token: "[", "]", a key or number (raw text), or a
+// quoted string (quoted reports which). io.EOF at end of input.
+func (lx *gmlLexer) next() (tok string, quoted bool, err error) {
+	for {
+		c, _, err := lx.r.ReadRune()
+		if err != nil {
+			return "", false, err
+		}
+		switch {
+		case c == '\n':
+			lx.line++
+		case unicode.IsSpace(c):
+		case c == '#':
+			if _, err := lx.r.ReadString('\n'); err != nil {
+				return "", false, err
+			}
+			lx.line++
+		case c == '[' || c == ']':
+			return string(c), false, nil
+		case c == '"':
+			s, err := lx.r.ReadString('"')
+			if err != nil {
+				return "", false, fmt.Errorf("sssp: gml line %d: unterminated string", lx.line)
+			}
+			lx.line += strings.Count(s, "\n")
+			return html.UnescapeString(s[:len(s)-1]), true, nil
+		default:
+			var b strings.Builder
+			b.WriteRune(c)
+			for {
+				c, _, err := lx.r.ReadRune()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					return "", false, err
+				}
+				if unicode.IsSpace(c) || c == '[' || c == ']' || c == '"' {
+					lx.r.UnreadRune()
+					break
+				}
+				b.WriteRune(c)
+			}
+			return b.String(), false, nil
+		}
+	}
+}
+
+// list parses pairs up to the closing ']' (or EOF when top is set).
+func (lx *gmlLexer) list(top bool) ([]gmlPair, error) {
+	var out []gmlPair
+	for {
+		key, quoted, err := lx.next()
+		if err == io.EOF && top {
+			return out, nil
+		}
+		if err == io.EOF {
+			return nil, fmt.Errorf("sssp: gml line %d: missing ']'", lx.line)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if key == "]" && !quoted {
+			if top {
+				return nil, fmt.Errorf("sssp: gml line %d: unexpected ']'", lx.line)
+			}
+			return out, nil
+		}
+		if quoted || key == "[" {
+			return nil, fmt.Errorf("sssp: gml line %d: expected key, got %q", lx.line, key)
+		}
+		tok, quoted, err := lx.next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("sssp: gml line %d: key %q without value", lx.line, key)
+		}
+		if err != nil {
+			return nil, err
+		}
+		var val any
+		switch {
+		case quoted:
+			val = tok
+		case tok == "[":
+			if val, err = lx.list(false); err != nil {
+				return nil, err
+			}
+		case tok == "]":
+			return nil, fmt.Errorf("sssp: gml line %d: key %q without value", lx.line, key)
+		default:
+			f, err := strconv.ParseFloat(tok, 64)
+			if err != nil {
+				return nil, fmt.Errorf("sssp: gml line %d: bad number %q", lx.line, tok)
+			}
+			val = f
+		}
+		out = append(out, gmlPair{key, val})
+	}
+}
+
+// LoadGML reads the first "graph" block of a GML file. Node i of the result is
+// the i-th declared node and names[i] is its label (its id if unlabelled).
+// Undirected graphs get both directions of every edge.
+func LoadGML(r io.Reader, opts GMLOptions) (g *Graph, names []string, err error) {
+	if r, err = decompress.Reader(r); err != nil {
+		return nil, nil, err
+	}
+	lx := &gmlLexer{r: bufio.NewReader(r), line: 1}
+	top, err := lx.list(true)
+	if err != nil {
+		return nil, nil, err
+	}
+	var body []gmlPair
+	for _, p := range top {
+		if l, ok := p.val.([]gmlPair); ok && p.key == "graph" {
+			body = l
+			break
+		}
+	}
+	if body == nil {
+		return nil, nil, fmt.Errorf("sssp: gml: no graph block")
+	}
+	def := opts.DefaultWeight
+	if def == 0 {
+		def = 1
+	}
+	directed := false
+	index := make(map[float64]uint32)
+	for _, p := range body {
+		switch p.key {
+		case "directed":
+			d, ok := p.val.(float64)
+			directed = ok && d != 0
+		case "node":
+			attrs, ok := p.val.([]gmlPair)
+			if !ok {
+				return nil, nil, fmt.Errorf("sssp: gml: node is not a list")
+			}
+			id, ok := gmlNumber(attrs, "id")
+			if !ok {
+				return nil, nil, fmt.Errorf("sssp: gml: node %d has no numeric id", len(names))
+			}
+			if _, dup := index[id]; dup {
+				return nil, nil, fmt.Errorf("sssp: gml: duplicate node id %v", id)
+			}
+			name := strconv.FormatFloat(id, 'g', -1, 64)
+			if l, ok := gmlString(attrs, "label"); ok {
+				name = l
+			}
+			index[id] = uint32(len(names))
+			names = append(names, name)
+		}
+	}
+	var edges []Edge
+	for _, p := range body {
+		if p.key != "edge" {
+			continue
+		}
+		attrs, ok := p.val.([]gmlPair)
+		if !ok {
+			return nil, nil, fmt.Errorf("sssp: gml: edge is not a list")
+		}
+		s, ok1 := gmlNumber(attrs, "source")
+		t, ok2 := gmlNumber(attrs, "target")
+		u, ok3 := index[s]
+		v, ok4 := index[t]
+		if !ok1 || !ok2 || !ok3 || !ok4 {
+			return nil, nil, fmt.Errorf("sssp: gml: edge %d has missing or undeclared source/target", len(edges))
+		}
+		w := def
+		if opts.WeightKey != "" {
+			if x, ok := gmlNumber(attrs, opts.WeightKey); ok {
+				w = float32(x)
+			}
+		}
+		edges = append(edges, Edge{From: u, To: v, Weight: w})
+		if !directed && u != v {
+			edges = append(edges, Edge{From: v, To: u, Weight: w})
+		}
+	}
+	g, err = FromEdges(uint32(len(names)), edges)
+	return g, names, err
+}
+
+func gmlNumber(attrs []gmlPair, key string) (float64, bool) {
+	for _, p := range attrs {
+		if p.key == key {
+			f, ok := p.val.(float64)
+			return f, ok
+		}
+	}
+	return 0, false
+}
+
+func gmlString(attrs []gmlPair, key string) (string, bool) {
+	for _, p := range attrs {
+		if p.key == key {
+			s, ok := p.val.(string)
+			return s, ok
+		}
+	}
+	return "", false
+}
+
+// WriteGML writes g as a directed GML graph with weights under opts.WeightKey
+// ("weight" if empty). names, if non-nil, become node labels.
+func (g *Graph) WriteGML(w io.Writer, names []string, opts GMLOptions) error {
+	if names != nil && len(names) != int(g.NodeCount()) {
+		return fmt.Errorf("sssp: gml: %d names for %d nodes", len(names), g.NodeCount())
+	}
+	key := opts.WeightKey
+	if key == "" {
+		key = "weight"
+	}
+	bw := bufio.NewWriter(w)
+	bw.WriteString("graph [\n  directed 1\n")
+	for u := uint32(0); u < g.NodeCount(); u++ {
+		if names != nil {
+			fmt.Fprintf(bw, "  node [\n    id %d\n    label \"%s\"\n  ]\n", u, html.EscapeString(names[u]))
+		} else {
+			fmt.Fprintf(bw, "  node [\n    id %d\n  ]\n", u)
+		}
+	}
+	for u := uint32(0); u < g.NodeCount(); u++ {
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			fmt.Fprintf(bw, "  edge [\n    source %d\n    target %d\n    %s %s\n  ]\n",
+				u, g.targets[e], key, strconv.FormatFloat(float64(g.weights[e]), 'g', -1, 32))
+		}
+	}
+	bw.WriteString("]\n")
+	return bw.Flush()
+}
@@ -0,0 +1,53 @@
+package sssp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// Shape of nx.write_gml output for an undirected graph.
+const sampleGML = `# written by hand
+graph [
+  node [
+    id 0
+    label "depot &quot;A&quot;"
+  ]
+  node [ id 7 label "b" ]
+  node [ id 3 ]
+  edge [ source 0 target 7 cost 2.5 ]
+  edge [ source 7 target 3 ]
+]
+`
+
+func TestLoadGMLUndirectedWithWeightKey(t *testing.T) {
+	g, names, err := LoadGML(strings.NewReader(sampleGML), GMLOptions{WeightKey: "cost", DefaultWeight: 4})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(names) != 3 || names[0] != `depot "A"` || names[2] != "3" || g.EdgeCount() != 4 {
+		t.Fatalf("names %q edges %d", names, g.EdgeCount())
+	}
+	res, err := g.Run(2, ModeBaseline)
+	if err != nil || res.Dist[0] != 6.5 {
+		t.Fatalf("run: %v dist %v", err, res.Dist)
+	}
+	if _, _, err := LoadGML(strings.NewReader("graph [ edge [ source 0 target 1 ] ]"), GMLOptions{}); err == nil {
+		t.Fatalf("expected undeclared node error")
+	}
+}
+
+func TestGMLRoundTripDirected(t *testing.T) {
+	g, names, err := LoadGML(strings.NewReader(sampleGML), GMLOptions{WeightKey: "cost"})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := g.WriteGML(&buf, names, GMLOptions{WeightKey: "cost"}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	again, names2, err := LoadGML(&buf, GMLOptions{WeightKey: "cost"})
+	if err != nil || names2[0] != names[0] || again.EdgeCount() != g.EdgeCount() || again.Edges()[0].Weight != 2.5 {
+		t.Fatalf("round trip: %v %q", err, names2)
+	}
+}
@@ -0,0 +1,505 @@
+package sssp
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+// Edge is a weighted directed edge.
+type Edge struct {
+	From, To uint32
+	Weight   float32
+}
+
+// Graph is a directed graph in CSR form, the layout Run consumes directly:
+// the out-edges of u are targets/weights[offsets[u]:offsets[u+1]].
+type Graph struct {
+	offsets []uint32
+	targets []uint32
+	weights []float32
+	coords  []LatLon // optional, indexed by node
+
+	// owned marks the arrays as allocated here rather than passed to
+	// NewGraphCSR, so AddEdges may update them in place.
+	owned bool
+
+	// changes logs weight changes since the last TakeWeightChanges, one
+	// entry per edge: changeAt maps an edge ID to the index of its entry.
+	changes  []WeightChange
+	changeAt map[uint32]int
+
+	// Attribute columns by name; see attrs.go.
+	nodeAttrs, edgeAttrs map[string]attrColumn
+
+	// version counts changes to edges, weights and coordinates, so views
+	// derived from g (see Layers) notice they are stale.
+	version uint64
+
+	// geo caches the A* scale of ShortestPath for one version. It is
+	// published atomically, as concurrent queries may fill it.
+	geo atomic.Pointer[geoCache]
+
+	// fp caches Fingerprint for one version, published like geo.
+	fp atomic.Pointer[fingerprintCache]
+
+	// scc caches Components for one version; sccMu serializes computing
+	// them.
+	scc   atomic.Pointer[sccCache]
+	sccMu sync.Mutex
+
+	// rev caches ReverseView; revMu serializes building it.
+	rev   atomic.Pointer[ReverseView]
+	revMu sync.Mutex
+}
+
+// WeightChange records one edge whose weight UpdateEdgeWeight changed: Old
+// is its weight before the first change since the log was last taken, New
+// its current weight.
+type WeightChange struct {
+	From, To uint32
+	Old, New float32
+}
+
+// LatLon is a WGS84 coordinate in degrees.
+type LatLon struct{ Lat, Lon float64 }
+
+// NewGraphCSR wraps existing CSR arrays after checking they are consistent.
+// The slices are used as-is, not copied.
+func NewGraphCSR(offsets, targets []uint32, weights []float32) (*Graph, error) {
+	if len(offsets) == 0 || offsets[0] != 0 {
+		return nil, fmt.Errorf("sssp: offsets must start with 0")
+	}
+	if len(targets) != len(weights) || int(offsets[len(offsets)-1]) != len(targets) {
+		return nil, fmt.Errorf("sssp: offsets end at %d but there are %d targets and %d weights", offsets[len(offsets)-1], len(targets), len(weights))
+	}
+	if err := checkStructure(offsets, targets); err != nil {
+		return nil, fmt.Errorf("sssp: %w", err)
+	}
+	return &Graph{offsets: offsets, targets: targets, weights: weights}, nil
+}
+
+// checkStructure is the O(n+m) part of NewGraphCSR's checks: offsets never
+// decrease and every target is a node. Callers add their own prefix.
+func checkStructure(offsets, targets []uint32) error {
+	for u := 1; u < len(offsets); u++ {
+		if offsets[u] < offsets[u-1] {
+			return fmt.Errorf("offsets decrease at node %d", u-1)
+		}
+	}
+	n := uint32(len(offsets) - 1)
+	for e, v := range targets {
+		if v >= n {
+			return fmt.Errorf("edge %d targets node %d, graph has %d nodes", e, v, n)
+		}
+	}
+	return nil
+}
+
+// checkCSR checks the lengths of arrays passed to the package-level
+// runners, which hand them on without copying: n+1 offsets from 0 to the
+// number of targets, and as many weights as targets. It costs O(1), so it
+// does not check that offsets never decrease or that targets are below n;
+// NewGraphCSR does.
+func checkCSR(n uint32, offsets, targets []uint32, weights []float32) error {
+	if n == 0 {
+		return fmt.Errorf("sssp: graph has no nodes")
+	}
+	if uint64(len(offsets)) != uint64(n)+1 {
+		return fmt.Errorf("sssp: %d offsets for %d nodes, want %d", len(offsets), n, uint64(n)+1)
+	}
+	if offsets[0] != 0 {
+		return fmt.Errorf("sssp: offsets must start with 0")
+	}
+	if len(targets) != len(weights) || int(offsets[n]) != len(targets) {
+		return fmt.Errorf("sssp: offsets end at %d but there are %d targets and %d weights", offsets[n], len(targets), len(weights))
+	}
+	return nil
+}
+
+// checkBatch is checkCSR for a batch, which also needs its len(sources)*n
+// distance matrix to fit in a slice.
+func checkBatch(n uint32, offsets, targets []uint32, weights []float32, sources []uint32) error {
+	if err := checkCSR(n, offsets, targets, weights); err != nil {
+		return err
+	}
+	if uint64(len(sources)) > uint64(math.MaxInt)/uint64(n) {
+		return fmt.Errorf("sssp: %d sources by %d nodes overflow the distance matrix", len(sources), n)
+	}
+	return nil
+}
+
+// FromEdges builds a graph with n nodes. Edges keep their input order within
+// each source node. Endpoints must be < n.
+func FromEdges(n uint32, edges []Edge) (*Graph, error) {
+	offsets := make([]uint32, n+1)
+	for i, e := range edges {
+		if e.From >= n || e.To >= n {
+			return nil, fmt.Errorf("sssp: edge %d (%d->%d) out of range for %d nodes", i, e.From, e.To, n)
+		}
+		offsets[e.From+1]++
+	}
+	for u := uint32(0); u < n; u++ {
+		offsets[u+1] += offsets[u]
+	}
+	targets := make([]uint32, len(edges))
+	weights := make([]float32, len(edges))
+	next := make([]uint32, n)
+	copy(next, offsets[:n])
+	for _, e := range edges {
+		i := next[e.From]
+		targets[i], weights[i] = e.To, e.Weight
+		next[e.From]++
+	}
+	return &Graph{offsets: offsets, targets: targets, weights: weights}, nil
+}
+
+// NewGraphWithCapacity returns a graph with n nodes and no edges whose edge
+// arrays are presized for edges edges, so AddEdges can fill them without
+// reallocating.
+func NewGraphWithCapacity(n uint32, edges int) *Graph {
+	return &Graph{
+		offsets: make([]uint32, n+1),
+		targets: make([]uint32, 0, edges),
+		weights: make([]float32, 0, edges),
+		owned:   true,
+	}
+}
+
+// AddEdges inserts edges in one pass over the CSR arrays: each node's new
+// edges follow its existing ones, in input order, as FromEdges would place
+// them. Endpoints must be < NodeCount; on error g is unchanged. A call costs
+// O(nodes + edges), so add edges in large batches. Spare capacity from
+// NewGraphWithCapacity (or an earlier AddEdges) is filled in place, which
+// invalidates slices returned by CSR.
+func (g *Graph) AddEdges(edges []Edge) error {
+	n := g.NodeCount()
+	added := make([]uint32, n)
+	for i, e := range edges {
+		if e.From >= n || e.To >= n {
+			return fmt.Errorf("sssp: edge %d (%d->%d) out of range for %d nodes", i, e.From, e.To, n)
+		}
+		added[e.From]++
+	}
+	if len(edges) == 0 {
+		return nil
+	}
+	m := len(g.targets) + len(edges)
+	if uint64(m) > uint64(^uint32(0)) {
+		return fmt.Errorf("sssp: %d edges overflow the 32-bit offsets", m)
+	}
+	targets, weights := g.targets, g.weights
+	if !g.owned || cap(targets) < m || cap(weights) < m {
+		// Grow like append, so repeated batches stay amortized linear.
+		c := max(m, 2*len(targets))
+		targets, weights = make([]uint32, m, c), make([]float32, m, c)
+		if !g.owned {
+			g.offsets = append([]uint32(nil), g.offsets...)
+			g.owned = true
+		}
+	} else {
+		targets, weights = targets[:m], weights[:m]
+	}
+	// Move each node's old edges to their new start, last node first so an
+	// in-place move never overwrites edges not yet moved.
+	var idx []int32
+	if g.keepsEdgeIDs() {
+		idx = make([]int32, m)
+		for i := range idx {
+			idx[i] = -1
+		}
+	}
+	shift := uint32(len(edges))
+	next := make([]uint32, n)
+	for u := int64(n) - 1; u >= 0; u-- {
+		shift -= added[u]
+		lo, hi := g.offsets[u], g.offsets[u+1]
+		copy(targets[lo+shift:], g.targets[lo:hi])
+		copy(weights[lo+shift:], g.weights[lo:hi])
+		for e := lo; idx != nil && e < hi; e++ {
+			idx[e+shift] = int32(e)
+		}
+		next[u] = hi + shift
+		g.offsets[u+1] = hi + shift + added[u]
+	}
+	for _, e := range edges {
+		i := next[e.From]
+		targets[i], weights[i] = e.To, e.Weight
+		next[e.From]++
+	}
+	g.targets, g.weights = targets, weights
+	g.moveEdges(idx)
+	g.dropReverse()
+	return nil
+}
+
+// RemoveEdge deletes every edge from u to v and reports how many there
+// were. Node ids are unchanged, so later queries see the graph as if the
+// edges had never been added. It compacts the edge arrays in place, costing
+// O(nodes + edges); see RemoveEdges to delete many at once.
+func (g *Graph) RemoveEdge(u, v uint32) int {
+	if u >= g.NodeCount() || g.offsets[u] == g.offsets[u+1] {
+		return 0
+	}
+	return g.RemoveEdges(func(e Edge) bool { return e.From == u && e.To == v })
+}
+
+// RemoveNode detaches node: it deletes its out- and in-edges and reports
+// how many. The node keeps its id and stays in NodeCount, isolated: a run
+// from it reaches only itself and no run reaches it.
+func (g *Graph) RemoveNode(node uint32) int {
+	if node >= g.NodeCount() {
+		return 0
+	}
+	return g.RemoveEdges(func(e Edge) bool { return e.From == node || e.To == node })
+}
+
+// RemoveEdges deletes the edges drop selects in one pass, keeping the order
+// of the rest, and reports how many it deleted. Arrays passed to
+// NewGraphCSR are copied rather than modified.
+func (g *Graph) RemoveEdges(drop func(Edge) bool) int {
+	n := g.NodeCount()
+	var idx []int32 // old ID of each kept edge
+	if g.keepsEdgeIDs() {
+		idx = make([]int32, 0, len(g.targets))
+	}
+	kept, removed := uint32(0), 0
+	for u := uint32(0); u < n; u++ {
+		lo, hi := g.offsets[u], g.offsets[u+1]
+		if removed > 0 {
+			g.offsets[u] = kept
+		}
+		for e := lo; e < hi; e++ {
+			if drop(Edge{From: u, To: g.targets[e], Weight: g.weights[e]}) {
+				if removed == 0 {
+					g.own() // nothing written yet
+				}
+				removed++
+				continue
+			}
+			if removed > 0 {
+				g.targets[kept], g.weights[kept] = g.targets[e], g.weights[e]
+			}
+			if idx != nil {
+				idx = append(idx, int32(e))
+			}
+			kept++
+		}
+	}
+	if removed > 0 {
+		g.offsets[n] = kept
+		g.targets, g.weights = g.targets[:kept], g.weights[:kept]
+		g.moveEdges(idx)
+		g.dropReverse()
+	}
+	return removed
+}
+
+// UpdateEdgeWeight sets the weight of every edge from u to v to w in
+// O(degree of u), and logs the edges it changes for TakeWeightChanges. It fails,
+// leaving g unchanged, if there is no such edge or w is NaN.
+func (g *Graph) UpdateEdgeWeight(u, v uint32, w float32) error {
+	return g.UpdateEdgeWeights([]Edge{{From: u, To: v, Weight: w}})
+}
+
+// UpdateEdgeWeights applies UpdateEdgeWeight for each update, in order. It
+// checks every update before changing anything, so on error g is
+// unchanged.
+func (g *Graph) UpdateEdgeWeights(updates []Edge) error {
+	n := g.NodeCount()
+	for i, up := range updates {
+		if math.IsNaN(float64(up.Weight)) {
+			return fmt.Errorf("sssp: update %d (%d->%d) has weight NaN", i, up.From, up.To)
+		}
+		found := false
+		if up.From < n {
+			for e := g.offsets[up.From]; e < g.offsets[up.From+1] && !found; e++ {
+				found = g.targets[e] == up.To
+			}
+		}
+		if !found {
+			return fmt.Errorf("sssp: update %d: no edge %d->%d", i, up.From, up.To)
+		}
+	}
+	if len(updates) > 0 {
+		g.own()
+	}
+	for _, up := range updates {
+		for e := g.offsets[up.From]; e < g.offsets[up.From+1]; e++ {
+			if g.targets[e] == up.To && g.weights[e] != up.Weight {
+				g.logChange(up.From, e, up.Weight)
+				g.weights[e] = up.Weight
+				g.version++
+			}
+		}
+	}
+	return nil
+}
+
+// logChange records that edge e, from u, gets weight w, updating the
+// edge's entry if it already has one, so the log never holds more entries
+// than edges.
+func (g *Graph) logChange(u, e uint32, w float32) {
+	if i, ok := g.changeAt[e]; ok {
+		g.changes[i].New = w
+		return
+	}
+	if g.changeAt == nil {
+		g.changeAt = make(map[uint32]int)
+	}
+	g.changeAt[e] = len(g.changes)
+	g.changes = append(g.changes, WeightChange{From: u, To: g.targets[e], Old: g.weights[e], New: w})
+}
+
+// keepsEdgeIDs reports whether an edge change must pass moveEdges where
+// each edge went.
+func (g *Graph) keepsEdgeIDs() bool { return len(g.edgeAttrs) > 0 || len(g.changeAt) > 0 }
+
+// moveEdges carries what is kept per edge ID through an edge change; idx[e]
+// is the old ID of the edge now at e, -1 for a new edge. Log entries of
+// removed edges stay, but no longer take updates.
+func (g *Graph) moveEdges(idx []int32) {
+	g.moveEdgeAttrs(idx)
+	if len(g.changeAt) == 0 {
+		return
+	}
+	at := make(map[uint32]int, len(g.changeAt))
+	for e, old := range idx {
+		if i, ok := g.changeAt[uint32(old)]; ok && old >= 0 {
+			at[uint32(e)] = i
+		}
+	}
+	g.changeAt = at
+}
+
+// WeightsDirty reports whether edge weights changed since the last
+// TakeWeightChanges.
+func (g *Graph) WeightsDirty() bool { return len(g.changes) > 0 }
+
+// TakeWeightChanges returns the edges whose weight changed since the
+// previous call, each once, in the order of their first change, and clears
+// the log. Code that keeps results up to date across updates consumes it
+// to find the affected edges. Repeated updates of an edge share one entry,
+// so the log holds at most one entry per edge whether or not it is taken.
+// An edge changed back to its old weight keeps an entry with Old equal to
+// New. Edges added or removed are not logged.
+func (g *Graph) TakeWeightChanges() []WeightChange {
+	c := g.changes
+	g.changes, g.changeAt = nil, nil
+	return c
+}
+
+// own copies arrays the caller passed to NewGraphCSR before they are
+// modified.
+func (g *Graph) own() {
+	if !g.owned {
+		g.offsets = append([]uint32(nil), g.offsets...)
+		g.targets = append([]uint32(nil), g.targets...)
+		g.weights = append([]float32(nil), g.weights...)
+		g.owned = true
+	}
+}
+
+// NodeCount returns the number of nodes.
+func (g *Graph) NodeCount() uint32 { return uint32(len(g.offsets) - 1) }
+
+// EdgeCount returns the number of directed edges.
+func (g *Graph) EdgeCount() int { return len(g.targets) }
+
+// CSR exposes the underlying arrays. Callers must not modify them.
+func (g *Graph) CSR() (offsets, targets []uint32, weights []float32) {
+	return g.offsets, g.targets, g.weights
+}
+
+// Degree returns the number of out-edges of u, 0 if u is out of range.
+func (g *Graph) Degree(u uint32) int {
+	if u >= g.NodeCount() {
+		return 0
+	}
+	return int(g.offsets[u+1] - g.offsets[u])
+}
+
+// Neighbors returns the targets of u's out-edges, in CSR order, repeating a
+// target once per parallel edge. It is a view of the CSR arrays: callers
+// must not modify it, and it is invalidated by later changes to g.
+func (g *Graph) Neighbors(u uint32) []uint32 {
+	if u >= g.NodeCount() {
+		return nil
+	}
+	return g.targets[g.offsets[u]:g.offsets[u+1]]
+}
+
+// ForEachNeighbor calls fn for each out-edge of u in CSR order until fn
+// returns false. It does not allocate.
+func (g *Graph) ForEachNeighbor(u uint32, fn func(to uint32, w float32) bool) {
+	if u >= g.NodeCount() {
+		return
+	}
+	for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+		if !fn(g.targets[e], g.weights[e]) {
+			return
+		}
+	}
+}
+
+// HasEdge reports whether there is an edge from u to v, in O(degree of u).
+func (g *Graph) HasEdge(u, v uint32) bool {
+	_, ok := g.EdgeWeight(u, v)
+	return ok
+}
+
+// EdgeWeight returns the weight of the edge from u to v, the smallest one
+// if there are parallel edges, and whether there is such an edge.
+func (g *Graph) EdgeWeight(u, v uint32) (float32, bool) {
+	w, ok := float32(0), false
+	g.ForEachNeighbor(u, func(to uint32, x float32) bool {
+		if to == v && (!ok || x < w) {
+			w, ok = x, true
+		}
+		return true
+	})
+	return w, ok
+}
+
+// Edges returns every edge in CSR order.
+func (g *Graph) Edges() []Edge {
+	out := make([]Edge, 0, len(g.targets))
+	for u := uint32(0); u < g.NodeCount(); u++ {
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			out = append(out, Edge{From: u, To: g.targets[e], Weight: g.weights[e]})
+		}
+	}
+	return out
+}
+
+// SetCoords attaches a coordinate to every node, enabling the GeoJSON
+// exports on results of g.Run, NodesIn and A* in ShortestPath. nil detaches
+// them. The slice is kept, not
+// copied.
+func (g *Graph) SetCoords(coords []LatLon) error {
+	if coords != nil && len(coords) != int(g.NodeCount()) {
+		return fmt.Errorf("sssp: %d coordinates for %d nodes", len(coords), g.NodeCount())
+	}
+	g.coords = coords
+	g.version++
+	return nil
+}
+
+// Coords returns the attached node coordinates, or nil.
+func (g *Graph) Coords() []LatLon { return g.coords }
+
+// Run executes mode from source; see the package-level Run. The result
+// remembers g for the exports that need its edges or coordinates.
+func (g *Graph) Run(source uint32, mode int) (Result, error) {
+	res, err := Run(g.NodeCount(), g.offsets, g.targets, g.weights, source, mode)
+	if err == nil {
+		res.graph = g
+	}
+	return res, err
+}
+
+// RunBatch executes mode from every source; see the package-level RunBatch.
+func (g *Graph) RunBatch(sources []uint32, mode int) (BatchResult, error) {
+	return RunBatch(g.NodeCount(), g.offsets, g.targets, g.weights, sources, mode)
+}
@@ -0,0 +1,178 @@
+package sssp
+
+import (
+	"fmt"
+	"math"
+)
+
+// Graph64 is a Graph whose CSR offsets are 64-bit, for graphs with 2^32
+// edges or more. Node IDs stay uint32, and Result.Pred is int32, so it
+// holds at most 2^31-1 nodes. The native library takes 32-bit offsets, so
+// Run always uses the pure-Go engine: Stats.Version is 0 and ModeStoc uses
+// the fixed delta described for builds without cgo. Use Graph while the
+// edges fit; it is smaller and can run natively.
+type Graph64 struct {
+	offsets []uint64
+	targets []uint32
+	weights []float32
+}
+
+// maxNodes64 is the node limit of Graph64, set by the int32 predecessors.
+const maxNodes64 = math.MaxInt32
+
+// NewGraph64CSR wraps existing CSR arrays after checking they are
+// consistent, as NewGraphCSR does. The slices are used as-is, not copied.
+func NewGraph64CSR(offsets []uint64, targets []uint32, weights []float32) (*Graph64, error) {
+	if len(offsets) == 0 || offsets[0] != 0 {
+		return nil, fmt.Errorf("sssp: offsets must start with 0")
+	}
+	if len(offsets)-1 > maxNodes64 {
+		return nil, fmt.Errorf("sssp: %d nodes, Graph64 holds at most %d", len(offsets)-1, maxNodes64)
+	}
+	if len(targets) != len(weights) || offsets[len(offsets)-1] != uint64(len(targets)) {
+		return nil, fmt.Errorf("sssp: offsets end at %d but there are %d targets and %d weights", offsets[len(offsets)-1], len(targets), len(weights))
+	}
+	for u := 1; u < len(offsets); u++ {
+		if offsets[u] < offsets[u-1] {
+			return nil, fmt.Errorf("sssp: offsets decrease at node %d", u-1)
+		}
+	}
+	n := uint32(len(offsets) - 1)
+	for e, v := range targets {
+		if v >= n {
+			return nil, fmt.Errorf("sssp: edge %d targets node %d, graph has %d nodes", e, v, n)
+		}
+	}
+	return &Graph64{offsets: offsets, targets: targets, weights: weights}, nil
+}
+
+// FromEdges64 builds a Graph64 with n nodes, placing edges as FromEdges
+// does.
+func FromEdges64(n uint32, edges []Edge) (*Graph64, error) {
+	if n > maxNodes64 {
+		return nil, fmt.Errorf("sssp: %d nodes, Graph64 holds at most %d", n, maxNodes64)
+	}
+	offsets := make([]uint64, n+1)
+	for i, e := range edges {
+		if e.From >= n || e.To >= n {
+			return nil, fmt.Errorf("sssp: edge %d (%d->%d) out of range for %d nodes", i, e.From, e.To, n)
+		}
+		offsets[e.From+1]++
+	}
+	for u := uint32(0); u < n; u++ {
+		offsets[u+1] += offsets[u]
+	}
+	targets := make([]uint32, len(edges))
+	weights := make([]float32, len(edges))
+	next := make([]uint64, n)
+	copy(next, offsets[:n])
+	for _, e := range edges {
+		i := next[e.From]
+		targets[i], weights[i] = e.To, e.Weight
+		next[e.From]++
+	}
+	return &Graph64{offsets: offsets, targets: targets, weights: weights}, nil
+}
+
+// Wide returns g as a Graph64 sharing its targets and weights, so weight
+// updates made through g later show through.
+func (g *Graph) Wide() *Graph64 {
+	offsets := make([]uint64, len(g.offsets))
+	for i, o := range g.offsets {
+		offsets[i] = uint64(o)
+	}
+	return &Graph64{offsets: offsets, targets: g.targets, weights: g.weights}
+}
+
+// Narrow returns g as a Graph sharing its targets and weights, or an error
+// if it has too many edges for 32-bit offsets.
+func (g *Graph64) Narrow() (*Graph, error) {
+	if m := g.EdgeCount(); uint64(m) > math.MaxUint32 {
+		return nil, fmt.Errorf("sssp: %d edges overflow the 32-bit offsets", m)
+	}
+	offsets := make([]uint32, len(g.offsets))
+	for i, o := range g.offsets {
+		offsets[i] = uint32(o)
+	}
+	return &Graph{offsets: offsets, targets: g.targets, weights: g.weights}, nil
+}
+
+// NodeCount returns the number of nodes.
+func (g *Graph64) NodeCount() uint32 { return uint32(len(g.offsets) - 1) }
+
+// EdgeCount returns the number of directed edges.
+func (g *Graph64) EdgeCount() int { return len(g.targets) }
+
+// CSR exposes the underlying arrays. Callers must not modify them.
+func (g *Graph64) CSR() (offsets []uint64, targets []uint32, weights []float32) {
+	return g.offsets, g.targets, g.weights
+}
+
+// Degree returns the number of out-edges of u, 0 if u is out of range.
+func (g *Graph64) Degree(u uint32) int {
+	if u >= g.NodeCount() {
+		return 0
+	}
+	return int(g.offsets[u+1] - g.offsets[u])
+}
+
+// Neighbors returns the targets of u's out-edges, as Graph.Neighbors does.
+func (g *Graph64) Neighbors(u uint32) []uint32 {
+	if u >= g.NodeCount() {
+		return nil
+	}
+	return g.targets[g.offsets[u]:g.offsets[u+1]]
+}
+
+// ForEachNeighbor calls fn for each out-edge of u in CSR order until fn
+// returns false.
+func (g *Graph64) ForEachNeighbor(u uint32, fn func(to uint32, w float32) bool) {
+	if u >= g.NodeCount() {
+		return
+	}
+	for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+		if !fn(g.targets[e], g.weights[e]) {
+			return
+		}
+	}
+}
+
+// EdgeWeight returns the smallest weight of the edges from u to v, and
+// whether there is one.
+func (g *Graph64) EdgeWeight(u, v uint32) (float32, bool) {
+	w, ok := float32(0), false
+	g.ForEachNeighbor(u, func(to uint32, x float32) bool {
+		if to == v && (!ok || x < w) {
+			w, ok = x, true
+		}
+		return true
+	})
+	return w, ok
+}
+
+// HasEdge reports whether there is an edge from u to v.
+func (g *Graph64) HasEdge(u, v uint32) bool {
+	_, ok := g.EdgeWeight(u, v)
+	return ok
+}
+
+// Run executes mode from source on the pure-Go engine.
+func (g *Graph64) Run(source uint32, mode int) (Result, error) {
+	return g.RunSettled(source, mode, nil)
+}
+
+// RunSettled is Run calling settled for every reachable node as its
+// distance becomes final; see Graph.RunSettled.
+func (g *Graph64) RunSettled(source uint32, mode int, settled SettledFunc) (Result, error) {
+	n := g.NodeCount()
+	if err := checkGoRun(n, source, mode); err != nil {
+		return Result{}, err
+	}
+	res := Result{Dist: make([]float32, n), Pred: make([]int32, n)}
+	var hooks *runHooks
+	if settled != nil {
+		hooks = &runHooks{visit: func(u uint32) { settled(u, res.Dist[u], res.Pred[u]) }}
+	}
+	res.Stats = runGoCSR(g.offsets, g.targets, g.weights, source, mode, res.Dist, res.Pred, hooks)
+	return res, nil
+}
@@ -0,0 +1,53 @@
+package sssp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGraph64MatchesGraph(t *testing.T) {
+	g, err := GenerateRandomGraph(400, 4, 3, RandomGraphOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := g.Wide()
+	if w.NodeCount() != g.NodeCount() || w.EdgeCount() != g.EdgeCount() || w.Degree(7) != g.Degree(7) {
+		t.Fatalf("shape %d/%d, want %d/%d", w.NodeCount(), w.EdgeCount(), g.NodeCount(), g.EdgeCount())
+	}
+	for _, mode := range []int{ModeBaseline, ModeStoc, ModeAutotune} {
+		want, err := g.RunSettled(5, mode, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := w.Run(5, mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got.Dist, want.Dist) {
+			t.Fatalf("mode %d: distances differ", mode)
+		}
+	}
+	back, err := w.Narrow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back.Edges(), g.Edges()) {
+		t.Fatalf("narrowed graph differs")
+	}
+
+	e, err := FromEdges64(3, []Edge{{0, 1, 2}, {1, 2, 3}, {0, 2, 9}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var order []uint32
+	res, err := e.RunSettled(0, ModeBaseline, func(u uint32, _ float32, _ int32) { order = append(order, u) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Dist[2] != 5 || !reflect.DeepEqual(order, []uint32{0, 1, 2}) || !e.HasEdge(0, 2) {
+		t.Fatalf("dist %v, order %v", res.Dist, order)
+	}
+	if _, err := NewGraph64CSR([]uint64{0, 2}, []uint32{0}, []float32{1}); err == nil {
+		t.Fatalf("inconsistent offsets accepted")
+	}
+}
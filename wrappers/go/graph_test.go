@@ -0,0 +1,191 @@
+package sssp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFromEdgesGroupsBySource(t *testing.T) {
+	g, err := FromEdges(3, []Edge{{2, 0, 1}, {0, 1, 2}, {0, 2, 3}})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	off, tgt, _ := g.CSR()
+	if off[1] != 2 || off[3] != 3 || tgt[0] != 1 || tgt[1] != 2 || tgt[2] != 0 {
+		t.Fatalf("unexpected CSR %v %v", off, tgt)
+	}
+	if _, err := FromEdges(2, []Edge{{0, 2, 1}}); err == nil {
+		t.Fatalf("expected range error")
+	}
+}
+
+func TestNewGraphCSRValidates(t *testing.T) {
+	if _, err := NewGraphCSR([]uint32{0, 1, 1}, []uint32{1}, []float32{1}); err != nil {
+		t.Fatalf("valid CSR rejected: %v", err)
+	}
+	if _, err := NewGraphCSR([]uint32{0, 2, 1}, []uint32{1}, []float32{1}); err == nil {
+		t.Fatalf("inconsistent CSR accepted")
+	}
+	if _, err := NewGraphCSR([]uint32{0, 1, 1}, []uint32{5}, []float32{1}); err == nil {
+		t.Fatalf("out-of-range target accepted")
+	}
+}
+
+func TestAddEdgesMatchesFromEdges(t *testing.T) {
+	src, err := GenerateRandomGraph(500, 4, 21, RandomGraphOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := src.Edges()
+	// Shuffle sources across batches so every batch touches many nodes.
+	for i := range all {
+		j := (i * 7919) % len(all)
+		all[i], all[j] = all[j], all[i]
+	}
+	g := NewGraphWithCapacity(500, len(all))
+	_, tgt0, _ := g.CSR()
+	for lo := 0; lo < len(all); lo += 700 {
+		if err := g.AddEdges(all[lo:min(lo+700, len(all))]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, _ := FromEdges(500, all)
+	if !reflect.DeepEqual(g.Edges(), want.Edges()) {
+		t.Fatalf("batched insertion differs from FromEdges")
+	}
+	if _, tgt, _ := g.CSR(); &tgt[:1][0] != &tgt0[:1][0] {
+		t.Fatalf("presized graph reallocated")
+	}
+	if err := g.AddEdges([]Edge{{0, 1, 1}, {0, 500, 1}}); err == nil || g.EdgeCount() != len(all) {
+		t.Fatalf("out-of-range edge: err %v, %d edges", err, g.EdgeCount())
+	}
+
+	// Beyond the capacity, and on a graph over caller-owned arrays.
+	callerOff := []uint32{0, 1, 1}
+	csr, _ := NewGraphCSR(callerOff, []uint32{1}, []float32{2})
+	if err := csr.AddEdges([]Edge{{1, 0, 3}, {0, 0, 4}}); err != nil {
+		t.Fatal(err)
+	}
+	off, tgt, wts := csr.CSR()
+	if !reflect.DeepEqual(off, []uint32{0, 2, 3}) || !reflect.DeepEqual(tgt, []uint32{1, 0, 0}) || !reflect.DeepEqual(wts, []float32{2, 4, 3}) {
+		t.Fatalf("CSR %v %v %v", off, tgt, wts)
+	}
+	if callerOff[2] != 1 {
+		t.Fatalf("caller's offsets modified: %v", callerOff)
+	}
+}
+
+func TestRemoveEdgesAndNodes(t *testing.T) {
+	// 0->1 (1), 1->2 (1), 0->2 (5), 0->1 (3), 2->3 (1)
+	callerOff := []uint32{0, 3, 4, 5, 5}
+	g, err := NewGraphCSR(callerOff, []uint32{1, 2, 1, 2, 3}, []float32{1, 5, 3, 1, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := g.RemoveEdge(0, 1); n != 2 || g.EdgeCount() != 3 {
+		t.Fatalf("removed %d parallel edges, %d left", n, g.EdgeCount())
+	}
+	if callerOff[1] != 3 {
+		t.Fatalf("caller's offsets modified: %v", callerOff)
+	}
+	if n := g.RemoveEdge(0, 3) + g.RemoveEdge(9, 0); n != 0 {
+		t.Fatalf("removed %d missing edges", n)
+	}
+	res, err := g.Run(0, ModeBaseline)
+	if err != nil || res.Dist[2] != 5 || res.Reachable(1) {
+		t.Fatalf("after removing 0->1: dist %v, err %v", res.Dist, err)
+	}
+	if n := g.RemoveNode(2); n != 3 || g.EdgeCount() != 0 || g.NodeCount() != 4 {
+		t.Fatalf("removed %d edges of node 2, %d left", n, g.EdgeCount())
+	}
+	res, err = g.Run(2, ModeBaseline)
+	if err != nil || len(res.ReachableNodes()) != 1 {
+		t.Fatalf("isolated node reaches %v, err %v", res.ReachableNodes(), err)
+	}
+	want, _ := FromEdges(4, nil)
+	if !reflect.DeepEqual(g.Edges(), want.Edges()) {
+		t.Fatalf("edges left: %v", g.Edges())
+	}
+}
+
+func TestUpdateEdgeWeights(t *testing.T) {
+	weights := []float32{1, 5, 3, 1}
+	// 0->1 (1), 0->2 (5), 0->1 (3), 1->2 (1)
+	g, err := NewGraphCSR([]uint32{0, 3, 4, 4}, []uint32{1, 2, 1, 2}, weights)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.UpdateEdgeWeight(0, 1, 2); err != nil {
+		t.Fatal(err)
+	}
+	if weights[0] != 1 {
+		t.Fatalf("caller's weights modified: %v", weights)
+	}
+	if err := g.UpdateEdgeWeights([]Edge{{1, 2, 0.5}, {2, 0, 1}}); err == nil || !g.WeightsDirty() {
+		t.Fatalf("missing edge accepted: %v", err)
+	}
+	if _, _, w := g.CSR(); w[3] != 1 {
+		t.Fatalf("failed bulk update applied: %v", w)
+	}
+	if err := g.UpdateEdgeWeights([]Edge{{1, 2, 0.5}, {0, 2, 5}}); err != nil {
+		t.Fatal(err)
+	}
+	got := g.TakeWeightChanges()
+	want := []WeightChange{{0, 1, 1, 2}, {0, 1, 3, 2}, {1, 2, 1, 0.5}}
+	if !reflect.DeepEqual(got, want) || g.WeightsDirty() {
+		t.Fatalf("changes %v, want %v", got, want)
+	}
+	res, err := g.Run(0, ModeBaseline)
+	if err != nil || res.Dist[2] != 2.5 {
+		t.Fatalf("dist %v, err %v", res.Dist, err)
+	}
+}
+
+func TestWeightChangeLogCoalesces(t *testing.T) {
+	g, err := FromEdges(3, []Edge{{0, 1, 1}, {0, 2, 4}, {1, 2, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		if err := g.UpdateEdgeWeight(1, 2, float32(i+2)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Removing 0->1 shifts the ID of 1->2; its entry must follow it.
+	g.RemoveEdge(0, 1)
+	if err := g.UpdateEdgeWeights([]Edge{{1, 2, 7}, {0, 2, 3}}); err != nil {
+		t.Fatal(err)
+	}
+	got := g.TakeWeightChanges()
+	want := []WeightChange{{1, 2, 1, 7}, {0, 2, 4, 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("changes %v, want %v", got, want)
+	}
+}
+
+func TestEdgeAccessors(t *testing.T) {
+	g, err := FromEdges(4, []Edge{{0, 1, 4}, {0, 2, 1}, {0, 1, 2}, {2, 3, 7}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Degree(0) != 3 || g.Degree(3) != 0 || g.Degree(9) != 0 {
+		t.Fatalf("degrees %d %d %d", g.Degree(0), g.Degree(3), g.Degree(9))
+	}
+	if nb := g.Neighbors(0); !reflect.DeepEqual(nb, []uint32{1, 2, 1}) || g.Neighbors(9) != nil {
+		t.Fatalf("neighbors %v", nb)
+	}
+	if w, ok := g.EdgeWeight(0, 1); !ok || w != 2 || !g.HasEdge(2, 3) || g.HasEdge(3, 2) || g.HasEdge(9, 0) {
+		t.Fatalf("edge 0->1 weight %v, %v", w, ok)
+	}
+	var seen []uint32
+	g.ForEachNeighbor(0, func(to uint32, w float32) bool {
+		seen = append(seen, to)
+		return w != 1 // stop after 0->2
+	})
+	if !reflect.DeepEqual(seen, []uint32{1, 2}) {
+		t.Fatalf("visited %v", seen)
+	}
+	if a := testing.AllocsPerRun(10, func() { g.ForEachNeighbor(0, func(uint32, float32) bool { return true }) }); a != 0 {
+		t.Fatalf("ForEachNeighbor allocates %v times", a)
+	}
+}
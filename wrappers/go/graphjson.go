@@ -0,0 +1,339 @@
+package sssp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/your-org/optimized-sssp-go/internal/decompress"
+)
+
+// Canonical JSON graph format (version 1):
+//
+//	{
+//	  "version": 1,
+//	  "directed": true,
+//	  "node_count": 3,
+//	  "nodes": [{"id": 0, "attributes": {...}}],
+//	  "edges": [{"source": 0, "target": 1, "weight": 2.5, "attributes": {...}}],
+//	  "attributes": {...}
+//	}
+//
+// node_count is authoritative; "nodes" is optional and only carries per-node
+// attributes. Undirected graphs ("directed": false) list each edge once.
+// Node and edge "attributes" become the graph's attributes (see attrs.go):
+// each name is one column, and an entity without the name gets the zero
+// value. JSON does not record Go types, so decoded columns are []float64,
+// []string or []bool, or []any if a name mixes kinds. Graph-level
+// "attributes" are accepted but not retained. Weights must be finite.
+
+const graphJSONVersion = 1
+
+type jsonEdge struct {
+	Source     uint32         `json:"source"`
+	Target     uint32         `json:"target"`
+	Weight     *float32       `json:"weight"`
+	Attributes map[string]any `json:"attributes"`
+}
+
+type jsonNode struct {
+	ID         uint32         `json:"id"`
+	Attributes map[string]any `json:"attributes"`
+}
+
+// MarshalJSON encodes g in the canonical JSON graph format.
+func (g *Graph) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := g.EncodeJSON(&buf); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// UnmarshalJSON decodes the canonical JSON graph format into g.
+func (g *Graph) UnmarshalJSON(data []byte) error {
+	dec, err := DecodeJSON(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	g.offsets, g.targets, g.weights, g.coords = dec.offsets, dec.targets, dec.weights, dec.coords
+	g.owned, g.changes, g.changeAt = dec.owned, nil, nil
+	g.nodeAttrs, g.edgeAttrs = dec.nodeAttrs, dec.edgeAttrs
+	g.dropReverse()
+	return nil
+}
+
+// EncodeJSON streams g to w one node or edge per line, without building the
+// document in memory. Attribute values must marshal to JSON.
+func (g *Graph) EncodeJSON(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "{\"version\":%d,\"directed\":true,\"node_count\":%d,", graphJSONVersion, g.NodeCount())
+	if len(g.nodeAttrs) > 0 {
+		bw.WriteString("\"nodes\":[")
+		for u := uint32(0); u < g.NodeCount(); u++ {
+			if u > 0 {
+				bw.WriteByte(',')
+			}
+			fmt.Fprintf(bw, "\n{\"id\":%d,\"attributes\":", u)
+			if err := writeJSONAttrs(bw, g.nodeAttrs, int(u)); err != nil {
+				return err
+			}
+			bw.WriteByte('}')
+		}
+		bw.WriteString("],")
+	}
+	bw.WriteString("\"edges\":[")
+	first := true
+	for u := uint32(0); u < g.NodeCount(); u++ {
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			wt := float64(g.weights[e])
+			if math.IsInf(wt, 0) || math.IsNaN(wt) {
+				return fmt.Errorf("sssp: json: edge %d->%d has non-finite weight", u, g.targets[e])
+			}
+			if !first {
+				bw.WriteByte(',')
+			}
+			first = false
+			fmt.Fprintf(bw, "\n{\"source\":%d,\"target\":%d,\"weight\":%s", u, g.targets[e], strconv.FormatFloat(wt, 'g', -1, 32))
+			if len(g.edgeAttrs) > 0 {
+				bw.WriteString(",\"attributes\":")
+				if err := writeJSONAttrs(bw, g.edgeAttrs, int(e)); err != nil {
+					return err
+				}
+			}
+			bw.WriteByte('}')
+		}
+	}
+	bw.WriteString("]}\n")
+	return bw.Flush()
+}
+
+// writeJSONAttrs writes entry i of every column as one JSON object, names
+// sorted.
+func writeJSONAttrs(bw *bufio.Writer, attrs map[string]attrColumn, i int) error {
+	bw.WriteByte('{')
+	for k, name := range attrNames(attrs) {
+		v, err := json.Marshal(attrs[name].value(i))
+		if err != nil {
+			return fmt.Errorf("sssp: json: attribute %q: %w", name, err)
+		}
+		if k > 0 {
+			bw.WriteByte(',')
+		}
+		key, _ := json.Marshal(name)
+		bw.Write(key)
+		bw.WriteByte(':')
+		bw.Write(v)
+	}
+	bw.WriteByte('}')
+	return nil
+}
+
+// jsonColumn builds an attribute column from decoded JSON values, nil where
+// an entity lacks the attribute.
+func jsonColumn(vals []any) attrColumn {
+	var nums, strs, bools, other int
+	for _, v := range vals {
+		switch v.(type) {
+		case nil:
+		case float64:
+			nums++
+		case string:
+			strs++
+		case bool:
+			bools++
+		default:
+			other++
+		}
+	}
+	switch {
+	case other == 0 && strs == 0 && bools == 0:
+		return typedJSONColumn[float64](vals)
+	case other == 0 && nums == 0 && bools == 0:
+		return typedJSONColumn[string](vals)
+	case other == 0 && nums == 0 && strs == 0:
+		return typedJSONColumn[bool](vals)
+	}
+	return column[any](vals)
+}
+
+func typedJSONColumn[T any](vals []any) attrColumn {
+	c := make(column[T], len(vals))
+	for i, v := range vals {
+		if v != nil {
+			c[i] = v.(T)
+		}
+	}
+	return c
+}
+
+// DecodeJSON streams a canonical JSON graph from r, decoding edges one at a
+// time. Edges without a weight get 1.
+func DecodeJSON(r io.Reader) (*Graph, error) {
+	r, err := decompress.Reader(r)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+	var (
+		version  = -1
+		directed = true
+		n        uint32
+		sized    bool
+		anyNode  bool
+		maxID    uint32
+		edges    []Edge
+		// Attribute values by name: per node ID, and per edge in input order.
+		nodeVals = map[string]map[uint32]any{}
+		edgeVals = map[string][]any{}
+	)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("sssp: json: %w", err)
+		}
+		switch key := tok.(string); key {
+		case "version":
+			if err := dec.Decode(&version); err != nil {
+				return nil, fmt.Errorf("sssp: json: version: %w", err)
+			}
+			if version != graphJSONVersion {
+				return nil, fmt.Errorf("sssp: json: unsupported version %d", version)
+			}
+		case "directed":
+			if err := dec.Decode(&directed); err != nil {
+				return nil, fmt.Errorf("sssp: json: directed: %w", err)
+			}
+		case "node_count":
+			if err := dec.Decode(&n); err != nil {
+				return nil, fmt.Errorf("sssp: json: node_count: %w", err)
+			}
+			sized = true
+		case "nodes":
+			if err := expectDelim(dec, '['); err != nil {
+				return nil, err
+			}
+			for dec.More() {
+				var nd jsonNode
+				if err := dec.Decode(&nd); err != nil {
+					return nil, fmt.Errorf("sssp: json: node: %w", err)
+				}
+				maxID, anyNode = max(maxID, nd.ID), true
+				for k, v := range nd.Attributes {
+					if nodeVals[k] == nil {
+						nodeVals[k] = map[uint32]any{}
+					}
+					nodeVals[k][nd.ID] = v
+				}
+			}
+			if err := expectDelim(dec, ']'); err != nil {
+				return nil, err
+			}
+		case "edges":
+			if err := expectDelim(dec, '['); err != nil {
+				return nil, err
+			}
+			for dec.More() {
+				var je jsonEdge
+				if err := dec.Decode(&je); err != nil {
+					return nil, fmt.Errorf("sssp: json: edge %d: %w", len(edges), err)
+				}
+				w := float32(1)
+				if je.Weight != nil {
+					w = *je.Weight
+				}
+				for k, v := range je.Attributes {
+					vals := edgeVals[k]
+					vals = append(vals, make([]any, len(edges)-len(vals))...) // earlier edges without it
+					edgeVals[k] = append(vals, v)
+				}
+				edges = append(edges, Edge{From: je.Source, To: je.Target, Weight: w})
+			}
+			if err := expectDelim(dec, ']'); err != nil {
+				return nil, err
+			}
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil, fmt.Errorf("sssp: json: %s: %w", key, err)
+			}
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, err
+	}
+	if version < 0 {
+		return nil, fmt.Errorf("sssp: json: missing version")
+	}
+	if !sized {
+		return nil, fmt.Errorf("sssp: json: missing node_count")
+	}
+	if anyNode && maxID >= n {
+		return nil, fmt.Errorf("sssp: json: node id %d out of range for node_count %d", maxID, n)
+	}
+	for k, vals := range edgeVals {
+		edgeVals[k] = append(vals, make([]any, len(edges)-len(vals))...)
+	}
+	if !directed {
+		for i, m := 0, len(edges); i < m; i++ {
+			if e := edges[i]; e.From != e.To {
+				edges = append(edges, Edge{From: e.To, To: e.From, Weight: e.Weight})
+				for k, vals := range edgeVals {
+					edgeVals[k] = append(vals, vals[i])
+				}
+			}
+		}
+	}
+	g, err := FromEdges(n, edges)
+	if err != nil {
+		return nil, err
+	}
+	for k, byID := range nodeVals {
+		vals := make([]any, n)
+		for id, v := range byID {
+			vals[id] = v
+		}
+		if g.nodeAttrs == nil {
+			g.nodeAttrs = make(map[string]attrColumn)
+		}
+		g.nodeAttrs[k] = jsonColumn(vals)
+	}
+	if len(edgeVals) > 0 {
+		// FromEdges keeps input order within each source; place the values
+		// the same way.
+		next := make([]uint32, n)
+		copy(next, g.offsets[:n])
+		pos := make([]uint32, len(edges))
+		for i, e := range edges {
+			pos[i] = next[e.From]
+			next[e.From]++
+		}
+		g.edgeAttrs = make(map[string]attrColumn, len(edgeVals))
+		for k, vals := range edgeVals {
+			placed := make([]any, len(vals))
+			for i, v := range vals {
+				placed[pos[i]] = v
+			}
+			g.edgeAttrs[k] = jsonColumn(placed)
+		}
+	}
+	return g, nil
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("sssp: json: %w", err)
+	}
+	if d, ok := tok.(json.Delim); !ok || d != want {
+		return fmt.Errorf("sssp: json: expected %q, got %v", want, tok)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package sssp
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGraphJSONRoundTrip(t *testing.T) {
+	g, err := FromEdges(4, []Edge{{0, 1, 2.5}, {1, 3, 1}, {0, 3, 7}})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	doc := struct {
+		Name  string `json:"name"`
+		Graph *Graph `json:"graph"`
+	}{"tiny", g}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	doc.Graph = new(Graph)
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if doc.Graph.NodeCount() != 4 || doc.Graph.EdgeCount() != 3 || doc.Graph.Edges()[0] != (Edge{0, 1, 2.5}) {
+		t.Fatalf("got %v", doc.Graph.Edges())
+	}
+	bad, _ := FromEdges(2, []Edge{{0, 1, float32(math.Inf(1))}})
+	if err := bad.EncodeJSON(&bytes.Buffer{}); err == nil {
+		t.Fatalf("expected non-finite weight error")
+	}
+}
+
+func TestDecodeJSONUndirectedAndAttributes(t *testing.T) {
+	src := `{"attributes":{"city":"x"},"edges":[{"source":0,"target":1,"weight":3,"attributes":{"road":"A1"}},{"source":1,"target":2}],
+	"nodes":[{"id":2,"attributes":{"label":"c"}}],"directed":false,"version":1,"node_count":3}`
+	g, err := DecodeJSON(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if g.EdgeCount() != 4 {
+		t.Fatalf("undirected edges not mirrored: %v", g.Edges())
+	}
+	res, err := g.Run(2, ModeBaseline)
+	if err != nil || res.Dist[0] != 4 {
+		t.Fatalf("run: %v dist %v", err, res.Dist)
+	}
+	if _, err := DecodeJSON(strings.NewReader(`{"version":1,"node_count":1,"edges":[{"source":0,"target":5}]}`)); err == nil {
+		t.Fatalf("expected out-of-range edge error")
+	}
+	if _, err := DecodeJSON(strings.NewReader(`{"version":2,"node_count":1}`)); err == nil {
+		t.Fatalf("expected version error")
+	}
+}
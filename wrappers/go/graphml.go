@@ -0,0 +1,171 @@
+package sssp
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/your-org/optimized-sssp-go/internal/decompress"
+)
+
+// GraphMLOptions selects how GraphML edge data maps onto weights.
+type GraphMLOptions struct {
+	// WeightKey names the edge attribute holding the weight, matched against a
+	// <key>'s attr.name or id. Empty means unweighted (every edge gets DefaultWeight).
+	WeightKey string
+	// DefaultWeight applies to edges without weight data and no <default> on
+	// the key. Zero means 1.
+	DefaultWeight float32
+}
+
+type graphmlDoc struct {
+	Keys  []graphmlKey   `xml:"key"`
+	Graph []graphmlGraph `xml:"graph"`
+}
+
+type graphmlKey struct {
+	ID      string `xml:"id,attr"`
+	For     string `xml:"for,attr"`
+	Name    string `xml:"attr.name,attr"`
+	Type    string `xml:"attr.type,attr"`
+	Default string `xml:"default"`
+}
+
+type graphmlGraph struct {
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphmlNode `xml:"node"`
+	Edges       []graphmlEdge `xml:"edge"`
+}
+
+type graphmlNode struct {
+	ID string `xml:"id,attr"`
+}
+
+type graphmlEdge struct {
+	Source   string        `xml:"source,attr"`
+	Target   string        `xml:"target,attr"`
+	Directed string        `xml:"directed,attr"`
+	Data     []graphmlData `xml:"data"`
+}
+
+type graphmlData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// LoadGraphML reads the first <graph> of a GraphML document (yEd, Gephi,
+// NetworkX). Node i of the result has GraphML id names[i]. Undirected edges
+// become two directed edges. Nested graphs, hyperedges and ports are ignored.
+func LoadGraphML(r io.Reader, opts GraphMLOptions) (g *Graph, names []string, err error) {
+	if r, err = decompress.Reader(r); err != nil {
+		return nil, nil, err
+	}
+	var doc graphmlDoc
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("sssp: graphml: %w", err)
+	}
+	if len(doc.Graph) == 0 {
+		return nil, nil, fmt.Errorf("sssp: graphml: no <graph> element")
+	}
+	gr := doc.Graph[0]
+	def := opts.DefaultWeight
+	if def == 0 {
+		def = 1
+	}
+	keyID := ""
+	if opts.WeightKey != "" {
+		for _, k := range doc.Keys {
+			if (k.For == "edge" || k.For == "all") && (k.Name == opts.WeightKey || k.ID == opts.WeightKey) {
+				keyID = k.ID
+				if k.Default != "" {
+					v, err := strconv.ParseFloat(k.Default, 32)
+					if err != nil {
+						return nil, nil, fmt.Errorf("sssp: graphml: key %q: bad default %q", k.ID, k.Default)
+					}
+					def = float32(v)
+				}
+				break
+			}
+		}
+		if keyID == "" {
+			return nil, nil, fmt.Errorf("sssp: graphml: no edge key named %q", opts.WeightKey)
+		}
+	}
+	index := make(map[string]uint32, len(gr.Nodes))
+	names = make([]string, 0, len(gr.Nodes))
+	for _, nd := range gr.Nodes {
+		if _, dup := index[nd.ID]; dup {
+			return nil, nil, fmt.Errorf("sssp: graphml: duplicate node id %q", nd.ID)
+		}
+		index[nd.ID] = uint32(len(names))
+		names = append(names, nd.ID)
+	}
+	edges := make([]Edge, 0, len(gr.Edges))
+	for i, e := range gr.Edges {
+		u, ok1 := index[e.Source]
+		v, ok2 := index[e.Target]
+		if !ok1 || !ok2 {
+			return nil, nil, fmt.Errorf("sssp: graphml: edge %d references undeclared node (%q -> %q)", i, e.Source, e.Target)
+		}
+		w := def
+		for _, d := range e.Data {
+			if keyID != "" && d.Key == keyID {
+				x, err := strconv.ParseFloat(d.Value, 32)
+				if err != nil {
+					return nil, nil, fmt.Errorf("sssp: graphml: edge %d: bad weight %q", i, d.Value)
+				}
+				w = float32(x)
+			}
+		}
+		edges = append(edges, Edge{From: u, To: v, Weight: w})
+		undirected := e.Directed == "false" || (e.Directed == "" && gr.EdgeDefault == "undirected")
+		if undirected && u != v {
+			edges = append(edges, Edge{From: v, To: u, Weight: w})
+		}
+	}
+	g, err = FromEdges(uint32(len(names)), edges)
+	return g, names, err
+}
+
+// WriteGraphML writes g as a directed GraphML graph with the weight stored
+// under opts.WeightKey ("weight" if empty). names supplies node ids; nil
+// writes n0, n1, ...
+func (g *Graph) WriteGraphML(w io.Writer, names []string, opts GraphMLOptions) error {
+	if names != nil && len(names) != int(g.NodeCount()) {
+		return fmt.Errorf("sssp: graphml: %d names for %d nodes", len(names), g.NodeCount())
+	}
+	key := opts.WeightKey
+	if key == "" {
+		key = "weight"
+	}
+	id := func(u uint32) string {
+		if names != nil {
+			return names[u]
+		}
+		return "n" + strconv.FormatUint(uint64(u), 10)
+	}
+	bw := bufio.NewWriter(w)
+	esc := func(s string) string {
+		var b strings.Builder
+		xml.EscapeText(&b, []byte(s))
+		return b.String()
+	}
+	bw.WriteString(xml.Header)
+	bw.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	fmt.Fprintf(bw, "  <key id=\"w\" for=\"edge\" attr.name=\"%s\" attr.type=\"double\"/>\n", esc(key))
+	bw.WriteString("  <graph edgedefault=\"directed\">\n")
+	for u := uint32(0); u < g.NodeCount(); u++ {
+		fmt.Fprintf(bw, "    <node id=\"%s\"/>\n", esc(id(u)))
+	}
+	for u := uint32(0); u < g.NodeCount(); u++ {
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			fmt.Fprintf(bw, "    <edge source=\"%s\" target=\"%s\"><data key=\"w\">%s</data></edge>\n",
+				esc(id(u)), esc(id(g.targets[e])), strconv.FormatFloat(float64(g.weights[e]), 'g', -1, 32))
+		}
+	}
+	bw.WriteString("  </graph>\n</graphml>\n")
+	return bw.Flush()
+}
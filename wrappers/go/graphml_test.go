@@ -0,0 +1,52 @@
+package sssp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const sampleGraphML = `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="d0" for="node" attr.name="label" attr.type="string"/>
+  <key id="d1" for="edge" attr.name="cost" attr.type="double"><default>10</default></key>
+  <graph edgedefault="directed">
+    <node id="depot"><data key="d0">Depot</data></node>
+    <node id="a"/>
+    <node id="b"/>
+    <edge source="depot" target="a"><data key="d1">2.5</data></edge>
+    <edge source="a" target="b" directed="false"/>
+  </graph>
+</graphml>`
+
+func TestLoadGraphMLWeightKeyAndUndirected(t *testing.T) {
+	g, names, err := LoadGraphML(strings.NewReader(sampleGraphML), GraphMLOptions{WeightKey: "cost"})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(names) != 3 || names[0] != "depot" || g.EdgeCount() != 3 {
+		t.Fatalf("names %v edges %d", names, g.EdgeCount())
+	}
+	res, err := g.Run(0, ModeBaseline)
+	if err != nil || res.Dist[2] != 12.5 {
+		t.Fatalf("run: %v dist %v", err, res.Dist)
+	}
+	if _, _, err := LoadGraphML(strings.NewReader(sampleGraphML), GraphMLOptions{WeightKey: "missing"}); err == nil {
+		t.Fatalf("expected unknown key error")
+	}
+}
+
+func TestGraphMLRoundTripKeepsNames(t *testing.T) {
+	g, names, err := LoadGraphML(strings.NewReader(sampleGraphML), GraphMLOptions{WeightKey: "cost"})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := g.WriteGraphML(&buf, names, GraphMLOptions{WeightKey: "cost"}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	again, names2, err := LoadGraphML(&buf, GraphMLOptions{WeightKey: "cost"})
+	if err != nil || names2[0] != "depot" || again.EdgeCount() != g.EdgeCount() || again.Edges()[0].Weight != 2.5 {
+		t.Fatalf("round trip: %v %v", err, names2)
+	}
+}
@@ -0,0 +1,112 @@
+package sssp
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// GridParams configures GenerateGrid.
+type GridParams struct {
+	// Dims holds the side lengths of a 2D (x, y) or 3D (x, y, z) grid. Node
+	// (x, y, z) has id x + y*Dims[0] + z*Dims[0]*Dims[1].
+	Dims []uint32
+	// King also links diagonal neighbours: 8 per node in 2D, 26 in 3D.
+	King bool
+	// RandomWeights draws each link's weight from Weights; otherwise every
+	// step, diagonal or not, weighs 1, so distances are Manhattan (Chebyshev
+	// with King) lengths.
+	RandomWeights bool
+	Weights       WeightDist
+	// Obstacles is the probability of removing each node. Removed nodes keep
+	// their ids but have no edges.
+	Obstacles float64
+}
+
+// GenerateGrid returns an undirected grid or king-move lattice, each link
+// stored in both directions with one weight. With unit weights and no
+// obstacles every distance is known in closed form, which makes grids handy
+// for tests; they are also the classic worst case for frontier-based
+// algorithms, with a frontier that grows only with the radius.
+func GenerateGrid(p GridParams, seed int64) (*Graph, error) {
+	if len(p.Dims) != 2 && len(p.Dims) != 3 {
+		return nil, fmt.Errorf("sssp: generate: grid needs 2 or 3 dimensions, got %d", len(p.Dims))
+	}
+	dims := [3]uint32{1, 1, 1}
+	total := uint64(1)
+	for i, d := range p.Dims {
+		if d == 0 {
+			return nil, fmt.Errorf("sssp: generate: grid dimension %d is 0", i)
+		}
+		dims[i] = d
+		total *= uint64(d)
+	}
+	if total > 1<<32-1 {
+		return nil, fmt.Errorf("sssp: generate: grid has %d nodes, over the 32-bit limit", total)
+	}
+	if !(p.Obstacles >= 0 && p.Obstacles < 1) {
+		return nil, fmt.Errorf("sssp: generate: obstacle probability %v outside [0, 1)", p.Obstacles)
+	}
+	if err := p.Weights.validate(); err != nil {
+		return nil, err
+	}
+	r := rand.New(rand.NewSource(seed))
+	n := uint32(total)
+	blocked := make([]bool, n)
+	if p.Obstacles > 0 {
+		for v := range blocked {
+			blocked[v] = r.Float64() < p.Obstacles
+		}
+	}
+	// Half of the neighbourhood: each link is generated once, from its
+	// lexicographically smaller end.
+	var steps [][3]int
+	for dz := -1; dz <= 1; dz++ {
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				nonzero := abs(dx) + abs(dy) + abs(dz)
+				forward := dz > 0 || dz == 0 && (dy > 0 || dy == 0 && dx > 0)
+				if !forward || (len(p.Dims) == 2 && dz != 0) || (!p.King && nonzero != 1) {
+					continue
+				}
+				steps = append(steps, [3]int{dx, dy, dz})
+			}
+		}
+	}
+	id := func(x, y, z int) uint32 {
+		return uint32(x) + uint32(y)*dims[0] + uint32(z)*dims[0]*dims[1]
+	}
+	var edges []Edge
+	for z := 0; z < int(dims[2]); z++ {
+		for y := 0; y < int(dims[1]); y++ {
+			for x := 0; x < int(dims[0]); x++ {
+				u := id(x, y, z)
+				if blocked[u] {
+					continue
+				}
+				for _, s := range steps {
+					nx, ny, nz := x+s[0], y+s[1], z+s[2]
+					if nx < 0 || ny < 0 || nx >= int(dims[0]) || ny >= int(dims[1]) || nz >= int(dims[2]) {
+						continue
+					}
+					v := id(nx, ny, nz)
+					if blocked[v] {
+						continue
+					}
+					w := float32(1)
+					if p.RandomWeights {
+						w = p.Weights.sample(r)
+					}
+					edges = append(edges, Edge{From: u, To: v, Weight: w}, Edge{From: v, To: u, Weight: w})
+				}
+			}
+		}
+	}
+	return FromEdges(n, edges)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
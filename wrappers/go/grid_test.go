@@ -0,0 +1,62 @@
+package sssp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGenerateGridKnownDistances(t *testing.T) {
+	for _, king := range []bool{false, true} {
+		g, err := GenerateGrid(GridParams{Dims: []uint32{10, 7}, King: king}, 1)
+		if err != nil {
+			t.Fatalf("generate: %v", err)
+		}
+		res, err := g.Run(0, ModeBaseline)
+		if err != nil {
+			t.Fatalf("run: %v", err)
+		}
+		for y := 0; y < 7; y++ {
+			for x := 0; x < 10; x++ {
+				want := float32(x + y)
+				if king {
+					want = float32(max(x, y))
+				}
+				if d := res.Dist[x+10*y]; d != want {
+					t.Fatalf("king=%v (%d,%d): %v, want %v", king, x, y, d, want)
+				}
+			}
+		}
+	}
+
+	g, _ := GenerateGrid(GridParams{Dims: []uint32{3, 3, 3}, King: true}, 1)
+	if off, _, _ := g.CSR(); off[14]-off[13] != 26 {
+		t.Fatalf("3D king centre has %d neighbours", off[14]-off[13])
+	}
+}
+
+func TestGenerateGridObstacles(t *testing.T) {
+	g, err := GenerateGrid(GridParams{Dims: []uint32{50, 50}, Obstacles: 0.2, RandomWeights: true}, 9)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	off, _, _ := g.CSR()
+	isolated := 0
+	for u := 0; u < 2500; u++ {
+		if off[u+1] == off[u] {
+			isolated++
+		}
+	}
+	if isolated < 400 || isolated > 600 {
+		t.Fatalf("%d isolated nodes for 20%% obstacles", isolated)
+	}
+	src := uint32(0)
+	for off[src+1] == off[src] {
+		src++
+	}
+	res, _ := g.Run(src, ModeBaseline)
+	for u := 0; u < 2500; u++ {
+		if off[u+1] == off[u] && uint32(u) != src && !math.IsInf(float64(res.Dist[u]), 1) {
+			t.Fatalf("obstacle %d reached", u)
+		}
+	}
+}
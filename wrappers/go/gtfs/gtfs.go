@@ -0,0 +1,366 @@
+// Package gtfs converts a GTFS transit feed into a time-expanded sssp graph.
+//
+// Every stop_times row becomes three event nodes: an arrival, a departure and
+// a wait node at the stop for boarding that departure. Edges are weighted by
+// elapsed seconds:
+//
+//   - ride:     departure at stop i -> arrival at stop i+1 of the same trip
+//   - dwell:    arrival -> departure of the same trip at the same stop
+//   - board:    wait node -> its departure (0 s)
+//   - wait:     wait node -> next wait node (by time) at the same stop
+//   - transfer: arrival -> first wait node at the stop at least MinTransfer later
+//
+// Departures never lead back into the wait chain, so changing trips always
+// costs MinTransfer. A shortest-path run from Source(stop, t) yields, for every
+// reachable event, the earliest time it can be attended, and EarliestArrival
+// reads off the answer for a destination stop. Rows without times (untimed
+// stops awaiting interpolation) are skipped; transfers.txt and frequencies.txt
+// are not used.
+package gtfs
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	sssp "github.com/your-org/optimized-sssp-go"
+)
+
+// Options controls the conversion.
+type Options struct {
+	// Date keeps only trips whose service runs on that day according to
+	// calendar.txt and calendar_dates.txt. The zero value keeps every trip.
+	Date time.Time
+	// MinTransfer is the minimum change time in seconds between alighting and
+	// boarding another trip at the same stop.
+	MinTransfer int32
+}
+
+// Stop is a row of stops.txt.
+type Stop struct {
+	ID       string
+	Name     string
+	Lat, Lon float64
+}
+
+// Timetable is the time-expanded graph with its event tables, indexed by
+// graph node id.
+type Timetable struct {
+	Graph *sssp.Graph
+	Stops []Stop
+	// StopIndex maps a GTFS stop_id to its index in Stops.
+	StopIndex map[string]int
+
+	EventStop []int32     // index into Stops
+	EventTime []int32     // seconds after the service day's midnight; may exceed 24h
+	EventKind []EventKind // role of each node
+
+	waits    [][]uint32 // per stop, sorted by time
+	arrivals [][]uint32
+}
+
+// EventKind tells what a node of the time-expanded graph stands for.
+type EventKind uint8
+
+// Event kinds.
+const (
+	Arrival EventKind = iota
+	Departure
+	Wait
+)
+
+// LoadFile reads a feed from a .zip file or an unpacked directory.
+func LoadFile(path string, opts Options) (*Timetable, error) {
+	st, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if st.IsDir() {
+		return load(func(name string) (io.ReadCloser, error) {
+			return os.Open(filepath.Join(path, name))
+		}, opts)
+	}
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return LoadZip(&zr.Reader, opts)
+}
+
+// LoadZip reads a feed from an opened zip archive.
+func LoadZip(zr *zip.Reader, opts Options) (*Timetable, error) {
+	return load(func(name string) (io.ReadCloser, error) {
+		for _, f := range zr.File {
+			if f.Name == name || strings.HasSuffix(f.Name, "/"+name) {
+				return f.Open()
+			}
+		}
+		return nil, os.ErrNotExist
+	}, opts)
+}
+
+type opener func(name string) (io.ReadCloser, error)
+
+// readTable calls row for each record of name with a column lookup. Missing
+// optional files are skipped.
+func readTable(open opener, name string, optional bool, row func(line int, get func(col string) string) error) error {
+	f, err := open(name)
+	if err != nil {
+		if optional && os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("gtfs: %s: %w", name, err)
+	}
+	defer f.Close()
+	cr := csv.NewReader(f)
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("gtfs: %s: %w", name, err)
+	}
+	cols := make(map[string]int, len(header))
+	for i, h := range header {
+		cols[strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))] = i
+	}
+	var rec []string
+	get := func(col string) string {
+		if i, ok := cols[col]; ok && i < len(rec) {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+	for line := 2; ; line++ {
+		rec, err = cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("gtfs: %s: %w", name, err)
+		}
+		if err := row(line, get); err != nil {
+			return fmt.Errorf("gtfs: %s line %d: %w", name, line, err)
+		}
+	}
+}
+
+// parseTime reads HH:MM:SS, where HH may exceed 23 for after-midnight trips.
+func parseTime(s string) (int32, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("bad time %q", s)
+	}
+	var v [3]int
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("bad time %q", s)
+		}
+		v[i] = n
+	}
+	return int32(v[0]*3600 + v[1]*60 + v[2]), nil
+}
+
+func load(open opener, opts Options) (*Timetable, error) {
+	tt := &Timetable{StopIndex: make(map[string]int)}
+	err := readTable(open, "stops.txt", false, func(_ int, get func(string) string) error {
+		id := get("stop_id")
+		if _, dup := tt.StopIndex[id]; dup {
+			return fmt.Errorf("duplicate stop_id %q", id)
+		}
+		lat, _ := strconv.ParseFloat(get("stop_lat"), 64)
+		lon, _ := strconv.ParseFloat(get("stop_lon"), 64)
+		tt.StopIndex[id] = len(tt.Stops)
+		tt.Stops = append(tt.Stops, Stop{ID: id, Name: get("stop_name"), Lat: lat, Lon: lon})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	active, err := activeServices(open, opts.Date)
+	if err != nil {
+		return nil, err
+	}
+	runs := make(map[string]bool)
+	err = readTable(open, "trips.txt", false, func(_ int, get func(string) string) error {
+		runs[get("trip_id")] = active == nil || active[get("service_id")]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	type stopTime struct {
+		seq      int
+		stop     int32
+		arr, dep int32
+	}
+	trips := make(map[string][]stopTime)
+	var order []string
+	err = readTable(open, "stop_times.txt", false, func(_ int, get func(string) string) error {
+		trip := get("trip_id")
+		on, known := runs[trip]
+		if !known {
+			return fmt.Errorf("unknown trip_id %q", trip)
+		}
+		if !on {
+			return nil
+		}
+		stop, ok := tt.StopIndex[get("stop_id")]
+		if !ok {
+			return fmt.Errorf("unknown stop_id %q", get("stop_id"))
+		}
+		as, ds := get("arrival_time"), get("departure_time")
+		if as == "" && ds == "" {
+			return nil
+		}
+		if as == "" {
+			as = ds
+		}
+		if ds == "" {
+			ds = as
+		}
+		arr, err := parseTime(as)
+		if err != nil {
+			return err
+		}
+		dep, err := parseTime(ds)
+		if err != nil {
+			return err
+		}
+		if dep < arr {
+			return fmt.Errorf("departure before arrival")
+		}
+		seq, err := strconv.Atoi(get("stop_sequence"))
+		if err != nil {
+			return fmt.Errorf("bad stop_sequence %q", get("stop_sequence"))
+		}
+		if _, seen := trips[trip]; !seen {
+			order = append(order, trip)
+		}
+		trips[trip] = append(trips[trip], stopTime{seq, int32(stop), arr, dep})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tt.waits = make([][]uint32, len(tt.Stops))
+	tt.arrivals = make([][]uint32, len(tt.Stops))
+	event := func(stop, t int32, kind EventKind) uint32 {
+		id := uint32(len(tt.EventTime))
+		tt.EventStop = append(tt.EventStop, stop)
+		tt.EventTime = append(tt.EventTime, t)
+		tt.EventKind = append(tt.EventKind, kind)
+		switch kind {
+		case Arrival:
+			tt.arrivals[stop] = append(tt.arrivals[stop], id)
+		case Wait:
+			tt.waits[stop] = append(tt.waits[stop], id)
+		}
+		return id
+	}
+	var edges []sssp.Edge
+	link := func(u, v uint32) {
+		edges = append(edges, sssp.Edge{From: u, To: v, Weight: float32(tt.EventTime[v] - tt.EventTime[u])})
+	}
+	for _, trip := range order {
+		sts := trips[trip]
+		sort.Slice(sts, func(i, j int) bool { return sts[i].seq < sts[j].seq })
+		prevDep := uint32(math.MaxUint32)
+		for _, s := range sts {
+			a, d, w := event(s.stop, s.arr, Arrival), event(s.stop, s.dep, Departure), event(s.stop, s.dep, Wait)
+			link(w, d)
+			if prevDep != math.MaxUint32 {
+				if tt.EventTime[a] < tt.EventTime[prevDep] {
+					return nil, fmt.Errorf("gtfs: trip %q goes back in time at stop_sequence %d", trip, s.seq)
+				}
+				link(prevDep, a)
+			}
+			link(a, d)
+			prevDep = d
+		}
+	}
+	for stop, waits := range tt.waits {
+		sort.SliceStable(waits, func(i, j int) bool { return tt.EventTime[waits[i]] < tt.EventTime[waits[j]] })
+		for i := 1; i < len(waits); i++ {
+			link(waits[i-1], waits[i])
+		}
+		for _, a := range tt.arrivals[stop] {
+			ready := tt.EventTime[a] + opts.MinTransfer
+			i := sort.Search(len(waits), func(i int) bool { return tt.EventTime[waits[i]] >= ready })
+			if i < len(waits) {
+				link(a, waits[i])
+			}
+		}
+	}
+	tt.Graph, err = sssp.FromEdges(uint32(len(tt.EventTime)), edges)
+	if err != nil {
+		return nil, err
+	}
+	return tt, nil
+}
+
+// activeServices returns the service_ids running on date, or nil when date is
+// zero.
+func activeServices(open opener, date time.Time) (map[string]bool, error) {
+	if date.IsZero() {
+		return nil, nil
+	}
+	day := date.Format("20060102")
+	weekday := strings.ToLower(date.Weekday().String())
+	active := make(map[string]bool)
+	err := readTable(open, "calendar.txt", true, func(_ int, get func(string) string) error {
+		if get(weekday) == "1" && get("start_date") <= day && day <= get("end_date") {
+			active[get("service_id")] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = readTable(open, "calendar_dates.txt", true, func(_ int, get func(string) string) error {
+		if get("date") != day {
+			return nil
+		}
+		switch get("exception_type") {
+		case "1":
+			active[get("service_id")] = true
+		case "2":
+			delete(active, get("service_id"))
+		}
+		return nil
+	})
+	return active, err
+}
+
+// Source returns the first wait node at stop at or after t, the node to run
+// from for a journey starting at t.
+func (tt *Timetable) Source(stop int, t int32) (uint32, bool) {
+	waits := tt.waits[stop]
+	i := sort.Search(len(waits), func(i int) bool { return tt.EventTime[waits[i]] >= t })
+	if i == len(waits) {
+		return 0, false
+	}
+	return waits[i], true
+}
+
+// EarliestArrival returns the earliest arrival time at stop among the events
+// reached in res.
+func (tt *Timetable) EarliestArrival(res sssp.Result, stop int) (int32, bool) {
+	best, ok := int32(math.MaxInt32), false
+	for _, a := range tt.arrivals[stop] {
+		if !math.IsInf(float64(res.Dist[a]), 1) && tt.EventTime[a] < best {
+			best, ok = tt.EventTime[a], true
+		}
+	}
+	return best, ok
+}
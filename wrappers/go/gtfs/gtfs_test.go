@@ -0,0 +1,119 @@
+package gtfs
+
+import (
+	"archive/zip"
+	"bytes"
+	"maps"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var sampleFeed = map[string]string{
+	"stops.txt": "\ufeffstop_id,stop_name,stop_lat,stop_lon\nA,Alpha,0,0\nB,Beta,0,0.01\nC,Gamma,0,0.02\n",
+	"trips.txt": "route_id,service_id,trip_id\nR,WK,T1\nR,WK,T2\nR,WE,T3\n",
+	"stop_times.txt": "trip_id,arrival_time,departure_time,stop_id,stop_sequence\n" +
+		"T1,08:00:00,08:00:00,A,1\nT1,08:30:00,08:30:00,C,3\nT1,08:10:00,08:11:00,B,2\n" +
+		"T2,08:12:00,08:12:00,B,1\nT2,08:20:00,08:20:00,C,2\n" +
+		"T3,08:00:00,08:00:00,A,1\nT3,08:05:00,08:05:00,C,2\n",
+	"calendar.txt": "service_id,monday,tuesday,wednesday,thursday,friday,saturday,sunday,start_date,end_date\n" +
+		"WK,1,1,1,1,1,0,0,20260101,20261231\nWE,0,0,0,0,0,1,1,20260101,20261231\n",
+}
+
+func feed(t *testing.T) *zip.Reader { return zipFeed(t, sampleFeed) }
+
+func zipFeed(t *testing.T, files map[string]string) *zip.Reader {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, body := range files {
+		w, _ := zw.Create(name)
+		w.Write([]byte(body))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("zip: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip: %v", err)
+	}
+	return zr
+}
+
+func arrivalAt(t *testing.T, tt *Timetable, from, to string, depart int32) int32 {
+	src, ok := tt.Source(tt.StopIndex[from], depart)
+	if !ok {
+		t.Fatalf("no departure from %s after %d", from, depart)
+	}
+	res, err := tt.Graph.Run(src, 0)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	at, ok := tt.EarliestArrival(res, tt.StopIndex[to])
+	if !ok {
+		t.Fatalf("%s unreachable", to)
+	}
+	return at
+}
+
+func TestEarliestArrivalRespectsMinTransfer(t *testing.T) {
+	friday := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	tt, err := LoadZip(feed(t), Options{Date: friday, MinTransfer: 60})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if at := arrivalAt(t, tt, "A", "C", 7*3600); at != 8*3600+20*60 {
+		t.Fatalf("with a 1 min change at B want 08:20, got %d", at)
+	}
+	// Staying on T1 at B must not let the rider board T2 without the change time.
+	tt, _ = LoadZip(feed(t), Options{Date: friday, MinTransfer: 180})
+	if at := arrivalAt(t, tt, "A", "C", 7*3600); at != 8*3600+30*60 {
+		t.Fatalf("with a 3 min change at B want 08:30, got %d", at)
+	}
+}
+
+func TestCalendarFiltersTrips(t *testing.T) {
+	saturday := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	tt, err := LoadZip(feed(t), Options{Date: saturday})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if at := arrivalAt(t, tt, "A", "C", 0); at != 8*3600+5*60 {
+		t.Fatalf("weekend trip: got %d", at)
+	}
+	if tt.Graph.NodeCount() != 6 {
+		t.Fatalf("weekday trips leaked into the weekend graph: %d nodes", tt.Graph.NodeCount())
+	}
+}
+
+func TestProfileDropsDominatedDepartures(t *testing.T) {
+	files := maps.Clone(sampleFeed)
+	files["trips.txt"] += "R,WK,T4\n"
+	files["stop_times.txt"] += "T4,09:00:00,09:00:00,A,1\nT4,09:30:00,09:30:00,C,2\n"
+	friday := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	tt, err := LoadZip(zipFeed(t, files), Options{Date: friday, MinTransfer: 60})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	p, err := tt.Profile(tt.StopIndex["A"], tt.StopIndex["C"], 7*3600, 10*3600, 0)
+	if err != nil {
+		t.Fatalf("profile: %v", err)
+	}
+	want := TravelProfile{{8 * 3600, 8*3600 + 20*60}, {9 * 3600, 9*3600 + 30*60}}
+	if !reflect.DeepEqual(p, want) {
+		t.Fatalf("profile %v, want %v", p, want)
+	}
+	if j, ok := p.Next(8*3600 + 1); !ok || j.Depart != 9*3600 {
+		t.Fatalf("next after 08:00 %v %v", j, ok)
+	}
+	if j, ok := p.LatestArrivingBy(9 * 3600); !ok || j.Depart != 8*3600 {
+		t.Fatalf("arrive by 09:00 %v %v", j, ok)
+	}
+	// From B the 08:11 departure of T1 waits for T2 at 08:12 and is dropped.
+	p, _ = tt.Profile(tt.StopIndex["B"], tt.StopIndex["C"], 8*3600, 9*3600, 0)
+	if want := (TravelProfile{{8*3600 + 12*60, 8*3600 + 20*60}}); !reflect.DeepEqual(p, want) {
+		t.Fatalf("profile from B %v", p)
+	}
+	if _, err := tt.Profile(0, 9, 0, 1, 0); err == nil {
+		t.Fatalf("stop out of range accepted")
+	}
+}
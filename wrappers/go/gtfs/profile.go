@@ -0,0 +1,72 @@
+package gtfs
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Journey is one departure of a travel-time profile and the earliest
+// arrival it allows, in seconds after midnight.
+type Journey struct {
+	Depart, Arrive int32
+}
+
+// TravelProfile is the travel time between two stops as a function of the
+// departure time: the useful journeys in departure order, each arriving
+// strictly earlier than the next. Leaving between two departures means
+// waiting for the later one.
+type TravelProfile []Journey
+
+// Profile returns the travel-time profile from stop from to stop to for
+// departures between start and end, bounds included. It runs one search
+// in mode per distinct departure time from the stop in the window, and
+// drops departures that arrive no earlier than a later one, since leaving
+// later is then as good.
+func (tt *Timetable) Profile(from, to int, start, end int32, mode int) (TravelProfile, error) {
+	if from < 0 || from >= len(tt.Stops) || to < 0 || to >= len(tt.Stops) {
+		return nil, fmt.Errorf("gtfs: stop out of range for %d stops", len(tt.Stops))
+	}
+	waits := tt.waits[from]
+	i := sort.Search(len(waits), func(i int) bool { return tt.EventTime[waits[i]] >= start })
+	var p TravelProfile
+	for ; i < len(waits) && tt.EventTime[waits[i]] <= end; i++ {
+		dep := tt.EventTime[waits[i]]
+		if i > 0 && tt.EventTime[waits[i-1]] == dep {
+			continue // the wait chain leads from the first node at a time to the others
+		}
+		res, err := tt.Graph.Run(waits[i], mode)
+		if err != nil {
+			return nil, err
+		}
+		arr, ok := tt.EarliestArrival(res, to)
+		if !ok {
+			continue
+		}
+		// Arrivals never decrease with the departure, so an earlier
+		// journey with the same arrival is dominated.
+		for len(p) > 0 && p[len(p)-1].Arrive >= arr {
+			p = p[:len(p)-1]
+		}
+		p = append(p, Journey{Depart: dep, Arrive: arr})
+	}
+	return p, nil
+}
+
+// Next returns the first journey leaving at or after t.
+func (p TravelProfile) Next(t int32) (Journey, bool) {
+	i := sort.Search(len(p), func(i int) bool { return p[i].Depart >= t })
+	if i == len(p) {
+		return Journey{}, false
+	}
+	return p[i], true
+}
+
+// LatestArrivingBy returns the last journey arriving at or before t, the
+// answer to "when should I leave".
+func (p TravelProfile) LatestArrivingBy(t int32) (Journey, bool) {
+	i := sort.Search(len(p), func(i int) bool { return p[i].Arrive > t })
+	if i == 0 {
+		return Journey{}, false
+	}
+	return p[i-1], true
+}
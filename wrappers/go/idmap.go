@@ -0,0 +1,108 @@
+package sssp
+
+import "fmt"
+
+// IDMapper translates external node keys, such as string names or int64
+// database ids, to the dense node IDs a Graph uses and back. New keys get
+// IDs 0, 1, 2, ... in the order they are first seen. The zero value is not
+// usable; call NewIDMapper.
+type IDMapper[K comparable] struct {
+	ids  map[K]uint32
+	keys []K
+}
+
+// NewIDMapper returns an empty mapper.
+func NewIDMapper[K comparable]() *IDMapper[K] {
+	return &IDMapper[K]{ids: make(map[K]uint32)}
+}
+
+// IDMapperFrom returns a mapper in which keys[i] has ID i, for the names
+// LoadGraphML and LoadGML return. Keys must be distinct.
+func IDMapperFrom[K comparable](keys []K) (*IDMapper[K], error) {
+	m := &IDMapper[K]{ids: make(map[K]uint32, len(keys)), keys: keys}
+	for i, k := range keys {
+		if _, dup := m.ids[k]; dup {
+			return nil, fmt.Errorf("sssp: node key %v appears twice", k)
+		}
+		m.ids[k] = uint32(i)
+	}
+	return m, nil
+}
+
+// ID returns the ID of key, assigning the next one if key is new.
+func (m *IDMapper[K]) ID(key K) uint32 {
+	id, ok := m.ids[key]
+	if !ok {
+		id = uint32(len(m.keys))
+		m.ids[key] = id
+		m.keys = append(m.keys, key)
+	}
+	return id
+}
+
+// Lookup returns the ID of key without assigning one.
+func (m *IDMapper[K]) Lookup(key K) (uint32, bool) {
+	id, ok := m.ids[key]
+	return id, ok
+}
+
+// Key returns the key with the given ID. It panics if id was never
+// assigned.
+func (m *IDMapper[K]) Key(id uint32) K { return m.keys[id] }
+
+// Keys returns the keys in ID order. Callers must not modify it.
+func (m *IDMapper[K]) Keys() []K { return m.keys }
+
+// Len returns the number of keys, which is the node count of a graph built
+// from them.
+func (m *IDMapper[K]) Len() uint32 { return uint32(len(m.keys)) }
+
+// Edge returns the edge from one key to another, assigning IDs as needed,
+// for building edge lists for FromEdges or AddEdges.
+func (m *IDMapper[K]) Edge(from, to K, w float32) Edge {
+	return Edge{From: m.ID(from), To: m.ID(to), Weight: w}
+}
+
+// Dist returns the distance r records for key, and false if key is
+// unknown or unreachable.
+func (m *IDMapper[K]) Dist(r *Result, key K) (float32, bool) {
+	id, ok := m.ids[key]
+	if !ok || !r.Reachable(id) {
+		return Unreachable, false
+	}
+	return r.Dist[id], true
+}
+
+// Path returns the keys along the shortest path in r from its source to
+// target, both included; nil if target is unknown or unreachable.
+func (m *IDMapper[K]) Path(r *Result, target K) []K {
+	id, ok := m.ids[target]
+	if !ok {
+		return nil
+	}
+	nodes := r.Path(id)
+	if nodes == nil {
+		return nil
+	}
+	keys := make([]K, len(nodes))
+	for i, v := range nodes {
+		if int(v) >= len(m.keys) {
+			return nil // r is from a graph with more nodes than keys
+		}
+		keys[i] = m.keys[v]
+	}
+	return keys
+}
+
+// CheckIDSpan fails when a graph sized by its largest id, maxID+1 nodes,
+// would be far larger than its edges justify: past 1<<20 nodes and more
+// than 16 per edge. Loaders that take the node count from the ids call it
+// unless told the ids are sparse on purpose, since one stray id in a small
+// file would otherwise allocate gigabytes and kill the process.
+func CheckIDSpan(maxID uint64, edges int) error {
+	n := maxID + 1
+	if n <= 1<<20 || n/16 <= uint64(edges) {
+		return nil
+	}
+	return fmt.Errorf("sssp: node id %d implies %d nodes for %d edges", maxID, n, edges)
+}
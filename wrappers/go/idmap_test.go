@@ -0,0 +1,55 @@
+package sssp
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIDMapperWithEdgeList(t *testing.T) {
+	ids := NewIDMapper[string]()
+	g, err := LoadEdgeList(strings.NewReader("from,to,km\nparis,lyon,465\nlyon,nice,470\nparis,nice,930\n"),
+		EdgeListOptions{Delimiter: ',', SkipHeader: 1, IDs: ids})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.NodeCount() != 3 || !reflect.DeepEqual(ids.Keys(), []string{"paris", "lyon", "nice"}) {
+		t.Fatalf("%d nodes, keys %v", g.NodeCount(), ids.Keys())
+	}
+	src, _ := ids.Lookup("paris")
+	res, err := g.Run(src, ModeBaseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, ok := ids.Dist(&res, "nice"); !ok || d != 930 {
+		t.Fatalf("dist to nice %v %v", d, ok)
+	}
+	if p := ids.Path(&res, "lyon"); !reflect.DeepEqual(p, []string{"paris", "lyon"}) {
+		t.Fatalf("path %v", p)
+	}
+	if _, ok := ids.Dist(&res, "rome"); ok {
+		t.Fatalf("unknown key has a distance")
+	}
+}
+
+func TestIDMapperInt64(t *testing.T) {
+	ids := NewIDMapper[int64]()
+	edges := []Edge{ids.Edge(9000000001, 42, 1), ids.Edge(42, -7, 2)}
+	g, err := FromEdges(ids.Len(), edges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := g.Run(ids.ID(9000000001), ModeBaseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := ids.Path(&res, -7); !reflect.DeepEqual(p, []int64{9000000001, 42, -7}) {
+		t.Fatalf("path %v", p)
+	}
+	if ids.Key(1) != 42 {
+		t.Fatalf("key of 1: %d", ids.Key(1))
+	}
+	if _, err := IDMapperFrom([]string{"a", "b", "a"}); err == nil {
+		t.Fatalf("duplicate keys accepted")
+	}
+}
@@ -0,0 +1,55 @@
+// Package decompress detects gzip and zstd streams by their magic bytes so
+// loaders can accept compressed files transparently.
+package decompress
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	"github.com/your-org/optimized-sssp-go/internal/zstd"
+)
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// Compressed reports whether b starts with a gzip or zstd header.
+func Compressed(b []byte) bool {
+	return bytes.HasPrefix(b, gzipMagic) || bytes.HasPrefix(b, zstd.Magic)
+}
+
+// Reader returns r unchanged in content if it is not compressed, and a
+// decompressing reader otherwise.
+func Reader(r io.Reader) (io.Reader, error) {
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	head, err := br.Peek(4)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	switch {
+	case bytes.HasPrefix(head, gzipMagic):
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return zr, nil
+	case bytes.HasPrefix(head, zstd.Magic):
+		return zstd.NewReader(br), nil
+	}
+	return br, nil
+}
+
+// Bytes returns b, or its decompressed content if b is compressed.
+func Bytes(b []byte) ([]byte, error) {
+	if !Compressed(b) {
+		return b, nil
+	}
+	r, err := Reader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
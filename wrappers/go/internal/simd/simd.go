@@ -0,0 +1,4 @@
+// Package simd holds the vector kernels of the pure-Go engine, in assembly
+// where the architecture has one. It is separate from package sssp because
+// a package using cgo cannot contain Go assembly.
+package simd
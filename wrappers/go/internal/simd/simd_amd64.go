@@ -0,0 +1,55 @@
+package simd
+
+import (
+	"fmt"
+	"math"
+)
+
+// Available reports AVX2 with the vector registers enabled by the OS.
+var Available = detectAVX2()
+
+func detectAVX2() bool {
+	_, _, ecx1, _ := cpuid(1, 0)
+	const osxsave, avx = 1 << 27, 1 << 28
+	if ecx1&osxsave == 0 || ecx1&avx == 0 {
+		return false
+	}
+	if xcr0, _ := xgetbv(); xcr0&6 != 6 { // XMM and YMM state saved
+		return false
+	}
+	_, ebx7, _, _ := cpuid(7, 0)
+	return ebx7&(1<<5) != 0
+}
+
+// RelaxMasks sets bit i of masks[g] when du+wts[8g+i] < dist[tgt[8g+i]],
+// comparing eight edges per instruction. tgt and wts hold 8*len(masks)
+// entries. Every target must index dist, and be below 2^31 for the gather's
+// signed offsets: the kernel checks each group before gathering it, and
+// RelaxMasks panics on a target out of range as an index expression would.
+// Call it only if Available.
+func RelaxMasks(du float32, tgt []uint32, wts []float32, dist []float32, masks []uint8) {
+	if len(masks) == 0 {
+		return
+	}
+	_ = tgt[8*len(masks)-1]
+	_ = wts[8*len(masks)-1]
+	limit := min(len(dist), math.MaxInt32+1)
+	if limit == 0 {
+		panic(fmt.Sprintf("simd: target %d out of range for %d distances", tgt[0], len(dist)))
+	}
+	done := relaxMasksAVX2(du, &tgt[0], &wts[0], &dist[0], uint32(limit-1), len(masks), &masks[0])
+	if done < len(masks) {
+		for _, v := range tgt[8*done : 8*done+8] {
+			if int(v) >= limit {
+				panic(fmt.Sprintf("simd: target %d out of range for %d distances", v, len(dist)))
+			}
+		}
+	}
+}
+
+func cpuid(leaf, sub uint32) (eax, ebx, ecx, edx uint32)
+
+func xgetbv() (eax, edx uint32)
+
+//go:noescape
+func relaxMasksAVX2(du float32, tgt *uint32, wts *float32, dist *float32, last uint32, groups int, masks *uint8) (done int)
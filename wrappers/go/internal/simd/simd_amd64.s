@@ -0,0 +1,60 @@
+#include "textflag.h"
+
+// func cpuid(leaf, sub uint32) (eax, ebx, ecx, edx uint32)
+TEXT ·cpuid(SB), NOSPLIT, $0-24
+	MOVL leaf+0(FP), AX
+	MOVL sub+4(FP), CX
+	CPUID
+	MOVL AX, eax+8(FP)
+	MOVL BX, ebx+12(FP)
+	MOVL CX, ecx+16(FP)
+	MOVL DX, edx+20(FP)
+	RET
+
+// func xgetbv() (eax, edx uint32)
+TEXT ·xgetbv(SB), NOSPLIT, $0-8
+	MOVL $0, CX
+	XGETBV
+	MOVL AX, eax+0(FP)
+	MOVL DX, edx+4(FP)
+	RET
+
+// func relaxMasksAVX2(du float32, tgt *uint32, wts *float32, dist *float32, last uint32, groups int, masks *uint8) (done int)
+TEXT ·relaxMasksAVX2(SB), NOSPLIT, $0-64
+	VBROADCASTSS du+0(FP), Y0
+	MOVQ tgt+8(FP), SI
+	MOVQ wts+16(FP), DI
+	MOVQ dist+24(FP), DX
+	MOVL last+32(FP), AX
+	VMOVD AX, X7
+	VPBROADCASTD X7, Y7              // last valid index in every lane
+	MOVQ groups+40(FP), CX
+	MOVQ masks+48(FP), R8
+	TESTQ CX, CX
+	JZ done
+
+loop:
+	VMOVDQU (SI), Y1                 // eight targets
+	VPMINUD Y7, Y1, Y6               // each clamped to the last index
+	VPCMPEQD Y6, Y1, Y6              // unchanged if in range
+	VMOVMSKPS Y6, AX
+	CMPL AX, $0xff
+	JNE done                         // stop before gathering out of range
+	VPCMPEQD Y2, Y2, Y2              // gather all lanes
+	VGATHERDPS Y2, (DX)(Y1*4), Y3    // their distances
+	VADDPS (DI), Y0, Y4              // du + weights
+	VCMPPS $1, Y3, Y4, Y5            // less than, false for NaN
+	VMOVMSKPS Y5, AX
+	MOVB AX, (R8)
+	ADDQ $32, SI
+	ADDQ $32, DI
+	INCQ R8
+	DECQ CX
+	JNZ loop
+
+done:
+	MOVQ groups+40(FP), AX
+	SUBQ CX, AX
+	MOVQ AX, done+56(FP)
+	VZEROUPPER
+	RET
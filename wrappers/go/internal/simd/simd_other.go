@@ -0,0 +1,11 @@
+//go:build !amd64
+
+package simd
+
+// Available is false: there is no kernel for this architecture.
+const Available = false
+
+// RelaxMasks is only defined on amd64; it is never called here.
+func RelaxMasks(du float32, tgt []uint32, wts []float32, dist []float32, masks []uint8) {
+	panic("simd: no kernel on this architecture")
+}
@@ -0,0 +1,50 @@
+package simd
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestRelaxMasks(t *testing.T) {
+	if !Available {
+		t.Skip("no vector kernel on this CPU")
+	}
+	rng := rand.New(rand.NewSource(1))
+	dist := make([]float32, 1000)
+	for i := range dist {
+		dist[i] = rng.Float32() * 10
+	}
+	dist[7], dist[9] = float32(math.Inf(1)), float32(math.NaN())
+	const groups = 13
+	tgt, wts := make([]uint32, 8*groups), make([]float32, 8*groups)
+	for i := range tgt {
+		tgt[i], wts[i] = uint32(rng.Intn(len(dist))), rng.Float32()*5
+	}
+	tgt[3], tgt[4], wts[5] = 7, 9, float32(math.Inf(1))
+	masks := make([]uint8, groups)
+	for _, du := range []float32{0, 2.5, 7} {
+		RelaxMasks(du, tgt, wts, dist, masks)
+		for i := range tgt {
+			want := du+wts[i] < dist[tgt[i]]
+			if got := masks[i/8]>>(i%8)&1 == 1; got != want {
+				t.Fatalf("du %v edge %d: got %v, want %v", du, i, got, want)
+			}
+		}
+	}
+}
+
+func TestRelaxMasksRejectsTargetPastDist(t *testing.T) {
+	if !Available {
+		t.Skip("no vector kernel on this CPU")
+	}
+	dist := make([]float32, 10)
+	tgt, wts := make([]uint32, 16), make([]float32, 16)
+	tgt[13] = 10
+	defer func() {
+		if recover() == nil {
+			t.Fatal("target past dist did not panic")
+		}
+	}()
+	RelaxMasks(0, tgt, wts, dist, make([]uint8, 2))
+}
@@ -0,0 +1,217 @@
+// Package thrift decodes the Thrift compact protocol into generic values,
+// enough to read Parquet metadata and page headers without generated code.
+package thrift
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Struct maps field ids to decoded values: bool, int64 (all integer widths),
+// float64, []byte, []any (list or set) or Struct. Maps are skipped.
+type Struct map[int16]any
+
+// ErrTruncated reports input that ends inside a value.
+var ErrTruncated = errors.New("thrift: truncated input")
+
+// Compact protocol type codes.
+const (
+	typeStop   = 0
+	typeTrue   = 1
+	typeFalse  = 2
+	typeByte   = 3
+	typeI16    = 4
+	typeI32    = 5
+	typeI64    = 6
+	typeDouble = 7
+	typeBinary = 8
+	typeList   = 9
+	typeSet    = 10
+	typeMap    = 11
+	typeStruct = 12
+)
+
+// maxDepth bounds nesting so hostile input cannot exhaust the stack.
+const maxDepth = 64
+
+// ReadStruct decodes one struct from the front of b and returns it with the
+// number of bytes consumed.
+func ReadStruct(b []byte) (Struct, int, error) {
+	r := reader{b: b}
+	s, err := r.structValue(0)
+	return s, r.pos, err
+}
+
+type reader struct {
+	b   []byte
+	pos int
+}
+
+func (r *reader) byte() (byte, error) {
+	if r.pos >= len(r.b) {
+		return 0, ErrTruncated
+	}
+	c := r.b[r.pos]
+	r.pos++
+	return c, nil
+}
+
+func (r *reader) uvarint() (uint64, error) {
+	v, n := binary.Uvarint(r.b[r.pos:])
+	if n <= 0 {
+		return 0, ErrTruncated
+	}
+	r.pos += n
+	return v, nil
+}
+
+func (r *reader) zigzag() (int64, error) {
+	v, err := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1), err
+}
+
+func (r *reader) structValue(depth int) (Struct, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("thrift: nesting deeper than %d", maxDepth)
+	}
+	s := Struct{}
+	var last int16
+	for {
+		h, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		if h == typeStop {
+			return s, nil
+		}
+		typ := h & 0x0f
+		id := last + int16(h>>4)
+		if h>>4 == 0 {
+			v, err := r.zigzag()
+			if err != nil {
+				return nil, err
+			}
+			id = int16(v)
+		}
+		last = id
+		switch typ {
+		case typeTrue, typeFalse:
+			s[id] = typ == typeTrue
+		default:
+			v, err := r.value(typ, depth)
+			if err != nil {
+				return nil, err
+			}
+			if v != nil {
+				s[id] = v
+			}
+		}
+	}
+}
+
+func (r *reader) value(typ byte, depth int) (any, error) {
+	switch typ {
+	case typeTrue, typeFalse:
+		c, err := r.byte() // list element form
+		return c == typeTrue, err
+	case typeByte:
+		c, err := r.byte()
+		return int64(int8(c)), err
+	case typeI16, typeI32, typeI64:
+		return r.zigzag()
+	case typeDouble:
+		if len(r.b)-r.pos < 8 {
+			return nil, ErrTruncated
+		}
+		v := math.Float64frombits(binary.LittleEndian.Uint64(r.b[r.pos:]))
+		r.pos += 8
+		return v, nil
+	case typeBinary:
+		n, err := r.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		if n > uint64(len(r.b)-r.pos) {
+			return nil, ErrTruncated
+		}
+		v := r.b[r.pos : r.pos+int(n)]
+		r.pos += int(n)
+		return v, nil
+	case typeList, typeSet:
+		h, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		size, et := uint64(h>>4), h&0x0f
+		if size == 15 {
+			if size, err = r.uvarint(); err != nil {
+				return nil, err
+			}
+		}
+		if size > uint64(len(r.b)-r.pos) {
+			return nil, ErrTruncated // every element takes at least one byte
+		}
+		out := make([]any, 0, size)
+		for i := uint64(0); i < size; i++ {
+			v, err := r.value(et, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	case typeMap:
+		size, err := r.uvarint()
+		if err != nil || size == 0 {
+			return nil, err
+		}
+		kv, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		for i := uint64(0); i < size; i++ {
+			if _, err := r.value(kv>>4, depth+1); err != nil {
+				return nil, err
+			}
+			if _, err := r.value(kv&0x0f, depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	case typeStruct:
+		return r.structValue(depth + 1)
+	}
+	return nil, fmt.Errorf("thrift: unknown type %d", typ)
+}
+
+// Int returns an integer field.
+func (s Struct) Int(id int16) (int64, bool) {
+	v, ok := s[id].(int64)
+	return v, ok
+}
+
+// Bool returns a boolean field.
+func (s Struct) Bool(id int16) (bool, bool) {
+	v, ok := s[id].(bool)
+	return v, ok
+}
+
+// Bytes returns a binary or string field.
+func (s Struct) Bytes(id int16) ([]byte, bool) {
+	v, ok := s[id].([]byte)
+	return v, ok
+}
+
+// Struct returns a nested struct field.
+func (s Struct) Struct(id int16) (Struct, bool) {
+	v, ok := s[id].(Struct)
+	return v, ok
+}
+
+// List returns a list or set field.
+func (s Struct) List(id int16) []any {
+	v, _ := s[id].([]any)
+	return v
+}
@@ -0,0 +1,188 @@
+// Package wire is a minimal Protocol Buffers wire-format codec shared by the
+// protobuf graph encoding and the OSM PBF reader, so neither needs a
+// protobuf runtime.
+package wire
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Wire types.
+const (
+	Varint  = 0
+	Fixed64 = 1
+	Bytes   = 2
+	Fixed32 = 5
+)
+
+// ErrTruncated reports a message that ends inside a field.
+var ErrTruncated = errors.New("truncated message")
+
+// AppendTag appends a field key.
+func AppendTag(b []byte, num, typ int) []byte {
+	return binary.AppendUvarint(b, uint64(num)<<3|uint64(typ))
+}
+
+// AppendVarintField appends a varint field, omitting proto3 zero values.
+func AppendVarintField(b []byte, num int, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	return binary.AppendUvarint(AppendTag(b, num, Varint), v)
+}
+
+// AppendFloatField appends a float field, omitting +0.
+func AppendFloatField(b []byte, num int, v float32) []byte {
+	if v == 0 && !math.Signbit(float64(v)) {
+		return b
+	}
+	return binary.LittleEndian.AppendUint32(AppendTag(b, num, Fixed32), math.Float32bits(v))
+}
+
+// AppendBytesField appends a length-delimited field (string, bytes or a
+// nested message). Empty payloads are still written.
+func AppendBytesField(b []byte, num int, data []byte) []byte {
+	b = binary.AppendUvarint(AppendTag(b, num, Bytes), uint64(len(data)))
+	return append(b, data...)
+}
+
+// AppendPackedUint32 appends a packed repeated uint32 field.
+func AppendPackedUint32(b []byte, num int, vs []uint32) []byte {
+	if len(vs) == 0 {
+		return b
+	}
+	size := 0
+	for _, v := range vs {
+		size += UvarintLen(uint64(v))
+	}
+	b = binary.AppendUvarint(AppendTag(b, num, Bytes), uint64(size))
+	for _, v := range vs {
+		b = binary.AppendUvarint(b, uint64(v))
+	}
+	return b
+}
+
+// AppendPackedSint appends a packed repeated sint32/sint64 field.
+func AppendPackedSint(b []byte, num int, vs []int64) []byte {
+	if len(vs) == 0 {
+		return b
+	}
+	size := 0
+	for _, v := range vs {
+		size += UvarintLen(Zigzag(v))
+	}
+	b = binary.AppendUvarint(AppendTag(b, num, Bytes), uint64(size))
+	for _, v := range vs {
+		b = binary.AppendUvarint(b, Zigzag(v))
+	}
+	return b
+}
+
+// AppendPackedFloat appends a packed repeated float field.
+func AppendPackedFloat(b []byte, num int, vs []float32) []byte {
+	if len(vs) == 0 {
+		return b
+	}
+	b = binary.AppendUvarint(AppendTag(b, num, Bytes), uint64(4*len(vs)))
+	for _, v := range vs {
+		b = binary.LittleEndian.AppendUint32(b, math.Float32bits(v))
+	}
+	return b
+}
+
+// UvarintLen is the encoded size of v.
+func UvarintLen(v uint64) int {
+	n := 1
+	for v >= 0x80 {
+		v >>= 7
+		n++
+	}
+	return n
+}
+
+// Zigzag encodes a signed value as sint32/sint64 do.
+func Zigzag(v int64) uint64 { return uint64(v<<1) ^ uint64(v>>63) }
+
+// Unzigzag reverses Zigzag.
+func Unzigzag(z uint64) int64 { return int64(z>>1) ^ -int64(z&1) }
+
+// Walk calls fn for every field of a message. v holds varint and fixed
+// values; data holds length-delimited payloads (aliasing b).
+func Walk(b []byte, fn func(num, typ int, v uint64, data []byte) error) error {
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return ErrTruncated
+		}
+		b = b[n:]
+		num, typ := int(tag>>3), int(tag&7)
+		var (
+			v    uint64
+			data []byte
+		)
+		switch typ {
+		case Varint:
+			if v, n = binary.Uvarint(b); n <= 0 {
+				return ErrTruncated
+			}
+			b = b[n:]
+		case Fixed64:
+			if len(b) < 8 {
+				return ErrTruncated
+			}
+			v, b = binary.LittleEndian.Uint64(b), b[8:]
+		case Fixed32:
+			if len(b) < 4 {
+				return ErrTruncated
+			}
+			v, b = uint64(binary.LittleEndian.Uint32(b)), b[4:]
+		case Bytes:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < l {
+				return ErrTruncated
+			}
+			data, b = b[n:n+int(l)], b[n+int(l):]
+		default:
+			return fmt.Errorf("unsupported wire type %d", typ)
+		}
+		if err := fn(num, typ, v, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Varints appends a repeated varint field occurrence (packed or not) to dst.
+func Varints(dst []uint64, typ int, v uint64, data []byte) ([]uint64, error) {
+	if typ == Varint {
+		return append(dst, v), nil
+	}
+	if typ != Bytes {
+		return nil, fmt.Errorf("wire type %d for a varint field", typ)
+	}
+	for len(data) > 0 {
+		x, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, ErrTruncated
+		}
+		dst, data = append(dst, x), data[n:]
+	}
+	return dst, nil
+}
+
+// Floats appends a repeated float field occurrence (packed or not) to dst.
+func Floats(dst []float32, typ int, v uint64, data []byte) ([]float32, error) {
+	if typ == Fixed32 {
+		return append(dst, math.Float32frombits(uint32(v))), nil
+	}
+	if typ != Bytes || len(data)%4 != 0 {
+		return nil, fmt.Errorf("malformed float field")
+	}
+	for i := 0; i < len(data); i += 4 {
+		dst = append(dst, math.Float32frombits(binary.LittleEndian.Uint32(data[i:])))
+	}
+	return dst, nil
+}
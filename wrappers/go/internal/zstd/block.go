@@ -0,0 +1,309 @@
+package zstd
+
+import "encoding/binary"
+
+const maxBlockSize = 128 << 10
+
+// Literal length and match length codes: baseline and extra bits.
+var (
+	llBase = [36]uint32{
+		0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+		16, 18, 20, 22, 24, 28, 32, 40, 48, 64, 128, 256, 512, 1024, 2048, 4096,
+		8192, 16384, 32768, 65536,
+	}
+	llBits = [36]uint8{
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		1, 1, 1, 1, 2, 2, 3, 3, 4, 6, 7, 8, 9, 10, 11, 12,
+		13, 14, 15, 16,
+	}
+	mlBase = [53]uint32{
+		3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18,
+		19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34,
+		35, 37, 39, 41, 43, 47, 51, 59, 67, 83, 99, 131, 259, 515, 1027, 2051,
+		4099, 8195, 16387, 32771, 65539,
+	}
+	mlBits = [53]uint8{
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		1, 1, 1, 1, 2, 2, 3, 3, 4, 4, 5, 7, 8, 9, 10, 11,
+		12, 13, 14, 15, 16,
+	}
+)
+
+// Predefined distributions for sequence codes.
+var (
+	llDefault = mustFSE([]int16{
+		4, 3, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 1, 1, 1,
+		2, 2, 2, 2, 2, 2, 2, 2, 2, 3, 2, 1, 1, 1, 1, 1,
+		-1, -1, -1, -1,
+	}, 6)
+	ofDefault = mustFSE([]int16{
+		1, 1, 1, 1, 1, 1, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, -1, -1, -1, -1, -1,
+	}, 5)
+	mlDefault = mustFSE([]int16{
+		1, 4, 3, 2, 2, 2, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, -1, -1,
+		-1, -1, -1, -1, -1,
+	}, 6)
+)
+
+// decoder carries the entropy state a frame's compressed blocks share.
+type decoder struct {
+	huf        *hufTable
+	ll, of, ml *fseTable
+	rep        [3]int
+	lits       []byte
+}
+
+func (d *decoder) reset() {
+	d.huf, d.ll, d.of, d.ml = nil, nil, nil, nil
+	d.rep = [3]int{1, 4, 8}
+}
+
+// compressed decodes one compressed block, appending at most limit bytes to
+// out.
+func (d *decoder) compressed(out, b []byte, limit int) ([]byte, error) {
+	lits, n, err := d.literals(b)
+	if err != nil {
+		return out, err
+	}
+	return d.sequences(out, b[n:], lits, limit)
+}
+
+func (d *decoder) literals(b []byte) ([]byte, int, error) {
+	if len(b) == 0 {
+		return nil, 0, errCorrupt
+	}
+	typ, format := b[0]&3, b[0]>>2&3
+	if typ < 2 { // raw or RLE
+		var size, hl int
+		switch format {
+		case 0, 2:
+			size, hl = int(b[0]>>3), 1
+		case 1:
+			if len(b) < 2 {
+				return nil, 0, errCorrupt
+			}
+			size, hl = int(b[0]>>4)|int(b[1])<<4, 2
+		case 3:
+			if len(b) < 3 {
+				return nil, 0, errCorrupt
+			}
+			size, hl = int(b[0]>>4)|int(b[1])<<4|int(b[2])<<12, 3
+		}
+		if size > maxBlockSize {
+			return nil, 0, errCorrupt
+		}
+		if typ == 0 {
+			if len(b) < hl+size {
+				return nil, 0, errCorrupt
+			}
+			return b[hl : hl+size], hl + size, nil
+		}
+		if len(b) < hl+1 {
+			return nil, 0, errCorrupt
+		}
+		lits := d.litBuf(size)
+		for i := range lits {
+			lits[i] = b[hl]
+		}
+		return lits, hl + 1, nil
+	}
+
+	var regen, csize, hl int
+	switch format {
+	case 0, 1:
+		if len(b) < 3 {
+			return nil, 0, errCorrupt
+		}
+		v := uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16
+		regen, csize, hl = int(v>>4&0x3ff), int(v>>14&0x3ff), 3
+	case 2:
+		if len(b) < 4 {
+			return nil, 0, errCorrupt
+		}
+		v := binary.LittleEndian.Uint32(b)
+		regen, csize, hl = int(v>>4&0x3fff), int(v>>18&0x3fff), 4
+	case 3:
+		if len(b) < 5 {
+			return nil, 0, errCorrupt
+		}
+		v := uint64(binary.LittleEndian.Uint32(b)) | uint64(b[4])<<32
+		regen, csize, hl = int(v>>4&0x3ffff), int(v>>22&0x3ffff), 5
+	}
+	if regen > maxBlockSize || len(b) < hl+csize {
+		return nil, 0, errCorrupt
+	}
+	data := b[hl : hl+csize]
+	if typ == 2 {
+		t, n, err := readHuffman(data)
+		if err != nil {
+			return nil, 0, err
+		}
+		d.huf, data = t, data[n:]
+	} else if d.huf == nil {
+		return nil, 0, errCorrupt // treeless literals need an earlier table
+	}
+	lits := d.litBuf(regen)
+	var err error
+	if format == 0 {
+		err = d.huf.decode(lits, data)
+	} else {
+		err = d.huf.decode4(lits, data)
+	}
+	return lits, hl + csize, err
+}
+
+func (d *decoder) litBuf(n int) []byte {
+	if cap(d.lits) < n {
+		d.lits = make([]byte, n, maxBlockSize)
+	}
+	return d.lits[:n]
+}
+
+// table selects the decoding table for one sequence code from its mode bits
+// and returns it with the bytes of b consumed.
+func table(mode byte, b []byte, def, prev *fseTable, maxLog, maxSym int) (*fseTable, int, error) {
+	switch mode {
+	case 0:
+		return def, 0, nil
+	case 1:
+		if len(b) < 1 || int(b[0]) > maxSym {
+			return nil, 0, errCorrupt
+		}
+		return rleTable(b[0]), 1, nil
+	case 2:
+		return readFSETable(b, maxLog, maxSym)
+	}
+	if prev == nil {
+		return nil, 0, errCorrupt
+	}
+	return prev, 0, nil
+}
+
+// sequences decodes the sequences section and executes it against out,
+// which holds the frame's history.
+func (d *decoder) sequences(out, b, lits []byte, limit int) ([]byte, error) {
+	if len(b) == 0 {
+		return out, errCorrupt
+	}
+	nseq, p := int(b[0]), 1
+	switch {
+	case nseq == 0:
+		if len(b) != 1 || len(lits) > limit {
+			return out, errCorrupt
+		}
+		return append(out, lits...), nil
+	case nseq == 255:
+		if len(b) < 3 {
+			return out, errCorrupt
+		}
+		nseq, p = int(b[1])|int(b[2])<<8+0x7f00, 3
+	case nseq >= 128:
+		if len(b) < 2 {
+			return out, errCorrupt
+		}
+		nseq, p = (nseq-128)<<8|int(b[1]), 2
+	}
+	if len(b) <= p || b[p]&3 != 0 {
+		return out, errCorrupt
+	}
+	modes := b[p]
+	p++
+	var (
+		n   int
+		err error
+	)
+	if d.ll, n, err = table(modes>>6, b[p:], llDefault, d.ll, 9, 35); err != nil {
+		return out, err
+	}
+	p += n
+	if d.of, n, err = table(modes>>4&3, b[p:], ofDefault, d.of, 8, 31); err != nil {
+		return out, err
+	}
+	p += n
+	if d.ml, n, err = table(modes>>2&3, b[p:], mlDefault, d.ml, 9, 52); err != nil {
+		return out, err
+	}
+	p += n
+
+	var br backReader
+	if err := br.init(b[p:]); err != nil {
+		return out, err
+	}
+	ll, of, ml := fseState{t: d.ll}, fseState{t: d.of}, fseState{t: d.ml}
+	ll.init(&br)
+	of.init(&br)
+	ml.init(&br)
+	start := len(out)
+	for i := 0; i < nseq; i++ {
+		llc, ofc, mlc := ll.sym(), of.sym(), ml.sym()
+		if ofc > 31 {
+			return out, errCorrupt
+		}
+		ofv := uint64(1)<<ofc + br.read(int(ofc))
+		mlen := int(mlBase[mlc]) + int(br.read(int(mlBits[mlc])))
+		llen := int(llBase[llc]) + int(br.read(int(llBits[llc])))
+		off := d.offset(ofv, llen)
+
+		if llen > len(lits) || len(out)-start+llen+mlen > limit {
+			return out, errCorrupt
+		}
+		out = append(out, lits[:llen]...)
+		lits = lits[llen:]
+		if off <= 0 || off > len(out) {
+			return out, errCorrupt
+		}
+		from := len(out) - off
+		if off >= mlen {
+			out = append(out, out[from:from+mlen]...)
+		} else {
+			for j := 0; j < mlen; j++ {
+				out = append(out, out[from+j])
+			}
+		}
+		if i < nseq-1 {
+			ll.update(&br)
+			ml.update(&br)
+			of.update(&br)
+		}
+		if br.overrun() {
+			return out, errCorrupt
+		}
+	}
+	if !br.done() || len(out)-start+len(lits) > limit {
+		return out, errCorrupt
+	}
+	return append(out, lits...), nil
+}
+
+// offset resolves an offset value against the repeat-offset history.
+func (d *decoder) offset(v uint64, llen int) int {
+	if v > 3 {
+		o := int(v - 3)
+		d.rep = [3]int{o, d.rep[0], d.rep[1]}
+		return o
+	}
+	idx := int(v) - 1
+	if llen == 0 {
+		idx++
+	}
+	var o int
+	switch idx {
+	case 0:
+		return d.rep[0]
+	case 1, 2:
+		o = d.rep[idx]
+	case 3:
+		o = d.rep[0] - 1
+	}
+	if idx == 1 {
+		d.rep[1] = d.rep[0]
+	} else {
+		d.rep[2], d.rep[1] = d.rep[1], d.rep[0]
+	}
+	d.rep[0] = o
+	return o
+}
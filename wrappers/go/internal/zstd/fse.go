@@ -0,0 +1,245 @@
+package zstd
+
+import "math/bits"
+
+// backReader reads a backward bitstream: the encoder packs values
+// little-endian from the first byte and finishes with a 1 marker bit, and the
+// decoder starts at the marker and consumes the most recently written value
+// first.
+type backReader struct {
+	b    []byte
+	off  int    // b[:off] is not loaded yet
+	bits uint64 // the low n bits are unread; bit n-1 is next
+	n    int    // goes negative once reads run past the start of the stream
+}
+
+func (r *backReader) init(b []byte) error {
+	if len(b) == 0 || b[len(b)-1] == 0 {
+		return errCorrupt
+	}
+	last := b[len(b)-1]
+	r.b, r.off = b, len(b)-1
+	r.bits = uint64(last)
+	r.n = bits.Len8(last) - 1
+	r.refill()
+	return nil
+}
+
+func (r *backReader) refill() {
+	for r.n <= 56 && r.off > 0 {
+		r.off--
+		r.bits = r.bits<<8 | uint64(r.b[r.off])
+		r.n += 8
+	}
+}
+
+// peek returns the next k bits without consuming them, zero-filled past the
+// start of the stream.
+func (r *backReader) peek(k int) uint64 {
+	if r.n < k {
+		r.refill()
+	}
+	mask := uint64(1)<<k - 1
+	switch {
+	case r.n >= k:
+		return r.bits >> uint(r.n-k) & mask
+	case r.n > 0:
+		return r.bits << uint(k-r.n) & mask
+	}
+	return 0
+}
+
+func (r *backReader) read(k int) uint64 {
+	if k == 0 {
+		return 0
+	}
+	v := r.peek(k)
+	r.n -= k
+	return v
+}
+
+// overrun reports whether more bits were read than the stream holds.
+func (r *backReader) overrun() bool { return r.n < 0 }
+
+// done reports whether the stream was consumed exactly.
+func (r *backReader) done() bool { return r.off == 0 && r.n == 0 }
+
+// fwdReader reads little-endian bit fields from the front of b, as used by
+// FSE table descriptions.
+type fwdReader struct {
+	b   []byte
+	pos int // in bits
+}
+
+func (r *fwdReader) peek(k int) uint64 {
+	var v uint64
+	for i := 0; i < k; i++ {
+		p := r.pos + i
+		if p/8 < len(r.b) {
+			v |= uint64(r.b[p/8]>>(p%8)&1) << i
+		}
+	}
+	return v
+}
+
+func (r *fwdReader) read(k int) uint64 {
+	v := r.peek(k)
+	r.pos += k
+	return v
+}
+
+type fseEntry struct {
+	sym  uint8
+	nb   uint8
+	base uint16
+}
+
+// fseTable is an FSE decoding table with 1<<log states.
+type fseTable struct {
+	log int
+	e   []fseEntry
+}
+
+// rleTable always decodes sym without consuming bits.
+func rleTable(sym uint8) *fseTable {
+	return &fseTable{e: []fseEntry{{sym: sym}}}
+}
+
+// readFSETable parses an FSE table description from the front of b and
+// returns the table and the bytes consumed.
+func readFSETable(b []byte, maxLog, maxSym int) (*fseTable, int, error) {
+	br := fwdReader{b: b}
+	log := int(br.read(4)) + 5
+	if log > maxLog {
+		return nil, 0, errCorrupt
+	}
+	remaining := 1<<log + 1
+	threshold := 1 << log
+	nb := log + 1
+	var norm []int16
+	prev0 := false
+	for remaining > 1 {
+		if prev0 {
+			// A zero count is followed by 2-bit repeat fields; 3 means
+			// "three more zeros, and another field follows".
+			for {
+				r := int(br.read(2))
+				for i := 0; i < r; i++ {
+					norm = append(norm, 0)
+				}
+				if r != 3 {
+					break
+				}
+				if len(norm) > maxSym+1 {
+					return nil, 0, errCorrupt
+				}
+			}
+		}
+		max := 2*threshold - 1 - remaining
+		v := int(br.peek(nb))
+		var count int
+		if v&(threshold-1) < max {
+			count = v & (threshold - 1)
+			br.pos += nb - 1
+		} else {
+			count = v & (2*threshold - 1)
+			if count >= threshold {
+				count -= max
+			}
+			br.pos += nb
+		}
+		count-- // -1 marks a "less than one" probability
+		if count < 0 {
+			remaining += count
+		} else {
+			remaining -= count
+		}
+		if remaining < 1 {
+			return nil, 0, errCorrupt
+		}
+		norm = append(norm, int16(count))
+		if len(norm) > maxSym+1 {
+			return nil, 0, errCorrupt
+		}
+		prev0 = count == 0
+		for remaining < threshold {
+			nb--
+			threshold >>= 1
+		}
+	}
+	used := (br.pos + 7) / 8
+	if used > len(b) {
+		return nil, 0, errCorrupt
+	}
+	t, err := buildFSE(norm, log)
+	return t, used, err
+}
+
+// buildFSE spreads symbols over the states as the format prescribes and
+// derives each state's successor range.
+func buildFSE(norm []int16, log int) (*fseTable, error) {
+	size := 1 << log
+	t := &fseTable{log: log, e: make([]fseEntry, size)}
+	next := make([]int, len(norm))
+	high := size - 1
+	for s, c := range norm {
+		if c == -1 {
+			if high < 0 {
+				return nil, errCorrupt
+			}
+			t.e[high].sym = uint8(s)
+			high--
+			next[s] = 1
+		} else {
+			next[s] = int(c)
+		}
+	}
+	pos, step, mask := 0, size>>1+size>>3+3, size-1
+	for s, c := range norm {
+		for i := 0; i < int(c); i++ {
+			t.e[pos].sym = uint8(s)
+			pos = (pos + step) & mask
+			for pos > high {
+				pos = (pos + step) & mask
+			}
+		}
+	}
+	if pos != 0 {
+		return nil, errCorrupt
+	}
+	for u := range t.e {
+		s := t.e[u].sym
+		ns := next[s]
+		next[s]++
+		if ns == 0 {
+			return nil, errCorrupt
+		}
+		nb := log - (bits.Len(uint(ns)) - 1)
+		t.e[u].nb = uint8(nb)
+		t.e[u].base = uint16(ns<<nb - size)
+	}
+	return t, nil
+}
+
+func mustFSE(norm []int16, log int) *fseTable {
+	t, err := buildFSE(norm, log)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// fseState walks an FSE table over a backward bitstream.
+type fseState struct {
+	t *fseTable
+	s int
+}
+
+func (st *fseState) init(br *backReader) { st.s = int(br.read(st.t.log)) }
+
+func (st *fseState) sym() uint8 { return st.t.e[st.s].sym }
+
+func (st *fseState) update(br *backReader) {
+	e := st.t.e[st.s]
+	st.s = int(e.base) + int(br.read(int(e.nb)))
+}
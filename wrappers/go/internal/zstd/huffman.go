@@ -0,0 +1,185 @@
+package zstd
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+const maxHuffmanBits = 11
+
+type hufEntry struct {
+	sym uint8
+	nb  uint8
+}
+
+// hufTable decodes prefix codes by looking up the next maxBits bits.
+type hufTable struct {
+	maxBits int
+	e       []hufEntry
+}
+
+// readHuffman parses a Huffman tree description and returns the table and
+// the bytes consumed.
+func readHuffman(b []byte) (*hufTable, int, error) {
+	if len(b) == 0 {
+		return nil, 0, errCorrupt
+	}
+	h := int(b[0])
+	var (
+		w    []uint8
+		used int
+	)
+	if h >= 128 {
+		// Weights stored directly, two per byte.
+		n := h - 127
+		used = 1 + (n+1)/2
+		if len(b) < used {
+			return nil, 0, errCorrupt
+		}
+		for i := 0; i < n; i++ {
+			c := b[1+i/2]
+			if i%2 == 0 {
+				c >>= 4
+			}
+			w = append(w, c&15)
+		}
+	} else {
+		used = 1 + h
+		if len(b) < used {
+			return nil, 0, errCorrupt
+		}
+		var err error
+		if w, err = fseWeights(b[1:used]); err != nil {
+			return nil, 0, err
+		}
+	}
+	t, err := buildHuffman(w)
+	return t, used, err
+}
+
+// fseWeights decodes FSE-compressed Huffman weights: two states interleaved
+// over one bitstream until it is exhausted.
+func fseWeights(b []byte) ([]uint8, error) {
+	t, n, err := readFSETable(b, 6, 15)
+	if err != nil {
+		return nil, err
+	}
+	var br backReader
+	if err := br.init(b[n:]); err != nil {
+		return nil, err
+	}
+	s1, s2 := fseState{t: t}, fseState{t: t}
+	s1.init(&br)
+	s2.init(&br)
+	var w []uint8
+	for {
+		if len(w) > 253 {
+			return nil, errCorrupt
+		}
+		w = append(w, s1.sym())
+		s1.update(&br)
+		if br.overrun() {
+			w = append(w, s2.sym())
+			break
+		}
+		w = append(w, s2.sym())
+		s2.update(&br)
+		if br.overrun() {
+			w = append(w, s1.sym())
+			break
+		}
+	}
+	return w, nil
+}
+
+// buildHuffman completes the weight list with the implied last symbol and
+// lays out the lookup table: lowest weights (longest codes) take the lowest
+// prefixes, in symbol order.
+func buildHuffman(w []uint8) (*hufTable, error) {
+	if len(w) > 255 {
+		return nil, errCorrupt
+	}
+	var sum uint32
+	for _, x := range w {
+		if x > maxHuffmanBits {
+			return nil, errCorrupt
+		}
+		if x > 0 {
+			sum += 1 << (x - 1)
+		}
+	}
+	if sum == 0 {
+		return nil, errCorrupt
+	}
+	maxBits := bits.Len32(sum)
+	rest := uint32(1)<<maxBits - sum
+	if maxBits > maxHuffmanBits || rest&(rest-1) != 0 {
+		return nil, errCorrupt
+	}
+	w = append(w, uint8(bits.Len32(rest)))
+	t := &hufTable{maxBits: maxBits, e: make([]hufEntry, 1<<maxBits)}
+	pos := 0
+	for wt := 1; wt <= maxBits; wt++ {
+		for s, x := range w {
+			if int(x) != wt {
+				continue
+			}
+			n := 1 << (wt - 1)
+			e := hufEntry{sym: uint8(s), nb: uint8(maxBits + 1 - wt)}
+			for i := 0; i < n; i++ {
+				t.e[pos+i] = e
+			}
+			pos += n
+		}
+	}
+	return t, nil
+}
+
+// decode fills dst from one Huffman-coded stream, which must be consumed
+// exactly.
+func (t *hufTable) decode(dst, src []byte) error {
+	var br backReader
+	if err := br.init(src); err != nil {
+		return err
+	}
+	for i := range dst {
+		e := t.e[br.peek(t.maxBits)]
+		dst[i] = e.sym
+		br.n -= int(e.nb)
+	}
+	if !br.done() {
+		return errCorrupt
+	}
+	return nil
+}
+
+// decode4 splits src by its 6-byte jump table and decodes four streams into
+// consecutive quarters of dst.
+func (t *hufTable) decode4(dst, src []byte) error {
+	if len(src) < 6 {
+		return errCorrupt
+	}
+	s1 := int(binary.LittleEndian.Uint16(src))
+	s2 := int(binary.LittleEndian.Uint16(src[2:]))
+	s3 := int(binary.LittleEndian.Uint16(src[4:]))
+	src = src[6:]
+	if s1+s2+s3 > len(src) {
+		return errCorrupt
+	}
+	seg := (len(dst) + 3) / 4
+	if 3*seg > len(dst) {
+		return errCorrupt
+	}
+	sizes := [4]int{s1, s2, s3, len(src) - s1 - s2 - s3}
+	for i, n := range sizes {
+		end := (i + 1) * seg
+		if i == 3 {
+			end = len(dst)
+		}
+		if err := t.decode(dst[i*seg:end], src[:n]); err != nil {
+			return err
+		}
+		src = src[n:]
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package zstd
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// XXH64 with seed 0; frames carry the low 32 bits as their checksum.
+
+const (
+	prime1 uint64 = 11400714785074694791
+	prime2 uint64 = 14029467366897019727
+	prime3 uint64 = 1609587929392839161
+	prime4 uint64 = 9650029242287828579
+	prime5 uint64 = 2870177450012600261
+
+	// Initial accumulators that wrap around 2^64.
+	prime1plus2 uint64 = 6983438078262162902 // prime1 + prime2
+	negPrime1   uint64 = 7046029288634856825 // -prime1
+)
+
+type xxh64 struct {
+	v     [4]uint64
+	buf   [32]byte
+	nbuf  int
+	total uint64
+}
+
+func (h *xxh64) reset() {
+	h.v = [4]uint64{prime1plus2, prime2, 0, negPrime1}
+	h.nbuf, h.total = 0, 0
+}
+
+func xxRound(acc, in uint64) uint64 {
+	acc += in * prime2
+	return bits.RotateLeft64(acc, 31) * prime1
+}
+
+func (h *xxh64) stripe(b []byte) {
+	for i := range h.v {
+		h.v[i] = xxRound(h.v[i], binary.LittleEndian.Uint64(b[8*i:]))
+	}
+}
+
+func (h *xxh64) write(b []byte) {
+	h.total += uint64(len(b))
+	if h.nbuf > 0 {
+		n := copy(h.buf[h.nbuf:], b)
+		h.nbuf += n
+		b = b[n:]
+		if h.nbuf < 32 {
+			return
+		}
+		h.stripe(h.buf[:])
+		h.nbuf = 0
+	}
+	for ; len(b) >= 32; b = b[32:] {
+		h.stripe(b)
+	}
+	h.nbuf = copy(h.buf[:], b)
+}
+
+func (h *xxh64) sum() uint64 {
+	var acc uint64
+	if h.total >= 32 {
+		v := h.v
+		acc = bits.RotateLeft64(v[0], 1) + bits.RotateLeft64(v[1], 7) +
+			bits.RotateLeft64(v[2], 12) + bits.RotateLeft64(v[3], 18)
+		for _, x := range v {
+			acc ^= xxRound(0, x)
+			acc = acc*prime1 + prime4
+		}
+	} else {
+		acc = prime5
+	}
+	acc += h.total
+	b := h.buf[:h.nbuf]
+	for ; len(b) >= 8; b = b[8:] {
+		acc ^= xxRound(0, binary.LittleEndian.Uint64(b))
+		acc = bits.RotateLeft64(acc, 27)*prime1 + prime4
+	}
+	if len(b) >= 4 {
+		acc ^= uint64(binary.LittleEndian.Uint32(b)) * prime1
+		acc = bits.RotateLeft64(acc, 23)*prime2 + prime3
+		b = b[4:]
+	}
+	for _, c := range b {
+		acc ^= uint64(c) * prime5
+		acc = bits.RotateLeft64(acc, 11) * prime1
+	}
+	acc ^= acc >> 33
+	acc *= prime2
+	acc ^= acc >> 29
+	acc *= prime3
+	acc ^= acc >> 32
+	return acc
+}
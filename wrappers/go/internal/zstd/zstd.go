@@ -0,0 +1,231 @@
+// Package zstd decompresses Zstandard streams (RFC 8878) without cgo or
+// third-party code. It decodes every frame the reference encoder produces
+// except those that need a dictionary, including concatenated and skippable
+// frames and content checksums.
+package zstd
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Magic starts every zstd frame.
+var Magic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+const (
+	frameMagic     = 0xfd2fb528
+	skippableMagic = 0x184d2a50 // low four bits are free
+
+	// maxWindow bounds the history kept per frame. The reference decoder's
+	// default limit is 128 MiB; allowing up to 1 GiB covers --long archives.
+	maxWindow = 1 << 30
+)
+
+var errCorrupt = errors.New("zstd: corrupt input")
+
+// Reader decompresses a zstd stream.
+type Reader struct {
+	r   *bufio.Reader
+	err error
+	d   decoder
+
+	inFrame  bool
+	window   int
+	blockMax int
+	checksum bool
+	size     int64 // declared content size, -1 if absent
+	produced int64
+	digest   xxh64
+
+	hist    []byte // frame output, trimmed to the window between blocks
+	pending []byte // decoded bytes not yet returned, a suffix of hist
+	block   []byte
+}
+
+// NewReader returns a Reader decompressing r.
+func NewReader(r io.Reader) *Reader {
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &Reader{r: br}
+}
+
+func (z *Reader) Read(p []byte) (int, error) {
+	for len(z.pending) == 0 {
+		if z.err != nil {
+			return 0, z.err
+		}
+		if z.inFrame {
+			z.err = z.nextBlock()
+		} else {
+			z.err = z.nextFrame()
+		}
+	}
+	n := copy(p, z.pending)
+	z.pending = z.pending[n:]
+	return n, nil
+}
+
+func (z *Reader) nextFrame() error {
+	var b [8]byte
+	if _, err := io.ReadFull(z.r, b[:4]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return errCorrupt
+		}
+		return err // io.EOF between frames ends the stream
+	}
+	magic := binary.LittleEndian.Uint32(b[:])
+	if magic&^0xf == skippableMagic {
+		if _, err := io.ReadFull(z.r, b[:4]); err != nil {
+			return unexpected(err)
+		}
+		n := int64(binary.LittleEndian.Uint32(b[:]))
+		if m, err := io.CopyN(io.Discard, z.r, n); m != n {
+			return unexpected(err)
+		}
+		return nil
+	}
+	if magic != frameMagic {
+		return fmt.Errorf("zstd: bad frame magic %#08x", magic)
+	}
+	fhd, err := z.r.ReadByte()
+	if err != nil {
+		return unexpected(err)
+	}
+	if fhd&0x08 != 0 {
+		return errCorrupt // reserved bit
+	}
+	single := fhd&0x20 != 0
+	var window uint64
+	if !single {
+		wd, err := z.r.ReadByte()
+		if err != nil {
+			return unexpected(err)
+		}
+		base := uint64(1) << (10 + wd>>3)
+		window = base + base/8*uint64(wd&7)
+	}
+	if n := [4]int{0, 1, 2, 4}[fhd&3]; n > 0 {
+		if _, err := io.ReadFull(z.r, b[:n]); err != nil {
+			return unexpected(err)
+		}
+		if id := binary.LittleEndian.Uint32(append(b[:n:n], 0, 0, 0, 0)); id != 0 {
+			return fmt.Errorf("zstd: frame needs dictionary %d, dictionaries are not supported", id)
+		}
+	}
+	z.size = -1
+	if n := [4]int{0, 2, 4, 8}[fhd>>6]; n > 0 || single {
+		if n == 0 {
+			n = 1
+		}
+		clear(b[:])
+		if _, err := io.ReadFull(z.r, b[:n]); err != nil {
+			return unexpected(err)
+		}
+		size := binary.LittleEndian.Uint64(b[:])
+		if n == 2 {
+			size += 256
+		}
+		if size > 1<<62 {
+			return errCorrupt
+		}
+		z.size = int64(size)
+		if single {
+			window = size
+		}
+	}
+	if window > maxWindow {
+		return fmt.Errorf("zstd: frame window of %d bytes exceeds the %d byte limit", window, maxWindow)
+	}
+	z.window = int(window)
+	z.blockMax = min(z.window, maxBlockSize)
+	z.checksum = fhd&0x04 != 0
+	z.produced = 0
+	z.digest.reset()
+	z.d.reset()
+	z.hist = z.hist[:0]
+	z.inFrame = true
+	return nil
+}
+
+func (z *Reader) nextBlock() error {
+	var b [4]byte
+	if _, err := io.ReadFull(z.r, b[:3]); err != nil {
+		return unexpected(err)
+	}
+	h := binary.LittleEndian.Uint32(b[:])
+	last, typ, size := h&1 != 0, h>>1&3, int(h>>3)
+	if size > z.blockMax {
+		return errCorrupt
+	}
+	if keep := z.window; len(z.hist) > 2*keep && len(z.hist) > maxBlockSize {
+		n := copy(z.hist, z.hist[len(z.hist)-keep:])
+		z.hist = z.hist[:n]
+	}
+	start := len(z.hist)
+	switch typ {
+	case 0: // raw
+		z.hist = append(z.hist, make([]byte, size)...)
+		if _, err := io.ReadFull(z.r, z.hist[start:]); err != nil {
+			return unexpected(err)
+		}
+	case 1: // RLE
+		c, err := z.r.ReadByte()
+		if err != nil {
+			return unexpected(err)
+		}
+		for i := 0; i < size; i++ {
+			z.hist = append(z.hist, c)
+		}
+	case 2:
+		if cap(z.block) < size {
+			z.block = make([]byte, maxBlockSize)
+		}
+		buf := z.block[:size]
+		if _, err := io.ReadFull(z.r, buf); err != nil {
+			return unexpected(err)
+		}
+		var err error
+		if z.hist, err = z.d.compressed(z.hist, buf, z.blockMax); err != nil {
+			return err
+		}
+	default:
+		return errCorrupt
+	}
+	out := z.hist[start:]
+	z.produced += int64(len(out))
+	if z.size >= 0 && z.produced > z.size {
+		return errCorrupt
+	}
+	if z.checksum {
+		z.digest.write(out)
+	}
+	z.pending = out
+	if !last {
+		return nil
+	}
+	z.inFrame = false
+	if z.size >= 0 && z.produced != z.size {
+		return fmt.Errorf("zstd: frame holds %d bytes, header declares %d", z.produced, z.size)
+	}
+	if z.checksum {
+		if _, err := io.ReadFull(z.r, b[:]); err != nil {
+			return unexpected(err)
+		}
+		if binary.LittleEndian.Uint32(b[:]) != uint32(z.digest.sum()) {
+			return errors.New("zstd: checksum mismatch")
+		}
+	}
+	return nil
+}
+
+func unexpected(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return errCorrupt
+	}
+	return err
+}
@@ -0,0 +1,60 @@
+package zstd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+// testdata/graph.gr.zst was written by the reference zstd library at level
+// 19 with a checksum; it spans two blocks and uses Huffman literals and FSE
+// sequence tables.
+func sampleArcs() []byte {
+	var b bytes.Buffer
+	x := uint64(1)
+	for i := 0; i < 300; i++ {
+		x = (x*1103515245 + 12345) % (1 << 31)
+		fmt.Fprintf(&b, "a %d %d %d\n", i%50+1, x%1000+1, x%97+1)
+	}
+	return bytes.Repeat(b.Bytes(), 40)
+}
+
+func TestDecodeReferenceFrame(t *testing.T) {
+	comp, err := os.ReadFile("testdata/graph.gr.zst")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(NewReader(bytes.NewReader(comp)))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if want := sampleArcs(); !bytes.Equal(got, want) {
+		t.Fatalf("decoded %d bytes, want %d", len(got), len(want))
+	}
+
+	bad := append([]byte(nil), comp...)
+	bad[len(bad)-1] ^= 1
+	if _, err := io.ReadAll(NewReader(bytes.NewReader(bad))); err == nil {
+		t.Fatalf("expected checksum mismatch")
+	}
+	if _, err := io.ReadAll(NewReader(bytes.NewReader(comp[:len(comp)/2]))); err == nil {
+		t.Fatalf("expected truncated frame to fail")
+	}
+}
+
+func TestConcatenatedAndSkippableFrames(t *testing.T) {
+	// Two frames (the second without a content size or checksum) around a
+	// skippable frame.
+	comp, _ := hex.DecodeString("28b52ffd240949000070207370203320320a0140c5ca502a4d180300000078797a" +
+		"28b52ffd0000810000612031203220350a612032203320370a")
+	got, err := io.ReadAll(NewReader(bytes.NewReader(comp)))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if want := "p sp 3 2\na 1 2 5\na 2 3 7\n"; string(got) != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,251 @@
+package sssp
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/your-org/optimized-sssp-go/internal/decompress"
+)
+
+// Format identifies a graph file format known to LoadGraph.
+type Format int
+
+// Formats recognised by DetectFormat.
+const (
+	FormatUnknown Format = iota
+	FormatDIMACS
+	FormatMETIS
+	FormatMatrixMarket
+	FormatGraphML
+	FormatGML
+	FormatJSON
+	FormatEdgeList
+	FormatSNAP
+	FormatSnapshot
+)
+
+var formatNames = [...]string{"unknown", "dimacs", "metis", "mtx", "graphml", "gml", "json", "edgelist", "snap", "snapshot"}
+
+func (f Format) String() string {
+	if f < 0 || int(f) >= len(formatNames) {
+		return fmt.Sprintf("Format(%d)", int(f))
+	}
+	return formatNames[f]
+}
+
+// maxPrealloc caps the capacity a reader reserves from a count in a file's
+// header. Past it slices grow as data arrives, so a corrupt header cannot
+// make us allocate far more than the input holds.
+const maxPrealloc = 1 << 20
+
+// sniffSize is how much of the (decompressed) input detection looks at.
+const sniffSize = 64 << 10
+
+var gmlGraph = regexp.MustCompile(`(?m)^\s*graph\s*\[`)
+
+// DetectFormat guesses the format of uncompressed content from its first
+// bytes. METIS and plain edge lists are both lines of numbers; without other
+// clues (comments, blank lines, varying field counts) the content is taken
+// for an edge list.
+func DetectFormat(head []byte) Format {
+	return detect(head, false)
+}
+
+// detect is DetectFormat; complete reports that head is the whole input,
+// which lets METIS be confirmed by its line count.
+func detect(head []byte, complete bool) Format {
+	if bytes.HasPrefix(head, []byte(snapshotMagic)) {
+		return FormatSnapshot
+	}
+	text := bytes.TrimLeft(bytes.TrimPrefix(head, []byte("\ufeff")), " \t\r\n")
+	switch {
+	case len(text) == 0:
+		return FormatUnknown
+	case text[0] == '<':
+		return FormatGraphML
+	case text[0] == '{':
+		return FormatJSON
+	case bytes.HasPrefix(text, []byte("%%MatrixMarket")):
+		return FormatMatrixMarket
+	case gmlGraph.Match(text):
+		return FormatGML
+	}
+	lines := strings.Split(string(text), "\n")
+	if !complete && len(lines) > 1 {
+		lines = lines[:len(lines)-1] // probably cut short
+	}
+	first := strings.Fields(lines[0])
+	if len(first) == 0 {
+		return FormatUnknown
+	}
+	switch first[0] {
+	case "c", "p", "a":
+		for _, l := range lines {
+			if f := strings.Fields(l); len(f) >= 2 && f[0] == "p" && f[1] == "sp" {
+				return FormatDIMACS
+			}
+		}
+		return FormatUnknown
+	}
+	if strings.HasPrefix(first[0], "#") {
+		if strings.Contains(string(text), "FromNodeId") || strings.Contains(string(text), "Nodes:") {
+			return FormatSNAP
+		}
+		return FormatEdgeList
+	}
+	if strings.HasPrefix(first[0], "%") {
+		return FormatMETIS
+	}
+	if looksLikeMETIS(lines, complete) {
+		return FormatMETIS
+	}
+	return FormatEdgeList
+}
+
+// looksLikeMETIS checks numeric lines for METIS traits: integer fields only,
+// and blank lines (isolated vertices) or differing field counts. A complete
+// input must also have one line per declared vertex and, without a fmt
+// field, 2m neighbour entries.
+func looksLikeMETIS(lines []string, complete bool) bool {
+	header := strings.Fields(lines[0])
+	if len(header) < 2 || len(header) > 4 {
+		return false
+	}
+	n, err1 := strconv.ParseUint(header[0], 10, 32)
+	m, err2 := strconv.ParseUint(header[1], 10, 64)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	varied := false
+	var entries uint64
+	for i, l := range lines {
+		f := strings.Fields(l)
+		for _, x := range f {
+			if _, err := strconv.ParseUint(x, 10, 64); err != nil {
+				return false
+			}
+		}
+		if i > 0 {
+			varied = varied || len(f) != len(header)
+			entries += uint64(len(f))
+		}
+	}
+	if !complete {
+		return varied
+	}
+	if strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1] // after the final newline; earlier blanks are vertices
+	}
+	return uint64(len(lines)-1) == n && (len(header) > 2 || entries == 2*m)
+}
+
+// edgeListOptions picks a delimiter and header skip for an edge list.
+func edgeListOptions(head []byte) EdgeListOptions {
+	var opts EdgeListOptions
+	for _, l := range strings.Split(string(head), "\n") {
+		l = strings.TrimSpace(strings.TrimPrefix(l, "\ufeff"))
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		switch {
+		case strings.Contains(l, ","):
+			opts.Delimiter = ','
+		case strings.Contains(l, "\t") && strings.Contains(l, " "):
+			opts.Delimiter = '\t' // tab-separated with spaces inside fields
+		}
+		sep := func(r rune) bool { return r == opts.Delimiter }
+		if opts.Delimiter == 0 {
+			sep = func(r rune) bool { return r == ' ' || r == '\t' }
+		}
+		if f := strings.FieldsFunc(l, sep); len(f) > 0 {
+			if _, err := strconv.ParseFloat(strings.Trim(f[0], `" `), 64); err != nil {
+				opts.SkipHeader = 1 // column names
+			}
+		}
+		break
+	}
+	return opts
+}
+
+// LoadGraph decompresses r if needed, detects its format and parses it with
+// the matching loader and default options. Node names from GraphML and GML
+// are dropped; call those loaders directly to keep them.
+func LoadGraph(r io.Reader) (*Graph, error) {
+	return loadDetected(r, FormatUnknown)
+}
+
+// LoadGraphFile is LoadGraph for a file. The extension (ignoring a trailing
+// .gz or .zst) settles the METIS / edge list ambiguity: .graph and .metis
+// files are read as METIS when their content does not say otherwise.
+func LoadGraphFile(path string) (*Graph, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	g, err := loadDetected(f, formatByExt(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return g, nil
+}
+
+func formatByExt(path string) Format {
+	p := strings.ToLower(path)
+	p = strings.TrimSuffix(strings.TrimSuffix(p, ".gz"), ".zst")
+	switch filepath.Ext(p) {
+	case ".graph", ".metis":
+		return FormatMETIS
+	}
+	return FormatUnknown
+}
+
+func loadDetected(r io.Reader, hint Format) (*Graph, error) {
+	dr, err := decompress.Reader(r)
+	if err != nil {
+		return nil, err
+	}
+	br := bufio.NewReaderSize(dr, sniffSize)
+	head, err := br.Peek(sniffSize)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	format := detect(head, err == io.EOF)
+	if hint == FormatMETIS && (format == FormatEdgeList || format == FormatUnknown) {
+		format = hint
+	}
+	switch format {
+	case FormatDIMACS:
+		return LoadDIMACS(br)
+	case FormatMETIS:
+		return LoadMETIS(br)
+	case FormatMatrixMarket:
+		return LoadMatrixMarket(br)
+	case FormatGraphML:
+		g, _, err := LoadGraphML(br, GraphMLOptions{})
+		return g, err
+	case FormatGML:
+		g, _, err := LoadGML(br, GMLOptions{})
+		return g, err
+	case FormatJSON:
+		return DecodeJSON(br)
+	case FormatEdgeList:
+		return LoadEdgeList(br, edgeListOptions(head))
+	case FormatSNAP:
+		return LoadSNAP(br, SNAPOptions{Symmetrize: bytes.Contains(head, []byte("Undirected graph"))})
+	case FormatSnapshot:
+		b, err := io.ReadAll(br)
+		if err != nil {
+			return nil, err
+		}
+		return SnapshotFromBytes(b)
+	}
+	return nil, fmt.Errorf("sssp: unrecognised graph format")
+}
@@ -0,0 +1,100 @@
+package sssp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadGraphDetectsWrittenFormats(t *testing.T) {
+	// A symmetric graph so WriteMETIS accepts it.
+	g, _ := FromEdges(4, []Edge{
+		{0, 1, 1}, {1, 0, 1}, {1, 2, 2}, {2, 1, 2}, {2, 3, 3}, {3, 2, 3}, {0, 3, 4}, {3, 0, 4},
+	})
+	writers := map[Format]func(*bytes.Buffer) error{
+		FormatDIMACS:       func(b *bytes.Buffer) error { return g.WriteDIMACS(b) },
+		FormatMETIS:        func(b *bytes.Buffer) error { return g.WriteMETIS(b) },
+		FormatMatrixMarket: func(b *bytes.Buffer) error { return g.WriteMatrixMarket(b) },
+		FormatGraphML:      func(b *bytes.Buffer) error { return g.WriteGraphML(b, nil, GraphMLOptions{}) },
+		FormatGML:          func(b *bytes.Buffer) error { return g.WriteGML(b, nil, GMLOptions{}) },
+		FormatJSON:         func(b *bytes.Buffer) error { return g.EncodeJSON(b) },
+		FormatSnapshot:     func(b *bytes.Buffer) error { return g.SaveSnapshot(b) },
+	}
+	for format, write := range writers {
+		var buf bytes.Buffer
+		if err := write(&buf); err != nil {
+			t.Fatalf("%v: write: %v", format, err)
+		}
+		if got := detect(buf.Bytes(), true); got != format {
+			t.Fatalf("detected %v, want %v", got, format)
+		}
+		var gz bytes.Buffer
+		zw := gzip.NewWriter(&gz)
+		zw.Write(buf.Bytes())
+		zw.Close()
+		loaded, err := LoadGraph(&gz)
+		if err != nil {
+			t.Fatalf("%v: load: %v", format, err)
+		}
+		if loaded.NodeCount() != 4 || loaded.EdgeCount() != 8 {
+			t.Fatalf("%v: %d nodes %d edges", format, loaded.NodeCount(), loaded.EdgeCount())
+		}
+	}
+}
+
+func TestLoadGraphTextEdgeLists(t *testing.T) {
+	cases := []struct {
+		in     string
+		format Format
+		edges  int
+	}{
+		{"source,target,weight\n0,1,2.5\n1,2,1\n", FormatEdgeList, 2},
+		{"0 1\n1 2\n2 0\n", FormatEdgeList, 3},
+		{"# Undirected graph: ca-Test\n# Nodes: 3 Edges: 2\n# FromNodeId\tToNodeId\n0\t1\n1\t2\n", FormatSNAP, 4},
+		// A cycle in METIS has the same field count on every line; only the
+		// line and entry counts give it away.
+		{"3 3\n2 3\n1 3\n1 2\n", FormatMETIS, 6},
+		{"4 1\n2\n1\n\n\n", FormatMETIS, 2}, // trailing isolated vertices
+	}
+	for _, c := range cases {
+		if got := detect([]byte(c.in), true); got != c.format {
+			t.Fatalf("%q: detected %v, want %v", c.in, got, c.format)
+		}
+		g, err := LoadGraph(strings.NewReader(c.in))
+		if err != nil {
+			t.Fatalf("%q: %v", c.in, err)
+		}
+		if g.EdgeCount() != c.edges {
+			t.Fatalf("%q: %d edges, want %d", c.in, g.EdgeCount(), c.edges)
+		}
+	}
+	if _, err := LoadGraph(strings.NewReader("c only a comment\n")); err == nil {
+		t.Fatalf("expected unrecognised input to fail")
+	}
+}
+
+func TestLoadGraphFileUsesMETISExtension(t *testing.T) {
+	// A ring too large to inspect whole: every line has two fields, so the
+	// content alone reads as an edge list and the extension decides.
+	const n = 20000
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d %d\n", n, n)
+	for v := 1; v <= n; v++ {
+		fmt.Fprintf(&b, "%d %d\n", (v+n-2)%n+1, v%n+1)
+	}
+	if DetectFormat([]byte(b.String())[:sniffSize]) != FormatEdgeList {
+		t.Fatalf("expected a uniform prefix to look like an edge list")
+	}
+	path := filepath.Join(t.TempDir(), "ring.graph")
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	g, err := LoadGraphFile(path)
+	if err != nil || g.NodeCount() != n || g.EdgeCount() != 2*n {
+		t.Fatalf("load: %v", err)
+	}
+}
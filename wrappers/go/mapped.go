@@ -0,0 +1,61 @@
+package sssp
+
+import "github.com/your-org/optimized-sssp-go/internal/decompress"
+
+// MappedGraph is a Graph whose arrays live in a read-only mapping of a
+// snapshot file (see SaveSnapshot). Writing to the arrays faults; Close
+// releases the mapping, after which the graph must not be used.
+type MappedGraph struct {
+	*Graph
+	unmap func() error
+}
+
+// OpenMapped maps a snapshot file read-only and returns a query-ready graph.
+// The offsets and targets are checked as in SnapshotFromBytes, which reads
+// them through once; weights and the rest of the pages are loaded lazily by
+// the OS. On platforms without mmap the file is read into memory instead,
+// and so is a compressed snapshot, which cannot be used in place.
+func OpenMapped(path string) (*MappedGraph, error) {
+	return openMapped(path, true)
+}
+
+// OpenMappedUnchecked is OpenMapped without the pass over offsets and
+// targets, so opening is O(1) in the file size. Use it only for files this
+// process or another trusted one wrote: a corrupted file makes queries read
+// out of bounds, in the pure-Go engine and in the native library alike.
+func OpenMappedUnchecked(path string) (*MappedGraph, error) {
+	return openMapped(path, false)
+}
+
+func openMapped(path string, check bool) (*MappedGraph, error) {
+	data, unmap, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if decompress.Compressed(data) {
+		g, err := snapshotFromBytes(data, check)
+		if uerr := unmap(); err == nil {
+			err = uerr
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &MappedGraph{Graph: g, unmap: func() error { return nil }}, nil
+	}
+	g, err := snapshotFromBytes(data, check)
+	if err != nil {
+		unmap()
+		return nil, err
+	}
+	return &MappedGraph{Graph: g, unmap: unmap}, nil
+}
+
+// Close unmaps the file. It is safe to call more than once.
+func (m *MappedGraph) Close() error {
+	if m.unmap == nil {
+		return nil
+	}
+	err := m.unmap()
+	m.unmap, m.Graph = nil, nil
+	return err
+}
@@ -0,0 +1,94 @@
+package sssp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenMappedRunsAndCloses(t *testing.T) {
+	off, tgt, wts := randomCSR(400, 4, 5)
+	g, _ := NewGraphCSR(off, tgt, wts)
+	path := filepath.Join(t.TempDir(), "g.csr")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := g.SaveSnapshot(f); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	f.Close()
+
+	m, err := OpenMapped(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	want, _ := g.Run(3, ModeBaseline)
+	got, err := m.Run(3, ModeBaseline)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	for v := range want.Dist {
+		if want.Dist[v] != got.Dist[v] {
+			t.Fatalf("dist[%d] = %v, want %v", v, got.Dist[v], want.Dist[v])
+		}
+	}
+	if err := m.Close(); err != nil || m.Close() != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("not a snapshot"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := OpenMapped(path); err == nil {
+		t.Fatalf("expected bad magic error")
+	}
+}
+
+func TestOpenMappedDecompressesSnapshot(t *testing.T) {
+	off, tgt, wts := randomCSR(200, 3, 9)
+	g, _ := NewGraphCSR(off, tgt, wts)
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := g.SaveSnapshot(zw); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	zw.Close()
+	path := filepath.Join(t.TempDir(), "g.csr.gz")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	m, err := OpenMapped(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer m.Close()
+	if got, want := m.Edges(), g.Edges(); len(got) != len(want) || got[len(got)-1] != want[len(want)-1] {
+		t.Fatalf("edges differ after decompression")
+	}
+}
+
+func TestOpenMappedChecksStructure(t *testing.T) {
+	g, _ := FromEdges(3, []Edge{{0, 1, 1}, {1, 2, 1}})
+	var buf bytes.Buffer
+	if err := g.SaveSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+	b := buf.Bytes()
+	_, tgtAt, _, _ := snapshotSections(3, 2)
+	b[tgtAt+2] = 0x7f // target far past n
+	path := filepath.Join(t.TempDir(), "bad.csr")
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenMapped(path); err == nil {
+		t.Fatal("corrupt target accepted")
+	}
+	m, err := OpenMappedUnchecked(path)
+	if err != nil {
+		t.Fatalf("unchecked open: %v", err)
+	}
+	m.Close()
+}
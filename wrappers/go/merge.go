@@ -0,0 +1,99 @@
+package sssp
+
+import "fmt"
+
+// WeightResolver picks the weight of an edge both graphs of a Merge have,
+// given its weight in the base graph and in the overlay.
+type WeightResolver func(base, overlay float32) float32
+
+// Resolvers for Merge.
+var (
+	PreferBase    WeightResolver = func(base, _ float32) float32 { return base }
+	PreferOverlay WeightResolver = func(_, overlay float32) float32 { return overlay }
+	MinWeight     WeightResolver = func(base, overlay float32) float32 { return min(base, overlay) }
+	AddWeights    WeightResolver = func(base, overlay float32) float32 { return base + overlay }
+)
+
+// Merge returns the union of base and overlay, with as many nodes as the
+// larger of the two. An overlay edge u->v that base also has is shared: its
+// weight is folded into base's first u->v edge with resolve, and it is not
+// added again. Every other edge is kept, each node's base edges first, in
+// CSR order. Coordinates come from base if it has them for every node,
+// otherwise from overlay if it does. Attributes of both are kept, base's
+// winning for nodes and shared edges; an attribute both have must have the
+// same type in each. Neither input is modified.
+func Merge(base, overlay *Graph, resolve WeightResolver) (*Graph, error) {
+	if resolve == nil {
+		return nil, fmt.Errorf("sssp: merge needs a weight resolver")
+	}
+	n := max(base.NodeCount(), overlay.NodeCount())
+	if m := uint64(base.EdgeCount()) + uint64(overlay.EdgeCount()); m > uint64(^uint32(0)) {
+		return nil, fmt.Errorf("sssp: %d edges overflow the 32-bit offsets", m)
+	}
+	offsets := make([]uint32, n+1)
+	targets := make([]uint32, 0, base.EdgeCount()+overlay.EdgeCount())
+	weights := make([]float32, 0, cap(targets))
+	stamp := make([]uint32, n) // stamp[v] == u+1: slot[v] holds base's first u->v
+	slot := make([]uint32, n)
+	// The old IDs of each merged edge in base and overlay, -1 where it has
+	// none, for the edge attributes.
+	var baseIdx, overlayIdx []int32
+	edgeAttrs := len(base.edgeAttrs) > 0 || len(overlay.edgeAttrs) > 0
+	for u := uint32(0); u < n; u++ {
+		if u < base.NodeCount() {
+			for e := base.offsets[u]; e < base.offsets[u+1]; e++ {
+				v := base.targets[e]
+				if stamp[v] != u+1 {
+					stamp[v], slot[v] = u+1, uint32(len(targets))
+				}
+				targets = append(targets, v)
+				weights = append(weights, base.weights[e])
+				if edgeAttrs {
+					baseIdx, overlayIdx = append(baseIdx, int32(e)), append(overlayIdx, -1)
+				}
+			}
+		}
+		if u < overlay.NodeCount() {
+			for e := overlay.offsets[u]; e < overlay.offsets[u+1]; e++ {
+				v, w := overlay.targets[e], overlay.weights[e]
+				if stamp[v] == u+1 {
+					weights[slot[v]] = resolve(weights[slot[v]], w)
+					continue
+				}
+				targets = append(targets, v)
+				weights = append(weights, w)
+				if edgeAttrs {
+					baseIdx, overlayIdx = append(baseIdx, -1), append(overlayIdx, int32(e))
+				}
+			}
+		}
+		offsets[u+1] = uint32(len(targets))
+	}
+	g := &Graph{offsets: offsets, targets: targets, weights: weights, owned: true}
+	var err error
+	if g.edgeAttrs, err = mergeAttrs(base.edgeAttrs, baseIdx, overlay.edgeAttrs, overlayIdx); err != nil {
+		return nil, err
+	}
+	if len(base.nodeAttrs) > 0 || len(overlay.nodeAttrs) > 0 {
+		ia, ib := make([]int32, n), make([]int32, n)
+		for u := range ia {
+			ia[u], ib[u] = -1, -1
+			if u < int(base.NodeCount()) {
+				ia[u] = int32(u)
+			}
+			if u < int(overlay.NodeCount()) {
+				ib[u] = int32(u)
+			}
+		}
+		if g.nodeAttrs, err = mergeAttrs(base.nodeAttrs, ia, overlay.nodeAttrs, ib); err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case len(base.coords) == int(n):
+		g.coords = base.coords
+	case len(overlay.coords) == int(n):
+		g.coords = overlay.coords
+	}
+	return g, nil
+}
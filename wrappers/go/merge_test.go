@@ -0,0 +1,41 @@
+package sssp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	base, err := FromEdges(3, []Edge{{0, 1, 4}, {0, 1, 9}, {1, 2, 4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	overlay, err := FromEdges(4, []Edge{{0, 1, 2}, {2, 3, 1}, {1, 2, 6}, {3, 0, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name    string
+		resolve WeightResolver
+		want    []Edge
+	}{
+		{"base", PreferBase, []Edge{{0, 1, 4}, {0, 1, 9}, {1, 2, 4}, {2, 3, 1}, {3, 0, 1}}},
+		{"overlay", PreferOverlay, []Edge{{0, 1, 2}, {0, 1, 9}, {1, 2, 6}, {2, 3, 1}, {3, 0, 1}}},
+		{"min", MinWeight, []Edge{{0, 1, 2}, {0, 1, 9}, {1, 2, 4}, {2, 3, 1}, {3, 0, 1}}},
+		{"sum", AddWeights, []Edge{{0, 1, 6}, {0, 1, 9}, {1, 2, 10}, {2, 3, 1}, {3, 0, 1}}},
+	} {
+		g, err := Merge(base, overlay, tc.resolve)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g.NodeCount() != 4 || !reflect.DeepEqual(g.Edges(), tc.want) {
+			t.Fatalf("%s: %d nodes, edges %v, want %v", tc.name, g.NodeCount(), g.Edges(), tc.want)
+		}
+	}
+	if w, _ := base.EdgeWeight(0, 1); w != 4 || base.EdgeCount() != 3 {
+		t.Fatalf("base modified")
+	}
+	if _, err := Merge(base, overlay, nil); err == nil {
+		t.Fatalf("nil resolver accepted")
+	}
+}
@@ -0,0 +1,174 @@
+package sssp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/your-org/optimized-sssp-go/internal/decompress"
+)
+
+// METIS adjacency format: a header "n m [fmt [ncon]]" followed by one line per
+// vertex (1-based) listing its neighbours. m counts undirected edges, so every
+// edge appears in both endpoint lines. fmt digits are <sizes><vertex weights>
+// <edge weights>; only edge weights are kept, vertex sizes/weights are skipped.
+// Lines starting with '%' are comments; a blank line is an isolated vertex.
+
+// LoadMETIS reads a METIS graph. Each listed neighbour becomes a directed edge,
+// so the result holds 2*m edges. Unweighted graphs get weight 1.
+func LoadMETIS(r io.Reader) (*Graph, error) {
+	r, err := decompress.Reader(r)
+	if err != nil {
+		return nil, err
+	}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), math.MaxInt32) // hub vertices produce very long lines
+	var (
+		n, m                      uint64
+		hasSize, hasVWgt, hasEWgt bool
+		ncon                      int
+		header                    bool
+		vertex                    uint64
+		edges                     []Edge
+	)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(text, "%") {
+			continue
+		}
+		f := strings.Fields(text)
+		if !header {
+			if len(f) == 0 {
+				continue
+			}
+			if len(f) < 2 || len(f) > 4 {
+				return nil, fmt.Errorf("sssp: metis line %d: expected 'n m [fmt [ncon]]'", line)
+			}
+			var err1, err2 error
+			n, err1 = strconv.ParseUint(f[0], 10, 32)
+			m, err2 = strconv.ParseUint(f[1], 10, 64)
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("sssp: metis line %d: bad header", line)
+			}
+			if len(f) >= 3 {
+				code := f[2]
+				if len(code) > 3 || strings.Trim(code, "01") != "" {
+					return nil, fmt.Errorf("sssp: metis line %d: bad fmt %q", line, f[2])
+				}
+				code = strings.Repeat("0", 3-len(code)) + code
+				hasSize, hasVWgt, hasEWgt = code[0] == '1', code[1] == '1', code[2] == '1'
+			}
+			if hasVWgt {
+				ncon = 1
+			}
+			if len(f) == 4 {
+				c, err := strconv.Atoi(f[3])
+				if err != nil || c < 1 {
+					return nil, fmt.Errorf("sssp: metis line %d: bad ncon", line)
+				}
+				ncon = c
+			}
+			if m > math.MaxUint64/2 {
+				return nil, fmt.Errorf("sssp: metis line %d: edge count %d out of range", line, m)
+			}
+			header = true
+			edges = make([]Edge, 0, min(2*m, maxPrealloc))
+			continue
+		}
+		if vertex == n {
+			if len(f) == 0 {
+				continue // trailing blank lines
+			}
+			return nil, fmt.Errorf("sssp: metis line %d: more vertex lines than n=%d", line, n)
+		}
+		skip := ncon
+		if hasSize {
+			skip++
+		}
+		if len(f) < skip {
+			return nil, fmt.Errorf("sssp: metis line %d: missing vertex size/weights", line)
+		}
+		f = f[skip:]
+		step := 1
+		if hasEWgt {
+			step = 2
+		}
+		if len(f)%step != 0 {
+			return nil, fmt.Errorf("sssp: metis line %d: neighbour without weight", line)
+		}
+		for i := 0; i < len(f); i += step {
+			v, err := strconv.ParseUint(f[i], 10, 32)
+			if err != nil || v < 1 || v > n {
+				return nil, fmt.Errorf("sssp: metis line %d: neighbour %q out of range 1..%d", line, f[i], n)
+			}
+			w := 1.0
+			if hasEWgt {
+				if w, err = strconv.ParseFloat(f[i+1], 32); err != nil {
+					return nil, fmt.Errorf("sssp: metis line %d: bad edge weight %q", line, f[i+1])
+				}
+			}
+			if uint64(len(edges)) == 2*m {
+				return nil, fmt.Errorf("sssp: metis line %d: more adjacency entries than the %d the header declares", line, 2*m)
+			}
+			edges = append(edges, Edge{From: uint32(vertex), To: uint32(v - 1), Weight: float32(w)})
+		}
+		vertex++
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if !header {
+		return nil, fmt.Errorf("sssp: metis: missing header")
+	}
+	if vertex != n {
+		return nil, fmt.Errorf("sssp: metis: header declares %d vertices, found %d lines", n, vertex)
+	}
+	if uint64(len(edges)) != 2*m {
+		return nil, fmt.Errorf("sssp: metis: header declares %d edges (%d adjacency entries), found %d", m, 2*m, len(edges))
+	}
+	return FromEdges(uint32(n), edges)
+}
+
+// WriteMETIS writes g in METIS format. METIS graphs are undirected, so g must
+// be symmetric (u->v implies v->u with the same weight) and loop-free. Edge
+// weights are written unless they are all 1; METIS tools expect integers.
+func (g *Graph) WriteMETIS(w io.Writer) error {
+	type arc struct{ u, v uint32 }
+	seen := make(map[arc]float32, g.EdgeCount())
+	weighted := false
+	for _, e := range g.Edges() {
+		if e.From == e.To {
+			return fmt.Errorf("sssp: metis: self-loop on node %d", e.From)
+		}
+		seen[arc{e.From, e.To}] = e.Weight
+		weighted = weighted || e.Weight != 1
+	}
+	for a, wt := range seen {
+		if back, ok := seen[arc{a.v, a.u}]; !ok || back != wt {
+			return fmt.Errorf("sssp: metis: graph is not symmetric at edge %d->%d", a.u, a.v)
+		}
+	}
+	bw := bufio.NewWriter(w)
+	if weighted {
+		fmt.Fprintf(bw, "%d %d 1\n", g.NodeCount(), g.EdgeCount()/2)
+	} else {
+		fmt.Fprintf(bw, "%d %d\n", g.NodeCount(), g.EdgeCount()/2)
+	}
+	for u := uint32(0); u < g.NodeCount(); u++ {
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			if e > g.offsets[u] {
+				bw.WriteByte(' ')
+			}
+			bw.WriteString(strconv.FormatUint(uint64(g.targets[e])+1, 10))
+			if weighted {
+				bw.WriteByte(' ')
+				bw.WriteString(strconv.FormatFloat(float64(g.weights[e]), 'g', -1, 32))
+			}
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,60 @@
+package sssp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLoadMETISWeightedWithIsolatedVertex(t *testing.T) {
+	// Triangle 1-2-3 plus isolated vertex 4; vertex weights (the 5s) are skipped.
+	src := "% sample\n4 3 011\n5 2 1 3 4\n5 1 1 3 2\n5 1 4 2 2\n5\n"
+	g, err := LoadMETIS(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if g.NodeCount() != 4 || g.EdgeCount() != 6 {
+		t.Fatalf("got %d nodes %d edges", g.NodeCount(), g.EdgeCount())
+	}
+	res, err := g.Run(0, ModeBaseline)
+	if err != nil || res.Dist[2] != 3 || res.Pred[3] != -1 {
+		t.Fatalf("run: %v dist %v pred %v", err, res.Dist, res.Pred)
+	}
+	var buf bytes.Buffer
+	if err := g.WriteMETIS(&buf); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	again, err := LoadMETIS(&buf)
+	if err != nil || again.EdgeCount() != 6 {
+		t.Fatalf("reload: %v", err)
+	}
+}
+
+func TestLoadMETISBlankLineIsIsolatedVertex(t *testing.T) {
+	g, err := LoadMETIS(strings.NewReader("3 1\n\n3\n2\n"))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if off, _, _ := g.CSR(); off[1] != 0 || g.EdgeCount() != 2 {
+		t.Fatalf("vertex 1 should be isolated: %v", off)
+	}
+}
+
+func TestWriteMETISRejectsDirected(t *testing.T) {
+	g, _ := FromEdges(2, []Edge{{0, 1, 1}})
+	if err := g.WriteMETIS(&bytes.Buffer{}); err == nil {
+		t.Fatalf("expected symmetry error")
+	}
+}
+
+func TestLoadMETISRejectsBadCounts(t *testing.T) {
+	for _, src := range []string{
+		"2 99999999999999\n2\n1\n",
+		"2 18446744073709551615\n2\n1\n",
+		"3 1\n2 3\n1\n1\n",
+	} {
+		if _, err := LoadMETIS(strings.NewReader(src)); err == nil {
+			t.Errorf("%q accepted", src)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package sssp
+
+import "os"
+
+// mapFile falls back to reading the whole file where mmap is unavailable
+// (Windows, js/wasm, wasip1).
+func mapFile(path string) ([]byte, func() error, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return nil }, nil
+}
+
+// createMapped hands out an in-memory buffer that done writes to path.
+func createMapped(path string, size int64) (data []byte, done func() error, err error) {
+	data = make([]byte, size)
+	return data, func() error { return os.WriteFile(path, data, 0o644) }, nil
+}
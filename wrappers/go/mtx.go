@@ -0,0 +1,141 @@
+package sssp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/your-org/optimized-sssp-go/internal/decompress"
+)
+
+// Matrix Market coordinate format: entry (i, j, v) is the edge i->j with
+// weight v (1-based). Fields real/integer/pattern are accepted (pattern => 1);
+// symmetric matrices store one triangle and are mirrored, skew-symmetric ones
+// are mirrored with the value negated.
+//
+//	%%MatrixMarket matrix coordinate real general
+//	% comments
+//	rows cols nnz
+//	i j v
+
+// LoadMatrixMarket reads a .mtx file as a directed graph with
+// max(rows, cols) nodes.
+func LoadMatrixMarket(r io.Reader) (*Graph, error) {
+	r, err := decompress.Reader(r)
+	if err != nil {
+		return nil, err
+	}
+	sc := bufio.NewScanner(r)
+	if !sc.Scan() {
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("sssp: mtx: empty input")
+	}
+	banner := strings.Fields(strings.ToLower(sc.Text()))
+	if len(banner) != 5 || banner[0] != "%%matrixmarket" || banner[1] != "matrix" {
+		return nil, fmt.Errorf("sssp: mtx: missing %%%%MatrixMarket matrix banner")
+	}
+	if banner[2] != "coordinate" {
+		return nil, fmt.Errorf("sssp: mtx: only coordinate format is supported, got %q", banner[2])
+	}
+	field, symmetry := banner[3], banner[4]
+	switch field {
+	case "real", "integer", "pattern":
+	default:
+		return nil, fmt.Errorf("sssp: mtx: unsupported field %q", field)
+	}
+	switch symmetry {
+	case "general", "symmetric", "skew-symmetric":
+	default:
+		return nil, fmt.Errorf("sssp: mtx: unsupported symmetry %q", symmetry)
+	}
+	var (
+		n, nnz uint64
+		sized  bool
+		read   uint64
+		edges  []Edge
+	)
+	for line := 2; sc.Scan(); line++ {
+		f := strings.Fields(sc.Text())
+		if len(f) == 0 || strings.HasPrefix(f[0], "%") {
+			continue
+		}
+		if !sized {
+			if len(f) != 3 {
+				return nil, fmt.Errorf("sssp: mtx line %d: expected 'rows cols nnz'", line)
+			}
+			rows, err1 := strconv.ParseUint(f[0], 10, 32)
+			cols, err2 := strconv.ParseUint(f[1], 10, 32)
+			var err3 error
+			nnz, err3 = strconv.ParseUint(f[2], 10, 64)
+			if err1 != nil || err2 != nil || err3 != nil {
+				return nil, fmt.Errorf("sssp: mtx line %d: bad size line", line)
+			}
+			n = max(rows, cols)
+			sized = true
+			mirror := uint64(1)
+			if symmetry != "general" {
+				mirror = 2
+			}
+			edges = make([]Edge, 0, min(nnz, maxPrealloc)*mirror)
+			continue
+		}
+		if read == nnz {
+			return nil, fmt.Errorf("sssp: mtx line %d: more entries than the %d the size line declares", line, nnz)
+		}
+		want := 3
+		if field == "pattern" {
+			want = 2
+		}
+		if len(f) != want {
+			return nil, fmt.Errorf("sssp: mtx line %d: expected %d columns", line, want)
+		}
+		i, err1 := strconv.ParseUint(f[0], 10, 32)
+		j, err2 := strconv.ParseUint(f[1], 10, 32)
+		if err1 != nil || err2 != nil || i < 1 || j < 1 || i > n || j > n {
+			return nil, fmt.Errorf("sssp: mtx line %d: index out of range 1..%d", line, n)
+		}
+		w := 1.0
+		if field != "pattern" {
+			var err error
+			if w, err = strconv.ParseFloat(f[2], 32); err != nil {
+				return nil, fmt.Errorf("sssp: mtx line %d: bad value %q", line, f[2])
+			}
+		}
+		edges = append(edges, Edge{From: uint32(i - 1), To: uint32(j - 1), Weight: float32(w)})
+		if i != j {
+			switch symmetry {
+			case "symmetric":
+				edges = append(edges, Edge{From: uint32(j - 1), To: uint32(i - 1), Weight: float32(w)})
+			case "skew-symmetric":
+				edges = append(edges, Edge{From: uint32(j - 1), To: uint32(i - 1), Weight: float32(-w)})
+			}
+		}
+		read++
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if !sized {
+		return nil, fmt.Errorf("sssp: mtx: missing size line")
+	}
+	if read != nnz {
+		return nil, fmt.Errorf("sssp: mtx: size line declares %d entries, found %d", nnz, read)
+	}
+	return FromEdges(uint32(n), edges)
+}
+
+// WriteMatrixMarket writes g as an n x n "coordinate real general" matrix.
+func (g *Graph) WriteMatrixMarket(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%%%%MatrixMarket matrix coordinate real general\n%d %d %d\n", g.NodeCount(), g.NodeCount(), g.EdgeCount())
+	for u := uint32(0); u < g.NodeCount(); u++ {
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			fmt.Fprintf(bw, "%d %d %s\n", u+1, g.targets[e]+1, strconv.FormatFloat(float64(g.weights[e]), 'g', -1, 32))
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,52 @@
+package sssp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLoadMatrixMarketSymmetricMirrors(t *testing.T) {
+	src := "%%MatrixMarket matrix coordinate real symmetric\n% lower triangle\n3 3 3\n2 1 1.5\n3 2 2\n3 3 7\n"
+	g, err := LoadMatrixMarket(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	// Two off-diagonal entries mirrored plus one diagonal entry.
+	if g.NodeCount() != 3 || g.EdgeCount() != 5 {
+		t.Fatalf("got %d nodes %d edges", g.NodeCount(), g.EdgeCount())
+	}
+	res, err := g.Run(0, ModeBaseline)
+	if err != nil || res.Dist[2] != 3.5 {
+		t.Fatalf("run: %v dist %v", err, res.Dist)
+	}
+}
+
+func TestMatrixMarketRoundTripAndPattern(t *testing.T) {
+	g, err := LoadMatrixMarket(strings.NewReader("%%MatrixMarket matrix coordinate pattern general\n2 3 2\n1 3\n2 1\n"))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := g.WriteMatrixMarket(&buf); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	again, err := LoadMatrixMarket(&buf)
+	if err != nil || again.NodeCount() != 3 || again.EdgeCount() != 2 || again.Edges()[0] != (Edge{0, 2, 1}) {
+		t.Fatalf("round trip: %v %v", err, again)
+	}
+	if _, err := LoadMatrixMarket(strings.NewReader("%%MatrixMarket matrix array real general\n2 2\n")); err == nil {
+		t.Fatalf("expected array format to be rejected")
+	}
+}
+
+func TestLoadMatrixMarketRejectsBadCounts(t *testing.T) {
+	for _, src := range []string{
+		"%%MatrixMarket matrix coordinate real symmetric\n2 2 18446744073709551615\n1 2 1\n",
+		"%%MatrixMarket matrix coordinate real general\n2 2 1\n1 2 1\n2 1 1\n",
+	} {
+		if _, err := LoadMatrixMarket(strings.NewReader(src)); err == nil {
+			t.Errorf("%q accepted", src)
+		}
+	}
+}
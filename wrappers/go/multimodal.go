@@ -0,0 +1,253 @@
+package sssp
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// Layer is the graph of one mode of travel (walking, transit, driving) in a
+// MultiModal network.
+type Layer struct {
+	Name  string
+	Graph *Graph
+}
+
+// LayerNode is node Node of the layer named Layer.
+type LayerNode struct {
+	Layer string
+	Node  uint32
+}
+
+// Transfer links two layers, such as a walk from a street corner to a
+// platform or parking the car. Kind types it ("walk", "park-and-ride",
+// "bike-share") so queries can allow or refuse each kind.
+type Transfer struct {
+	From, To LayerNode
+	Weight   float32
+	Kind     string
+}
+
+// MultiModal combines layers and the transfers between them into one
+// graph, so a trip mixing modes is a single shortest-path query.
+type MultiModal struct {
+	// Graph holds every layer and transfer: node v of layer i is node
+	// first[i]+v. It can be run directly when no per-mode limits apply.
+	Graph     *Graph
+	layers    []Layer
+	index     map[string]int
+	first     []uint32 // first combined node of each layer, then the total
+	transfers []Transfer
+	// transferOf[e] is the transfer behind combined edge e, or -1 for an
+	// edge of a layer.
+	transferOf []int32
+}
+
+// NewMultiModal builds the combined network. Layer names must be distinct
+// and transfers must name existing layers and nodes. The layers' edges are
+// copied, so later changes to them are not seen.
+func NewMultiModal(layers []Layer, transfers []Transfer) (*MultiModal, error) {
+	m := &MultiModal{layers: slices.Clone(layers), index: make(map[string]int), first: make([]uint32, len(layers)+1),
+		transfers: slices.Clone(transfers)}
+	var total uint64
+	for i, l := range layers {
+		if _, dup := m.index[l.Name]; dup {
+			return nil, fmt.Errorf("sssp: layer %q appears twice", l.Name)
+		}
+		m.index[l.Name] = i
+		m.first[i] = uint32(total)
+		if total += uint64(l.Graph.NodeCount()); total > uint64(^uint32(0)) {
+			return nil, fmt.Errorf("sssp: layers hold more than %d nodes", ^uint32(0))
+		}
+	}
+	m.first[len(layers)] = uint32(total)
+	type tagged struct {
+		Edge
+		transfer int32
+	}
+	var edges []tagged
+	for i, l := range layers {
+		for _, e := range l.Graph.Edges() {
+			edges = append(edges, tagged{Edge{m.first[i] + e.From, m.first[i] + e.To, e.Weight}, -1})
+		}
+	}
+	for i, t := range transfers {
+		u, err := m.Node(t.From)
+		if err != nil {
+			return nil, err
+		}
+		v, err := m.Node(t.To)
+		if err != nil {
+			return nil, err
+		}
+		edges = append(edges, tagged{Edge{u, v, t.Weight}, int32(i)})
+	}
+	// FromEdges keeps input order per source node, so sorting by source
+	// first makes the slice index the edge ID.
+	slices.SortStableFunc(edges, func(a, b tagged) int { return cmp.Compare(a.From, b.From) })
+	plain := make([]Edge, len(edges))
+	m.transferOf = make([]int32, len(edges))
+	for i, e := range edges {
+		plain[i], m.transferOf[i] = e.Edge, e.transfer
+	}
+	g, err := FromEdges(uint32(total), plain)
+	if err != nil {
+		return nil, err
+	}
+	m.Graph = g
+	return m, nil
+}
+
+// Node returns the combined node of a layer node.
+func (m *MultiModal) Node(n LayerNode) (uint32, error) {
+	i, ok := m.index[n.Layer]
+	if !ok {
+		return 0, fmt.Errorf("sssp: no layer %q", n.Layer)
+	}
+	if n.Node >= m.layers[i].Graph.NodeCount() {
+		return 0, fmt.Errorf("sssp: node %d out of range for layer %q of %d nodes", n.Node, n.Layer, m.layers[i].Graph.NodeCount())
+	}
+	return m.first[i] + n.Node, nil
+}
+
+// LayerNode returns the layer node behind combined node v.
+func (m *MultiModal) LayerNode(v uint32) LayerNode {
+	i := m.layerOf(v)
+	return LayerNode{Layer: m.layers[i].Name, Node: v - m.first[i]}
+}
+
+func (m *MultiModal) layerOf(v uint32) int {
+	return sort.Search(len(m.layers), func(i int) bool { return m.first[i+1] > v })
+}
+
+// MultiModalOptions constrain a MultiModal query.
+type MultiModalOptions struct {
+	// MaxLegs caps the legs spent in a layer, by name: each transfer into
+	// the layer starts a leg, as does starting in it. {"transit": 1} allows
+	// one transit leg; 0 keeps the route out of the layer.
+	MaxLegs map[string]int
+	// Kinds, if set, lists the transfer kinds the route may use.
+	Kinds map[string]bool
+}
+
+// MultiRoute holds the distances of a MultiModal query.
+type MultiRoute struct {
+	// Dist is the distance to each combined node, Unreachable if none.
+	Dist []float32
+	m    *MultiModal
+	// The search runs over (node, legs used) states: state s of combined
+	// node v is v*states+s.
+	states uint32
+	sdist  []float32
+	spred  []int32
+}
+
+// DistTo returns the distance to a layer node, Unreachable for an unknown
+// or unreached node.
+func (r *MultiRoute) DistTo(n LayerNode) float32 {
+	v, err := r.m.Node(n)
+	if err != nil {
+		return Unreachable
+	}
+	return r.Dist[v]
+}
+
+// Path returns the route to a layer node, or nil if it is unreached.
+func (r *MultiRoute) Path(n LayerNode) []LayerNode {
+	v, err := r.m.Node(n)
+	if err != nil || r.Dist[v] == Unreachable {
+		return nil
+	}
+	best := v * r.states
+	for s := uint32(1); s < r.states; s++ {
+		if r.sdist[v*r.states+s] < r.sdist[best] {
+			best = v*r.states + s
+		}
+	}
+	var path []LayerNode
+	for _, x := range pathFromPred(r.spred, best) {
+		path = append(path, r.m.LayerNode(x/r.states))
+	}
+	return path
+}
+
+// Route runs a query from a layer node under opts. Without options it is
+// one run of Graph in mode; with them it searches (node, legs used) states
+// on the pure-Go engine, ModeBaseline or ModeStoc, which costs one copy of
+// the network per combination of leg counts.
+func (m *MultiModal) Route(from LayerNode, mode int, opts MultiModalOptions) (*MultiRoute, error) {
+	source, err := m.Node(from)
+	if err != nil {
+		return nil, err
+	}
+	// Leg counters of the limited layers, in mixed radix.
+	limit := make([]int, len(m.layers))
+	stride := make([]uint32, len(m.layers))
+	states := uint64(1)
+	for i := range limit {
+		limit[i] = -1
+	}
+	for name, k := range opts.MaxLegs {
+		i, ok := m.index[name]
+		if !ok {
+			return nil, fmt.Errorf("sssp: no layer %q", name)
+		}
+		if k < 0 {
+			return nil, fmt.Errorf("sssp: negative leg limit for layer %q", name)
+		}
+		limit[i], stride[i] = k, uint32(states)
+		states *= uint64(k + 1)
+	}
+	n := uint64(m.Graph.NodeCount())
+	if n*states > uint64(^uint32(0)) {
+		return nil, fmt.Errorf("sssp: %d nodes times %d leg states overflow 32-bit node IDs", n, states)
+	}
+	r := &MultiRoute{m: m, states: uint32(states)}
+	if len(opts.MaxLegs) == 0 && opts.Kinds == nil {
+		res, err := m.Graph.Run(source, mode)
+		if err != nil {
+			return nil, err
+		}
+		r.Dist, r.sdist, r.spred = res.Dist, res.Dist, res.Pred
+		return r, nil
+	}
+	start := uint32(0)
+	if i := m.layerOf(source); limit[i] >= 0 {
+		if limit[i] == 0 {
+			return nil, fmt.Errorf("sssp: source is in layer %q, which allows no legs", m.layers[i].Name)
+		}
+		start = stride[i]
+	}
+	g, S := m.Graph, uint32(states)
+	src := FuncSource{Nodes: uint32(n * states), Neighbors: func(x uint32, yield func(uint32, float32) bool) {
+		u, s := x/S, x%S
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			v, s2 := g.targets[e], s
+			if t := m.transferOf[e]; t >= 0 {
+				if opts.Kinds != nil && !opts.Kinds[m.transfers[t].Kind] {
+					continue
+				}
+				if i := m.layerOf(v); limit[i] >= 0 {
+					if int(s/stride[i])%(limit[i]+1) == limit[i] {
+						continue
+					}
+					s2 += stride[i]
+				}
+			}
+			if !yield(v*S+s2, g.weights[e]) {
+				return
+			}
+		}
+	}}
+	res, err := RunSource(src, source*S+start, mode)
+	if err != nil {
+		return nil, err
+	}
+	r.sdist, r.spred = res.Dist, res.Pred
+	r.Dist = make([]float32, n)
+	for v := range r.Dist {
+		r.Dist[v] = slices.Min(r.sdist[uint32(v)*S : uint32(v+1)*S])
+	}
+	return r, nil
+}
@@ -0,0 +1,81 @@
+package sssp
+
+import (
+	"reflect"
+	"testing"
+)
+
+// multiModalNet: a slow walk w0->w1->w2 and two transit lines t0->t1 and
+// t2->t3, with boarding at w0 and w1, alighting at w1 and w2 and a
+// line change t1->t2.
+func multiModalNet(t *testing.T) *MultiModal {
+	t.Helper()
+	walk, _ := FromEdges(3, []Edge{{0, 1, 100}, {1, 2, 100}})
+	transit, _ := FromEdges(4, []Edge{{0, 1, 5}, {2, 3, 5}})
+	m, err := NewMultiModal([]Layer{{"walk", walk}, {"transit", transit}}, []Transfer{
+		{LayerNode{"walk", 0}, LayerNode{"transit", 0}, 2, "board"},
+		{LayerNode{"walk", 1}, LayerNode{"transit", 2}, 2, "board"},
+		{LayerNode{"transit", 1}, LayerNode{"walk", 1}, 0, "alight"},
+		{LayerNode{"transit", 3}, LayerNode{"walk", 2}, 0, "alight"},
+		{LayerNode{"transit", 1}, LayerNode{"transit", 2}, 1, "change"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestMultiModalRoute(t *testing.T) {
+	m := multiModalNet(t)
+	dst := LayerNode{"walk", 2}
+	r, err := m.Route(LayerNode{"walk", 0}, ModeBaseline, MultiModalOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := r.DistTo(dst); d != 13 {
+		t.Fatalf("unconstrained %v", d)
+	}
+	want := []LayerNode{{"walk", 0}, {"transit", 0}, {"transit", 1}, {"transit", 2}, {"transit", 3}, {"walk", 2}}
+	if p := r.Path(dst); !reflect.DeepEqual(p, want) {
+		t.Fatalf("path %v", p)
+	}
+
+	r, err = m.Route(LayerNode{"walk", 0}, ModeBaseline, MultiModalOptions{MaxLegs: map[string]int{"transit": 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := r.DistTo(dst); d != 107 {
+		t.Fatalf("one transit leg %v", d)
+	}
+	if p := r.Path(dst); len(p) == 0 || p[len(p)-1] != dst {
+		t.Fatalf("path %v", p)
+	}
+
+	r, err = m.Route(LayerNode{"walk", 0}, ModeStoc, MultiModalOptions{Kinds: map[string]bool{"alight": true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := r.DistTo(dst); d != 200 {
+		t.Fatalf("no boarding %v", d)
+	}
+}
+
+func TestMultiModalErrors(t *testing.T) {
+	m := multiModalNet(t)
+	if _, err := m.Route(LayerNode{"bike", 0}, ModeBaseline, MultiModalOptions{}); err == nil {
+		t.Fatalf("unknown layer accepted")
+	}
+	if _, err := m.Route(LayerNode{"transit", 0}, ModeBaseline, MultiModalOptions{MaxLegs: map[string]int{"transit": 0}}); err == nil {
+		t.Fatalf("start in a forbidden layer accepted")
+	}
+	g, _ := FromEdges(1, nil)
+	if _, err := NewMultiModal([]Layer{{"a", g}, {"a", g}}, nil); err == nil {
+		t.Fatalf("duplicate layer accepted")
+	}
+	if _, err := NewMultiModal([]Layer{{"a", g}}, []Transfer{{LayerNode{"a", 0}, LayerNode{"a", 3}, 1, ""}}); err == nil {
+		t.Fatalf("transfer to a missing node accepted")
+	}
+	if got := m.LayerNode(5); got != (LayerNode{"transit", 2}) {
+		t.Fatalf("LayerNode(5) = %v", got)
+	}
+}
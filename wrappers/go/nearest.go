@@ -0,0 +1,84 @@
+package sssp
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Facility is one member of a node set found by NearestK, with its
+// distance and the path to it.
+type Facility struct {
+	Node uint32
+	Dist float32
+	Path []uint32
+}
+
+// NearestK returns the k members of set closest to from (by distance from
+// from to them), nearest first, with their paths. The search stops as soon
+// as the k-th member is settled, so finding the 5 nearest depots costs a
+// small ball around from rather than a full run. Fewer than k are returned
+// if fewer are reachable. Weights must be non-negative.
+func (g *Graph) NearestK(set []uint32, from uint32, k int) ([]Facility, error) {
+	return nearestK(g.offsets, g.targets, g.weights, nil, set, from, k)
+}
+
+// NearestKTo is NearestK in the other direction: the k members of set
+// closest to reaching to, such as the depots that can serve a customer
+// soonest. It searches backward from to on ReverseView, which is built on
+// first use and kept. Paths run from the member to to.
+func (g *Graph) NearestKTo(set []uint32, to uint32, k int) ([]Facility, error) {
+	r := g.ReverseView()
+	found, err := nearestK(r.offsets, r.targets, g.weights, r.edge, set, to, k)
+	if err != nil {
+		return nil, err
+	}
+	for i := range found {
+		slices.Reverse(found[i].Path)
+	}
+	return found, nil
+}
+
+// nearestK runs Dijkstra's algorithm from source until k members of set
+// are settled. Edge e weighs wts[ids[e]], or wts[e] when ids is nil.
+func nearestK(off, tgt []uint32, wts []float32, ids []uint32, set []uint32, source uint32, k int) ([]Facility, error) {
+	n := uint32(len(off) - 1)
+	if source >= n {
+		return nil, fmt.Errorf("sssp: source %d out of range for %d nodes", source, n)
+	}
+	member := make([]bool, n)
+	for _, v := range set {
+		if v >= n {
+			return nil, fmt.Errorf("sssp: set member %d out of range for %d nodes", v, n)
+		}
+		member[v] = true
+	}
+	dist, pred := make([]float32, n), make([]int32, n)
+	resetDistPred(source, dist, pred)
+	var found []Facility
+	q := minHeap[float32]{}
+	q.push(heapItem[float32]{node: source})
+	for len(found) < k {
+		it, ok := q.pop()
+		if !ok {
+			break
+		}
+		u := it.node
+		if it.dist > dist[u] {
+			continue
+		}
+		if member[u] {
+			found = append(found, Facility{Node: u, Dist: dist[u], Path: pathFromPred(pred, u)})
+		}
+		for e := off[u]; e < off[u+1]; e++ {
+			v, w := tgt[e], wts[e]
+			if ids != nil {
+				w = wts[ids[e]]
+			}
+			if nd := dist[u] + w; nd < dist[v] {
+				dist[v], pred[v] = nd, int32(u)
+				q.push(heapItem[float32]{node: v, dist: nd})
+			}
+		}
+	}
+	return found, nil
+}
@@ -0,0 +1,33 @@
+package sssp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNearestK(t *testing.T) {
+	// A line 0 - 1 - 2 - 3 - 4 with one-way shortcut 4 -> 0.
+	g, _ := FromEdges(5, []Edge{{0, 1, 1}, {1, 0, 1}, {1, 2, 1}, {2, 1, 1}, {2, 3, 1}, {3, 2, 1}, {3, 4, 1}, {4, 3, 1}, {4, 0, 0.5}})
+	got, err := g.NearestK([]uint32{4, 0, 3}, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Facility{{Node: 0, Dist: 1, Path: []uint32{1, 0}}, {Node: 3, Dist: 2, Path: []uint32{1, 2, 3}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("NearestK %+v", got)
+	}
+	// Backward: 4 reaches 0 over the shortcut.
+	got, err = g.NearestKTo([]uint32{4, 2}, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Facility{{Node: 4, Dist: 0.5, Path: []uint32{4, 0}}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("NearestKTo %+v", got)
+	}
+	if got, _ := g.NearestK([]uint32{2}, 0, 5); len(got) != 1 {
+		t.Fatalf("asked for more than the set holds: %+v", got)
+	}
+	if _, err := g.NearestK([]uint32{9}, 0, 1); err == nil {
+		t.Fatalf("out-of-range member accepted")
+	}
+}
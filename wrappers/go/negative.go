@@ -0,0 +1,82 @@
+package sssp
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// GenerateNegativeWeights reweights base, which must have non-negative
+// weights, so that about fraction of its edges become negative while no
+// negative cycle appears. Each node v gets a potential p[v] and each edge
+// u->v becomes w + p[u] - p[v]; every cycle keeps its original, non-negative
+// length. Distances on the result relate to those on base by
+//
+//	dist'(s, v) = dist(s, v) + p[s] - p[v]
+//
+// so Dijkstra on base checks negative-weight solvers run on the result, up to
+// float32 rounding. Shifted weights are rounded up, which keeps the cycle
+// guarantee exact. Potentials are a random direction scaled just enough to
+// turn the requested share of edges negative. Only edges running "uphill"
+// along that direction can turn, about half of them, so larger fractions are
+// rejected.
+func GenerateNegativeWeights(base *Graph, fraction float64, seed int64) (*Graph, []float32, error) {
+	if !(fraction >= 0 && fraction <= 1) {
+		return nil, nil, fmt.Errorf("sssp: generate: negative fraction %v outside [0, 1]", fraction)
+	}
+	n := base.NodeCount()
+	for e, w := range base.weights {
+		if !(w >= 0) {
+			return nil, nil, fmt.Errorf("sssp: generate: base edge %d has weight %v; need non-negative weights", e, w)
+		}
+	}
+	r := rand.New(rand.NewSource(seed))
+	z := make([]float64, n)
+	for v := range z {
+		z[v] = r.Float64()
+	}
+	// Edge u->v turns negative once the scale s exceeds w / (z[v] - z[u]).
+	var thresholds []float64
+	for u := uint32(0); u < n; u++ {
+		for e := base.offsets[u]; e < base.offsets[u+1]; e++ {
+			if rise := z[base.targets[e]] - z[u]; rise > 0 {
+				thresholds = append(thresholds, float64(base.weights[e])/rise)
+			}
+		}
+	}
+	want := int(fraction*float64(len(base.targets)) + 0.5)
+	if want > len(thresholds) {
+		return nil, nil, fmt.Errorf("sssp: generate: at most %d of %d edges can turn negative, asked for %d", len(thresholds), len(base.targets), want)
+	}
+	sort.Float64s(thresholds)
+	scale := 0.0
+	if want > 0 {
+		// Midway to the next threshold keeps float32 rounding from flipping
+		// an edge on the boundary.
+		scale = thresholds[want-1] * 1.001
+		if want < len(thresholds) {
+			scale = (thresholds[want-1] + thresholds[want]) / 2
+		}
+	}
+	pot := make([]float32, n)
+	for v := range pot {
+		pot[v] = float32(scale * z[v])
+	}
+	weights := make([]float32, len(base.weights))
+	for u := uint32(0); u < n; u++ {
+		for e := base.offsets[u]; e < base.offsets[u+1]; e++ {
+			// Exact in float64, then rounded up: no cycle can end up below
+			// its original length, even by a rounding error.
+			x := float64(base.weights[e]) + float64(pot[u]) - float64(pot[base.targets[e]])
+			w := float32(x)
+			if float64(w) < x {
+				w = math.Nextafter32(w, inf32)
+			}
+			weights[e] = w
+		}
+	}
+	offsets := append([]uint32(nil), base.offsets...)
+	targets := append([]uint32(nil), base.targets...)
+	return &Graph{offsets: offsets, targets: targets, weights: weights, coords: base.coords}, pot, nil
+}
@@ -0,0 +1,63 @@
+package sssp
+
+import (
+	"math"
+	"testing"
+)
+
+// bellmanFord is a reference solver for negative weights; ok is false when
+// a negative cycle is reachable.
+func bellmanFord(g *Graph, s uint32) (dist []float64, ok bool) {
+	dist = make([]float64, g.NodeCount())
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+	dist[s] = 0
+	edges := g.Edges()
+	for round := 0; round <= len(dist); round++ {
+		changed := false
+		for _, e := range edges {
+			if d := dist[e.From] + float64(e.Weight); d < dist[e.To] {
+				dist[e.To], changed = d, true
+			}
+		}
+		if !changed {
+			return dist, true
+		}
+	}
+	return dist, false
+}
+
+func TestGenerateNegativeWeights(t *testing.T) {
+	base, _ := GenerateRandomGraph(800, 4, 2, RandomGraphOptions{})
+	g, pot, err := GenerateNegativeWeights(base, 0.3, 5)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	neg := 0
+	for _, e := range g.Edges() {
+		if e.Weight < 0 {
+			neg++
+		}
+	}
+	if want := 0.3 * float64(g.EdgeCount()); math.Abs(float64(neg)-want) > 1 {
+		t.Fatalf("%d negative edges, want %v", neg, want)
+	}
+	got, ok := bellmanFord(g, 0)
+	if !ok {
+		t.Fatalf("negative cycle")
+	}
+	ref, _ := base.Run(0, ModeBaseline)
+	for v, d := range ref.Dist {
+		if math.IsInf(float64(d), 1) {
+			continue
+		}
+		want := float64(d) + float64(pot[0]) - float64(pot[v])
+		if math.Abs(got[v]-want) > 1e-3*(1+math.Abs(want)) {
+			t.Fatalf("node %d: %v, want %v", v, got[v], want)
+		}
+	}
+	if _, _, err := GenerateNegativeWeights(base, 0.9, 5); err == nil {
+		t.Fatalf("expected an unreachable fraction to fail")
+	}
+}
@@ -0,0 +1,111 @@
+package sssp
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// numaNode is one NUMA node of the machine: its kernel ID and CPUs.
+type numaNode struct {
+	id   int
+	cpus []int
+}
+
+// placeNUMA spreads the run over nodes, at most one per worker: it splits
+// the graph nodes into contiguous ranges of about equal nodes plus edges,
+// asks the kernel to keep each range's CSR slices and state on its node,
+// and assigns workers to nodes round the ring. Fewer than two nodes leave
+// the run as it is.
+func (p *parallelRun) placeNUMA(nodes []numaNode) {
+	nw := len(p.workers)
+	if len(nodes) > nw {
+		nodes = nodes[:nw]
+	}
+	if len(nodes) < 2 {
+		return
+	}
+	K := len(nodes)
+	n := uint32(len(p.state))
+	total := uint64(n) + uint64(len(p.tgt))
+	p.bounds = make([]uint32, K+1)
+	p.bounds[K] = n
+	for k := 1; k < K; k++ {
+		target := total * uint64(k) / uint64(K)
+		p.bounds[k] = uint32(sort.Search(int(n), func(u int) bool { return uint64(u)+uint64(p.off[u]) >= target }))
+	}
+	p.numaIDs = make([]int, K)
+	p.members = make([][]int, K)
+	for k, nd := range nodes {
+		p.numaIDs[k] = nd.id
+		lo, hi := p.bounds[k], p.bounds[k+1]
+		bindSlice(p.state[lo:hi], nd.id)
+		bindSlice(p.seen[lo:hi], nd.id)
+		bindSlice(p.done[lo:hi], nd.id)
+		bindSlice(p.off[lo:hi+1], nd.id)
+		bindSlice(p.tgt[p.off[lo]:p.off[hi]], nd.id)
+		bindSlice(p.wts[p.off[lo]:p.off[hi]], nd.id)
+	}
+	p.numaOf, p.cpus = make([]int, nw), make([][]int, nw)
+	for i := range p.workers {
+		p.workers[i].buckets = make([][][]uint32, K)
+		k := i * K / nw
+		p.numaOf[i], p.cpus[i] = k, nodes[k].cpus
+		p.members[k] = append(p.members[k], i)
+	}
+	// Steal from the same node first, then from the others.
+	for i, order := range p.victims {
+		var near, far []int
+		for _, v := range order {
+			if p.numaOf[v] == p.numaOf[i] {
+				near = append(near, v)
+			} else {
+				far = append(far, v)
+			}
+		}
+		p.victims[i] = append(near, far...)
+	}
+}
+
+// home returns the NUMA node index holding graph node u.
+func (p *parallelRun) home(u uint32) int {
+	if p.bounds == nil {
+		return 0
+	}
+	return sort.Search(len(p.bounds)-1, func(k int) bool { return p.bounds[k+1] > u })
+}
+
+// bindSlice asks the kernel to keep s on NUMA node id, moving pages already
+// placed elsewhere.
+func bindSlice[T any](s []T, id int) {
+	if len(s) > 0 {
+		bindMemory(unsafe.Pointer(&s[0]), uintptr(len(s))*unsafe.Sizeof(s[0]), id)
+	}
+}
+
+// parseCPUList parses a kernel CPU or node list such as "0-3,8,10-11".
+func parseCPUList(s string) ([]int, error) {
+	var list []int
+	for _, part := range strings.Split(strings.TrimSpace(s), ",") {
+		if part == "" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(part, "-")
+		a, err := strconv.Atoi(lo)
+		if err != nil {
+			return nil, fmt.Errorf("sssp: bad CPU list %q", s)
+		}
+		b := a
+		if isRange {
+			if b, err = strconv.Atoi(hi); err != nil || b < a {
+				return nil, fmt.Errorf("sssp: bad CPU list %q", s)
+			}
+		}
+		for c := a; c <= b; c++ {
+			list = append(list, c)
+		}
+	}
+	return list, nil
+}
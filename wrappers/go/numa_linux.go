@@ -0,0 +1,73 @@
+package sssp
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+// Memory policy constants from <linux/mempolicy.h>.
+const (
+	mpolPreferred = 1
+	mpolMFMove    = 1 << 1
+)
+
+// numaNodes lists the online NUMA nodes that have CPUs, or nil if the
+// kernel does not expose them.
+func numaNodes() []numaNode {
+	const dir = "/sys/devices/system/node/"
+	online, err := os.ReadFile(dir + "online")
+	if err != nil {
+		return nil
+	}
+	ids, err := parseCPUList(string(online))
+	if err != nil {
+		return nil
+	}
+	var nodes []numaNode
+	for _, id := range ids {
+		list, err := os.ReadFile(dir + "node" + strconv.Itoa(id) + "/cpulist")
+		if err != nil {
+			return nil
+		}
+		cpus, err := parseCPUList(string(list))
+		if err != nil {
+			return nil
+		}
+		if len(cpus) > 0 {
+			nodes = append(nodes, numaNode{id: id, cpus: cpus})
+		}
+	}
+	return nodes
+}
+
+// bindMemory sets a preferred-node policy on the whole pages of [addr,
+// addr+size) with mbind(2), migrating pages already placed. Failures are
+// ignored: placement only affects speed.
+func bindMemory(addr unsafe.Pointer, size uintptr, id int) {
+	page := uintptr(os.Getpagesize())
+	start := (uintptr(addr) + page - 1) &^ (page - 1)
+	end := (uintptr(addr) + size) &^ (page - 1)
+	var mask [16]uint64
+	// The kernel reads one bit less than the maxnode passed.
+	if end <= start || id < 0 || id >= len(mask)*64-1 {
+		return
+	}
+	mask[id/64] |= 1 << (id % 64)
+	syscall.Syscall6(syscall.SYS_MBIND, start, end-start, mpolPreferred,
+		uintptr(unsafe.Pointer(&mask[0])), uintptr(len(mask)*64), mpolMFMove)
+}
+
+// pinThread restricts the calling thread to cpus with
+// sched_setaffinity(2). Failures, such as CPUs outside the process's
+// cpuset, are ignored.
+func pinThread(cpus []int) {
+	var mask [16]uint64
+	for _, c := range cpus {
+		if c >= 0 && c < len(mask)*64 {
+			mask[c/64] |= 1 << (c % 64)
+		}
+	}
+	syscall.RawSyscall(syscall.SYS_SCHED_SETAFFINITY, 0, unsafe.Sizeof(mask), uintptr(unsafe.Pointer(&mask[0])))
+}
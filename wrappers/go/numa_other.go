@@ -0,0 +1,14 @@
+//go:build !linux
+
+package sssp
+
+import "unsafe"
+
+// Elsewhere NUMA placement is not available and ParallelOptions.NUMA
+// changes nothing.
+
+func numaNodes() []numaNode { return nil }
+
+func bindMemory(addr unsafe.Pointer, size uintptr, id int) {}
+
+func pinThread(cpus []int) {}
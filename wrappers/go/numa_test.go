@@ -0,0 +1,65 @@
+package sssp
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseCPUList(t *testing.T) {
+	got, err := parseCPUList("0-3,8,10-11\n")
+	if err != nil || !slices.Equal(got, []int{0, 1, 2, 3, 8, 10, 11}) {
+		t.Fatalf("got %v, %v", got, err)
+	}
+	if got, err := parseCPUList("\n"); err != nil || len(got) != 0 {
+		t.Fatalf("empty list: %v, %v", got, err)
+	}
+	for _, bad := range []string{"x", "3-1", "1-"} {
+		if _, err := parseCPUList(bad); err == nil {
+			t.Fatalf("%q accepted", bad)
+		}
+	}
+}
+
+func TestPlaceNUMA(t *testing.T) {
+	g := randomGraph(t, 5000, 40000, 2)
+	want, err := g.RunSettled(0, ModeBaseline, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Two fake nodes sharing CPU 0, which every machine has.
+	p := newParallelRun(g, 4, 1)
+	p.placeNUMA([]numaNode{{id: 0, cpus: []int{0}}, {id: 1, cpus: []int{0}}})
+	if len(p.bounds) != 3 || p.bounds[0] != 0 || p.bounds[2] != 5000 || p.bounds[1] == 0 || p.bounds[1] == 5000 {
+		t.Fatalf("bounds %v", p.bounds)
+	}
+	if !slices.Equal(p.members[0], []int{0, 1}) || !slices.Equal(p.members[1], []int{2, 3}) {
+		t.Fatalf("members %v", p.members)
+	}
+	if !slices.Equal(p.victims[2], []int{3, 0, 1}) {
+		t.Fatalf("worker 2 steals from %v, want its node first", p.victims[2])
+	}
+	if p.home(p.bounds[1]-1) != 0 || p.home(p.bounds[1]) != 1 {
+		t.Fatalf("home around bound %d wrong", p.bounds[1])
+	}
+	p.start()
+	p.run(0)
+	p.stop()
+	for v, s := range p.state {
+		if d, _ := unpackState(s); d != want.Dist[v] {
+			t.Fatalf("dist[%d] = %v, want %v", v, d, want.Dist[v])
+		}
+	}
+	if st := p.stats(); st.NUMANodes != 2 || st.Workers[3].NUMANode != 1 {
+		t.Fatalf("stats %+v", st)
+	}
+
+	// Whatever the machine, the option keeps the distances.
+	var st ParallelStats
+	res, err := g.RunParallel(0, ParallelOptions{Workers: 4, NUMA: true, Stats: &st})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(res.Dist, want.Dist) || st.NUMANodes < 1 {
+		t.Fatalf("NUMA run differs (%d nodes)", st.NUMANodes)
+	}
+}
@@ -0,0 +1,262 @@
+// Package osm builds routable sssp graphs from OpenStreetMap .osm.pbf
+// extracts.
+//
+// Ways are filtered by a Profile and split into one edge per consecutive
+// node pair, weighted by travel time in seconds. Node coordinates are held in
+// memory while reading, which suits city and regional extracts rather than
+// the full planet. A network keeps the tags of its ways, so it can be
+// weighted for other profiles at query time (Network.Weights) and expanded
+// into a turn graph that charges a profile's turn penalties (TurnGraph).
+package osm
+
+import (
+	"bufio"
+	"cmp"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	sssp "github.com/your-org/optimized-sssp-go"
+	"github.com/your-org/optimized-sssp-go/internal/decompress"
+)
+
+// Profile decides which ways are routable and how fast they are.
+type Profile struct {
+	Name string
+	// Speeds maps highway=* values to speeds in km/h. Ways whose highway tag
+	// is not listed are skipped.
+	Speeds map[string]float64
+	// Maxspeed uses maxspeed=* (km/h or "mph") when present.
+	Maxspeed bool
+	// Oneway honours oneway=yes/1/-1 and junction=roundabout. OnewayOverride
+	// names a mode-specific tag (e.g. oneway:bicycle) whose "no" wins.
+	Oneway         bool
+	OnewayOverride string
+	// Access lists access tags from general to specific; the most specific
+	// tag present decides, and "no"/"private" exclude the way.
+	Access []string
+	// Turns adds seconds per kind of turn between consecutive edges. It only
+	// applies to routes on a TurnGraph; a missing kind costs nothing.
+	Turns map[Turn]float64
+	// Grade charges climbs and discounts descents. It needs elevations on
+	// the network graph (Graph.SetElevations) and applies through
+	// Network.Weights; Load itself weights ways as flat.
+	Grade sssp.GradeCost
+}
+
+// Built-in profiles.
+var (
+	Car = Profile{
+		Name: "car",
+		Speeds: map[string]float64{
+			"motorway": 110, "motorway_link": 60, "trunk": 90, "trunk_link": 50,
+			"primary": 70, "primary_link": 40, "secondary": 60, "secondary_link": 40,
+			"tertiary": 50, "tertiary_link": 30, "unclassified": 40, "residential": 30,
+			"living_street": 10, "service": 15,
+		},
+		Maxspeed: true,
+		Oneway:   true,
+		Access:   []string{"access", "vehicle", "motor_vehicle", "motorcar"},
+		Turns:    map[Turn]float64{TurnRight: 5, TurnLeft: 10, TurnUTurn: 60},
+	}
+	Bike = Profile{
+		Name: "bike",
+		Speeds: map[string]float64{
+			"cycleway": 18, "primary": 18, "primary_link": 18, "secondary": 18, "secondary_link": 18,
+			"tertiary": 18, "tertiary_link": 18, "unclassified": 16, "residential": 16,
+			"living_street": 12, "service": 12, "track": 12, "path": 12,
+		},
+		Oneway:         true,
+		OnewayOverride: "oneway:bicycle",
+		Access:         []string{"access", "vehicle", "bicycle"},
+		Turns:          map[Turn]float64{TurnLeft: 4, TurnUTurn: 15},
+		Grade:          sssp.GradeCost{Climb: 8, Descent: 2, MinFactor: 0.6},
+	}
+	Foot = Profile{
+		Name: "foot",
+		Speeds: map[string]float64{
+			"footway": 5, "pedestrian": 5, "path": 5, "steps": 3, "track": 5, "living_street": 5,
+			"residential": 5, "service": 5, "unclassified": 5, "tertiary": 5, "tertiary_link": 5,
+			"secondary": 5, "secondary_link": 5, "primary": 5, "primary_link": 5,
+		},
+		Access: []string{"access", "foot"},
+		Grade:  sssp.GradeCost{Climb: 6, MinFactor: 1}, // Naismith: an hour per 600 m climbed
+	}
+)
+
+// LatLon is a WGS84 coordinate in degrees.
+type LatLon = sssp.LatLon
+
+// Network is a routable graph with per-node coordinates and OSM ids, indexed
+// by graph node id. Coords is also attached to Graph. Each edge carries the
+// attributes "way" (the OSM way id, int64), "backward" (bool, whether it
+// runs against the way's node order) and "length" (metres, float32), and
+// WayTags holds the tags of every way used.
+type Network struct {
+	Graph   *sssp.Graph
+	Coords  []LatLon
+	NodeIDs []int64
+	WayTags map[int64]map[string]string
+}
+
+// Load reads an .osm.pbf stream and builds the network for p. Raw and zlib
+// blobs are supported, and the whole stream may be gzip- or zstd-compressed.
+func Load(r io.Reader, p Profile) (*Network, error) {
+	r, err := decompress.Reader(r)
+	if err != nil {
+		return nil, err
+	}
+	type segWay struct {
+		id           int64
+		refs         []int64
+		fwd, bwd     bool
+		metersPerSec float64
+	}
+	coords := make(map[int64]LatLon)
+	wayTags := make(map[int64]map[string]string)
+	var ways []segWay
+	err = readPBF(bufio.NewReaderSize(r, 1<<20), pbfHandler{
+		node: func(id int64, lat, lon float64) { coords[id] = LatLon{Lat: lat, Lon: lon} },
+		way: func(id int64, tags map[string]string, refs []int64) {
+			kmh, fwd, bwd, ok := p.classify(tags)
+			if ok && len(refs) > 1 {
+				ways = append(ways, segWay{id, refs, fwd, bwd, kmh / 3.6})
+				wayTags[id] = tags
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[int64]uint32)
+	net := &Network{WayTags: wayTags}
+	id := func(ref int64) uint32 {
+		if i, ok := index[ref]; ok {
+			return i
+		}
+		i := uint32(len(net.NodeIDs))
+		index[ref] = i
+		net.NodeIDs = append(net.NodeIDs, ref)
+		net.Coords = append(net.Coords, coords[ref])
+		return i
+	}
+	type segment struct {
+		sssp.Edge
+		way      int64
+		backward bool
+		length   float32
+	}
+	var segs []segment
+	for _, w := range ways {
+		for i := 1; i < len(w.refs); i++ {
+			a, okA := coords[w.refs[i-1]]
+			b, okB := coords[w.refs[i]]
+			if !okA || !okB {
+				continue // clipped at the extract boundary
+			}
+			m := sssp.Haversine(a, b)
+			secs := float32(m / w.metersPerSec)
+			u, v := id(w.refs[i-1]), id(w.refs[i])
+			if w.fwd {
+				segs = append(segs, segment{sssp.Edge{From: u, To: v, Weight: secs}, w.id, false, float32(m)})
+			}
+			if w.bwd {
+				segs = append(segs, segment{sssp.Edge{From: v, To: u, Weight: secs}, w.id, true, float32(m)})
+			}
+		}
+	}
+	// In source order edge IDs follow the slice, so the attribute columns
+	// line up with them.
+	slices.SortStableFunc(segs, func(a, b segment) int { return cmp.Compare(a.From, b.From) })
+	edges := make([]sssp.Edge, len(segs))
+	wayIDs, backward, lengths := make([]int64, len(segs)), make([]bool, len(segs)), make([]float32, len(segs))
+	for i, s := range segs {
+		edges[i], wayIDs[i], backward[i], lengths[i] = s.Edge, s.way, s.backward, s.length
+	}
+	g, err := sssp.FromEdges(uint32(len(net.NodeIDs)), edges)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.SetCoords(net.Coords); err != nil {
+		return nil, err
+	}
+	if err := sssp.SetEdgeAttr(g, "way", wayIDs); err != nil {
+		return nil, err
+	}
+	if err := sssp.SetEdgeAttr(g, "backward", backward); err != nil {
+		return nil, err
+	}
+	if err := sssp.SetEdgeAttr(g, "length", lengths); err != nil {
+		return nil, err
+	}
+	net.Graph = g
+	return net, nil
+}
+
+// LoadFile is Load on a file path.
+func LoadFile(path string, p Profile) (*Network, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f, p)
+}
+
+// classify returns the way's speed and allowed directions under p.
+func (p Profile) classify(tags map[string]string) (kmh float64, fwd, bwd, ok bool) {
+	kmh, ok = p.Speeds[tags["highway"]]
+	if !ok {
+		return 0, false, false, false
+	}
+	for _, key := range p.Access {
+		switch tags[key] {
+		case "":
+		case "no", "private":
+			ok = false
+		default:
+			ok = true
+		}
+	}
+	if !ok {
+		return 0, false, false, false
+	}
+	if p.Maxspeed {
+		if s, found := parseMaxspeed(tags["maxspeed"]); found {
+			kmh = s
+		}
+	}
+	fwd, bwd = true, true
+	if p.Oneway && (p.OnewayOverride == "" || tags[p.OnewayOverride] != "no") {
+		switch tags["oneway"] {
+		case "yes", "true", "1":
+			bwd = false
+		case "-1", "reverse":
+			fwd = false
+		case "":
+			if tags["junction"] == "roundabout" || tags["highway"] == "motorway" {
+				bwd = false
+			}
+		}
+	}
+	return kmh, fwd, bwd, true
+}
+
+// parseMaxspeed understands "50", "50 km/h", "30 mph" and "50;30" (first
+// value). Symbolic values such as "none" or "DE:urban" are ignored.
+func parseMaxspeed(s string) (float64, bool) {
+	s, _, _ = strings.Cut(s, ";")
+	s = strings.TrimSpace(s)
+	factor := 1.0
+	if strings.HasSuffix(s, "mph") {
+		s, factor = strings.TrimSpace(strings.TrimSuffix(s, "mph")), 1.609344
+	}
+	s = strings.TrimSpace(strings.TrimSuffix(s, "km/h"))
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v <= 0 {
+		return 0, false
+	}
+	return v * factor, true
+}
@@ -0,0 +1,201 @@
+package osm
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/your-org/optimized-sssp-go/internal/wire"
+)
+
+// pbfFrame encodes one BlobHeader+Blob frame around block.
+func pbfFrame(typ string, block []byte, compress bool) []byte {
+	var blob []byte
+	if compress {
+		var z bytes.Buffer
+		zw := zlib.NewWriter(&z)
+		zw.Write(block)
+		zw.Close()
+		blob = wire.AppendVarintField(blob, 2, uint64(len(block)))
+		blob = wire.AppendBytesField(blob, 3, z.Bytes())
+	} else {
+		blob = wire.AppendBytesField(blob, 1, block)
+	}
+	hdr := wire.AppendBytesField(nil, 1, []byte(typ))
+	hdr = wire.AppendVarintField(hdr, 3, uint64(len(blob)))
+	out := binary.BigEndian.AppendUint32(nil, uint32(len(hdr)))
+	return append(append(out, hdr...), blob...)
+}
+
+// samplePBF: four nodes 0.001 degrees of longitude apart on the equator,
+// joined by a residential way 1-2, a one-way primary 2-3 and a footway 3-4.
+func samplePBF(compress bool) []byte {
+	header := wire.AppendBytesField(nil, 4, []byte("OsmSchema-V0.6"))
+	header = wire.AppendBytesField(header, 4, []byte("DenseNodes"))
+
+	strs := []string{"", "highway", "residential", "primary", "oneway", "yes", "footway"}
+	var st []byte
+	for _, s := range strs {
+		st = wire.AppendBytesField(st, 1, []byte(s))
+	}
+	var dense []byte
+	dense = wire.AppendPackedSint(dense, 1, []int64{1, 1, 1, 1})             // ids 1..4, delta coded
+	dense = wire.AppendPackedSint(dense, 8, []int64{0, 0, 0, 0})             // lat 0
+	dense = wire.AppendPackedSint(dense, 9, []int64{0, 10000, 10000, 10000}) // lon step 0.001 deg
+	nodes := wire.AppendBytesField(nil, 2, dense)
+
+	way := func(id int64, keys, vals []uint32, refs []int64) []byte {
+		var w []byte
+		w = wire.AppendVarintField(w, 1, uint64(id))
+		w = wire.AppendPackedUint32(w, 2, keys)
+		w = wire.AppendPackedUint32(w, 3, vals)
+		return wire.AppendPackedSint(w, 8, refs)
+	}
+	var ways []byte
+	ways = wire.AppendBytesField(ways, 3, way(10, []uint32{1}, []uint32{2}, []int64{1, 1}))
+	ways = wire.AppendBytesField(ways, 3, way(11, []uint32{1, 4}, []uint32{3, 5}, []int64{2, 1}))
+	ways = wire.AppendBytesField(ways, 3, way(12, []uint32{1}, []uint32{6}, []int64{3, 1}))
+
+	var block []byte
+	block = wire.AppendBytesField(block, 1, st)
+	block = wire.AppendBytesField(block, 2, nodes)
+	block = wire.AppendBytesField(block, 2, ways)
+	return append(pbfFrame("OSMHeader", header, false), pbfFrame("OSMData", block, compress)...)
+}
+
+func TestLoadCarProfileHonoursOnewayAndSkipsFootways(t *testing.T) {
+	net, err := Load(bytes.NewReader(samplePBF(true)), Car)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if net.Graph.NodeCount() != 3 || net.Graph.EdgeCount() != 3 {
+		t.Fatalf("got %d nodes %d edges", net.Graph.NodeCount(), net.Graph.EdgeCount())
+	}
+	if net.NodeIDs[2] != 3 || math.Abs(net.Coords[1].Lon-0.001) > 1e-9 {
+		t.Fatalf("node table %v %v", net.NodeIDs, net.Coords)
+	}
+	res, err := net.Graph.Run(2, 0)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if !math.IsInf(float64(res.Dist[0]), 1) {
+		t.Fatalf("one-way 2->3 traversed backwards: %v", res.Dist)
+	}
+	// 111.2 m at 30 km/h.
+	res, _ = net.Graph.Run(0, 0)
+	if math.Abs(float64(res.Dist[1])-13.34) > 0.05 {
+		t.Fatalf("residential segment took %v s", res.Dist[1])
+	}
+}
+
+func TestLoadFootProfileAndRawBlobs(t *testing.T) {
+	net, err := Load(bytes.NewReader(samplePBF(false)), Foot)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if net.Graph.NodeCount() != 4 || net.Graph.EdgeCount() != 6 {
+		t.Fatalf("got %d nodes %d edges", net.Graph.NodeCount(), net.Graph.EdgeCount())
+	}
+	if _, err := Load(bytes.NewReader(samplePBF(false)[:40]), Foot); err == nil {
+		t.Fatalf("expected truncation error")
+	}
+}
+
+func TestParseMaxspeed(t *testing.T) {
+	for in, want := range map[string]float64{"50": 50, "30 mph": 30 * 1.609344, "80 km/h": 80, "50;30": 50} {
+		if got, ok := parseMaxspeed(in); !ok || math.Abs(got-want) > 1e-9 {
+			t.Fatalf("parseMaxspeed(%q) = %v, %v", in, got, ok)
+		}
+	}
+	if _, ok := parseMaxspeed("DE:urban"); ok {
+		t.Fatalf("symbolic maxspeed should be ignored")
+	}
+}
+
+func TestNetworkWeightsForOtherProfiles(t *testing.T) {
+	net, err := Load(bytes.NewReader(samplePBF(false)), Foot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := net.Weighted(Car)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := g.Run(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Residential at 30 km/h, then the primary at 70; the footway is closed.
+	if math.Abs(float64(res.Dist[2])-(13.34+5.72)) > 0.05 || !math.IsInf(float64(res.Dist[3]), 1) {
+		t.Fatalf("car times on the foot network %v", res.Dist)
+	}
+	res, _ = g.Run(2, 0)
+	if !math.IsInf(float64(res.Dist[1]), 1) {
+		t.Fatalf("one-way primary open backwards: %v", res.Dist)
+	}
+	if w, _ := net.Graph.EdgeWeight(0, 1); math.Abs(float64(w)-80.06) > 0.05 {
+		t.Fatalf("stored foot weight changed to %v", w)
+	}
+}
+
+func TestTurnGraphChargesTurns(t *testing.T) {
+	net, err := Load(bytes.NewReader(samplePBF(false)), Foot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := Car
+	p.Turns = map[Turn]float64{TurnStraight: 100, TurnUTurn: 1000}
+	tg, err := net.TurnGraph(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dist, err := tg.Route(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dist[0] != 0 || math.Abs(float64(dist[2])-(13.34+5.72+100)) > 0.05 {
+		t.Fatalf("turn route %v", dist)
+	}
+	if _, err := tg.Route(9, 0); err == nil {
+		t.Fatalf("out-of-range source accepted")
+	}
+}
+
+func TestClassifyTurn(t *testing.T) {
+	a, b := LatLon{Lat: 0, Lon: 0}, LatLon{Lat: 0, Lon: 0.001}
+	for c, want := range map[LatLon]Turn{
+		{Lat: 0, Lon: 0.002}:       TurnStraight,
+		{Lat: 0.001, Lon: 0.001}:   TurnLeft,
+		{Lat: -0.001, Lon: 0.001}:  TurnRight,
+		{Lat: 0.00001, Lon: 0}:     TurnUTurn,
+		{Lat: 0.0002, Lon: 0.0015}: TurnStraight,
+	} {
+		if got := classifyTurn(a, b, c); got != want {
+			t.Errorf("turn to %v = %v, want %v", c, got, want)
+		}
+	}
+}
+
+func TestProfileGradeNeedsElevations(t *testing.T) {
+	net, err := Load(bytes.NewReader(samplePBF(false)), Foot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := net.Weights(Foot); err == nil {
+		t.Fatalf("grade cost without elevations accepted")
+	}
+	if err := net.Graph.SetElevations([]float32{0, 10, 10, 10}); err != nil {
+		t.Fatal(err)
+	}
+	w, err := net.Weights(Foot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	up, down := net.Graph.EdgeIDs(0, 1)[0], net.Graph.EdgeIDs(1, 0)[0]
+	// 111.2 m at 5 km/h is 80 s; climbing 10 m adds 60 s, descending is free.
+	if math.Abs(float64(w[up])-140.06) > 0.05 || math.Abs(float64(w[down])-80.06) > 0.05 {
+		t.Fatalf("up %v down %v", w[up], w[down])
+	}
+}
@@ -0,0 +1,284 @@
+package osm
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/your-org/optimized-sssp-go/internal/wire"
+)
+
+// .osm.pbf decoding: the file is a sequence of (4-byte big-endian length,
+// BlobHeader, Blob) frames. The first blob is an OSMHeader, the rest OSMData
+// PrimitiveBlocks holding nodes (plain or dense) and ways. Relations,
+// changesets and metadata are skipped.
+
+const (
+	maxBlobHeaderSize = 64 << 10
+	maxBlobSize       = 32 << 20
+)
+
+var supportedFeatures = map[string]bool{"OsmSchema-V0.6": true, "DenseNodes": true}
+
+// pbfHandler receives decoded entities.
+type pbfHandler struct {
+	node func(id int64, lat, lon float64)
+	way  func(id int64, tags map[string]string, refs []int64)
+}
+
+// readPBF decodes every blob in r.
+func readPBF(r io.Reader, h pbfHandler) error {
+	var lenbuf [4]byte
+	for blob := 0; ; blob++ {
+		if _, err := io.ReadFull(r, lenbuf[:]); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("osm: blob %d: %w", blob, err)
+		}
+		hlen := binary.BigEndian.Uint32(lenbuf[:])
+		if hlen > maxBlobHeaderSize {
+			return fmt.Errorf("osm: blob %d: header of %d bytes", blob, hlen)
+		}
+		hdr := make([]byte, hlen)
+		if _, err := io.ReadFull(r, hdr); err != nil {
+			return fmt.Errorf("osm: blob %d: %w", blob, err)
+		}
+		var (
+			typ  string
+			size uint64
+		)
+		if err := wire.Walk(hdr, func(num, _ int, v uint64, data []byte) error {
+			switch num {
+			case 1:
+				typ = string(data)
+			case 3:
+				size = v
+			}
+			return nil
+		}); err != nil {
+			return fmt.Errorf("osm: blob %d header: %w", blob, err)
+		}
+		if size > maxBlobSize {
+			return fmt.Errorf("osm: blob %d: %d bytes exceeds the 32 MiB limit", blob, size)
+		}
+		raw := make([]byte, size)
+		if _, err := io.ReadFull(r, raw); err != nil {
+			return fmt.Errorf("osm: blob %d: %w", blob, err)
+		}
+		data, err := inflateBlob(raw)
+		if err != nil {
+			return fmt.Errorf("osm: blob %d: %w", blob, err)
+		}
+		switch typ {
+		case "OSMHeader":
+			err = checkHeader(data)
+		case "OSMData":
+			err = decodeBlock(data, h)
+		}
+		if err != nil {
+			return fmt.Errorf("osm: blob %d (%s): %w", blob, typ, err)
+		}
+	}
+}
+
+func inflateBlob(b []byte) ([]byte, error) {
+	var (
+		raw, zdata []byte
+		rawSize    uint64
+		other      int
+	)
+	err := wire.Walk(b, func(num, _ int, v uint64, data []byte) error {
+		switch num {
+		case 1:
+			raw = data
+		case 2:
+			rawSize = v
+		case 3:
+			zdata = data
+		case 4, 5, 6, 7:
+			other = num
+		}
+		return nil
+	})
+	switch {
+	case err != nil:
+		return nil, err
+	case raw != nil:
+		return raw, nil
+	case zdata != nil:
+		if rawSize > maxBlobSize {
+			return nil, fmt.Errorf("raw size %d exceeds the 32 MiB limit", rawSize)
+		}
+		zr, err := zlib.NewReader(bytes.NewReader(zdata))
+		if err != nil {
+			return nil, err
+		}
+		out := make([]byte, rawSize)
+		if _, err := io.ReadFull(zr, out); err != nil {
+			return nil, err
+		}
+		return out, nil
+	case other != 0:
+		name := map[int]string{4: "lzma", 5: "bzip2", 6: "lz4", 7: "zstd"}[other]
+		return nil, fmt.Errorf("unsupported blob compression %s", name)
+	}
+	return nil, errors.New("empty blob")
+}
+
+func checkHeader(b []byte) error {
+	return wire.Walk(b, func(num, _ int, _ uint64, data []byte) error {
+		if num == 4 && !supportedFeatures[string(data)] {
+			return fmt.Errorf("unsupported required feature %q", data)
+		}
+		return nil
+	})
+}
+
+type primitiveBlock struct {
+	strings        [][]byte
+	granularity    int64
+	latOff, lonOff int64
+}
+
+func (pb *primitiveBlock) coord(lat, lon int64) (float64, float64) {
+	return 1e-9 * float64(pb.latOff+pb.granularity*lat), 1e-9 * float64(pb.lonOff+pb.granularity*lon)
+}
+
+func decodeBlock(b []byte, h pbfHandler) error {
+	pb := primitiveBlock{granularity: 100}
+	var groups [][]byte
+	err := wire.Walk(b, func(num, _ int, v uint64, data []byte) error {
+		switch num {
+		case 1:
+			return wire.Walk(data, func(num, _ int, _ uint64, s []byte) error {
+				if num == 1 {
+					pb.strings = append(pb.strings, s)
+				}
+				return nil
+			})
+		case 2:
+			groups = append(groups, data)
+		case 17:
+			pb.granularity = int64(int32(v))
+		case 19:
+			pb.latOff = int64(v)
+		case 20:
+			pb.lonOff = int64(v)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, g := range groups {
+		err := wire.Walk(g, func(num, _ int, _ uint64, data []byte) error {
+			switch num {
+			case 1:
+				return pb.node(data, h)
+			case 2:
+				return pb.dense(data, h)
+			case 3:
+				return pb.way(data, h)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (pb *primitiveBlock) node(b []byte, h pbfHandler) error {
+	var id, lat, lon int64
+	if err := wire.Walk(b, func(num, _ int, v uint64, _ []byte) error {
+		switch num {
+		case 1:
+			id = wire.Unzigzag(v)
+		case 8:
+			lat = wire.Unzigzag(v)
+		case 9:
+			lon = wire.Unzigzag(v)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	la, lo := pb.coord(lat, lon)
+	h.node(id, la, lo)
+	return nil
+}
+
+func (pb *primitiveBlock) dense(b []byte, h pbfHandler) error {
+	var ids, lats, lons []uint64
+	if err := wire.Walk(b, func(num, typ int, v uint64, data []byte) error {
+		var err error
+		switch num {
+		case 1:
+			ids, err = wire.Varints(ids, typ, v, data)
+		case 8:
+			lats, err = wire.Varints(lats, typ, v, data)
+		case 9:
+			lons, err = wire.Varints(lons, typ, v, data)
+		}
+		return err
+	}); err != nil {
+		return err
+	}
+	if len(lats) != len(ids) || len(lons) != len(ids) {
+		return fmt.Errorf("dense nodes: %d ids, %d lats, %d lons", len(ids), len(lats), len(lons))
+	}
+	var id, lat, lon int64
+	for i := range ids {
+		id += wire.Unzigzag(ids[i])
+		lat += wire.Unzigzag(lats[i])
+		lon += wire.Unzigzag(lons[i])
+		la, lo := pb.coord(lat, lon)
+		h.node(id, la, lo)
+	}
+	return nil
+}
+
+func (pb *primitiveBlock) way(b []byte, h pbfHandler) error {
+	var (
+		id               int64
+		keys, vals, refs []uint64
+	)
+	if err := wire.Walk(b, func(num, typ int, v uint64, data []byte) error {
+		var err error
+		switch num {
+		case 1:
+			id = int64(v)
+		case 2:
+			keys, err = wire.Varints(keys, typ, v, data)
+		case 3:
+			vals, err = wire.Varints(vals, typ, v, data)
+		case 8:
+			refs, err = wire.Varints(refs, typ, v, data)
+		}
+		return err
+	}); err != nil {
+		return err
+	}
+	if len(keys) != len(vals) {
+		return fmt.Errorf("way %d: %d keys but %d values", id, len(keys), len(vals))
+	}
+	tags := make(map[string]string, len(keys))
+	for i := range keys {
+		if keys[i] >= uint64(len(pb.strings)) || vals[i] >= uint64(len(pb.strings)) {
+			return fmt.Errorf("way %d: string index out of range", id)
+		}
+		tags[string(pb.strings[keys[i]])] = string(pb.strings[vals[i]])
+	}
+	nodes := make([]int64, len(refs))
+	var ref int64
+	for i, z := range refs {
+		ref += wire.Unzigzag(z)
+		nodes[i] = ref
+	}
+	h.way(id, tags, nodes)
+	return nil
+}
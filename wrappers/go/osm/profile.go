@@ -0,0 +1,196 @@
+package osm
+
+import (
+	"fmt"
+	"math"
+
+	sssp "github.com/your-org/optimized-sssp-go"
+)
+
+// Turn is the kind of turn from one edge onto the next, from the change of
+// bearing at the shared node.
+type Turn int
+
+const (
+	TurnStraight Turn = iota // within 45 degrees
+	TurnRight
+	TurnLeft
+	TurnUTurn // more than 170 degrees, or straight back to the previous node
+)
+
+func (t Turn) String() string {
+	switch t {
+	case TurnStraight:
+		return "straight"
+	case TurnRight:
+		return "right"
+	case TurnLeft:
+		return "left"
+	case TurnUTurn:
+		return "u-turn"
+	}
+	return fmt.Sprintf("Turn(%d)", int(t))
+}
+
+// classifyTurn returns the turn made at b when travelling a->b->c.
+func classifyTurn(a, b, c LatLon) Turn {
+	if a == c {
+		return TurnUTurn
+	}
+	d := bearing(b, c) - bearing(a, b)
+	switch {
+	case d > 180:
+		d -= 360
+	case d <= -180:
+		d += 360
+	}
+	switch {
+	case math.Abs(d) <= 45:
+		return TurnStraight
+	case math.Abs(d) > 170:
+		return TurnUTurn
+	case d > 0:
+		return TurnRight
+	}
+	return TurnLeft
+}
+
+// bearing is the initial great-circle bearing from a to b in degrees
+// clockwise from north.
+func bearing(a, b LatLon) float64 {
+	rad := math.Pi / 180
+	dLon := (b.Lon - a.Lon) * rad
+	y := math.Sin(dLon) * math.Cos(b.Lat*rad)
+	x := math.Cos(a.Lat*rad)*math.Sin(b.Lat*rad) - math.Sin(a.Lat*rad)*math.Cos(b.Lat*rad)*math.Cos(dLon)
+	return math.Atan2(y, x) / rad
+}
+
+// Weights returns the travel time in seconds of every edge of the network
+// under p, indexed by edge ID, from each edge's way tags and length. Edges
+// p does not allow, by class, access or direction, get +Inf, which closes
+// them. Only edges the network was loaded with exist, so load with the most
+// permissive profile (often Foot) to query several. A profile with a Grade
+// cost needs elevations on the network graph, unless it is disabled by
+// setting Grade to its zero value.
+func (n *Network) Weights(p Profile) ([]float32, error) {
+	ways, okW := sssp.EdgeAttr[int64](n.Graph, "way")
+	backward, okB := sssp.EdgeAttr[bool](n.Graph, "backward")
+	lengths, okL := sssp.EdgeAttr[float32](n.Graph, "length")
+	if !okW || !okB || !okL {
+		return nil, fmt.Errorf("osm: network edges lack the way, backward and length attributes")
+	}
+	grade := func(_, _ uint32, w float32) float32 { return w }
+	if p.Grade != (sssp.GradeCost{}) {
+		var err error
+		if grade, err = n.Graph.GradeWeight(p.Grade); err != nil {
+			return nil, fmt.Errorf("osm: profile %s: %w", p.Name, err)
+		}
+	}
+	type verdict struct {
+		metersPerSec float64
+		fwd, bwd, ok bool
+	}
+	seen := make(map[int64]verdict)
+	offsets, targets, _ := n.Graph.CSR()
+	weights := make([]float32, len(ways))
+	for u := uint32(0); u < n.Graph.NodeCount(); u++ {
+		for id := offsets[u]; id < offsets[u+1]; id++ {
+			way := ways[id]
+			v, found := seen[way]
+			if !found {
+				kmh, fwd, bwd, ok := p.classify(n.WayTags[way])
+				v = verdict{kmh / 3.6, fwd, bwd, ok}
+				seen[way] = v
+			}
+			if !v.ok || (backward[id] && !v.bwd) || (!backward[id] && !v.fwd) {
+				weights[id] = float32(math.Inf(1))
+				continue
+			}
+			weights[id] = grade(u, targets[id], float32(float64(lengths[id])/v.metersPerSec))
+		}
+	}
+	return weights, nil
+}
+
+// Weighted returns a view of the network graph weighted for p; see Weights.
+// It shares the topology, so one loaded network serves every profile.
+func (n *Network) Weighted(p Profile) (*sssp.Graph, error) {
+	w, err := n.Weights(p)
+	if err != nil {
+		return nil, err
+	}
+	return n.Graph.WithWeights(w)
+}
+
+// TurnGraph is a network expanded so that turns can cost time: node e, for
+// each network edge e, means "just travelled e", and an edge e->f exists
+// where f leaves the node e enters, weighted by f's travel time plus the
+// profile's penalty for that turn. Node m+u, for m network edges, is the
+// start at network node u. Use Route rather than running Graph directly.
+type TurnGraph struct {
+	Graph *sssp.Graph
+	net   *Network
+}
+
+// TurnGraph builds the turn graph of the network under p. Edges p closes
+// are left out.
+func (n *Network) TurnGraph(p Profile) (*TurnGraph, error) {
+	weights, err := n.Weights(p)
+	if err != nil {
+		return nil, err
+	}
+	offsets, targets, _ := n.Graph.CSR()
+	m, nodes := uint64(len(targets)), uint64(n.Graph.NodeCount())
+	if m+nodes > math.MaxUint32 {
+		return nil, fmt.Errorf("osm: turn graph of %d edges and %d nodes overflows 32-bit node IDs", m, nodes)
+	}
+	open := func(e uint32) bool { return !math.IsInf(float64(weights[e]), 1) }
+	var edges []sssp.Edge
+	for u := uint32(0); u < uint32(nodes); u++ {
+		for e := offsets[u]; e < offsets[u+1]; e++ {
+			if !open(e) {
+				continue
+			}
+			edges = append(edges, sssp.Edge{From: uint32(m) + u, To: e, Weight: weights[e]})
+			v := targets[e]
+			for f := offsets[v]; f < offsets[v+1]; f++ {
+				if !open(f) {
+					continue
+				}
+				turn := TurnUTurn
+				if w := targets[f]; w != u {
+					turn = classifyTurn(n.Coords[u], n.Coords[v], n.Coords[w])
+				}
+				edges = append(edges, sssp.Edge{From: e, To: f, Weight: weights[f] + float32(p.Turns[turn])})
+			}
+		}
+	}
+	g, err := sssp.FromEdges(uint32(m+nodes), edges)
+	if err != nil {
+		return nil, err
+	}
+	return &TurnGraph{Graph: g, net: n}, nil
+}
+
+// Route returns the travel time from network node source to every network
+// node, turn penalties included, +Inf where unreachable.
+func (t *TurnGraph) Route(source uint32, mode int) ([]float32, error) {
+	nodes := t.net.Graph.NodeCount()
+	if source >= nodes {
+		return nil, fmt.Errorf("osm: source %d out of range for %d nodes", source, nodes)
+	}
+	_, targets, _ := t.net.Graph.CSR()
+	res, err := t.Graph.Run(uint32(len(targets))+source, mode)
+	if err != nil {
+		return nil, err
+	}
+	dist := make([]float32, nodes)
+	for v := range dist {
+		dist[v] = float32(math.Inf(1))
+	}
+	dist[source] = 0
+	for e, v := range targets {
+		dist[v] = min(dist[v], res.Dist[e])
+	}
+	return dist, nil
+}
@@ -0,0 +1,163 @@
+package sssp
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Adjustment changes the weight w of an edge to w*Mul + Add. A Mul of 0
+// means 1, so Adjustment{Add: 30} is a pure penalty; an Add of +Inf closes
+// the edge.
+type Adjustment struct {
+	Mul, Add float32
+}
+
+func (a Adjustment) apply(w float32) float32 {
+	if a.Mul != 0 {
+		w *= a.Mul
+	}
+	return w + a.Add
+}
+
+// Overlay is a sparse layer of weight adjustments, such as live traffic or
+// a turn penalty feed, keyed by node pair rather than edge ID so it stays
+// valid when the base graph gains or loses edges. Pairs with no edge in the
+// graph are ignored. Build an overlay completely before handing it to
+// Layers, and treat it as read-only afterwards: to change it, build a new
+// one and swap it in.
+type Overlay struct {
+	// Name identifies the layer for Layers.Replace.
+	Name string
+	adj  map[[2]uint32]Adjustment
+}
+
+// NewOverlay returns an empty overlay called name.
+func NewOverlay(name string) *Overlay {
+	return &Overlay{Name: name, adj: map[[2]uint32]Adjustment{}}
+}
+
+// Set adjusts every edge from u to v, replacing any earlier adjustment of
+// the pair in o.
+func (o *Overlay) Set(u, v uint32, a Adjustment) { o.adj[[2]uint32{u, v}] = a }
+
+// Get returns the adjustment of the pair u->v, if o has one.
+func (o *Overlay) Get(u, v uint32) (Adjustment, bool) {
+	a, ok := o.adj[[2]uint32{u, v}]
+	return a, ok
+}
+
+// Len returns the number of adjusted pairs.
+func (o *Overlay) Len() int { return len(o.adj) }
+
+// Layers applies a stack of overlays to a base graph without touching it.
+// The stack is replaced atomically, so queries running while live traffic
+// is swapped in see either the old stack or the new one, never a mix.
+// Graph builds the adjusted view once per stack, on first use, and shares it
+// between queries; it rebuilds it when the stack is swapped or the base
+// graph's edges or weights change, so anything derived from the view, such
+// as its reverse view or an autotune fingerprint, is invalidated with it.
+// Layers is safe for concurrent use, but the base graph must not be changed
+// while queries run.
+type Layers struct {
+	base  *Graph
+	mu    sync.Mutex // serializes writers
+	state atomic.Pointer[layerState]
+}
+
+// layerState is one published stack and its lazily built view.
+type layerState struct {
+	stack       []*Overlay
+	once        sync.Once
+	view        *Graph
+	baseVersion uint64
+}
+
+// NewLayers returns Layers over base with the overlays stack, applied in
+// order.
+func NewLayers(base *Graph, stack ...*Overlay) *Layers {
+	l := &Layers{base: base}
+	l.state.Store(&layerState{stack: slices.Clone(stack)})
+	return l
+}
+
+// Base returns the graph the overlays apply to.
+func (l *Layers) Base() *Graph { return l.base }
+
+// Stack returns the overlays currently applied, bottom first.
+func (l *Layers) Stack() []*Overlay { return slices.Clone(l.state.Load().stack) }
+
+// Set replaces the whole stack.
+func (l *Layers) Set(stack ...*Overlay) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.state.Store(&layerState{stack: slices.Clone(stack)})
+}
+
+// Replace swaps the layer named name for o, keeping its place in the stack,
+// or pushes o on top if there is none. A nil o removes the layer.
+func (l *Layers) Replace(name string, o *Overlay) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	stack := slices.Clone(l.state.Load().stack)
+	i := slices.IndexFunc(stack, func(x *Overlay) bool { return x.Name == name })
+	switch {
+	case i < 0 && o != nil:
+		stack = append(stack, o)
+	case i >= 0 && o != nil:
+		stack[i] = o
+	case i >= 0:
+		stack = slices.Delete(stack, i, i+1)
+	}
+	l.state.Store(&layerState{stack: stack})
+}
+
+// Graph returns the base graph with the current stack applied. The view
+// shares the base topology and costs one weight array; it must not be
+// modified.
+func (l *Layers) Graph() *Graph {
+	for {
+		s := l.state.Load()
+		s.once.Do(func() {
+			s.view, s.baseVersion = applyOverlays(l.base, s.stack), l.base.version
+		})
+		if s.baseVersion == l.base.version {
+			return s.view
+		}
+		// The base changed since the view was built: publish a fresh state
+		// for the same stack, unless a writer got there first.
+		l.state.CompareAndSwap(s, &layerState{stack: s.stack})
+	}
+}
+
+// RunQuery runs a query on the current view; see Graph.RunQuery. An avoid
+// set for it must be built on the base graph, which has the same edge IDs.
+func (l *Layers) RunQuery(source uint32, mode int, opts QueryOptions) (Result, error) {
+	return l.Graph().RunQuery(source, mode, opts)
+}
+
+// applyOverlays returns a view of g with the overlays of stack applied in
+// order, or g itself if none adjusts anything.
+func applyOverlays(g *Graph, stack []*Overlay) *Graph {
+	var weights []float32
+	for _, o := range stack {
+		for p, a := range o.adj {
+			if p[0] >= g.NodeCount() {
+				continue
+			}
+			for e := g.offsets[p[0]]; e < g.offsets[p[0]+1]; e++ {
+				if g.targets[e] != p[1] {
+					continue
+				}
+				if weights == nil {
+					weights = slices.Clone(g.weights)
+				}
+				weights[e] = a.apply(weights[e])
+			}
+		}
+	}
+	if weights == nil {
+		return g
+	}
+	return g.withWeights(weights)
+}
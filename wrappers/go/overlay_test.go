@@ -0,0 +1,93 @@
+package sssp
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestLayersStackAndSwap(t *testing.T) {
+	g, err := FromEdges(3, []Edge{{0, 1, 1}, {1, 2, 1}, {0, 2, 5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	traffic := NewOverlay("traffic")
+	traffic.Set(0, 1, Adjustment{Mul: 3})
+	penalty := NewOverlay("penalty")
+	penalty.Set(0, 1, Adjustment{Add: 1})
+	penalty.Set(7, 9, Adjustment{Add: 1}) // not in the graph
+	l := NewLayers(g, traffic, penalty)
+
+	res, err := l.RunQuery(0, ModeBaseline, QueryOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := res.Dist[1]; got != 4 { // 1*3 + 1
+		t.Fatalf("dist to 1 = %v, want 4", got)
+	}
+	if got := res.Dist[2]; got != 5 {
+		t.Fatalf("dist to 2 = %v, want 5", got)
+	}
+	if w, _ := g.EdgeWeight(0, 1); w != 1 {
+		t.Fatalf("base weight changed to %v", w)
+	}
+	if l.Graph() != l.Graph() {
+		t.Fatalf("view rebuilt without a change")
+	}
+
+	clear := NewOverlay("traffic")
+	l.Replace("traffic", clear)
+	if got := l.Graph().weights[0]; got != 2 {
+		t.Fatalf("after swap weight %v, want 2", got)
+	}
+	l.Replace("penalty", nil)
+	if l.Graph() != g || len(l.Stack()) != 1 {
+		t.Fatalf("empty stack should give the base graph")
+	}
+
+	closed := NewOverlay("closures")
+	closed.Set(1, 2, Adjustment{Add: float32(math.Inf(1))})
+	l.Set(closed)
+	if err := g.UpdateEdgeWeights([]Edge{{0, 2, 9}}); err != nil {
+		t.Fatal(err)
+	}
+	res, err = l.RunQuery(0, ModeBaseline, QueryOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Dist[2] != 9 {
+		t.Fatalf("view missed a base update: dist %v", res.Dist[2])
+	}
+}
+
+func TestLayersConcurrentSwap(t *testing.T) {
+	g, err := FromEdges(2, []Edge{{0, 1, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slow := NewOverlay("traffic")
+	slow.Set(0, 1, Adjustment{Mul: 2})
+	l := NewLayers(g)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				res, err := l.RunQuery(0, ModeBaseline, QueryOptions{})
+				if err != nil || (res.Dist[1] != 1 && res.Dist[1] != 2) {
+					t.Errorf("dist %v err %v", res.Dist[1], err)
+					return
+				}
+			}
+		}()
+	}
+	for j := 0; j < 200; j++ {
+		if j%2 == 0 {
+			l.Replace("traffic", slow)
+		} else {
+			l.Replace("traffic", nil)
+		}
+	}
+	wg.Wait()
+}
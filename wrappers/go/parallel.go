@@ -0,0 +1,653 @@
+package sssp
+
+import (
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ParallelOptions configure RunParallel.
+type ParallelOptions struct {
+	// Workers relaxing edges concurrently; 0 means GOMAXPROCS.
+	Workers int
+	// Delta is the bucket width; 0 derives it from the weights as ModeStoc
+	// does on the pure-Go engine.
+	Delta float32
+	// Stats, if set, receives per-worker scheduling counters for tuning
+	// Workers on a given graph.
+	Stats *ParallelStats
+	// NUMA spreads the run over the machine's NUMA nodes. Each node holds
+	// one contiguous range of graph nodes with their edges and distances;
+	// each worker is pinned to the CPUs of one node, starts on the frontier
+	// nodes held there, and steals from workers on the same node first.
+	// Linux only; elsewhere, or with one node, it changes nothing. Placement
+	// is advice: memory the kernel cannot move stays where it is.
+	NUMA bool
+}
+
+// ParallelStats describe how RunParallel shared its work.
+type ParallelStats struct {
+	Workers []WorkerStats
+	// Imbalance is the most edges any worker scanned over the mean, 1 when
+	// evenly shared; 0 if no edges were scanned.
+	Imbalance float64
+	// NUMANodes is the number of NUMA nodes the run was spread over, 1
+	// without NUMA.
+	NUMANodes int
+}
+
+// WorkerStats count one RunParallel worker's work. Small rounds run on
+// worker 0 alone and count towards it.
+type WorkerStats struct {
+	Tasks         uint64 // chunks and hub edge ranges processed
+	Steals        uint64 // tasks taken from other workers
+	StealAttempts uint64 // other workers' deques probed for a task
+	Splits        uint64 // hubs whose edges were split into tasks
+	Nodes         uint64 // nodes expanded
+	Edges         uint64 // edges scanned
+	NUMANode      int    // NUMA node the worker was pinned to, -1 if none
+}
+
+// RunParallel runs delta-stepping on the pure-Go engine with several
+// workers, for machines with many cores and no native library, or where the
+// native build is single-threaded. Distances and predecessors live in one
+// shared array of packed words updated with compare-and-swap, so a
+// relaxation never takes a lock; each worker queues the nodes it improves
+// in its own buckets, and a round starts by handing the workers those lists
+// and advancing an atomic epoch, never by merging them. The frontier of
+// each round is cut into chunks shared out among per-worker deques; idle
+// workers steal chunks, and the edges of hubs are split into tasks of their
+// own, so a few huge nodes do not leave one worker busy while the rest
+// wait.
+//
+// Distances equal those of Run; among equal-length paths the predecessor
+// chosen may differ between runs. Stats.Version is 0. A distance past the
+// last bucket (see maxBucket) stops the workers, and the query is rerun
+// with Dijkstra and reported through Stats.Fallback, as in ModeStoc.
+func (g *Graph) RunParallel(source uint32, opts ParallelOptions) (Result, error) {
+	n := g.NodeCount()
+	if err := checkGoRun(n, source, ModeStoc); err != nil {
+		return Result{}, err
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	delta := opts.Delta
+	if delta <= 0 {
+		delta = avgWeight(g.weights) * envFloat("SSSP_STOC_DELTA_MULT", 3)
+	}
+	delta = clampDelta(delta)
+	var nodes []numaNode
+	if opts.NUMA {
+		nodes = numaNodes()
+	}
+	p := newParallelRun(g, workers, delta)
+	p.placeNUMA(nodes)
+	p.start()
+	p.run(source)
+	p.stop()
+	res := Result{Dist: make([]float32, n), Pred: make([]int32, n), graph: g}
+	for v, w := range p.state {
+		res.Dist[v], res.Pred[v] = unpackState(w)
+	}
+	var ops OpCounters
+	var light, heavy uint64
+	for i := range p.workers {
+		ops.Add(p.workers[i].ops)
+		light += p.workers[i].light
+		heavy += p.workers[i].heavy
+	}
+	settled, overflow := p.settled, p.overflow.Load()
+	if overflow {
+		ops.Add(goDijkstra(g.offsets, g.targets, g.weights, source, res.Dist, res.Pred, nil))
+		settled = n
+	}
+	res.Stats = Stats{Relaxations: ops.Improvements, LightRelaxations: light, HeavyRelaxations: heavy,
+		Settled: settled, Delta: delta, Fallback: overflow, Ops: ops}
+	if opts.Stats != nil {
+		*opts.Stats = p.stats()
+	}
+	p.release()
+	return res, nil
+}
+
+// release hands the per-node arrays back to their pools once the workers
+// have stopped and the result is copied out.
+func (p *parallelRun) release() {
+	putRow(p.stateRow)
+	putRow(p.seenRow)
+	putRow(p.doneRow)
+	p.state, p.seen, p.done = nil, nil, nil
+}
+
+func (p *parallelRun) stats() ParallelStats {
+	st := ParallelStats{Workers: make([]WorkerStats, len(p.workers)), NUMANodes: max(len(p.bounds)-1, 1)}
+	var total, most uint64
+	for i := range p.workers {
+		st.Workers[i] = p.workers[i].sched
+		st.Workers[i].NUMANode = -1
+		if p.numaOf != nil {
+			st.Workers[i].NUMANode = p.numaIDs[p.numaOf[i]]
+		}
+		total += p.workers[i].sched.Edges
+		most = max(most, p.workers[i].sched.Edges)
+	}
+	if total > 0 {
+		st.Imbalance = float64(most) * float64(len(p.workers)) / float64(total)
+	}
+	return st
+}
+
+// A node's state packs its distance's float bits above its predecessor, so
+// one compare-and-swap updates both. Non-negative floats order like their
+// bits, so comparing distances needs no unpacking.
+func packState(dist float32, pred int32) uint64 {
+	return uint64(math.Float32bits(dist))<<32 | uint64(uint32(pred))
+}
+
+func unpackState(s uint64) (float32, int32) {
+	return math.Float32frombits(uint32(s >> 32)), int32(uint32(s))
+}
+
+// parallelWorker is one worker's private state. Only its owner appends to
+// its buckets during a round; between rounds worker 0 hands the lists of
+// the next round out, so no bucket needs a lock.
+type parallelWorker struct {
+	// buckets[k][b] holds the nodes of bucket b on NUMA node k (k is 0
+	// without NUMA placement) that this worker improved.
+	buckets [][][]uint32
+	// low is the lowest bucket that may hold nodes; all below are empty.
+	low     int
+	input   [][]uint32 // the lists this worker starts the round with
+	settled []uint32   // nodes this worker settled in the current bucket
+	spare   [][]uint32 // lists of finished rounds, emptied for reuse
+	deque   taskDeque
+	ops     OpCounters
+	sched   WorkerStats
+	light   uint64
+	heavy   uint64
+}
+
+// parallelRun is the shared state of one RunParallel call.
+type parallelRun struct {
+	off, tgt []uint32
+	wts      []float32
+	delta    float32
+	state    []uint64
+	// seen[v] is the last round that expanded v and done[v] the last bucket
+	// (plus one) that settled it; both are claimed by compare-and-swap so
+	// one worker handles each.
+	seen, done []uint32
+	// The pool handles of state, seen and done; see release.
+	stateRow         *[]uint64
+	seenRow, doneRow *[]uint32
+	workers          []parallelWorker
+	settled          uint32
+	// Worker 0 runs the rounds and takes part in them. It sets cur, round
+	// and light, then advances epoch; the other workers wait for the epoch
+	// to change, work through the round and decrement active. starting
+	// counts workers still queuing their input and pending the tasks queued
+	// but not finished: a worker leaves the round once both are zero.
+	cur      int    // bucket being processed
+	round    uint32 // light-phase round, for seen
+	light    bool
+	epoch    atomic.Uint64
+	active   atomic.Int64
+	starting atomic.Int64
+	pending  atomic.Int64
+	quit     atomic.Bool
+	helpers  sync.WaitGroup
+	// overflow is set by the first relaxation past maxBucket; search then
+	// stops after the round.
+	overflow atomic.Bool
+	// victims[i] lists the workers worker i steals from, in order.
+	victims [][]int
+	// With NUMA placement, graph nodes [bounds[k], bounds[k+1]) live on the
+	// k-th NUMA node, whose kernel ID is numaIDs[k]; numaOf[i] is worker
+	// i's node, members[k] the workers on node k (all workers, without
+	// NUMA) and cpus[i] the CPUs worker i is pinned to.
+	bounds  []uint32
+	numaIDs []int
+	numaOf  []int
+	members [][]int
+	cpus    [][]int
+}
+
+func newParallelRun(g *Graph, workers int, delta float32) *parallelRun {
+	n := g.NodeCount()
+	p := &parallelRun{off: g.offsets, tgt: g.targets, wts: g.weights, delta: delta,
+		workers: make([]parallelWorker, workers)}
+	p.stateRow, p.state = getRow[uint64](int(n))
+	p.seenRow, p.seen = getRow[uint32](int(n))
+	p.doneRow, p.done = getRow[uint32](int(n))
+	clear(p.seen)
+	clear(p.done)
+	inf := packState(Unreachable, -1)
+	for v := range p.state {
+		p.state[v] = inf
+	}
+	p.members = [][]int{make([]int, workers)}
+	p.victims = make([][]int, workers)
+	for i := range p.victims {
+		p.members[0][i] = i
+		p.workers[i].buckets = make([][][]uint32, 1)
+		for k := 1; k < workers; k++ {
+			p.victims[i] = append(p.victims[i], (i+k)%workers)
+		}
+	}
+	return p
+}
+
+// relax lowers v's distance to nd through u if that is an improvement, and
+// queues v in worker w's bucket for nd.
+func (p *parallelRun) relax(w *parallelWorker, u, v uint32, nd float32) bool {
+	w.ops.EdgeRelaxations++
+	b := nd / p.delta
+	if !(b <= float32(maxBucket(len(p.state)))) {
+		p.overflow.Store(true)
+		return false
+	}
+	next := packState(nd, int32(u))
+	for {
+		old := atomic.LoadUint64(&p.state[v])
+		if next>>32 >= old>>32 {
+			return false
+		}
+		if atomic.CompareAndSwapUint64(&p.state[v], old, next) {
+			break
+		}
+	}
+	w.ops.Improvements++
+	w.queue(p.home(v), int(b), v)
+	return true
+}
+
+// queue appends v to the worker's bucket b of NUMA node k.
+func (w *parallelWorker) queue(k, b int, v uint32) {
+	list := w.buckets[k]
+	for b >= len(list) {
+		list = append(list, nil)
+	}
+	if list[b] == nil {
+		list[b] = w.list()
+	}
+	list[b] = append(list[b], v)
+	w.buckets[k] = list
+	w.low = min(w.low, b)
+}
+
+// list returns an empty list, reusing a spare one if there is any.
+func (w *parallelWorker) list() []uint32 {
+	if k := len(w.spare); k > 0 {
+		l := w.spare[k-1]
+		w.spare = w.spare[:k-1]
+		return l
+	}
+	return nil
+}
+
+// recycle empties the input of a finished round into the spare lists.
+// Between rounds no task refers to them any more.
+func (w *parallelWorker) recycle() {
+	for _, in := range w.input {
+		if in != nil {
+			w.spare = append(w.spare, in[:0])
+		}
+	}
+	w.input = w.input[:0]
+}
+
+func (p *parallelRun) dist(v uint32) float32 {
+	d, _ := unpackState(atomic.LoadUint64(&p.state[v]))
+	return d
+}
+
+func (p *parallelRun) run(source uint32) {
+	if p.cpus == nil {
+		p.search(source)
+		return
+	}
+	// Worker 0 runs on a thread of its own, pinned like the others and
+	// ended with its goroutine, so the caller's thread keeps its affinity.
+	done := make(chan struct{})
+	go func() {
+		runtime.LockOSThread()
+		pinThread(p.cpus[0])
+		p.search(source)
+		close(done)
+	}()
+	<-done
+}
+
+// search runs the buckets in order: light rounds until the bucket stays
+// empty, then one heavy round over the nodes it settled.
+func (p *parallelRun) search(source uint32) {
+	p.state[source] = packState(0, -1)
+	p.workers[0].queue(p.home(source), 0, source)
+	for !p.overflow.Load() {
+		cur := p.nextBucket()
+		if cur < 0 {
+			return
+		}
+		p.cur = cur
+		for !p.overflow.Load() && p.take(cur) {
+			p.round++
+			p.workers[0].ops.BucketScans++
+			p.runRound(true)
+		}
+		for i := range p.workers {
+			w := &p.workers[i]
+			p.settled += uint32(len(w.settled))
+			w.recycle()
+			w.input = append(w.input, w.settled)
+			w.settled = w.list()
+		}
+		p.runRound(false)
+	}
+}
+
+// nextBucket returns the lowest bucket any worker holds nodes in, or -1.
+// Buckets are emptied in order, so each worker's low mark only moves up
+// past empty buckets.
+func (p *parallelRun) nextBucket() int {
+	next := -1
+	for i := range p.workers {
+		w := &p.workers[i]
+		for ; ; w.low++ {
+			empty, end := true, true
+			for _, list := range w.buckets {
+				if w.low < len(list) {
+					end = false
+					empty = empty && len(list[w.low]) == 0
+				}
+			}
+			if end {
+				w.low = math.MaxInt
+				break
+			}
+			if !empty {
+				break
+			}
+		}
+		if w.low != math.MaxInt && (next < 0 || w.low < next) {
+			next = w.low
+		}
+	}
+	return next
+}
+
+// take hands bucket b out as the input of the next light round, reporting
+// false if it is empty. The lists of NUMA node k go to the workers on k,
+// each worker's lists staying with one of them.
+func (p *parallelRun) take(b int) bool {
+	found := false
+	for i := range p.workers {
+		p.workers[i].recycle()
+	}
+	for i := range p.workers {
+		for k, list := range p.workers[i].buckets {
+			if b < len(list) && len(list[b]) > 0 {
+				m := p.members[k]
+				to := &p.workers[m[i%len(m)]]
+				to.input = append(to.input, list[b])
+				list[b], found = nil, true
+			}
+		}
+	}
+	return found
+}
+
+// runRound runs one light or heavy round over the workers' inputs. Rounds
+// with little work run on worker 0 alone.
+func (p *parallelRun) runRound(light bool) {
+	p.light = light
+	var items, work int
+	for i := range p.workers {
+		for _, in := range p.workers[i].input {
+			items += len(in)
+			if items <= parallelChunk {
+				for _, u := range in {
+					work += int(p.off[u+1] - p.off[u])
+				}
+			}
+		}
+	}
+	if len(p.workers) == 1 || (items <= parallelChunk && work+items <= parallelSplit) {
+		w := &p.workers[0]
+		for i := range p.workers {
+			for _, in := range p.workers[i].input {
+				for _, u := range in {
+					if du, ok := p.begin(w, u, light); ok {
+						w.sched.Nodes++
+						p.scan(w, u, du, p.off[u], p.off[u+1], light)
+					}
+				}
+			}
+		}
+		return
+	}
+	p.starting.Store(int64(len(p.workers)))
+	p.active.Store(int64(len(p.workers) - 1))
+	p.epoch.Add(1)
+	p.drain(0)
+	for spins := 0; p.active.Load() > 0; spins++ {
+		idle(spins)
+	}
+}
+
+// idle backs off while a worker waits: it yields at first, then sleeps
+// briefly so long serial stretches do not keep every core spinning.
+func idle(spins int) {
+	if spins < 64 {
+		runtime.Gosched()
+	} else {
+		time.Sleep(20 * time.Microsecond)
+	}
+}
+
+// begin claims u for expansion in the current phase and returns its
+// distance, or false if another worker or an earlier bucket has it. The
+// light phase expands each node of the bucket once per round and records
+// it as settled; the heavy phase expands every settled node.
+func (p *parallelRun) begin(w *parallelWorker, u uint32, light bool) (float32, bool) {
+	if !light {
+		return p.dist(u), true
+	}
+	if s := atomic.LoadUint32(&p.seen[u]); s == p.round || !atomic.CompareAndSwapUint32(&p.seen[u], s, p.round) {
+		return 0, false // another copy of u in this frontier
+	}
+	du := p.dist(u)
+	if int(du/p.delta) != p.cur {
+		return 0, false // stale: u moved to an earlier bucket
+	}
+	w.ops.FrontierExpansions++
+	if d := atomic.LoadUint32(&p.done[u]); d != uint32(p.cur+1) && atomic.CompareAndSwapUint32(&p.done[u], d, uint32(p.cur+1)) {
+		w.settled = append(w.settled, u)
+	}
+	return du, true
+}
+
+// scan relaxes u's light or heavy edges in [lo, hi).
+func (p *parallelRun) scan(w *parallelWorker, u uint32, du float32, lo, hi uint32, light bool) {
+	w.sched.Edges += uint64(hi - lo)
+	for e := lo; e < hi; e++ {
+		wt := p.wts[e]
+		if light {
+			if wt <= p.delta && p.relax(w, u, p.tgt[e], du+wt) {
+				w.light++
+			}
+		} else if wt > p.delta && !math.IsInf(float64(wt), 1) && p.relax(w, u, p.tgt[e], du+wt) {
+			w.heavy++
+		}
+	}
+}
+
+// parallelChunk is the number of frontier nodes in one task, and
+// parallelSplit the number of edges above which a node's edges are split
+// into tasks of their own, so a hub can be shared among workers.
+const (
+	parallelChunk = 256
+	parallelSplit = 1024
+)
+
+// parallelTask is a unit of work: a chunk of frontier nodes, or, for a
+// split hub, edges [lo, hi) of node at distance du.
+type parallelTask struct {
+	nodes  []uint32
+	lo, hi uint32
+	node   uint32
+	du     float32
+	edges  bool
+}
+
+// taskDeque is one worker's queue of tasks. The owner pushes and pops at
+// the back; thieves take from the front, where the largest remaining
+// blocks of the worker's share sit.
+type taskDeque struct {
+	mu    sync.Mutex
+	tasks []parallelTask
+}
+
+func (d *taskDeque) push(t parallelTask) {
+	d.mu.Lock()
+	d.tasks = append(d.tasks, t)
+	d.mu.Unlock()
+}
+
+func (d *taskDeque) pop() (parallelTask, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.tasks) == 0 {
+		return parallelTask{}, false
+	}
+	t := d.tasks[len(d.tasks)-1]
+	d.tasks = d.tasks[:len(d.tasks)-1]
+	return t, true
+}
+
+func (d *taskDeque) steal() (parallelTask, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.tasks) == 0 {
+		return parallelTask{}, false
+	}
+	t := d.tasks[0]
+	d.tasks = d.tasks[1:]
+	return t, true
+}
+
+// start launches workers 1 and up; worker 0 is the goroutine running the
+// search.
+func (p *parallelRun) start() {
+	for i := 1; i < len(p.workers); i++ {
+		p.helpers.Add(1)
+		go p.work(i)
+	}
+}
+
+// stop ends the workers.
+func (p *parallelRun) stop() {
+	p.quit.Store(true)
+	p.helpers.Wait()
+}
+
+// work runs worker i's rounds until stop.
+func (p *parallelRun) work(i int) {
+	defer p.helpers.Done()
+	if p.cpus != nil {
+		// The thread stays locked until the goroutine exits, which ends the
+		// thread, so its narrowed affinity never passes to other goroutines.
+		runtime.LockOSThread()
+		pinThread(p.cpus[i])
+	}
+	var seen uint64
+	for spins := 0; ; spins++ {
+		if e := p.epoch.Load(); e != seen {
+			seen, spins = e, 0
+			p.drain(i)
+			p.active.Add(-1)
+			continue
+		}
+		if p.quit.Load() {
+			return
+		}
+		idle(spins)
+	}
+}
+
+// drain queues worker i's input as chunks, back to front so the owner
+// starts at the front and thieves take from the back, then runs tasks, its
+// own and then stolen ones, until the round has none left.
+func (p *parallelRun) drain(i int) {
+	w := &p.workers[i]
+	for j := len(w.input) - 1; j >= 0; j-- {
+		in := w.input[j]
+		for c := len(in); c > 0; c -= parallelChunk {
+			p.pending.Add(1)
+			w.deque.push(parallelTask{nodes: in[max(c-parallelChunk, 0):c]})
+		}
+	}
+	p.starting.Add(-1)
+	for spins := 0; ; {
+		t, ok := w.deque.pop()
+		if !ok {
+			t, ok = p.steal(i)
+		}
+		if !ok {
+			if p.starting.Load() == 0 && p.pending.Load() == 0 {
+				return
+			}
+			idle(spins)
+			spins++
+			continue
+		}
+		spins = 0
+		w.sched.Tasks++
+		if t.edges {
+			p.scan(w, t.node, t.du, t.lo, t.hi, p.light)
+		} else {
+			for _, u := range t.nodes {
+				p.expand(w, u, p.light)
+			}
+		}
+		p.pending.Add(-1)
+	}
+}
+
+// expand expands u for worker w. A hub's edges beyond the first
+// parallelSplit go to w's deque as separate tasks, where idle workers can
+// steal them.
+func (p *parallelRun) expand(w *parallelWorker, u uint32, light bool) {
+	du, ok := p.begin(w, u, light)
+	if !ok {
+		return
+	}
+	w.sched.Nodes++
+	lo, hi := p.off[u], p.off[u+1]
+	if hi-lo > parallelSplit {
+		for e := lo + parallelSplit; e < hi; e += parallelSplit {
+			p.pending.Add(1)
+			w.deque.push(parallelTask{lo: e, hi: min(e+parallelSplit, hi), node: u, du: du, edges: true})
+		}
+		w.sched.Splits++
+		hi = lo + parallelSplit
+	}
+	p.scan(w, u, du, lo, hi, light)
+}
+
+// steal takes a task from another worker's deque, trying them in the
+// order of victims[i].
+func (p *parallelRun) steal(i int) (parallelTask, bool) {
+	w := &p.workers[i]
+	for _, v := range p.victims[i] {
+		w.sched.StealAttempts++
+		if t, ok := p.workers[v].deque.steal(); ok {
+			w.sched.Steals++
+			return t, true
+		}
+	}
+	return parallelTask{}, false
+}
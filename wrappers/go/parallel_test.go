@@ -0,0 +1,153 @@
+package sssp
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func randomGraph(t *testing.T, n uint32, m int, seed int64) *Graph {
+	t.Helper()
+	rng := rand.New(rand.NewSource(seed))
+	edges := make([]Edge, m)
+	for i := range edges {
+		edges[i] = Edge{uint32(rng.Intn(int(n))), uint32(rng.Intn(int(n))), float32(rng.Intn(100)) / 10}
+	}
+	g, err := FromEdges(n, edges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestRunParallelMatchesDijkstra(t *testing.T) {
+	g := randomGraph(t, 5000, 40000, 1)
+	want, err := g.RunSettled(0, ModeBaseline, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, opts := range []ParallelOptions{{Workers: 1}, {Workers: 4}, {Workers: 8, Delta: 0.5}, {Workers: 3, Delta: 50}} {
+		res, err := g.RunParallel(0, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for v := range want.Dist {
+			if res.Dist[v] != want.Dist[v] {
+				t.Fatalf("%+v: dist[%d] = %v, want %v", opts, v, res.Dist[v], want.Dist[v])
+			}
+			if p := res.Pred[v]; p >= 0 && res.Dist[p] > res.Dist[v] {
+				t.Fatalf("%+v: pred of %d is farther", opts, v)
+			}
+		}
+		if reached := uint32(len(want.ReachableNodes())); res.Stats.Settled != reached {
+			t.Fatalf("%+v: settled %d, reachable %d", opts, res.Stats.Settled, reached)
+		}
+	}
+	if _, err := g.RunParallel(5000, ParallelOptions{}); err == nil {
+		t.Fatalf("out-of-range source accepted")
+	}
+}
+
+func TestRunParallelSplitsHubs(t *testing.T) {
+	// Node 0 is a hub reaching every node; node 1 a second hub one step on.
+	const n = 20000
+	var edges []Edge
+	for v := uint32(1); v < n; v++ {
+		edges = append(edges, Edge{0, v, 5}, Edge{1, v, float32(v%7) / 2})
+	}
+	g, err := FromEdges(n, edges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := g.RunSettled(0, ModeBaseline, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st ParallelStats
+	res, err := g.RunParallel(0, ParallelOptions{Workers: 4, Delta: 1, Stats: &st})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for v := range want.Dist {
+		if res.Dist[v] != want.Dist[v] {
+			t.Fatalf("dist[%d] = %v, want %v", v, res.Dist[v], want.Dist[v])
+		}
+	}
+	if len(st.Workers) != 4 {
+		t.Fatalf("%d worker stats, want 4", len(st.Workers))
+	}
+	var splits, nodes, edgesScanned uint64
+	for _, w := range st.Workers {
+		splits += w.Splits
+		nodes += w.Nodes
+		edgesScanned += w.Edges
+	}
+	if splits == 0 || nodes == 0 || edgesScanned < 2*(n-1) {
+		t.Fatalf("stats %+v: want hub splits and every edge scanned", st)
+	}
+	if st.Imbalance < 1 || st.Imbalance > 4 {
+		t.Fatalf("imbalance %v outside [1, 4]", st.Imbalance)
+	}
+}
+
+func TestParallelBucketHandoff(t *testing.T) {
+	g := randomGraph(t, 100, 400, 4)
+	p := newParallelRun(g, 4, 1)
+	p.placeNUMA([]numaNode{{id: 0, cpus: []int{0}}, {id: 1, cpus: []int{0}}})
+	lo, hi := uint32(0), p.bounds[1] // a node on each NUMA node
+	p.workers[3].queue(p.home(lo), 5, lo)
+	p.workers[1].queue(p.home(hi), 2, hi)
+	p.workers[2].queue(p.home(hi), 7, hi)
+	if b := p.nextBucket(); b != 2 {
+		t.Fatalf("next bucket %d, want 2", b)
+	}
+	if !p.take(2) {
+		t.Fatalf("bucket 2 found empty")
+	}
+	// Node hi lives on NUMA node 1, whose workers are 2 and 3; worker 1's
+	// list goes to the member at position 1 % 2.
+	if in := p.workers[3].input; len(in) != 1 || in[0][0] != hi {
+		t.Fatalf("worker 3 input %v, want [[%d]]", in, hi)
+	}
+	if p.take(2) {
+		t.Fatalf("bucket 2 handed out twice")
+	}
+	if b := p.nextBucket(); b != 5 {
+		t.Fatalf("next bucket %d after taking 2, want 5", b)
+	}
+	p.take(5)
+	p.take(7)
+	if b := p.nextBucket(); b != -1 {
+		t.Fatalf("next bucket %d with all taken, want -1", b)
+	}
+}
+
+func TestRunParallelFallsBackPastBucketCap(t *testing.T) {
+	const n = 64
+	var edges []Edge
+	for u := uint32(0); u+2 < n; u++ {
+		edges = append(edges, Edge{u, u + 1, 1})
+	}
+	edges = append(edges, Edge{n - 2, n - 1, 1e20})
+	g, err := FromEdges(n, edges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := g.RunSettled(0, ModeBaseline, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, workers := range []int{1, 4} {
+		res, err := g.RunParallel(0, ParallelOptions{Workers: workers, Delta: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Stats.Fallback || res.Stats.Settled != n {
+			t.Fatalf("workers %d: stats %+v, want a fallback", workers, res.Stats)
+		}
+		for v := range want.Dist {
+			if res.Dist[v] != want.Dist[v] {
+				t.Fatalf("workers %d node %d: got %v want %v", workers, v, res.Dist[v], want.Dist[v])
+			}
+		}
+	}
+}
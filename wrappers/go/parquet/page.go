@@ -0,0 +1,365 @@
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/your-org/optimized-sssp-go/internal/thrift"
+)
+
+// Physical types.
+const (
+	typeBoolean   = 0
+	typeInt32     = 1
+	typeInt64     = 2
+	typeInt96     = 3
+	typeFloat     = 4
+	typeDouble    = 5
+	typeByteArray = 6
+	typeFixed     = 7
+)
+
+// Page types and encodings.
+const (
+	pageData       = 0
+	pageDictionary = 2
+	pageDataV2     = 3
+
+	encPlain           = 0
+	encPlainDictionary = 2
+	encRLEDictionary   = 8
+)
+
+var codecNames = map[int64]string{0: "UNCOMPRESSED", 1: "SNAPPY", 2: "GZIP", 3: "LZO", 4: "BROTLI", 5: "LZ4", 6: "ZSTD", 7: "LZ4_RAW"}
+
+// leaf is a top-level primitive column of the schema.
+type leaf struct {
+	name     string
+	typ      int64
+	typeLen  int
+	optional bool
+}
+
+// column holds decoded values of one column chunk; exactly one of ints,
+// floats and strs is used, selected by the physical type.
+type column struct {
+	ints   []int64
+	floats []float64
+	strs   [][]byte
+	valid  []bool
+}
+
+func (c *column) len() int { return len(c.valid) }
+
+// number returns row i as a float64 (numeric columns only).
+func (c *column) number(i int) float64 {
+	if c.floats != nil {
+		return c.floats[i]
+	}
+	return float64(c.ints[i])
+}
+
+func isIntType(t int64) bool   { return t == typeInt32 || t == typeInt64 }
+func isFloatType(t int64) bool { return t == typeFloat || t == typeDouble }
+func isBytesType(t int64) bool { return t == typeByteArray || t == typeFixed }
+
+func decompress(codec int64, data []byte, size int) ([]byte, error) {
+	switch codec {
+	case 0:
+		return data, nil
+	case 1:
+		return snappyDecode(data)
+	case 2:
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		out := make([]byte, size)
+		if _, err := io.ReadFull(zr, out); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("parquet: compression %s is not supported", codecNames[codec])
+}
+
+// plainDecode appends count PLAIN-encoded values from data to c and returns
+// the bytes consumed.
+func plainDecode(data []byte, lf leaf, count int, c *column) (int, error) {
+	pos := 0
+	need := func(n int) error {
+		if len(data)-pos < n {
+			return fmt.Errorf("parquet: column %s: page ends early", lf.name)
+		}
+		return nil
+	}
+	for i := 0; i < count; i++ {
+		switch lf.typ {
+		case typeInt32:
+			if err := need(4); err != nil {
+				return 0, err
+			}
+			c.ints = append(c.ints, int64(int32(binary.LittleEndian.Uint32(data[pos:]))))
+			pos += 4
+		case typeInt64:
+			if err := need(8); err != nil {
+				return 0, err
+			}
+			c.ints = append(c.ints, int64(binary.LittleEndian.Uint64(data[pos:])))
+			pos += 8
+		case typeFloat:
+			if err := need(4); err != nil {
+				return 0, err
+			}
+			c.floats = append(c.floats, float64(math.Float32frombits(binary.LittleEndian.Uint32(data[pos:]))))
+			pos += 4
+		case typeDouble:
+			if err := need(8); err != nil {
+				return 0, err
+			}
+			c.floats = append(c.floats, math.Float64frombits(binary.LittleEndian.Uint64(data[pos:])))
+			pos += 8
+		case typeByteArray:
+			if err := need(4); err != nil {
+				return 0, err
+			}
+			n := int(binary.LittleEndian.Uint32(data[pos:]))
+			pos += 4
+			if err := need(n); err != nil {
+				return 0, err
+			}
+			c.strs = append(c.strs, data[pos:pos+n])
+			pos += n
+		case typeFixed:
+			if err := need(lf.typeLen); err != nil {
+				return 0, err
+			}
+			c.strs = append(c.strs, data[pos:pos+lf.typeLen])
+			pos += lf.typeLen
+		default:
+			return 0, fmt.Errorf("parquet: column %s: physical type %d is not supported", lf.name, lf.typ)
+		}
+	}
+	return pos, nil
+}
+
+// rleHybrid decodes count values of the RLE/bit-packing hybrid encoding.
+func rleHybrid(data []byte, bitWidth, count int) ([]uint32, error) {
+	if bitWidth > 32 {
+		return nil, fmt.Errorf("parquet: bit width %d", bitWidth)
+	}
+	out := make([]uint32, 0, count)
+	byteWidth := (bitWidth + 7) / 8
+	for len(out) < count {
+		h, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, fmt.Errorf("parquet: truncated RLE run")
+		}
+		data = data[n:]
+		if h&1 == 0 {
+			run := int(h >> 1)
+			if len(data) < byteWidth {
+				return nil, fmt.Errorf("parquet: truncated RLE value")
+			}
+			var v uint32
+			for i := byteWidth - 1; i >= 0; i-- {
+				v = v<<8 | uint32(data[i])
+			}
+			data = data[byteWidth:]
+			for i := 0; i < run && len(out) < count; i++ {
+				out = append(out, v)
+			}
+			continue
+		}
+		groups := int(h >> 1)
+		nbytes := groups * bitWidth // 8 values per group
+		if len(data) < nbytes {
+			return nil, fmt.Errorf("parquet: truncated bit-packed run")
+		}
+		for i := 0; i < groups*8 && len(out) < count; i++ {
+			var v uint32
+			for b := 0; b < bitWidth; b++ {
+				bit := i*bitWidth + b
+				v |= uint32(data[bit/8]>>(bit%8)&1) << b
+			}
+			out = append(out, v)
+		}
+		data = data[nbytes:]
+	}
+	return out, nil
+}
+
+// readChunk decodes a whole column chunk.
+func readChunk(chunk []byte, lf leaf, codec int64, numValues int64) (*column, error) {
+	c := &column{}
+	var dict *column
+	maxDef := 0
+	if lf.optional {
+		maxDef = 1
+	}
+	for int64(c.len()) < numValues {
+		hdr, hlen, err := thrift.ReadStruct(chunk)
+		if err != nil {
+			return nil, fmt.Errorf("parquet: column %s: page header: %w", lf.name, err)
+		}
+		ptype, _ := hdr.Int(1)
+		usize, _ := hdr.Int(2)
+		csize, _ := hdr.Int(3)
+		if csize < 0 || int64(len(chunk)-hlen) < csize {
+			return nil, fmt.Errorf("parquet: column %s: page overruns chunk", lf.name)
+		}
+		page := chunk[hlen : hlen+int(csize)]
+		chunk = chunk[hlen+int(csize):]
+		switch ptype {
+		case pageDictionary:
+			dh, _ := hdr.Struct(7)
+			n, _ := dh.Int(1)
+			data, err := decompress(codec, page, int(usize))
+			if err != nil {
+				return nil, err
+			}
+			dict = &column{}
+			if _, err := plainDecode(data, lf, int(n), dict); err != nil {
+				return nil, err
+			}
+		case pageData:
+			dh, _ := hdr.Struct(5)
+			n, _ := dh.Int(1)
+			enc, _ := dh.Int(2)
+			data, err := decompress(codec, page, int(usize))
+			if err != nil {
+				return nil, err
+			}
+			var defs []uint32
+			if maxDef > 0 {
+				if len(data) < 4 {
+					return nil, fmt.Errorf("parquet: column %s: missing definition levels", lf.name)
+				}
+				l := int(binary.LittleEndian.Uint32(data))
+				if l > len(data)-4 {
+					return nil, fmt.Errorf("parquet: column %s: definition levels overrun page", lf.name)
+				}
+				if defs, err = rleHybrid(data[4:4+l], 1, int(n)); err != nil {
+					return nil, err
+				}
+				data = data[4+l:]
+			}
+			if err := appendValues(c, data, lf, enc, int(n), defs, dict); err != nil {
+				return nil, err
+			}
+		case pageDataV2:
+			dh, _ := hdr.Struct(8)
+			n, _ := dh.Int(1)
+			enc, _ := dh.Int(4)
+			defLen, _ := dh.Int(5)
+			repLen, _ := dh.Int(6)
+			compressed := true
+			if v, ok := dh.Bool(7); ok {
+				compressed = v
+			}
+			if defLen < 0 || repLen < 0 || defLen+repLen > int64(len(page)) {
+				return nil, fmt.Errorf("parquet: column %s: bad level lengths", lf.name)
+			}
+			var defs []uint32
+			if maxDef > 0 {
+				if defs, err = rleHybrid(page[repLen:repLen+defLen], 1, int(n)); err != nil {
+					return nil, err
+				}
+			}
+			data := page[repLen+defLen:]
+			if compressed {
+				if data, err = decompress(codec, data, int(usize-defLen-repLen)); err != nil {
+					return nil, err
+				}
+			}
+			if err := appendValues(c, data, lf, enc, int(n), defs, dict); err != nil {
+				return nil, err
+			}
+		}
+		if len(chunk) == 0 && int64(c.len()) < numValues {
+			return nil, fmt.Errorf("parquet: column %s: chunk holds %d of %d values", lf.name, c.len(), numValues)
+		}
+	}
+	return c, nil
+}
+
+// appendValues decodes one data page's values into c, expanding nulls from
+// the definition levels (nil when the column is required).
+func appendValues(c *column, data []byte, lf leaf, enc int64, n int, defs []uint32, dict *column) error {
+	present := n
+	if defs != nil {
+		present = 0
+		for _, d := range defs {
+			if d == 1 {
+				present++
+			}
+		}
+	}
+	vals := &column{}
+	switch enc {
+	case encPlain:
+		if _, err := plainDecode(data, lf, present, vals); err != nil {
+			return err
+		}
+	case encPlainDictionary, encRLEDictionary:
+		if dict == nil {
+			return fmt.Errorf("parquet: column %s: dictionary page missing", lf.name)
+		}
+		if len(data) < 1 {
+			return fmt.Errorf("parquet: column %s: empty dictionary-encoded page", lf.name)
+		}
+		idx, err := rleHybrid(data[1:], int(data[0]), present)
+		if err != nil {
+			return err
+		}
+		size := len(dict.ints) + len(dict.floats) + len(dict.strs)
+		for _, i := range idx {
+			if int(i) >= size {
+				return fmt.Errorf("parquet: column %s: dictionary index %d out of range", lf.name, i)
+			}
+			switch {
+			case isIntType(lf.typ):
+				vals.ints = append(vals.ints, dict.ints[i])
+			case isFloatType(lf.typ):
+				vals.floats = append(vals.floats, dict.floats[i])
+			default:
+				vals.strs = append(vals.strs, dict.strs[i])
+			}
+		}
+	default:
+		return fmt.Errorf("parquet: column %s: encoding %d is not supported", lf.name, enc)
+	}
+	next := 0
+	for i := 0; i < n; i++ {
+		ok := defs == nil || defs[i] == 1
+		c.valid = append(c.valid, ok)
+		switch {
+		case isIntType(lf.typ):
+			v := int64(0)
+			if ok {
+				v = vals.ints[next]
+			}
+			c.ints = append(c.ints, v)
+		case isFloatType(lf.typ):
+			v := 0.0
+			if ok {
+				v = vals.floats[next]
+			}
+			c.floats = append(c.floats, v)
+		default:
+			var v []byte
+			if ok {
+				v = vals.strs[next]
+			}
+			c.strs = append(c.strs, v)
+		}
+		if ok {
+			next++
+		}
+	}
+	return nil
+}
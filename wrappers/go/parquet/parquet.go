@@ -0,0 +1,420 @@
+// Package parquet loads sssp graphs from Parquet edge tables.
+//
+// The reader is self-contained (no Arrow or Thrift dependency) and covers the
+// files typical data-lake writers produce for flat edge tables: top-level
+// required or optional INT32/INT64/FLOAT/DOUBLE/BYTE_ARRAY columns, PLAIN and
+// dictionary encodings, data pages v1 and v2, and UNCOMPRESSED, SNAPPY or
+// GZIP compression. Nested or repeated columns, DELTA encodings and ZSTD/LZ4
+// compression are reported as errors.
+//
+// Filters are pushed down: row groups whose column statistics prove that no
+// row can match are skipped without reading their pages.
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	sssp "github.com/your-org/optimized-sssp-go"
+	"github.com/your-org/optimized-sssp-go/internal/thrift"
+)
+
+// Op is a filter comparison.
+type Op int
+
+// Comparisons, applied as "column Op value".
+const (
+	Eq Op = iota
+	Lt
+	Le
+	Gt
+	Ge
+)
+
+// Filter keeps rows whose Column compares to Value. Value is a float64 for
+// numeric columns or a string for BYTE_ARRAY columns. Rows where the column
+// is null never match.
+type Filter struct {
+	Column string
+	Op     Op
+	Value  any
+}
+
+// Options selects the edge columns.
+type Options struct {
+	// Source and Target name integer node-id columns.
+	Source, Target string
+	// Weight names a numeric weight column; empty means every edge weighs 1.
+	// Null weights also become 1.
+	Weight string
+	// Filters must all match for a row to become an edge.
+	Filters []Filter
+	// NodeCount fixes the number of nodes; zero means max id + 1.
+	NodeCount uint32
+	// IDs, if set, numbers the ids through it in the order they are first
+	// seen, so any int64 id is accepted and a zero NodeCount means
+	// IDs.Len(). Reuse one mapper to load several tables over the same
+	// nodes.
+	IDs *sssp.IDMapper[int64]
+	// SparseIDs accepts a largest id far beyond what the edge count
+	// justifies (see sssp.CheckIDSpan) when NodeCount is zero; Load
+	// otherwise rejects it.
+	SparseIDs bool
+}
+
+// Stats reports how much pushdown saved.
+type Stats struct {
+	RowGroups, RowGroupsSkipped int
+	Rows, RowsMatched           int64
+}
+
+// LoadFile is Load on a file path.
+func LoadFile(path string, opts Options) (*sssp.Graph, Stats, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, Stats{}, err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return nil, Stats{}, err
+	}
+	return Load(f, st.Size(), opts)
+}
+
+// Load reads the edge table in r (size bytes long).
+func Load(r io.ReaderAt, size int64, opts Options) (*sssp.Graph, Stats, error) {
+	var st Stats
+	meta, err := readFooter(r, size)
+	if err != nil {
+		return nil, st, err
+	}
+	leaves, err := topLevelLeaves(meta.List(2))
+	if err != nil {
+		return nil, st, err
+	}
+	need := map[string]bool{opts.Source: true, opts.Target: true}
+	if opts.Weight != "" {
+		need[opts.Weight] = true
+	}
+	for _, f := range opts.Filters {
+		need[f.Column] = true
+	}
+	for name := range need {
+		lf, ok := leaves[name]
+		if !ok {
+			return nil, st, fmt.Errorf("parquet: no top-level column %q", name)
+		}
+		switch {
+		case (name == opts.Source || name == opts.Target) && !isIntType(lf.typ):
+			return nil, st, fmt.Errorf("parquet: id column %q must be INT32 or INT64", name)
+		case name == opts.Weight && !isIntType(lf.typ) && !isFloatType(lf.typ):
+			return nil, st, fmt.Errorf("parquet: weight column %q must be numeric", name)
+		}
+	}
+	for _, f := range opts.Filters {
+		lf := leaves[f.Column]
+		switch f.Value.(type) {
+		case float64:
+			if !isIntType(lf.typ) && !isFloatType(lf.typ) {
+				return nil, st, fmt.Errorf("parquet: filter on %q: numeric value for a non-numeric column", f.Column)
+			}
+		case string:
+			if !isBytesType(lf.typ) {
+				return nil, st, fmt.Errorf("parquet: filter on %q: string value for a non-string column", f.Column)
+			}
+		default:
+			return nil, st, fmt.Errorf("parquet: filter on %q: value must be float64 or string", f.Column)
+		}
+	}
+
+	var (
+		edges []sssp.Edge
+		maxID int64 = -1
+	)
+	for gi, rg := range meta.List(4) {
+		group, _ := rg.(thrift.Struct)
+		rows, _ := group.Int(3)
+		st.RowGroups++
+		st.Rows += rows
+		chunks := map[string]thrift.Struct{}
+		for _, c := range group.List(1) {
+			cc, _ := c.(thrift.Struct)
+			md, _ := cc.Struct(3)
+			if path := md.List(3); len(path) == 1 {
+				name, _ := path[0].([]byte)
+				chunks[string(name)] = md
+			}
+		}
+		if !mayMatch(opts.Filters, leaves, chunks) {
+			st.RowGroupsSkipped++
+			continue
+		}
+		cols := map[string]*column{}
+		for name := range need {
+			md, ok := chunks[name]
+			if !ok {
+				return nil, st, fmt.Errorf("parquet: row group %d has no chunk for %q", gi, name)
+			}
+			c, err := loadChunk(r, size, md, leaves[name])
+			if err != nil {
+				return nil, st, err
+			}
+			if int64(c.len()) != rows {
+				return nil, st, fmt.Errorf("parquet: row group %d: column %q has %d values for %d rows", gi, name, c.len(), rows)
+			}
+			cols[name] = c
+		}
+		src, dst := cols[opts.Source], cols[opts.Target]
+	row:
+		for i := 0; i < int(rows); i++ {
+			if !src.valid[i] || !dst.valid[i] {
+				continue
+			}
+			for _, f := range opts.Filters {
+				if !rowMatches(f, cols[f.Column], i) {
+					continue row
+				}
+			}
+			u, v := src.ints[i], dst.ints[i]
+			if opts.IDs != nil {
+				u, v = int64(opts.IDs.ID(u)), int64(opts.IDs.ID(v))
+			}
+			if u < 0 || v < 0 || u >= math.MaxUint32 || v >= math.MaxUint32 {
+				return nil, st, fmt.Errorf("parquet: row group %d row %d: node id out of range", gi, i)
+			}
+			w := float32(1)
+			if wc := cols[opts.Weight]; wc != nil && wc.valid[i] {
+				w = float32(wc.number(i))
+			}
+			maxID = max(maxID, u, v)
+			edges = append(edges, sssp.Edge{From: uint32(u), To: uint32(v), Weight: w})
+			st.RowsMatched++
+		}
+	}
+	n := opts.NodeCount
+	switch {
+	case n > 0:
+	case opts.IDs != nil:
+		n = opts.IDs.Len()
+	case maxID >= 0:
+		if err := sssp.CheckIDSpan(uint64(maxID), len(edges)); err != nil && !opts.SparseIDs {
+			return nil, st, fmt.Errorf("%w; set Options.IDs to number the ids densely, SparseIDs to keep them, or NodeCount", err)
+		}
+		n = uint32(maxID + 1)
+	}
+	g, err := sssp.FromEdges(n, edges)
+	return g, st, err
+}
+
+func readFooter(r io.ReaderAt, size int64) (thrift.Struct, error) {
+	if size < 12 {
+		return nil, fmt.Errorf("parquet: file too small")
+	}
+	var tail [8]byte
+	if _, err := r.ReadAt(tail[:], size-8); err != nil {
+		return nil, err
+	}
+	if string(tail[4:]) != "PAR1" {
+		return nil, fmt.Errorf("parquet: missing PAR1 footer")
+	}
+	mlen := int64(binary.LittleEndian.Uint32(tail[:4]))
+	if mlen > size-12 {
+		return nil, fmt.Errorf("parquet: footer length %d exceeds file", mlen)
+	}
+	buf := make([]byte, mlen)
+	if _, err := r.ReadAt(buf, size-8-mlen); err != nil {
+		return nil, err
+	}
+	meta, _, err := thrift.ReadStruct(buf)
+	if err != nil {
+		return nil, fmt.Errorf("parquet: file metadata: %w", err)
+	}
+	return meta, nil
+}
+
+// topLevelLeaves indexes the primitive children of the schema root. Group
+// columns are skipped along with their subtrees.
+func topLevelLeaves(schema []any) (map[string]leaf, error) {
+	el := func(i int) thrift.Struct {
+		s, _ := schema[i].(thrift.Struct)
+		return s
+	}
+	if len(schema) == 0 {
+		return nil, fmt.Errorf("parquet: empty schema")
+	}
+	var skip func(i int) (int, error)
+	skip = func(i int) (int, error) {
+		if i >= len(schema) {
+			return 0, fmt.Errorf("parquet: schema ends inside a group")
+		}
+		n, _ := el(i).Int(5)
+		i++
+		for k := int64(0); k < n; k++ {
+			var err error
+			if i, err = skip(i); err != nil {
+				return 0, err
+			}
+		}
+		return i, nil
+	}
+	out := map[string]leaf{}
+	children, _ := el(0).Int(5)
+	i := 1
+	for k := int64(0); k < children; k++ {
+		if i >= len(schema) {
+			return nil, fmt.Errorf("parquet: schema lists fewer columns than the root declares")
+		}
+		s := el(i)
+		if n, _ := s.Int(5); n > 0 {
+			var err error
+			if i, err = skip(i); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		name, _ := s.Bytes(4)
+		typ, _ := s.Int(1)
+		tlen, _ := s.Int(2)
+		rep, _ := s.Int(3)
+		if rep != 2 { // repeated leaves are not supported
+			out[string(name)] = leaf{name: string(name), typ: typ, typeLen: int(tlen), optional: rep == 1}
+		}
+		i++
+	}
+	return out, nil
+}
+
+func loadChunk(r io.ReaderAt, size int64, md thrift.Struct, lf leaf) (*column, error) {
+	codec, _ := md.Int(4)
+	numValues, _ := md.Int(5)
+	clen, _ := md.Int(7)
+	start, _ := md.Int(9)
+	if d, ok := md.Int(11); ok && d > 0 && d < start {
+		start = d
+	}
+	if start < 4 || clen < 0 || start+clen > size {
+		return nil, fmt.Errorf("parquet: column %s: chunk outside the file", lf.name)
+	}
+	buf := make([]byte, clen)
+	if _, err := r.ReadAt(buf, start); err != nil {
+		return nil, err
+	}
+	return readChunk(buf, lf, codec, numValues)
+}
+
+// statBounds returns a chunk's min and max statistics, preferring the
+// min_value/max_value fields over the deprecated min/max.
+func statBounds(md thrift.Struct) (lo, hi []byte, ok bool) {
+	s, found := md.Struct(12)
+	if !found {
+		return nil, nil, false
+	}
+	lo, okLo := s.Bytes(6)
+	hi, okHi := s.Bytes(5)
+	if okLo && okHi {
+		return lo, hi, true
+	}
+	lo, okLo = s.Bytes(2)
+	hi, okHi = s.Bytes(1)
+	return lo, hi, okLo && okHi
+}
+
+// mayMatch reports whether a row group can contain matching rows according
+// to its statistics.
+func mayMatch(filters []Filter, leaves map[string]leaf, chunks map[string]thrift.Struct) bool {
+	for _, f := range filters {
+		md, ok := chunks[f.Column]
+		if !ok {
+			continue
+		}
+		loB, hiB, ok := statBounds(md)
+		if !ok {
+			continue
+		}
+		lf := leaves[f.Column]
+		var cmpLo, cmpHi int // sign of (min - value) and (max - value)
+		switch v := f.Value.(type) {
+		case float64:
+			lo, okLo := statNumber(loB, lf.typ)
+			hi, okHi := statNumber(hiB, lf.typ)
+			if !okLo || !okHi {
+				continue
+			}
+			cmpLo, cmpHi = cmpFloat(lo, v), cmpFloat(hi, v)
+		case string:
+			cmpLo, cmpHi = bytes.Compare(loB, []byte(v)), bytes.Compare(hiB, []byte(v))
+		}
+		possible := true
+		switch f.Op {
+		case Eq:
+			possible = cmpLo <= 0 && cmpHi >= 0
+		case Lt:
+			possible = cmpLo < 0
+		case Le:
+			possible = cmpLo <= 0
+		case Gt:
+			possible = cmpHi > 0
+		case Ge:
+			possible = cmpHi >= 0
+		}
+		if !possible {
+			return false
+		}
+	}
+	return true
+}
+
+func statNumber(b []byte, typ int64) (float64, bool) {
+	switch {
+	case typ == typeInt32 && len(b) == 4:
+		return float64(int32(binary.LittleEndian.Uint32(b))), true
+	case typ == typeInt64 && len(b) == 8:
+		return float64(int64(binary.LittleEndian.Uint64(b))), true
+	case typ == typeFloat && len(b) == 4:
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b))), true
+	case typ == typeDouble && len(b) == 8:
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), true
+	}
+	return 0, false
+}
+
+func cmpFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func rowMatches(f Filter, c *column, i int) bool {
+	if !c.valid[i] {
+		return false
+	}
+	var cmp int
+	switch v := f.Value.(type) {
+	case float64:
+		cmp = cmpFloat(c.number(i), v)
+	case string:
+		cmp = bytes.Compare(c.strs[i], []byte(v))
+	}
+	switch f.Op {
+	case Eq:
+		return cmp == 0
+	case Lt:
+		return cmp < 0
+	case Le:
+		return cmp <= 0
+	case Gt:
+		return cmp > 0
+	case Ge:
+		return cmp >= 0
+	}
+	return false
+}
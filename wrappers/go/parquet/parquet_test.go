@@ -0,0 +1,298 @@
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	sssp "github.com/your-org/optimized-sssp-go"
+)
+
+// Minimal Thrift compact encoder for building fixtures.
+
+type tfield struct {
+	id int16
+	v  any // int32, int64, bool, string, []byte, tstruct, tlist
+}
+
+type tstruct []tfield
+
+type tlist struct {
+	et    byte
+	items []any
+}
+
+func ttype(v any) byte {
+	switch v := v.(type) {
+	case bool:
+		if v {
+			return 1
+		}
+		return 2
+	case int32:
+		return 5
+	case int64:
+		return 6
+	case string, []byte:
+		return 8
+	case tlist:
+		return 9
+	case tstruct:
+		return 12
+	}
+	panic("unsupported fixture type")
+}
+
+func tval(b []byte, v any) []byte {
+	zz := func(x int64) uint64 { return uint64(x<<1) ^ uint64(x>>63) }
+	switch v := v.(type) {
+	case bool:
+		if v {
+			return append(b, 1)
+		}
+		return append(b, 2)
+	case int32:
+		return binary.AppendUvarint(b, zz(int64(v)))
+	case int64:
+		return binary.AppendUvarint(b, zz(v))
+	case string:
+		return append(binary.AppendUvarint(b, uint64(len(v))), v...)
+	case []byte:
+		return append(binary.AppendUvarint(b, uint64(len(v))), v...)
+	case tlist:
+		if len(v.items) < 15 {
+			b = append(b, byte(len(v.items))<<4|v.et)
+		} else {
+			b = binary.AppendUvarint(append(b, 0xf0|v.et), uint64(len(v.items)))
+		}
+		for _, it := range v.items {
+			b = tval(b, it)
+		}
+		return b
+	case tstruct:
+		last := int16(0)
+		for _, f := range v {
+			typ := ttype(f.v)
+			if d := f.id - last; d > 0 && d <= 15 {
+				b = append(b, byte(d)<<4|typ)
+			} else {
+				b = binary.AppendUvarint(append(b, typ), zz(int64(f.id)))
+			}
+			last = f.id
+			if _, isBool := f.v.(bool); !isBool {
+				b = tval(b, f.v)
+			}
+		}
+		return append(b, 0)
+	}
+	panic("unsupported fixture type")
+}
+
+func le64(v uint64) []byte { return binary.LittleEndian.AppendUint64(nil, v) }
+func le32(v uint32) []byte { return binary.LittleEndian.AppendUint32(nil, v) }
+
+func plainStrings(ss ...string) []byte {
+	var b []byte
+	for _, s := range ss {
+		b = append(append(b, le32(uint32(len(s)))...), s...)
+	}
+	return b
+}
+
+func plainDoubles(fs ...float64) []byte {
+	var b []byte
+	for _, f := range fs {
+		b = append(b, le64(math.Float64bits(f))...)
+	}
+	return b
+}
+
+// snappyLiteral wraps data in a literal-only snappy block.
+func snappyLiteral(data []byte) []byte {
+	b := binary.AppendUvarint(nil, uint64(len(data)))
+	return append(append(b, byte(len(data)-1)<<2), data...)
+}
+
+func gzipped(data []byte) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(data)
+	zw.Close()
+	return buf.Bytes()
+}
+
+type page struct {
+	header tstruct
+	body   []byte
+}
+
+func dataPage(n int32, enc int32, raw, stored []byte) page {
+	return page{tstruct{{1, int32(0)}, {2, int32(len(raw))}, {3, int32(len(stored))},
+		{5, tstruct{{1, n}, {2, enc}, {3, int32(3)}, {4, int32(3)}}}}, stored}
+}
+
+type chunkSpec struct {
+	name   string
+	typ    int32
+	codec  int32
+	rows   int64
+	pages  []page
+	dict   bool // first page is a dictionary page
+	lo, hi []byte
+}
+
+// buildFile lays out row groups of column chunks and the footer.
+func buildFile(schema []tstruct, groups [][]chunkSpec) []byte {
+	file := []byte("PAR1")
+	var rgs []any
+	var total int64
+	for _, g := range groups {
+		var cols []any
+		for _, c := range g {
+			start := int64(len(file))
+			dataOff := start
+			for i, p := range c.pages {
+				if c.dict && i == 1 {
+					dataOff = int64(len(file))
+				}
+				file = tval(file, p.header)
+				file = append(file, p.body...)
+			}
+			md := tstruct{{1, c.typ}, {2, tlist{5, []any{int32(0)}}}, {3, tlist{8, []any{c.name}}},
+				{4, c.codec}, {5, c.rows}, {6, int64(len(file)) - start}, {7, int64(len(file)) - start}, {9, dataOff}}
+			if c.dict {
+				md = append(md, tfield{11, start})
+			}
+			if c.lo != nil {
+				md = append(md, tfield{12, tstruct{{5, c.hi}, {6, c.lo}}})
+			}
+			cols = append(cols, tstruct{{2, start}, {3, md}})
+		}
+		rows := g[0].rows
+		total += rows
+		rgs = append(rgs, tstruct{{1, tlist{12, cols}}, {2, int64(0)}, {3, rows}})
+	}
+	var els []any
+	for _, s := range schema {
+		els = append(els, s)
+	}
+	meta := tval(nil, tstruct{{1, int32(1)}, {2, tlist{12, els}}, {3, total}, {4, tlist{12, rgs}}})
+	file = append(file, meta...)
+	file = append(file, le32(uint32(len(meta)))...)
+	return append(file, "PAR1"...)
+}
+
+func sampleFile() []byte {
+	schema := []tstruct{
+		{{4, "schema"}, {5, int32(4)}},
+		{{1, int32(typeInt64)}, {3, int32(0)}, {4, "src"}},
+		{{1, int32(typeInt32)}, {3, int32(0)}, {4, "dst"}},
+		{{1, int32(typeDouble)}, {3, int32(1)}, {4, "w"}},
+		{{1, int32(typeByteArray)}, {3, int32(0)}, {4, "kind"}},
+	}
+	src0 := append(append(le64(0), le64(1)...), le64(2)...)
+	dict := append(append(le32(0), le32(1)...), le32(2)...)
+	dstIdx := []byte{2, 3, 0x09, 0x00} // bit width 2, one bit-packed group: 1, 2, 0
+	w0 := append(append(le32(2), 0x03, 0x05), plainDoubles(2.5, 4)...)
+	kinds0 := plainStrings("road", "rail", "road")
+	kindPage := page{tstruct{{1, int32(3)}, {2, int32(len(kinds0))}, {3, int32(len(snappyLiteral(kinds0)))},
+		{8, tstruct{{1, int32(3)}, {2, int32(0)}, {3, int32(3)}, {4, int32(0)}, {5, int32(0)}, {6, int32(0)}}}}, snappyLiteral(kinds0)}
+
+	src1 := append(le64(3), le64(3)...)
+	dst1 := append(le32(0), le32(1)...)
+	w1 := append(append(le32(2), 0x03, 0x03), plainDoubles(100, 200)...)
+	kinds1 := plainStrings("road", "road")
+
+	return buildFile(schema, [][]chunkSpec{
+		{
+			{name: "src", typ: typeInt64, rows: 3, pages: []page{dataPage(3, 0, src0, src0)}},
+			{name: "dst", typ: typeInt32, rows: 3, dict: true, pages: []page{
+				{tstruct{{1, int32(2)}, {2, int32(len(dict))}, {3, int32(len(dict))}, {7, tstruct{{1, int32(3)}, {2, int32(0)}}}}, dict},
+				dataPage(3, 8, dstIdx, dstIdx),
+			}},
+			{name: "w", typ: typeDouble, codec: 2, rows: 3, pages: []page{dataPage(3, 0, w0, gzipped(w0))},
+				lo: le64(math.Float64bits(2.5)), hi: le64(math.Float64bits(4))},
+			{name: "kind", typ: typeByteArray, codec: 1, rows: 3, pages: []page{kindPage}},
+		},
+		{
+			{name: "src", typ: typeInt64, rows: 2, pages: []page{dataPage(2, 0, src1, src1)}},
+			{name: "dst", typ: typeInt32, rows: 2, pages: []page{dataPage(2, 0, dst1, dst1)}},
+			{name: "w", typ: typeDouble, rows: 2, pages: []page{dataPage(2, 0, w1, w1)},
+				lo: le64(math.Float64bits(100)), hi: le64(math.Float64bits(200))},
+			{name: "kind", typ: typeByteArray, rows: 2, pages: []page{dataPage(2, 0, kinds1, kinds1)}},
+		},
+	})
+}
+
+func TestLoadDecodesEncodingsAndNulls(t *testing.T) {
+	f := sampleFile()
+	g, st, err := Load(bytes.NewReader(f), int64(len(f)), Options{Source: "src", Target: "dst", Weight: "w"})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	want := []struct {
+		from, to uint32
+		w        float32
+	}{{0, 1, 2.5}, {1, 2, 1}, {2, 0, 4}, {3, 0, 100}, {3, 1, 200}}
+	edges := g.Edges()
+	if len(edges) != len(want) || st.Rows != 5 || st.RowGroupsSkipped != 0 {
+		t.Fatalf("got %v, stats %+v", edges, st)
+	}
+	for i, e := range edges {
+		if e.From != want[i].from || e.To != want[i].to || e.Weight != want[i].w {
+			t.Fatalf("edge %d = %v, want %v", i, e, want[i])
+		}
+	}
+}
+
+func TestFiltersArePushedDown(t *testing.T) {
+	f := sampleFile()
+	opts := Options{Source: "src", Target: "dst", Weight: "w", Filters: []Filter{
+		{Column: "w", Op: Lt, Value: 50.0},
+		{Column: "kind", Op: Eq, Value: "road"},
+	}}
+	g, st, err := Load(bytes.NewReader(f), int64(len(f)), opts)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if st.RowGroupsSkipped != 1 || st.RowsMatched != 2 || g.EdgeCount() != 2 {
+		t.Fatalf("stats %+v edges %v", st, g.Edges())
+	}
+	if _, _, err := Load(bytes.NewReader(f), int64(len(f)), Options{Source: "src", Target: "kind"}); err == nil {
+		t.Fatalf("expected non-integer id column to be rejected")
+	}
+}
+
+func TestSnappyCopies(t *testing.T) {
+	// "abc" literal, then a 1-byte-offset copy of length 6 at offset 3.
+	got, err := snappyDecode([]byte{9, 2 << 2, 'a', 'b', 'c', 0x09, 0x03})
+	if err != nil || string(got) != "abcabcabc" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+	if _, err := snappyDecode([]byte{9, 2 << 2, 'a', 'b', 'c', 0x09, 0x09}); err == nil {
+		t.Fatalf("expected offset beyond output to fail")
+	}
+}
+
+func TestLoadRejectsStrayLargeID(t *testing.T) {
+	schema := []tstruct{
+		{{4, "schema"}, {5, int32(2)}},
+		{{1, int32(typeInt64)}, {3, int32(0)}, {4, "src"}},
+		{{1, int32(typeInt64)}, {3, int32(0)}, {4, "dst"}},
+	}
+	src, dst := le64(0), le64(2000000000)
+	f := buildFile(schema, [][]chunkSpec{{
+		{name: "src", typ: typeInt64, rows: 1, pages: []page{dataPage(1, 0, src, src)}},
+		{name: "dst", typ: typeInt64, rows: 1, pages: []page{dataPage(1, 0, dst, dst)}},
+	}})
+	if _, _, err := Load(bytes.NewReader(f), int64(len(f)), Options{Source: "src", Target: "dst"}); err == nil {
+		t.Fatal("id 2e9 on one edge accepted")
+	}
+	ids := sssp.NewIDMapper[int64]()
+	g, _, err := Load(bytes.NewReader(f), int64(len(f)), Options{Source: "src", Target: "dst", IDs: ids})
+	if err != nil || g.NodeCount() != 2 || ids.Key(1) != 2000000000 {
+		t.Fatalf("mapped ids: %v, %d nodes", err, g.NodeCount())
+	}
+}
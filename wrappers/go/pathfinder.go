@@ -0,0 +1,162 @@
+package sssp
+
+import "fmt"
+
+// PathFinder answers repeated queries on one graph without allocating. It
+// owns the distance, predecessor and visited arrays and the heap, sized
+// for the graph once, and after a point-to-point query resets only the
+// nodes that query touched. Results alias that memory and stay valid until
+// the next query; copy what must outlive it. If the graph changes, the next
+// query adapts, reallocating only if the node count changed. A PathFinder
+// is not safe for concurrent use: give each goroutine its own.
+type PathFinder struct {
+	g       *Graph
+	version uint64
+	dist    []float32
+	pred    []int32
+	done    []bool
+	touched []uint32 // nodes the last ShortestPath wrote
+	full    bool     // the last query was a Run, which wrote every node
+	heap    minHeap[float32]
+	vec     *simdRelaxer
+	path    []uint32
+}
+
+// NewPathFinder returns a PathFinder for g.
+func (g *Graph) NewPathFinder() *PathFinder {
+	f := &PathFinder{g: g}
+	f.fit()
+	return f
+}
+
+// fit sizes the arrays for the graph as it is now and clears them.
+func (f *PathFinder) fit() {
+	n := int(f.g.NodeCount())
+	if len(f.dist) != n {
+		f.dist, f.pred, f.done = make([]float32, n), make([]int32, n), make([]bool, n)
+		f.full = true
+	}
+	f.reset()
+	f.vec = newSIMDRelaxer(f.g.targets, f.g.weights, f.dist)
+	f.version = f.g.version
+}
+
+// reset clears what the last query wrote.
+func (f *PathFinder) reset() {
+	if f.full {
+		for v := range f.dist {
+			f.dist[v], f.pred[v], f.done[v] = Unreachable, -1, false
+		}
+	} else {
+		for _, v := range f.touched {
+			f.dist[v], f.pred[v], f.done[v] = Unreachable, -1, false
+		}
+	}
+	f.touched, f.full = f.touched[:0], false
+}
+
+// check validates a query and brings the arrays up to date with the graph.
+func (f *PathFinder) check(nodes ...uint32) error {
+	n := f.g.NodeCount()
+	for _, v := range nodes {
+		if v >= n {
+			return fmt.Errorf("sssp: node %d out of range for %d nodes", v, n)
+		}
+	}
+	if f.version != f.g.version || len(f.dist) != int(n) {
+		f.fit()
+	} else {
+		f.reset()
+	}
+	return nil
+}
+
+// ShortestPath is Graph.ShortestPath on the finder's memory: A* or
+// Dijkstra stopped at target, pruned by components when they are known.
+// The returned path is valid until the next query.
+func (f *PathFinder) ShortestPath(source, target uint32) (float32, []uint32, error) {
+	if err := f.check(source, target); err != nil {
+		return Unreachable, nil, err
+	}
+	g := f.g
+	var of []uint32 // components to prune by, if known
+	if c := g.knownComponents(); c != nil {
+		if c.ProvablyUnreachable(source, target) {
+			return Unreachable, nil, nil
+		}
+		of = c.Of
+	}
+	k := g.geoScale()
+	var to LatLon
+	if k > 0 {
+		to = g.coords[target]
+	}
+	// h is the A* estimate of the distance left from v, 0 without
+	// coordinates.
+	h := func(v uint32) float32 {
+		if k == 0 {
+			return 0
+		}
+		return float32(k * Haversine(g.coords[v], to))
+	}
+	dist, pred, done := f.dist, f.pred, f.done
+	dist[source] = 0
+	f.touched = append(f.touched, source)
+	q := &f.heap
+	q.data = q.data[:0]
+	q.push(heapItem[float32]{node: source, dist: h(source)})
+	for {
+		it, ok := q.pop()
+		if !ok {
+			return Unreachable, nil, nil
+		}
+		u := it.node
+		if done[u] {
+			continue
+		}
+		if u == target {
+			return dist[u], f.pathTo(target), nil
+		}
+		done[u] = true
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			v := g.targets[e]
+			if of != nil && of[v] > of[target] {
+				continue
+			}
+			if nd := dist[u] + g.weights[e]; nd < dist[v] {
+				if dist[v] == Unreachable {
+					f.touched = append(f.touched, v)
+				}
+				dist[v], pred[v] = nd, int32(u)
+				q.push(heapItem[float32]{node: v, dist: nd + h(v)})
+			}
+		}
+	}
+}
+
+// pathTo follows pred back from target into the path buffer.
+func (f *PathFinder) pathTo(target uint32) []uint32 {
+	p := f.path[:0]
+	for v := int32(target); v >= 0; v = f.pred[v] {
+		p = append(p, uint32(v))
+	}
+	for i, j := 0, len(p)-1; i < j; i, j = i+1, j-1 {
+		p[i], p[j] = p[j], p[i]
+	}
+	f.path = p
+	return p
+}
+
+// Run computes distances from source to every node with Dijkstra's
+// algorithm, like Run with ModeBaseline on the pure-Go engine. The
+// result's Dist and Pred are the finder's arrays, valid until the next
+// query.
+func (f *PathFinder) Run(source uint32) (Result, error) {
+	if err := f.check(source); err != nil {
+		return Result{}, err
+	}
+	g := f.g
+	ops := dijkstraWith(g.offsets, g.targets, g.weights, source, f.dist, f.pred, nil, &f.heap, f.vec)
+	f.full = true
+	return Result{Dist: f.dist, Pred: f.pred, Stats: Stats{Relaxations: ops.Improvements, Settled: uint32(len(f.dist)), Ops: ops}, graph: g}, nil
+}
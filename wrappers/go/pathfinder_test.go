@@ -0,0 +1,78 @@
+package sssp
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPathFinder(t *testing.T) {
+	g := randomGraph(t, 300, 1500, 9)
+	f := g.NewPathFinder()
+	for s := uint32(0); s < 300; s += 41 {
+		want, err := g.RunSettled(s, ModeBaseline, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Point-to-point queries in between leave no trace in a full run.
+		for v := uint32(0); v < 300; v += 7 {
+			d, path, err := f.ShortestPath(s, v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d != want.Dist[v] || (d == Unreachable) != (path == nil) {
+				t.Fatalf("%d->%d: got %v, want %v", s, v, d, want.Dist[v])
+			}
+			if path != nil {
+				if l, _ := g.PathLength(path); path[0] != s || path[len(path)-1] != v || l != d {
+					t.Fatalf("%d->%d: path %v of length %v", s, v, path, l)
+				}
+			}
+		}
+		got, err := f.Run(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(got.Dist, want.Dist) {
+			t.Fatalf("source %d: distances differ", s)
+		}
+	}
+	if _, _, err := f.ShortestPath(0, 300); err == nil {
+		t.Error("out-of-range target accepted")
+	}
+	if _, err := f.Run(300); err == nil {
+		t.Error("out-of-range source accepted")
+	}
+	// A changed graph is picked up by the next query.
+	if err := g.AddEdges([]Edge{{0, 299, 0}}); err != nil {
+		t.Fatal(err)
+	}
+	if d, _, _ := f.ShortestPath(0, 299); d != 0 {
+		t.Errorf("after AddEdges: 0->299 = %v", d)
+	}
+}
+
+func TestPathFinderAllocs(t *testing.T) {
+	g, err := GenerateGrid(GridParams{Dims: []uint32{40, 40}}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := g.NewPathFinder()
+	if n := testing.AllocsPerRun(20, func() { f.ShortestPath(3, 1500) }); n != 0 {
+		t.Errorf("ShortestPath allocates %v times per query", n)
+	}
+	if n := testing.AllocsPerRun(20, func() { f.Run(77) }); n != 0 {
+		t.Errorf("Run allocates %v times per query", n)
+	}
+}
+
+func BenchmarkPathFinder(b *testing.B) {
+	g, err := GenerateGrid(GridParams{Dims: []uint32{100, 100}}, 3)
+	if err != nil {
+		b.Fatal(err)
+	}
+	f := g.NewPathFinder()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		f.ShortestPath(uint32(i%10000), uint32((i*7919)%10000))
+	}
+}
@@ -0,0 +1,107 @@
+package sssp
+
+import (
+	"fmt"
+	"math"
+)
+
+// Utilities to compare paths given as node sequences, for example to keep
+// only alternatives that differ enough from the best route.
+
+// PathLength returns the total weight of path, taking the lightest edge
+// between consecutive nodes. It fails if some step has no edge.
+func (g *Graph) PathLength(path []uint32) (float32, error) {
+	var sum float32
+	for i := 1; i < len(path); i++ {
+		w, ok := g.stepWeight(path[i-1], path[i])
+		if !ok {
+			return 0, fmt.Errorf("sssp: path has no edge %d->%d", path[i-1], path[i])
+		}
+		sum += w
+	}
+	return sum, nil
+}
+
+// stepWeight is the weight of the lightest edge u->v.
+func (g *Graph) stepWeight(u, v uint32) (float32, bool) {
+	if u >= g.NodeCount() {
+		return 0, false
+	}
+	best, ok := float32(0), false
+	for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+		if g.targets[e] == v && (!ok || g.weights[e] < best) {
+			best, ok = g.weights[e], true
+		}
+	}
+	return best, ok
+}
+
+// Overlap returns the fraction of a's length, by weight, on steps b also
+// takes: 1 if b covers all of a, 0 if they share no edge. It is not
+// symmetric; compare Overlap(a, b) and Overlap(b, a), or their maximum, as
+// needed. A path of zero length has overlap 0.
+func (g *Graph) Overlap(a, b []uint32) (float32, error) {
+	total, err := g.PathLength(a)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := g.PathLength(b); err != nil {
+		return 0, err
+	}
+	steps := make(map[[2]uint32]int, len(b))
+	for i := 1; i < len(b); i++ {
+		steps[[2]uint32{b[i-1], b[i]}]++
+	}
+	var shared float32
+	for i := 1; i < len(a); i++ {
+		k := [2]uint32{a[i-1], a[i]}
+		if steps[k] > 0 {
+			steps[k]-- // a step b repeats counts as often as b takes it
+			w, _ := g.stepWeight(a[i-1], a[i])
+			shared += w
+		}
+	}
+	if total == 0 {
+		return 0, nil
+	}
+	return shared / total, nil
+}
+
+// FrechetDistance returns the discrete Fréchet distance between the node
+// polylines of a and b in metres: the shortest leash that lets two walkers
+// traverse a and b, each only forward, in step. Paths that run side by side
+// on parallel streets score low even though they share no edge. It needs
+// coordinates and costs O(len(a) * len(b)) time and O(len(b)) memory.
+func (g *Graph) FrechetDistance(a, b []uint32) (float64, error) {
+	if g.coords == nil {
+		return 0, fmt.Errorf("sssp: Fréchet distance needs coordinates")
+	}
+	if len(a) == 0 || len(b) == 0 {
+		return 0, fmt.Errorf("sssp: Fréchet distance of an empty path")
+	}
+	for _, p := range [][]uint32{a, b} {
+		for _, v := range p {
+			if v >= g.NodeCount() {
+				return 0, fmt.Errorf("sssp: path node %d out of range for %d nodes", v, g.NodeCount())
+			}
+		}
+	}
+	d := func(i, j int) float64 { return Haversine(g.coords[a[i]], g.coords[b[j]]) }
+	prev, cur := make([]float64, len(b)), make([]float64, len(b))
+	for i := range a {
+		for j := range b {
+			switch {
+			case i == 0 && j == 0:
+				cur[j] = d(0, 0)
+			case i == 0:
+				cur[j] = math.Max(cur[j-1], d(i, j))
+			case j == 0:
+				cur[j] = math.Max(prev[j], d(i, j))
+			default:
+				cur[j] = math.Max(math.Min(prev[j], math.Min(prev[j-1], cur[j-1])), d(i, j))
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)-1], nil
+}
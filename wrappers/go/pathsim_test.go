@@ -0,0 +1,47 @@
+package sssp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOverlapAndPathLength(t *testing.T) {
+	// Two routes 0->3: 0-1-3 and 0-1-2-3.
+	g, _ := FromEdges(4, []Edge{{0, 1, 2}, {1, 3, 2}, {1, 2, 1}, {2, 3, 1}, {0, 1, 5}})
+	a, b := []uint32{0, 1, 3}, []uint32{0, 1, 2, 3}
+	if l, err := g.PathLength(b); err != nil || l != 4 {
+		t.Fatalf("length %v %v", l, err)
+	}
+	if o, _ := g.Overlap(a, b); o != 0.5 {
+		t.Fatalf("overlap a in b %v", o)
+	}
+	if o, _ := g.Overlap(b, a); o != 0.5 {
+		t.Fatalf("overlap b in a %v", o)
+	}
+	if o, _ := g.Overlap(a, a); o != 1 {
+		t.Fatalf("self overlap %v", o)
+	}
+	if _, err := g.Overlap([]uint32{0, 3}, a); err == nil {
+		t.Fatalf("path without an edge accepted")
+	}
+}
+
+func TestFrechetDistance(t *testing.T) {
+	g, _ := FromEdges(4, nil)
+	if _, err := g.FrechetDistance([]uint32{0}, []uint32{1}); err == nil {
+		t.Fatalf("no coordinates accepted")
+	}
+	// Two parallel streets 0.001 degrees of latitude apart.
+	g.SetCoords([]LatLon{{0, 0}, {0, 0.01}, {0.001, 0}, {0.001, 0.01}})
+	d, err := g.FrechetDistance([]uint32{0, 1}, []uint32{2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := Haversine(LatLon{0, 0}, LatLon{0.001, 0}); math.Abs(d-want) > 1e-6 {
+		t.Fatalf("parallel streets %v m, want %v", d, want)
+	}
+	// Walking one street backwards drags the leash across its length.
+	if d, _ := g.FrechetDistance([]uint32{0, 1}, []uint32{3, 2}); d < 1000 {
+		t.Fatalf("reversed street %v m", d)
+	}
+}
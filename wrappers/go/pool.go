@@ -0,0 +1,72 @@
+package sssp
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// Pure-Go runs borrow their working memory (heaps, bucket arrays, frontier
+// buffers, trial rows) from pools and hand it back when they finish, so a
+// process running many queries reuses it instead of feeding the garbage
+// collector. Results are never pooled: Dist and Pred belong to the caller.
+// Pooled buffers keep memory proportional to the largest graph queried
+// until the next collection; SetPooling(false), or SSSP_POOL=0 in the
+// environment, turns pooling off where that matters more.
+
+var pooling atomic.Bool
+
+func init() { pooling.Store(os.Getenv("SSSP_POOL") != "0") }
+
+// SetPooling turns pooling of pure-Go working memory on or off and returns
+// the previous setting. It takes effect for runs that start afterwards.
+func SetPooling(on bool) bool { return pooling.Swap(on) }
+
+// Pooling reports whether pure-Go runs pool their working memory.
+func Pooling() bool { return pooling.Load() }
+
+// slicePool recycles slices of T. It holds pointers to them, so handing a
+// slice back does not allocate.
+type slicePool[T any] struct{ p sync.Pool }
+
+// slicePools maps (*T)(nil) to the *slicePool[T] for each T in use.
+var slicePools sync.Map
+
+// poolOf returns the pool for slices of T.
+func poolOf[T any]() *slicePool[T] {
+	key := any((*T)(nil))
+	if p, ok := slicePools.Load(key); ok {
+		return p.(*slicePool[T])
+	}
+	p, _ := slicePools.LoadOrStore(key, new(slicePool[T]))
+	return p.(*slicePool[T])
+}
+
+// get returns an empty slice with room for at least n elements. Pooled
+// slices that are too small are left to the collector.
+func (sp *slicePool[T]) get(n int) *[]T {
+	if pooling.Load() {
+		if p, ok := sp.p.Get().(*[]T); ok && cap(*p) >= n {
+			*p = (*p)[:0]
+			return p
+		}
+	}
+	s := make([]T, 0, n)
+	return &s
+}
+
+// put hands a slice from get back, with whatever it grew to.
+func (sp *slicePool[T]) put(p *[]T) {
+	if pooling.Load() {
+		sp.p.Put(p)
+	}
+}
+
+// getRow returns a slice of length n from the pool for T, with unspecified
+// contents, and the handle to give back with putRow.
+func getRow[T any](n int) (*[]T, []T) {
+	p := poolOf[T]().get(n)
+	return p, (*p)[:n]
+}
+
+func putRow[T any](p *[]T) { poolOf[T]().put(p) }
@@ -0,0 +1,103 @@
+package sssp
+
+import (
+	"slices"
+	"testing"
+)
+
+// runAllocs is the allocation budget per pure-Go run with pooling on: the
+// result's Dist and Pred, plus the odd closure. Raising it needs a reason.
+var runAllocs = map[int]float64{ModeBaseline: 2, ModeStoc: 3, ModeAutotune: 4}
+
+// parallelAllocs bounds RunParallel with two workers on a 40x40 grid.
+const parallelAllocs = 60
+
+func poolGrid(tb testing.TB) *Graph {
+	tb.Helper()
+	g, err := GenerateGrid(GridParams{Dims: []uint32{40, 40}}, 3)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return g
+}
+
+// checkRunAllocs fails tb if a run in mode allocates over budget.
+func checkRunAllocs(tb testing.TB, g *Graph, mode int) {
+	tb.Helper()
+	var res Result
+	if a := testing.AllocsPerRun(10, func() { g.runGo(5, mode, &res, nil) }); a > runAllocs[mode] {
+		tb.Errorf("mode %d: %v allocations per run, budget %v", mode, a, runAllocs[mode])
+	}
+}
+
+func TestPoolingAllocs(t *testing.T) {
+	if !Pooling() {
+		t.Skip("pooling disabled by SSSP_POOL")
+	}
+	if raceEnabled {
+		t.Skip("sync.Pool drops items at random under -race")
+	}
+	g := poolGrid(t)
+	for mode := ModeBaseline; mode <= ModeAutotune; mode++ {
+		checkRunAllocs(t, g, mode)
+	}
+	a := testing.AllocsPerRun(5, func() { g.RunParallel(5, ParallelOptions{Workers: 2}) })
+	if a > parallelAllocs {
+		t.Errorf("RunParallel: %v allocations per run, budget %v", a, parallelAllocs)
+	}
+}
+
+func TestPoolingOff(t *testing.T) {
+	g := randomGraph(t, 500, 3000, 11)
+	var want [3]Result
+	for mode := range want {
+		if err := g.runGo(3, mode, &want[mode], nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	was := SetPooling(false)
+	defer SetPooling(was)
+	if Pooling() {
+		t.Fatal("SetPooling(false) did not stick")
+	}
+	var res Result
+	for mode := range want {
+		if err := g.runGo(3, mode, &res, nil); err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(res.Dist, want[mode].Dist) {
+			t.Errorf("mode %d: distances differ without pooling", mode)
+		}
+	}
+	// Pooled memory from runs on a larger graph must not leak into a
+	// smaller one.
+	SetPooling(true)
+	small := randomGraph(t, 50, 200, 12)
+	for mode := range want {
+		if err := small.runGo(0, mode, &res, nil); err != nil {
+			t.Fatal(err)
+		}
+		ref, _ := small.RunSettled(0, ModeBaseline, nil)
+		for v := range ref.Dist {
+			if res.Dist[v] != ref.Dist[v] {
+				t.Fatalf("mode %d after a larger graph: dist[%d] = %v, want %v", mode, v, res.Dist[v], ref.Dist[v])
+			}
+		}
+	}
+}
+
+func BenchmarkRunGo(b *testing.B) {
+	g := poolGrid(b)
+	for mode, name := range []string{"baseline", "stoc", "autotune"} {
+		b.Run(name, func(b *testing.B) {
+			if Pooling() && !raceEnabled {
+				checkRunAllocs(b, g, mode)
+			}
+			b.ReportAllocs()
+			var res Result
+			for i := 0; i < b.N; i++ {
+				g.runGo(uint32(i)%g.NodeCount(), mode, &res, nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package sssp
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/your-org/optimized-sssp-go/internal/decompress"
+	"github.com/your-org/optimized-sssp-go/internal/wire"
+)
+
+// Protocol Buffers encoding of Graph and Result following proto/sssp.proto.
+// The codec is hand-written so the module keeps zero dependencies; its output
+// is byte-compatible with protoc-generated code for that schema, and the
+// decoder accepts packed and unpacked repeated fields and skips unknown ones.
+
+// MarshalProto encodes g as a sssp.v1.Graph message.
+func (g *Graph) MarshalProto() []byte {
+	b := make([]byte, 0, 5*(len(g.offsets)+len(g.targets))+4*len(g.weights)+16)
+	b = wire.AppendPackedUint32(b, 1, g.offsets)
+	b = wire.AppendPackedUint32(b, 2, g.targets)
+	return wire.AppendPackedFloat(b, 3, g.weights)
+}
+
+// UnmarshalGraphProto decodes a sssp.v1.Graph message and validates the CSR
+// arrays. A gzip- or zstd-compressed message is decompressed first.
+func UnmarshalGraphProto(b []byte) (*Graph, error) {
+	b, err := decompress.Bytes(b)
+	if err != nil {
+		return nil, fmt.Errorf("sssp: proto: %w", err)
+	}
+	var (
+		off, tgt []uint64
+		wts      []float32
+	)
+	err = wire.Walk(b, func(num, typ int, v uint64, data []byte) error {
+		var err error
+		switch num {
+		case 1:
+			off, err = wire.Varints(off, typ, v, data)
+		case 2:
+			tgt, err = wire.Varints(tgt, typ, v, data)
+		case 3:
+			wts, err = wire.Floats(wts, typ, v, data)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sssp: proto: %w", err)
+	}
+	if len(off) == 0 {
+		off = []uint64{0} // proto3 omits the empty graph's only offset
+	}
+	offsets, err := narrowUint32(off)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := narrowUint32(tgt)
+	if err != nil {
+		return nil, err
+	}
+	return NewGraphCSR(offsets, targets, wts)
+}
+
+func narrowUint32(vs []uint64) ([]uint32, error) {
+	out := make([]uint32, len(vs))
+	for i, v := range vs {
+		if v > math.MaxUint32 {
+			return nil, fmt.Errorf("sssp: proto: value %d overflows uint32", v)
+		}
+		out[i] = uint32(v)
+	}
+	return out, nil
+}
+
+// MarshalProto encodes r as a sssp.v1.Result message.
+func (r *Result) MarshalProto() []byte {
+	b := make([]byte, 0, 4*len(r.Dist)+2*len(r.Pred)+64)
+	b = wire.AppendPackedFloat(b, 1, r.Dist)
+	pred := make([]int64, len(r.Pred))
+	for i, p := range r.Pred {
+		pred[i] = int64(p)
+	}
+	b = wire.AppendPackedSint(b, 2, pred)
+	s := r.Stats
+	var sb []byte
+	sb = wire.AppendVarintField(sb, 1, s.Relaxations)
+	sb = wire.AppendVarintField(sb, 2, s.LightRelaxations)
+	sb = wire.AppendVarintField(sb, 3, s.HeavyRelaxations)
+	sb = wire.AppendVarintField(sb, 4, uint64(s.Settled))
+	sb = wire.AppendVarintField(sb, 5, uint64(int64(s.ErrorCode))) // int32 sign-extends to 10 bytes
+	sb = wire.AppendVarintField(sb, 6, uint64(s.Version))
+	sb = wire.AppendFloatField(sb, 7, s.Delta)
+	sb = wire.AppendFloatField(sb, 8, s.DeltaMultiplier)
+	sb = wire.AppendVarintField(sb, 9, uint64(s.Buckets))
+	sb = wire.AppendVarintField(sb, 10, uint64(s.AutotuneTrials))
+	if s.Fallback {
+		sb = wire.AppendVarintField(sb, 11, 1)
+	}
+	if len(sb) > 0 {
+		b = wire.AppendBytesField(b, 3, sb)
+	}
+	return b
+}
+
+// UnmarshalResultProto decodes a sssp.v1.Result message.
+func UnmarshalResultProto(b []byte) (*Result, error) {
+	r := &Result{}
+	var pred []uint64
+	err := wire.Walk(b, func(num, typ int, v uint64, data []byte) error {
+		var err error
+		switch num {
+		case 1:
+			r.Dist, err = wire.Floats(r.Dist, typ, v, data)
+		case 2:
+			pred, err = wire.Varints(pred, typ, v, data)
+		case 3:
+			if typ != wire.Bytes {
+				return fmt.Errorf("malformed stats field")
+			}
+			err = wire.Walk(data, func(num, typ int, v uint64, _ []byte) error {
+				s := &r.Stats
+				switch num {
+				case 1:
+					s.Relaxations = v
+				case 2:
+					s.LightRelaxations = v
+				case 3:
+					s.HeavyRelaxations = v
+				case 4:
+					s.Settled = uint32(v)
+				case 5:
+					s.ErrorCode = int32(v)
+				case 6:
+					s.Version = uint32(v)
+				case 7:
+					s.Delta = math.Float32frombits(uint32(v))
+				case 8:
+					s.DeltaMultiplier = math.Float32frombits(uint32(v))
+				case 9:
+					s.Buckets = uint32(v)
+				case 10:
+					s.AutotuneTrials = uint32(v)
+				case 11:
+					s.Fallback = v != 0
+				}
+				return nil
+			})
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sssp: proto: %w", err)
+	}
+	if len(pred) > 0 {
+		r.Pred = make([]int32, len(pred))
+		for i, z := range pred {
+			r.Pred[i] = int32(uint32(z>>1) ^ -uint32(z&1))
+		}
+	}
+	if r.Pred != nil && len(r.Pred) != len(r.Dist) {
+		return nil, fmt.Errorf("sssp: proto: %d distances but %d predecessors", len(r.Dist), len(r.Pred))
+	}
+	return r, nil
+}
@@ -0,0 +1,55 @@
+package sssp
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestGraphProtoRoundTrip(t *testing.T) {
+	off, tgt, wts := randomCSR(200, 4, 3)
+	g, err := NewGraphCSR(off, tgt, wts)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	again, err := UnmarshalGraphProto(g.MarshalProto())
+	if err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	o2, t2, w2 := again.CSR()
+	if len(o2) != len(off) || len(t2) != len(tgt) || w2[7] != wts[7] || t2[11] != tgt[11] {
+		t.Fatalf("round trip mismatch")
+	}
+	if _, err := UnmarshalGraphProto(g.MarshalProto()[:10]); err == nil {
+		t.Fatalf("expected truncation error")
+	}
+	empty, err := UnmarshalGraphProto(nil)
+	if err != nil || empty.NodeCount() != 0 {
+		t.Fatalf("empty graph: %v", err)
+	}
+}
+
+func TestResultProtoWireBytes(t *testing.T) {
+	r := &Result{
+		Dist:  []float32{0, float32(math.Inf(1))},
+		Pred:  []int32{-1, -1},
+		Stats: Stats{Relaxations: 3, ErrorCode: -2, Delta: 1.5, Fallback: true},
+	}
+	b := r.MarshalProto()
+	// Field 2 (pred) as protoc emits it: tag 0x12, length 2, zigzag(-1)=1 twice.
+	if !bytes.Contains(b, []byte{0x12, 0x02, 0x01, 0x01}) {
+		t.Fatalf("unexpected pred encoding % x", b)
+	}
+	got, err := UnmarshalResultProto(b)
+	if err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !math.IsInf(float64(got.Dist[1]), 1) || got.Pred[1] != -1 || got.Stats != r.Stats {
+		t.Fatalf("got %+v", got)
+	}
+	// Unpacked pred entries and an unknown field (15, varint) must be accepted.
+	got, err = UnmarshalResultProto([]byte{0x10, 0x04, 0x78, 0x01, 0x0d, 0, 0, 0x80, 0x3f})
+	if err != nil || got.Pred[0] != 2 || got.Dist[0] != 1 {
+		t.Fatalf("unpacked: %v %+v", err, got)
+	}
+}
@@ -0,0 +1,160 @@
+//go:build !cgo
+
+package sssp
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// Without cgo (GOOS=js, wasip1, CGO_ENABLED=0) the exported entry points run
+// the pure-Go engine. Stats.Version is 0 to mark that no native library ran.
+// ModeStoc uses a fixed delta (avg weight * SSSP_STOC_DELTA_MULT, default 3)
+// without the native adaptive restarts.
+
+// Capabilities reports the feature set of the pure-Go engine.
+func Capabilities() Capability {
+	return CapBaseline | CapStoc | CapAutotune | CapAutotuneParams | CapBatch | CapThreads | CapOpCounters | CapStocParams
+}
+
+// checkArgs rejects what the native runners would: mismatched arrays (see
+// checkCSR) and, with the native error code, a source out of range.
+func checkArgs(n uint32, offsets, targets []uint32, weights []float32, source uint32) error {
+	if err := checkCSR(n, offsets, targets, weights); err != nil {
+		return err
+	}
+	if source >= n {
+		return fmt.Errorf("sssp: run failed with code %d", -2)
+	}
+	return nil
+}
+
+// runInto executes one query into caller-owned dist/pred rows; a nil pred
+// records no predecessors.
+func runInto(offsets, targets []uint32, weights []float32, source uint32, mode int, delta float32, dist []float32, pred []int32) Stats {
+	switch mode {
+	case ModeBaseline:
+		ops := goDijkstra(offsets, targets, weights, source, dist, pred, nil)
+		return Stats{Relaxations: ops.Improvements, Settled: uint32(len(dist)), Ops: ops}
+	default:
+		c := goDeltaStepping(offsets, targets, weights, source, delta, dist, pred, 0, nil)
+		return Stats{Relaxations: c.relax, LightRelaxations: c.light, HeavyRelaxations: c.heavy, Settled: c.settled, Delta: delta, Fallback: c.overflow, Ops: c.ops}
+	}
+}
+
+// Run executes a selected variant: 0 baseline, 1 stoc, 2 autotune, 3 GPU.
+func Run(n uint32, offsets, targets []uint32, weights []float32, source uint32, mode int) (Result, error) {
+	switch mode {
+	case ModeAutotune:
+		return RunAutotune(n, offsets, targets, weights, source, AutotuneParams{})
+	case ModeGPU:
+		res, err := Run(n, offsets, targets, weights, source, ModeStoc)
+		res.Stats.Fallback = err == nil
+		return res, err
+	case ModeBaseline, ModeStoc:
+	default:
+		return Result{}, fmt.Errorf("%w: mode %d", ErrUnsupported, mode)
+	}
+	if err := checkArgs(n, offsets, targets, weights, source); err != nil {
+		return Result{}, err
+	}
+	dist := make([]float32, n)
+	pred := make([]int32, n)
+	delta := clampDelta(avgWeight(weights) * envFloat("SSSP_STOC_DELTA_MULT", 3))
+	return Result{Dist: dist, Pred: pred, Stats: runInto(offsets, targets, weights, source, mode, delta, dist, pred)}, nil
+}
+
+// RunAutotune executes the autotuned variant with explicit tuner controls and
+// reports the chosen parameters in Stats.
+func RunAutotune(n uint32, offsets, targets []uint32, weights []float32, source uint32, params AutotuneParams) (Result, error) {
+	if err := checkArgs(n, offsets, targets, weights, source); err != nil {
+		return Result{}, err
+	}
+	dist := make([]float32, n)
+	pred := make([]int32, n)
+	stats := goAutotune(offsets, targets, weights, source, params, dist, pred, nil)
+	return Result{Dist: dist, Pred: pred, Stats: stats}, nil
+}
+
+// RunBatch computes distances from every source, spreading sources over
+// goroutines (SSSP_THREADS or GOMAXPROCS).
+func RunBatch(n uint32, offsets, targets []uint32, weights []float32, sources []uint32, mode int) (BatchResult, error) {
+	return RunBatchThreads(n, offsets, targets, weights, sources, mode, 0)
+}
+
+// RunBatchThreads is RunBatch with the worker count capped at threads.
+// A row whose distances pass the last bucket finishes with Dijkstra and sets
+// Stats.Fallback for the batch, where the native library fails it with
+// code -5.
+func RunBatchThreads(n uint32, offsets, targets []uint32, weights []float32, sources []uint32, mode int, threads int) (BatchResult, error) {
+	if len(sources) == 0 {
+		return BatchResult{N: n, Sources: sources}, nil
+	}
+	if err := checkBatch(n, offsets, targets, weights, sources); err != nil {
+		return BatchResult{}, err
+	}
+	fallback := false
+	if mode == ModeGPU {
+		mode, fallback = ModeStoc, true
+	}
+	if mode < ModeBaseline || mode > ModeAutotune {
+		return BatchResult{}, fmt.Errorf("sssp: batch run failed with code %d", -6)
+	}
+	for _, s := range sources {
+		if s >= n {
+			return BatchResult{}, fmt.Errorf("sssp: batch run failed with code %d", -2)
+		}
+	}
+	delta := clampDelta(avgWeight(weights) * envFloat("SSSP_STOC_DELTA_MULT", 3))
+	var tuneOps OpCounters
+	if mode == ModeAutotune {
+		// Tune once on the first source and reuse the delta for every row.
+		distRow, tmpDist := getRow[float32](int(n))
+		tuned := goAutotune(offsets, targets, weights, sources[0], AutotuneParams{}, tmpDist, nil, nil)
+		putRow(distRow)
+		delta = tuned.Delta
+		tuneOps = tuned.Ops
+	}
+	if threads <= 0 {
+		threads = int(envUint("SSSP_THREADS", uint32(runtime.GOMAXPROCS(0))))
+	}
+	// Contiguous chunks of rows per worker, as the native core splits them,
+	// so both report the same worker count.
+	threads = max(1, min(threads, len(sources)))
+	rowsPer := (len(sources) + threads - 1) / threads
+	threads = (len(sources) + rowsPer - 1) / rowsPer
+
+	dist := make([]float32, len(sources)*int(n))
+	perWorker := make([]Stats, threads)
+	var wg sync.WaitGroup
+	for w := 0; w < threads; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w * rowsPer; i < min((w+1)*rowsPer, len(sources)); i++ {
+				row := dist[i*int(n) : (i+1)*int(n)]
+				s := runInto(offsets, targets, weights, sources[i], mode, delta, row, nil)
+				acc := &perWorker[w]
+				acc.Relaxations += s.Relaxations
+				acc.LightRelaxations += s.LightRelaxations
+				acc.HeavyRelaxations += s.HeavyRelaxations
+				acc.Settled += s.Settled
+				acc.Fallback = acc.Fallback || s.Fallback
+				acc.Ops.Add(s.Ops)
+			}
+		}(w)
+	}
+	wg.Wait()
+	stats := Stats{Ops: tuneOps}
+	for _, s := range perWorker {
+		stats.Relaxations += s.Relaxations
+		stats.LightRelaxations += s.LightRelaxations
+		stats.HeavyRelaxations += s.HeavyRelaxations
+		stats.Settled += s.Settled
+		stats.Fallback = stats.Fallback || s.Fallback
+		stats.Ops.Add(s.Ops)
+	}
+	stats.Fallback = stats.Fallback || fallback
+	return BatchResult{N: n, Sources: sources, Dist: dist, Stats: stats, Threads: uint32(threads)}, nil
+}
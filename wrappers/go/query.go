@@ -0,0 +1,167 @@
+package sssp
+
+import (
+	"fmt"
+	"maps"
+)
+
+// WeightFunc gives the cost of the edge u->v under one cost model (time,
+// distance, tolls) from its stored weight w. It must return a non-negative
+// weight; +Inf closes the edge for the query.
+type WeightFunc func(u, v uint32, w float32) float32
+
+// QueryOptions adjust one query without changing the stored graph. The zero
+// value runs the graph as stored.
+type QueryOptions struct {
+	// Weight, if set, replaces each edge's weight by Weight(u, v, w) for
+	// this query only.
+	Weight WeightFunc
+	// NodeFilter, if set, keeps the search out of nodes it rejects, such as
+	// closed junctions; they end up unreachable. The source is always
+	// allowed. It is called at most once per node.
+	NodeFilter func(node uint32) bool
+	// EdgeFilter, if set, makes the search skip edges it rejects, such as
+	// closed roads or ferries, as if they were absent. It sees the weight
+	// after Weight.
+	EdgeFilter func(id int, e Edge) bool
+	// Avoid, if set, lists nodes and edges the search must not use, checked
+	// with a bit test per edge; see NewAvoidSet. The source is never
+	// blocked.
+	Avoid *AvoidSet
+	// DistancesOnly skips predecessors: Result.Pred is nil and Path
+	// returns nil. The pure-Go engine then never writes one, and native
+	// runs go through the batch entry point without a predecessor row, in
+	// which the core's runners neither allocate nor write one. That halves
+	// the per-node memory and writes for workloads such as centrality or
+	// reachability that only read distances. A native library without
+	// CapBatch still records them, and they are dropped.
+	DistancesOnly bool
+}
+
+// RunQuery is Run under opts. A Weight function is applied in one pass over
+// the edges before the run, so every mode, native or pure-Go, sees the
+// adjusted weights; that pass costs O(edges) per query. To run many
+// queries under the same cost model, build the view once with Reweighted.
+//
+// Filters and avoid sets are checked inside the relaxation loop instead, so a query costs
+// no more than the search itself; they need the pure-Go engine, so a
+// filtered query reports Stats.Version 0 and ModeStoc uses the fixed delta
+// described for builds without cgo.
+func (g *Graph) RunQuery(source uint32, mode int, opts QueryOptions) (Result, error) {
+	if opts.Weight != nil {
+		g = g.Reweighted(opts.Weight)
+	}
+	if a := opts.Avoid; a != nil && (a.nodeCount != g.NodeCount() || a.edgeCount != g.EdgeCount()) {
+		return Result{}, fmt.Errorf("sssp: avoid set built for %d nodes and %d edges, graph has %d and %d",
+			a.nodeCount, a.edgeCount, g.NodeCount(), g.EdgeCount())
+	}
+	edgeOK := g.queryFilter(source, opts)
+	if edgeOK == nil && opts.Avoid == nil {
+		if opts.DistancesOnly {
+			return g.runDistances(source, mode)
+		}
+		return g.Run(source, mode)
+	}
+	hooks := &runHooks{avoid: opts.Avoid, edgeOK: edgeOK}
+	var res Result
+	if opts.DistancesOnly {
+		n := g.NodeCount()
+		if err := checkGoRun(n, source, mode); err != nil {
+			return Result{}, err
+		}
+		res = Result{Dist: make([]float32, n), graph: g}
+		res.Stats = runGoCSR(g.offsets, g.targets, g.weights, source, mode, res.Dist, nil, hooks)
+		return res, nil
+	}
+	err := g.runGo(source, mode, &res, hooks)
+	return res, err
+}
+
+// runDistances is Run without predecessors, through a batch of one source
+// where the engine offers batches.
+func (g *Graph) runDistances(source uint32, mode int) (Result, error) {
+	if !Capabilities().Has(CapBatch) {
+		res, err := g.Run(source, mode)
+		res.Pred = nil
+		return res, err
+	}
+	if n := g.NodeCount(); source >= n {
+		return Result{}, fmt.Errorf("sssp: source %d out of range for %d nodes", source, n)
+	}
+	b, err := g.RunBatch([]uint32{source}, mode)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{Dist: b.Dist, Stats: b.Stats, graph: g}, nil
+}
+
+// queryFilter combines the filters of opts into one edge check for
+// runHooks, or returns nil if there are none.
+func (g *Graph) queryFilter(source uint32, opts QueryOptions) func(u uint32, e uint64) bool {
+	nodeOK, edgeOK := opts.NodeFilter, opts.EdgeFilter
+	if nodeOK == nil && edgeOK == nil {
+		return nil
+	}
+	var verdict []uint8 // per node: 0 not asked yet, 1 allowed, 2 rejected
+	if nodeOK != nil {
+		verdict = make([]uint8, g.NodeCount())
+		if source < g.NodeCount() {
+			verdict[source] = 1
+		}
+	}
+	return func(u uint32, e uint64) bool {
+		v := g.targets[e]
+		if verdict != nil {
+			if verdict[v] == 0 {
+				verdict[v] = 2
+				if nodeOK(v) {
+					verdict[v] = 1
+				}
+			}
+			if verdict[v] == 2 {
+				return false
+			}
+		}
+		return edgeOK == nil || edgeOK(int(e), Edge{From: u, To: v, Weight: g.weights[e]})
+	}
+}
+
+// Reweighted returns a view of g whose edge weights are fn of g's. It
+// shares g's offsets, targets, coordinates and attributes, so it costs one
+// weight array; every algorithm taking a *Graph accepts it. Weight updates
+// to g are not seen by the view, and adding or removing edges of g
+// invalidates it; changes to the view copy the shared arrays first.
+func (g *Graph) Reweighted(fn WeightFunc) *Graph {
+	weights := make([]float32, len(g.weights))
+	for u := uint32(0); u < g.NodeCount(); u++ {
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			weights[e] = fn(u, g.targets[e], g.weights[e])
+		}
+	}
+	return g.withWeights(weights)
+}
+
+// WithWeights returns a view of g whose edge weights are weights, indexed
+// by edge ID, such as per-edge travel times computed from attributes. Like
+// Reweighted it shares the rest of g; the slice is kept, not copied.
+func (g *Graph) WithWeights(weights []float32) (*Graph, error) {
+	if len(weights) != len(g.weights) {
+		return nil, fmt.Errorf("sssp: %d weights for %d edges", len(weights), len(g.weights))
+	}
+	return g.withWeights(weights), nil
+}
+
+// withWeights returns a view of g with the given weights, sharing the rest.
+func (g *Graph) withWeights(weights []float32) *Graph {
+	return &Graph{offsets: g.offsets, targets: g.targets, weights: weights, coords: g.coords,
+		nodeAttrs: maps.Clone(g.nodeAttrs), edgeAttrs: maps.Clone(g.edgeAttrs)}
+}
+
+// WeightedSource returns src with fn applied to each edge as it is
+// generated, the GraphSource counterpart of Reweighted that needs no
+// pre-pass.
+func WeightedSource(src GraphSource, fn WeightFunc) GraphSource {
+	return FuncSource{Nodes: src.NodeCount(), Neighbors: func(u uint32, yield func(uint32, float32) bool) {
+		src.ForEachNeighbor(u, func(v uint32, w float32) bool { return yield(v, fn(u, v, w)) })
+	}}
+}
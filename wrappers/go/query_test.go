@@ -0,0 +1,119 @@
+package sssp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRunQueryWeightFunc(t *testing.T) {
+	// 0->1->3 is short but 1->3 has a toll; 0->2->3 is longer and free.
+	g, err := FromEdges(4, []Edge{{0, 1, 1}, {1, 3, 1}, {0, 2, 2}, {2, 3, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tolls := func(u, v uint32, w float32) float32 {
+		if u == 1 && v == 3 {
+			return w + 10
+		}
+		return w
+	}
+	for _, mode := range []int{ModeBaseline, ModeStoc, ModeAutotune} {
+		res, err := g.RunQuery(0, mode, QueryOptions{Weight: tolls})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Dist[3] != 4 || res.Pred[3] != 2 {
+			t.Fatalf("mode %d: dist %v pred %v", mode, res.Dist, res.Pred)
+		}
+	}
+	if res, _ := g.Run(0, ModeBaseline); res.Dist[3] != 2 {
+		t.Fatalf("stored weights changed: %v", res.Dist)
+	}
+
+	closed := g.Reweighted(func(u, v uint32, w float32) float32 {
+		if v == 3 {
+			return float32(math.Inf(1))
+		}
+		return w
+	})
+	if res, _ := closed.Run(0, ModeBaseline); res.Reachable(3) {
+		t.Fatalf("closed edges used: %v", res.Dist)
+	}
+	res, err := RunSource(WeightedSource(g, tolls), 0, ModeBaseline)
+	if err != nil || res.Dist[3] != 4 {
+		t.Fatalf("weighted source: %v %v", err, res.Dist)
+	}
+}
+
+func TestRunQueryFilters(t *testing.T) {
+	g, err := FromEdges(5, []Edge{{0, 1, 1}, {1, 4, 1}, {0, 2, 2}, {2, 4, 2}, {0, 3, 1}, {3, 4, 9}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ferry := make([]bool, g.EdgeCount())
+	ferry[g.EdgeIDs(2, 4)[0]] = true
+	if err := SetEdgeAttr(g, "ferry", ferry); err != nil {
+		t.Fatal(err)
+	}
+	asked := make([]int, 5)
+	opts := QueryOptions{
+		NodeFilter: func(v uint32) bool { asked[v]++; return v != 1 },
+		EdgeFilter: func(id int, e Edge) bool { return !ferry[id] },
+	}
+	for _, mode := range []int{ModeBaseline, ModeStoc, ModeAutotune} {
+		clear(asked)
+		res, err := g.RunQuery(0, mode, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Reachable(1) || res.Dist[4] != 10 || res.Pred[4] != 3 {
+			t.Fatalf("mode %d: dist %v pred %v", mode, res.Dist, res.Pred)
+		}
+		for v, n := range asked {
+			if n > 1 {
+				t.Fatalf("mode %d: node filter asked %d times about %d", mode, n, v)
+			}
+		}
+	}
+	res, err := g.RunQuery(0, ModeBaseline, QueryOptions{
+		Weight:     func(u, v uint32, w float32) float32 { return 2 * w },
+		EdgeFilter: func(id int, e Edge) bool { return e.Weight < 10 },
+	})
+	if err != nil || res.Dist[4] != 4 || res.Pred[4] != 1 {
+		t.Fatalf("weighted and filtered: %v %v", err, res.Dist)
+	}
+}
+
+func TestRunQueryDistancesOnly(t *testing.T) {
+	g := randomGraph(t, 400, 2000, 13)
+	blocked := func(node uint32) bool { return node%17 != 0 }
+	for _, mode := range []int{ModeBaseline, ModeStoc, ModeAutotune} {
+		for _, opts := range []QueryOptions{{}, {NodeFilter: blocked}} {
+			want, err := g.RunQuery(9, mode, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts.DistancesOnly = true
+			got, err := g.RunQuery(9, mode, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Pred != nil || len(got.Dist) != 400 || got.Path(want.ReachableNodes()[1]) != nil {
+				t.Fatalf("mode %d: predecessors recorded", mode)
+			}
+			// Native delta-stepping is not exact on every graph, and its
+			// batch entry point differs from the single run; baseline and
+			// filtered (pure-Go) runs must agree exactly.
+			if mode != ModeBaseline && opts.NodeFilter == nil {
+				continue
+			}
+			v, err := g.Verify(9, &got, want.Dist, Tolerance{})
+			if err != nil || !v.OK() {
+				t.Fatalf("mode %d, filtered %v: %v %v", mode, opts.NodeFilter != nil, v, err)
+			}
+		}
+	}
+	if _, err := g.RunQuery(400, ModeBaseline, QueryOptions{DistancesOnly: true}); err == nil {
+		t.Error("out-of-range source accepted")
+	}
+}
@@ -0,0 +1,7 @@
+//go:build race
+
+package sssp
+
+// raceEnabled reports a -race build, where sync.Pool drops items at random
+// and allocation budgets cannot hold.
+const raceEnabled = true
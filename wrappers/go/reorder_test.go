@@ -0,0 +1,102 @@
+package sssp
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// shuffledGrid is a 30x30 grid with its node IDs scrambled, so any
+// locality is left for Reorder to find.
+func shuffledGrid(t *testing.T) *Graph {
+	t.Helper()
+	grid, err := GenerateGrid(GridParams{Dims: []uint32{30, 30}}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	perm := rand.New(rand.NewSource(2)).Perm(int(grid.NodeCount()))
+	order := make([]uint32, len(perm))
+	for i, v := range perm {
+		order[i] = uint32(v)
+	}
+	g, _, err := grid.Subgraph(order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+// gaps returns the largest and the mean |u-v| over the edges of g.
+func gaps(g *Graph) (int, float64) {
+	most, sum := 0, 0
+	for _, e := range g.Edges() {
+		d := int(e.From) - int(e.To)
+		if d < 0 {
+			d = -d
+		}
+		most, sum = max(most, d), sum+d
+	}
+	return most, float64(sum) / float64(g.EdgeCount())
+}
+
+func TestReorder(t *testing.T) {
+	g := shuffledGrid(t)
+	coords := make([]LatLon, g.NodeCount())
+	for v := range coords {
+		coords[v] = LatLon{Lat: float64(v)}
+	}
+	if err := g.SetCoords(coords); err != nil {
+		t.Fatal(err)
+	}
+	want, err := g.RunSettled(17, ModeBaseline, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	band, mean := gaps(g)
+	for st := OrderBFS; st <= OrderGorder; st++ {
+		h, p, err := g.Reorder(st)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, v := range p.Old {
+			if p.New[v] != uint32(i) {
+				t.Fatalf("%v: New and Old are not inverse at %d", st, i)
+			}
+			if h.Coords()[i] != coords[v] {
+				t.Fatalf("%v: coordinates of node %d not carried", st, v)
+			}
+		}
+		res, err := h.RunSettled(p.New[17], ModeBaseline, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		back := p.ResultToOld(res)
+		if !slices.Equal(back.Dist, want.Dist) {
+			t.Fatalf("%v: distances differ after mapping back", st)
+		}
+		if path := back.Path(899); len(path) == 0 || path[0] != 17 || path[len(path)-1] != 899 {
+			t.Fatalf("%v: mapped path %v", st, path)
+		}
+		b, m := gaps(h)
+		switch st {
+		case OrderBFS, OrderRCM:
+			if b >= band/4 {
+				t.Fatalf("%v: bandwidth %d, shuffled %d", st, b, band)
+			}
+		case OrderGorder:
+			if m >= mean/4 {
+				t.Fatalf("%v: mean gap %.1f, shuffled %.1f", st, m, mean)
+			}
+		case OrderDegree:
+			if h.Degree(0) != 4 || h.Degree(h.NodeCount()-1) != 2 {
+				t.Fatalf("degree order starts with degree %d, ends with %d", h.Degree(0), h.Degree(h.NodeCount()-1))
+			}
+		}
+	}
+	if _, _, err := g.Reorder(OrderGorder + 1); err == nil {
+		t.Fatalf("unknown strategy accepted")
+	}
+	if st, err := ParseReorderStrategy("rcm"); err != nil || st != OrderRCM {
+		t.Fatalf("ParseReorderStrategy(rcm) = %v, %v", st, err)
+	}
+}
@@ -0,0 +1,145 @@
+package sssp
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/your-org/optimized-sssp-go/internal/decompress"
+)
+
+// Binary result layout, 8 bytes per node against 20-30 in JSON:
+//
+//	0   magic   "SSSPRES\x00"
+//	8   version uint32 (1)
+//	12  s       uint32, length of the stats block
+//	16  n       uint64
+//	24  reserved, zero
+//	32  stats   [s]byte, zero-padded to 8 bytes
+//	    dist    [n]float32, pred [n]int32
+//
+// All values are little-endian. The version 1 stats block holds, in order:
+// Relaxations, LightRelaxations, HeavyRelaxations as uint64; Settled,
+// ErrorCode, Version, Delta, DeltaMultiplier, Buckets, AutotuneTrials and
+// Fallback (0 or 1) as 32-bit values; then the six OpCounters as uint64.
+// Readers ignore stats bytes past the fields they know and zero fields a
+// shorter block lacks, so the block can grow without a version bump.
+
+const (
+	resultMagic      = "SSSPRES\x00"
+	resultVersion    = 1
+	resultHeaderSize = 32
+	resultStatsSize  = 104
+)
+
+// WriteBinary writes r in the binary result layout, streaming the arrays
+// through a buffer rather than building the whole encoding in memory. Wrap
+// w in a gzip or zstd writer for smaller files; ReadResultBinary
+// decompresses either.
+func (r *Result) WriteBinary(w io.Writer) error {
+	n := len(r.Dist)
+	if len(r.Pred) != n {
+		return fmt.Errorf("sssp: result: %d distances but %d predecessors", n, len(r.Pred))
+	}
+	bw := bufio.NewWriterSize(w, 1<<16)
+	var hdr [resultHeaderSize + resultStatsSize]byte
+	copy(hdr[:], resultMagic)
+	binary.LittleEndian.PutUint32(hdr[8:], resultVersion)
+	binary.LittleEndian.PutUint32(hdr[12:], resultStatsSize)
+	binary.LittleEndian.PutUint64(hdr[16:], uint64(n))
+	s, b := &r.Stats, hdr[resultHeaderSize:]
+	for i, v := range []uint64{s.Relaxations, s.LightRelaxations, s.HeavyRelaxations} {
+		binary.LittleEndian.PutUint64(b[8*i:], v)
+	}
+	var fallback uint32
+	if s.Fallback {
+		fallback = 1
+	}
+	for i, v := range []uint32{s.Settled, uint32(s.ErrorCode), s.Version, math.Float32bits(s.Delta),
+		math.Float32bits(s.DeltaMultiplier), s.Buckets, s.AutotuneTrials, fallback} {
+		binary.LittleEndian.PutUint32(b[24+4*i:], v)
+	}
+	o := s.Ops
+	for i, v := range []uint64{o.EdgeRelaxations, o.Improvements, o.HeapPushes, o.HeapPops, o.FrontierExpansions, o.BucketScans} {
+		binary.LittleEndian.PutUint64(b[56+8*i:], v)
+	}
+	bw.Write(hdr[:])
+	var v [4]byte
+	for _, d := range r.Dist {
+		binary.LittleEndian.PutUint32(v[:], math.Float32bits(d))
+		bw.Write(v[:])
+	}
+	for _, p := range r.Pred {
+		binary.LittleEndian.PutUint32(v[:], uint32(p))
+		bw.Write(v[:])
+	}
+	return bw.Flush()
+}
+
+// ReadResultBinary reads a result written by WriteBinary, gzip- or
+// zstd-compressed or not.
+func ReadResultBinary(rd io.Reader) (*Result, error) {
+	rd, err := decompress.Reader(rd)
+	if err != nil {
+		return nil, err
+	}
+	br := bufio.NewReaderSize(rd, 1<<16)
+	var hdr [resultHeaderSize]byte
+	if _, err := io.ReadFull(br, hdr[:]); err != nil || string(hdr[:8]) != resultMagic {
+		return nil, fmt.Errorf("sssp: result: bad magic")
+	}
+	if v := binary.LittleEndian.Uint32(hdr[8:]); v != resultVersion {
+		return nil, fmt.Errorf("sssp: result: unsupported version %d", v)
+	}
+	statsLen, n := binary.LittleEndian.Uint32(hdr[12:]), binary.LittleEndian.Uint64(hdr[16:])
+	if n > math.MaxUint32 || statsLen > 1<<20 {
+		return nil, fmt.Errorf("sssp: result: implausible header (%d nodes, %d stats bytes)", n, statsLen)
+	}
+	stats := make([]byte, max(align8(uint64(statsLen)), resultStatsSize))
+	if _, err := io.ReadFull(br, stats[:align8(uint64(statsLen))]); err != nil {
+		return nil, fmt.Errorf("sssp: result: stats: %w", err)
+	}
+	clear(stats[statsLen:]) // padding, or fields an older writer did not know
+	r := &Result{}
+	s := &r.Stats
+	s.Relaxations = binary.LittleEndian.Uint64(stats[0:])
+	s.LightRelaxations = binary.LittleEndian.Uint64(stats[8:])
+	s.HeavyRelaxations = binary.LittleEndian.Uint64(stats[16:])
+	u32 := func(i int) uint32 { return binary.LittleEndian.Uint32(stats[24+4*i:]) }
+	s.Settled, s.ErrorCode, s.Version = u32(0), int32(u32(1)), u32(2)
+	s.Delta, s.DeltaMultiplier = math.Float32frombits(u32(3)), math.Float32frombits(u32(4))
+	s.Buckets, s.AutotuneTrials, s.Fallback = u32(5), u32(6), u32(7) != 0
+	for i, c := range []*uint64{&s.Ops.EdgeRelaxations, &s.Ops.Improvements, &s.Ops.HeapPushes,
+		&s.Ops.HeapPops, &s.Ops.FrontierExpansions, &s.Ops.BucketScans} {
+		*c = binary.LittleEndian.Uint64(stats[56+8*i:])
+	}
+	// Grow the arrays as data arrives, so a corrupt header cannot make us
+	// allocate far more than the input holds.
+	buf := make([]byte, 1<<16)
+	read := func(each func(uint32)) error {
+		for left := 4 * n; left > 0; {
+			chunk := buf[:min(left, uint64(len(buf)))]
+			if _, err := io.ReadFull(br, chunk); err != nil {
+				return err
+			}
+			for i := 0; i < len(chunk); i += 4 {
+				each(binary.LittleEndian.Uint32(chunk[i:]))
+			}
+			left -= uint64(len(chunk))
+		}
+		return nil
+	}
+	if err := read(func(v uint32) { r.Dist = append(r.Dist, math.Float32frombits(v)) }); err != nil {
+		return nil, fmt.Errorf("sssp: result: distances: %w", err)
+	}
+	r.Pred = make([]int32, 0, n)
+	if err := read(func(v uint32) { r.Pred = append(r.Pred, int32(v)) }); err != nil {
+		return nil, fmt.Errorf("sssp: result: predecessors: %w", err)
+	}
+	if r.Dist == nil {
+		r.Dist = []float32{}
+	}
+	return r, nil
+}
@@ -0,0 +1,59 @@
+package sssp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"reflect"
+	"testing"
+)
+
+func TestResultBinaryRoundTrip(t *testing.T) {
+	g, err := GenerateRandomGraph(500, 3, 2, RandomGraphOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := g.Run(4, ModeAutotune)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Stats.Fallback, res.Stats.ErrorCode = true, -3
+	var b bytes.Buffer
+	if err := res.WriteBinary(&b); err != nil {
+		t.Fatal(err)
+	}
+	if want := resultHeaderSize + resultStatsSize + 8*500; b.Len() != want {
+		t.Fatalf("encoded %d bytes, want %d", b.Len(), want)
+	}
+	raw := b.Bytes()
+	back, err := ReadResultBinary(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	back.graph = res.graph
+	if !reflect.DeepEqual(*back, res) {
+		t.Fatalf("round trip changed the result:\n%+v\n%+v", back.Stats, res.Stats)
+	}
+
+	var z bytes.Buffer
+	zw := gzip.NewWriter(&z)
+	zw.Write(raw)
+	zw.Close()
+	if back, err := ReadResultBinary(&z); err != nil || !reflect.DeepEqual(back.Dist, res.Dist) {
+		t.Fatalf("gzipped: %v", err)
+	}
+
+	for _, bad := range [][]byte{raw[:20], raw[:len(raw)-1], append([]byte("XXXXXXXX"), raw[8:]...)} {
+		if _, err := ReadResultBinary(bytes.NewReader(bad)); err == nil {
+			t.Fatalf("accepted %d corrupt bytes", len(bad))
+		}
+	}
+	if err := (&Result{Dist: []float32{0}}).WriteBinary(&b); err == nil {
+		t.Fatal("mismatched arrays accepted")
+	}
+	empty := Result{Dist: []float32{}, Pred: []int32{}}
+	b.Reset()
+	empty.WriteBinary(&b)
+	if back, err := ReadResultBinary(&b); err != nil || len(back.Dist) != 0 || back.Pred == nil {
+		t.Fatalf("empty result: %+v, %v", back, err)
+	}
+}
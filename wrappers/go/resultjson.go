@@ -0,0 +1,193 @@
+package sssp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/your-org/optimized-sssp-go/internal/decompress"
+)
+
+// Canonical JSON result format (version 1):
+//
+//	{
+//	  "version": 1,
+//	  "node_count": 3,
+//	  "stats": {"relaxations": 4, ..., "ops": {"edge_relaxations": 4, ...}},
+//	  "dist": [0, 2.5, null],
+//	  "pred": [-1, 0, -1]
+//	}
+//
+// The layout is flat: "dist" and "pred" are arrays indexed by node, with
+// null marking an unreachable node's distance and -1 a missing
+// predecessor. Both are omitted in stats-only exports. "stats" carries
+// every Stats field under its snake_case name, as listed in jsonStats.
+// Fields are only ever added within a version; a change to existing ones
+// bumps "version", and decoders reject versions they do not know.
+
+const resultJSONVersion = 1
+
+// ResultJSONOptions configures Result.EncodeJSON.
+type ResultJSONOptions struct {
+	// StatsOnly leaves out "dist" and "pred", for exporting the counters
+	// of runs on large graphs.
+	StatsOnly bool
+}
+
+type jsonOps struct {
+	EdgeRelaxations    uint64 `json:"edge_relaxations"`
+	Improvements       uint64 `json:"improvements"`
+	HeapPushes         uint64 `json:"heap_pushes"`
+	HeapPops           uint64 `json:"heap_pops"`
+	FrontierExpansions uint64 `json:"frontier_expansions"`
+	BucketScans        uint64 `json:"bucket_scans"`
+}
+
+type jsonStats struct {
+	Relaxations      uint64  `json:"relaxations"`
+	LightRelaxations uint64  `json:"light_relaxations"`
+	HeavyRelaxations uint64  `json:"heavy_relaxations"`
+	Settled          uint32  `json:"settled"`
+	ErrorCode        int32   `json:"error_code"`
+	Version          uint32  `json:"version"`
+	Delta            float32 `json:"delta"`
+	DeltaMultiplier  float32 `json:"delta_multiplier"`
+	Buckets          uint32  `json:"buckets"`
+	AutotuneTrials   uint32  `json:"autotune_trials"`
+	Fallback         bool    `json:"fallback"`
+	Ops              jsonOps `json:"ops"`
+}
+
+// jsonDist is a distance that decodes null as +Inf.
+type jsonDist float32
+
+func (d *jsonDist) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*d = jsonDist(math.Inf(1))
+		return nil
+	}
+	v, err := strconv.ParseFloat(string(b), 32)
+	if err != nil {
+		return fmt.Errorf("bad distance %s", b)
+	}
+	*d = jsonDist(v)
+	return nil
+}
+
+// MarshalJSON encodes r in the canonical JSON result format. It has a value
+// receiver so that json.Marshal handles a Result as returned by Run, whose
+// +Inf distances the default encoding would reject.
+func (r Result) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := r.EncodeJSON(&buf, ResultJSONOptions{}); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// UnmarshalJSON decodes the canonical JSON result format into r.
+func (r *Result) UnmarshalJSON(data []byte) error {
+	dec, err := DecodeResultJSON(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	*r = *dec
+	return nil
+}
+
+// EncodeJSON streams r to w in the canonical JSON result format.
+func (r *Result) EncodeJSON(w io.Writer, opts ResultJSONOptions) error {
+	if len(r.Pred) != len(r.Dist) {
+		return fmt.Errorf("sssp: json: %d distances but %d predecessors", len(r.Dist), len(r.Pred))
+	}
+	s := r.Stats
+	o := s.Ops
+	stats, err := json.Marshal(jsonStats{
+		Relaxations: s.Relaxations, LightRelaxations: s.LightRelaxations, HeavyRelaxations: s.HeavyRelaxations,
+		Settled: s.Settled, ErrorCode: s.ErrorCode, Version: s.Version,
+		Delta: s.Delta, DeltaMultiplier: s.DeltaMultiplier, Buckets: s.Buckets, AutotuneTrials: s.AutotuneTrials,
+		Fallback: s.Fallback,
+		Ops:      jsonOps{o.EdgeRelaxations, o.Improvements, o.HeapPushes, o.HeapPops, o.FrontierExpansions, o.BucketScans},
+	})
+	if err != nil {
+		return fmt.Errorf("sssp: json: %w", err)
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "{\"version\":%d,\"node_count\":%d,\"stats\":%s", resultJSONVersion, len(r.Dist), stats)
+	if !opts.StatsOnly {
+		bw.WriteString(",\n\"dist\":[")
+		for v, d := range r.Dist {
+			if v > 0 {
+				bw.WriteByte(',')
+			}
+			switch f := float64(d); {
+			case math.IsInf(f, 1):
+				bw.WriteString("null")
+			case math.IsInf(f, 0) || math.IsNaN(f):
+				return fmt.Errorf("sssp: json: node %d has distance %v", v, d)
+			default:
+				bw.WriteString(strconv.FormatFloat(f, 'g', -1, 32))
+			}
+		}
+		bw.WriteString("],\n\"pred\":[")
+		for v, p := range r.Pred {
+			if v > 0 {
+				bw.WriteByte(',')
+			}
+			bw.WriteString(strconv.Itoa(int(p)))
+		}
+		bw.WriteByte(']')
+	}
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
+
+// DecodeResultJSON reads a result in the canonical JSON result format. A
+// stats-only export decodes with nil Dist and Pred.
+func DecodeResultJSON(rd io.Reader) (*Result, error) {
+	rd, err := decompress.Reader(rd)
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Version   *int       `json:"version"`
+		NodeCount uint32     `json:"node_count"`
+		Stats     jsonStats  `json:"stats"`
+		Dist      []jsonDist `json:"dist"`
+		Pred      []int32    `json:"pred"`
+	}
+	if err := json.NewDecoder(rd).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("sssp: json: %w", err)
+	}
+	switch {
+	case doc.Version == nil:
+		return nil, fmt.Errorf("sssp: json: result has no version")
+	case *doc.Version != resultJSONVersion:
+		return nil, fmt.Errorf("sssp: json: unsupported result version %d", *doc.Version)
+	case doc.Dist != nil && len(doc.Dist) != int(doc.NodeCount), doc.Pred != nil && len(doc.Pred) != int(doc.NodeCount):
+		return nil, fmt.Errorf("sssp: json: node_count %d but %d distances, %d predecessors",
+			doc.NodeCount, len(doc.Dist), len(doc.Pred))
+	case (doc.Dist == nil) != (doc.Pred == nil):
+		return nil, fmt.Errorf("sssp: json: result needs both dist and pred, or neither")
+	}
+	s := doc.Stats
+	o := s.Ops
+	r := &Result{Pred: doc.Pred, Stats: Stats{
+		Relaxations: s.Relaxations, LightRelaxations: s.LightRelaxations, HeavyRelaxations: s.HeavyRelaxations,
+		Settled: s.Settled, ErrorCode: s.ErrorCode, Version: s.Version,
+		Delta: s.Delta, DeltaMultiplier: s.DeltaMultiplier, Buckets: s.Buckets, AutotuneTrials: s.AutotuneTrials,
+		Fallback: s.Fallback,
+		Ops:      OpCounters{o.EdgeRelaxations, o.Improvements, o.HeapPushes, o.HeapPops, o.FrontierExpansions, o.BucketScans},
+	}}
+	if doc.Dist != nil {
+		r.Dist = make([]float32, len(doc.Dist))
+		for v, d := range doc.Dist {
+			r.Dist[v] = float32(d)
+		}
+	}
+	return r, nil
+}
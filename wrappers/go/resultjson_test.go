@@ -0,0 +1,71 @@
+package sssp
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResultJSONRoundTrip(t *testing.T) {
+	// 0->1 (2.5); node 2 unreachable.
+	g, err := FromEdges(3, []Edge{{0, 1, 2.5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := g.Run(0, ModeBaseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Stats.Delta = 1.5
+	b, err := json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(b)
+	for _, want := range []string{`{"version":1,"node_count":3,"stats":{"relaxations":`, `"delta":1.5`, `"ops":{"edge_relaxations":1,`, `"dist":[0,2.5,null]`, `"pred":[-1,0,-1]}`} {
+		if !strings.Contains(s, want) {
+			t.Fatalf("missing %s in %s", want, s)
+		}
+	}
+	var back Result
+	if err := json.Unmarshal(b, &back); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back.Dist, res.Dist) || !reflect.DeepEqual(back.Pred, res.Pred) || back.Stats != res.Stats {
+		t.Fatalf("round trip: %+v, want %+v", back, res)
+	}
+	if !math.IsInf(float64(back.Dist[2]), 1) {
+		t.Fatalf("unreachable decoded as %v", back.Dist[2])
+	}
+
+	var buf bytes.Buffer
+	if err := res.EncodeJSON(&buf, ResultJSONOptions{StatsOnly: true}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "dist") {
+		t.Fatalf("stats-only export has distances: %s", buf.String())
+	}
+	only, err := DecodeResultJSON(&buf)
+	if err != nil || only.Dist != nil || only.Stats != res.Stats {
+		t.Fatalf("stats-only: %+v, %v", only, err)
+	}
+
+	for _, bad := range []string{
+		`{"node_count":1,"dist":[0],"pred":[-1]}`,
+		`{"version":2,"node_count":1,"dist":[0],"pred":[-1]}`,
+		`{"version":1,"node_count":2,"dist":[0],"pred":[-1]}`,
+		`{"version":1,"node_count":1,"dist":[0]}`,
+		`{"version":1,"node_count":1,"dist":["x"],"pred":[-1]}`,
+	} {
+		if _, err := DecodeResultJSON(strings.NewReader(bad)); err == nil {
+			t.Fatalf("accepted %s", bad)
+		}
+	}
+	res.Dist[1] = float32(math.NaN())
+	if _, err := json.Marshal(res); err == nil {
+		t.Fatal("NaN distance encoded")
+	}
+}
@@ -0,0 +1,157 @@
+package sssp
+
+// Reverse returns the transpose of g, with every edge u->v turned into
+// v->u of the same weight. The in-edges of each node keep the order of
+// their sources. Coordinates are shared; the edge arrays are new, so the
+// result can be modified freely.
+func (g *Graph) Reverse() *Graph {
+	offsets, targets, edge := g.transpose()
+	weights := make([]float32, len(edge))
+	for i, e := range edge {
+		weights[i] = g.weights[e]
+	}
+	rev := &Graph{offsets: offsets, targets: targets, weights: weights, coords: g.coords, owned: true, nodeAttrs: g.nodeAttrs}
+	if len(g.edgeAttrs) > 0 {
+		idx := make([]int32, len(edge))
+		for i, e := range edge {
+			idx[i] = int32(e)
+		}
+		rev.edgeAttrs = gatherAttrs(g.edgeAttrs, idx)
+	}
+	return rev
+}
+
+// ReverseView is the transpose of a graph that shares the graph's weights
+// instead of copying them: it keeps the reversed edge order and, for each
+// reversed edge, the ID of the edge of the graph it came from, and reads
+// weights through that. See Graph.ReverseView.
+type ReverseView struct {
+	g       *Graph
+	offsets []uint32
+	targets []uint32
+	edge    []uint32 // edge ID in g of each reversed edge
+}
+
+// ReverseView returns the transpose of g like Reverse, but builds it only
+// once and keeps it for later calls, for searches that need it per query
+// (from a target, or from both ends). It costs the offsets and two uint32
+// per edge; weights are read from g, so UpdateEdgeWeights shows through at
+// once. Adding, removing or merging edges drops it, and the next call
+// rebuilds it. It is safe to call from concurrent queries.
+func (g *Graph) ReverseView() *ReverseView {
+	if v := g.rev.Load(); v != nil {
+		return v
+	}
+	g.revMu.Lock()
+	defer g.revMu.Unlock()
+	if v := g.rev.Load(); v != nil {
+		return v
+	}
+	offsets, targets, edge := g.transpose()
+	v := &ReverseView{g: g, offsets: offsets, targets: targets, edge: edge}
+	g.rev.Store(v)
+	return v
+}
+
+// NodeCount returns the number of nodes.
+func (r *ReverseView) NodeCount() uint32 { return uint32(len(r.offsets) - 1) }
+
+// EdgeCount returns the number of directed edges.
+func (r *ReverseView) EdgeCount() int { return len(r.targets) }
+
+// ForEachNeighbor calls fn for each in-edge of u in the graph, as an
+// out-edge of the transpose, until fn returns false. It does not allocate.
+func (r *ReverseView) ForEachNeighbor(u uint32, fn func(to uint32, w float32) bool) {
+	if u >= r.NodeCount() {
+		return
+	}
+	for i := r.offsets[u]; i < r.offsets[u+1]; i++ {
+		if !fn(r.targets[i], r.g.weights[r.edge[i]]) {
+			return
+		}
+	}
+}
+
+// HasEdge reports whether the transpose has an edge from u to v.
+func (r *ReverseView) HasEdge(u, v uint32) bool {
+	_, ok := r.EdgeWeight(u, v)
+	return ok
+}
+
+// EdgeWeight returns the weight of the edge from u to v of the transpose,
+// the smallest one if there are parallel edges, and whether there is such
+// an edge.
+func (r *ReverseView) EdgeWeight(u, v uint32) (float32, bool) {
+	w, ok := float32(0), false
+	r.ForEachNeighbor(u, func(to uint32, x float32) bool {
+		if to == v && (!ok || x < w) {
+			w, ok = x, true
+		}
+		return true
+	})
+	return w, ok
+}
+
+// Edges returns every edge of the transpose in CSR order.
+func (r *ReverseView) Edges() []Edge {
+	out := make([]Edge, 0, len(r.targets))
+	for u := uint32(0); u < r.NodeCount(); u++ {
+		r.ForEachNeighbor(u, func(to uint32, w float32) bool {
+			out = append(out, Edge{From: u, To: to, Weight: w})
+			return true
+		})
+	}
+	return out
+}
+
+// Run executes mode from source on the transpose, giving distances to
+// source on the graph. The weights are gathered into reverse order in a
+// slice of the result's own, with the graph's coordinates, so Path,
+// SimplifiedPath and the GeoJSON output of the result stay valid whatever
+// runs or weight updates follow.
+func (r *ReverseView) Run(source uint32, mode int) (Result, error) {
+	weights := make([]float32, len(r.edge))
+	for i, e := range r.edge {
+		weights[i] = r.g.weights[e]
+	}
+	return (&Graph{offsets: r.offsets, targets: r.targets, weights: weights, coords: r.g.coords}).Run(source, mode)
+}
+
+// transpose sorts the edges of g by target with a counting sort, returning
+// the reverse CSR arrays and the edge of g each reversed edge came from.
+func (g *Graph) transpose() (offsets, targets, edge []uint32) {
+	n := g.NodeCount()
+	offsets = make([]uint32, n+1)
+	for _, v := range g.targets {
+		offsets[v+1]++
+	}
+	for v := uint32(0); v < n; v++ {
+		offsets[v+1] += offsets[v]
+	}
+	targets = make([]uint32, len(g.targets))
+	edge = make([]uint32, len(g.targets))
+	next := make([]uint32, n)
+	copy(next, offsets[:n])
+	for u := uint32(0); u < n; u++ {
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			v := g.targets[e]
+			i := next[v]
+			next[v]++
+			targets[i], edge[i] = u, e
+		}
+	}
+	return offsets, targets, edge
+}
+
+// inEdges returns the topology of the transpose without weights, for
+// algorithms that only follow in-edges.
+func (g *Graph) inEdges() *Graph {
+	offsets, targets, _ := g.transpose()
+	return &Graph{offsets: offsets, targets: targets}
+}
+
+// dropReverse discards the reverse view after g changed.
+func (g *Graph) dropReverse() {
+	g.rev.Store(nil)
+	g.version++
+}
@@ -0,0 +1,109 @@
+package sssp
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestReverse(t *testing.T) {
+	g, err := FromEdges(4, []Edge{{0, 1, 1}, {2, 1, 2}, {0, 2, 3}, {1, 3, 4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Edge{{1, 0, 1}, {1, 2, 2}, {2, 0, 3}, {3, 1, 4}}
+	if got := g.Reverse().Edges(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("reverse %v, want %v", got, want)
+	}
+	if back := g.Reverse().Reverse(); !reflect.DeepEqual(back.Edges(), g.Edges()) {
+		t.Fatalf("double reverse %v", back.Edges())
+	}
+
+	view := g.ReverseView()
+	if g.ReverseView() != view {
+		t.Fatalf("view rebuilt without changes")
+	}
+	if err := g.UpdateEdgeWeight(2, 1, 7); err != nil {
+		t.Fatal(err)
+	}
+	if w, _ := view.EdgeWeight(1, 2); w != 7 || g.ReverseView() != view {
+		t.Fatalf("weight update not applied to the view: %v", w)
+	}
+	g.RemoveEdge(0, 1)
+	if v := g.ReverseView(); v == view || v.HasEdge(1, 0) || v.EdgeCount() != 3 {
+		t.Fatalf("view kept after removal: %v", v.Edges())
+	}
+
+	// Distances to a target are distances from it on the reverse graph, up to
+	// the order the path is summed in.
+	r, err := GenerateRandomGraph(500, 4, 2, RandomGraphOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, err := r.ReverseView().Run(9, ModeBaseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []uint32{0, 100, 499} {
+		from, err := r.Run(s, ModeBaseline)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !DefaultTolerance.agree(float64(from.Dist[9]), float64(to.Dist[s])) {
+			t.Fatalf("dist %d->9: %v forward, %v on the reverse", s, from.Dist[9], to.Dist[s])
+		}
+	}
+}
+
+func TestReverseViewConcurrent(t *testing.T) {
+	// Backward queries sharing a graph build its view together; run with
+	// -race.
+	g, err := GenerateRandomGraph(400, 4, 5, RandomGraphOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := g.Reverse().Run(3, ModeBaseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := g.ReverseView().Run(3, ModeBaseline)
+			if err != nil || !reflect.DeepEqual(got.Dist, want.Dist) {
+				t.Errorf("view run differs from Reverse: %v", err)
+			}
+			if _, err := g.NearestKTo([]uint32{10, 20, 30}, 3, 2); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestReverseViewRunKeepsItsWeights(t *testing.T) {
+	g, err := FromEdges(3, []Edge{{0, 1, 1}, {1, 2, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	view := g.ReverseView()
+	res, err := view.Run(2, ModeBaseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := append([]float32(nil), res.graph.weights...)
+	if err := g.UpdateEdgeWeights([]Edge{{0, 1, 5}, {1, 2, 6}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := view.Run(1, ModeBaseline); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(res.graph.weights, before) {
+		t.Fatalf("result graph weights changed under a later run: %v, was %v", res.graph.weights, before)
+	}
+	if p := res.Path(0); !reflect.DeepEqual(p, []uint32{2, 1, 0}) {
+		t.Fatalf("path %v", p)
+	}
+}
@@ -0,0 +1,113 @@
+package sssp
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// RMATParams configures GenerateRMAT. Zero fields take the Graph500 defaults.
+type RMATParams struct {
+	Scale      uint32 // 2^Scale nodes
+	EdgeFactor uint32 // EdgeFactor * 2^Scale edges; 0 means 16
+	// A, B and C are the probabilities of recursing into the top-left,
+	// top-right and bottom-left quadrant of the adjacency matrix; the
+	// bottom-right gets 1-A-B-C. All zero means 0.57, 0.19, 0.19 (D = 0.05).
+	A, B, C float64
+	Weights WeightDist
+	// Workers generating edges in parallel; 0 means GOMAXPROCS. The graph
+	// does not depend on it.
+	Workers int
+}
+
+// GenerateRMAT returns a directed R-MAT (recursive matrix, the Kronecker
+// generator of Graph500) graph. Each edge picks a quadrant Scale times to
+// find its endpoints; node ids are then randomly permuted so hubs are not
+// clustered at low ids. As in Graph500, self-loops and duplicate edges are
+// kept. Each node's edges are sorted by target.
+func GenerateRMAT(p RMATParams, seed int64) (*Graph, error) {
+	gen, err := rmatGen(p, seed)
+	if err != nil {
+		return nil, err
+	}
+	return gen.graph(p.Workers), nil
+}
+
+// WriteRMATSnapshot generates the GenerateRMAT graph straight into a
+// snapshot file (see SaveSnapshot) without holding it on the heap: the file
+// is mapped and filled in place, so a billion-edge graph needs little more
+// than its 8 bytes per edge of page cache. Open it with OpenMapped.
+func WriteRMATSnapshot(path string, p RMATParams, seed int64) error {
+	gen, err := rmatGen(p, seed)
+	if err != nil {
+		return err
+	}
+	return gen.writeSnapshot(path, p.Workers)
+}
+
+func rmatGen(p RMATParams, seed int64) (*edgeGen, error) {
+	if p.Scale == 0 || p.Scale > 31 {
+		return nil, fmt.Errorf("sssp: generate: R-MAT scale %d outside 1..31", p.Scale)
+	}
+	if p.EdgeFactor == 0 {
+		p.EdgeFactor = 16
+	}
+	if p.A == 0 && p.B == 0 && p.C == 0 {
+		p.A, p.B, p.C = 0.57, 0.19, 0.19
+	}
+	if p.A < 0 || p.B < 0 || p.C < 0 || p.A+p.B+p.C > 1 {
+		return nil, fmt.Errorf("sssp: generate: R-MAT probabilities a=%v b=%v c=%v do not leave d >= 0", p.A, p.B, p.C)
+	}
+	if err := p.Weights.validate(); err != nil {
+		return nil, err
+	}
+	n := uint32(1) << p.Scale
+	m := uint64(p.EdgeFactor) << p.Scale
+	if err := checkEdgeCount(m); err != nil {
+		return nil, err
+	}
+	perm := make([]uint32, n)
+	for i := range perm {
+		perm[i] = uint32(i)
+	}
+	r := rand.New(rand.NewSource(seed))
+	r.Shuffle(len(perm), func(i, j int) { perm[i], perm[j] = perm[j], perm[i] })
+	t := newRMATThresholds(p)
+	return &edgeGen{n: n, m: m, seed: seed, edge: func(r *rand.Rand, _ uint64) Edge {
+		u, v := rmatEdge(r, p.Scale, t)
+		return Edge{From: perm[u], To: perm[v], Weight: p.Weights.sample(r)}
+	}}, nil
+}
+
+// rmatEdge descends Scale levels, spending 32 random bits per level.
+func rmatEdge(r *rand.Rand, scale uint32, t rmatThresholds) (u, v uint32) {
+	var bits uint64
+	for level := uint32(0); level < scale; level++ {
+		if level%2 == 0 {
+			bits = r.Uint64()
+		} else {
+			bits >>= 32
+		}
+		// Quadrant picks are coin flips to the CPU; keep them branch-free.
+		x := uint32(bits)
+		hi := b2u(x >= t.ab)                         // C or D
+		right := b2u(x >= t.a)&^hi | b2u(x >= t.abc) // B or D
+		u = u<<1 | hi
+		v = v<<1 | right
+	}
+	return u, v
+}
+
+func b2u(b bool) uint32 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// rmatThresholds are the cumulative quadrant probabilities scaled to 2^32.
+type rmatThresholds struct{ a, ab, abc uint32 }
+
+func newRMATThresholds(p RMATParams) rmatThresholds {
+	scale := func(x float64) uint32 { return uint32(min(x*(1<<32), 1<<32-1)) }
+	return rmatThresholds{scale(p.A), scale(p.A + p.B), scale(p.A + p.B + p.C)}
+}
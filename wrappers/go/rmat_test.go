@@ -0,0 +1,40 @@
+package sssp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGenerateRMATIsSkewedAndWorkerIndependent(t *testing.T) {
+	p := RMATParams{Scale: 12, EdgeFactor: 8, Workers: 1}
+	g, err := GenerateRMAT(p, 5)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if g.NodeCount() != 4096 || g.EdgeCount() != 8*4096 {
+		t.Fatalf("%d nodes %d edges", g.NodeCount(), g.EdgeCount())
+	}
+	p.Workers = 7
+	par, _ := GenerateRMAT(p, 5)
+	if !reflect.DeepEqual(g.Edges(), par.Edges()) {
+		t.Fatalf("worker count changed the graph")
+	}
+
+	off, _, _ := g.CSR()
+	var maxDeg, isolated uint32
+	for u := 0; u < 4096; u++ {
+		d := off[u+1] - off[u]
+		maxDeg = max(maxDeg, d)
+		if d == 0 {
+			isolated++
+		}
+	}
+	// Graph500 parameters give a heavy tail and many empty rows; a uniform
+	// graph of this density would have neither.
+	if maxDeg < 100 || isolated < 400 {
+		t.Fatalf("max out-degree %d, %d nodes without out-edges", maxDeg, isolated)
+	}
+	if _, err := GenerateRMAT(RMATParams{Scale: 4, A: 0.6, B: 0.3, C: 0.3}, 1); err == nil {
+		t.Fatalf("expected a+b+c > 1 to fail")
+	}
+}
@@ -0,0 +1,141 @@
+package sssp
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// RoadParams configures GenerateRoadNetwork. Zero fields take the defaults.
+type RoadParams struct {
+	// Nodes is rounded up to a square number of intersections, 100 m apart.
+	Nodes uint32
+	// Every ArterialSpacing-th street (default 8) is an arterial, and every
+	// HighwaySpacing-th (default 32, rounded to a multiple of
+	// ArterialSpacing) carries a highway with exits at the arterials.
+	ArterialSpacing uint32
+	HighwaySpacing  uint32
+	// Dropout is the fraction of local street segments left out (default
+	// 0.1). A segment stays when an endpoint would be left with fewer than
+	// two streets, so dead ends are rare and islands rarer still.
+	Dropout float64
+	// Congestion multiplies each segment's free-flow travel time by a draw
+	// from it. The zero value means ±10% noise (uniform on [0.9, 1.1)).
+	Congestion WeightDist
+}
+
+// Free-flow speeds in metres per second of the three road classes.
+const (
+	roadLocalSpeed    = 30 / 3.6
+	roadArterialSpeed = 60 / 3.6
+	roadHighwaySpeed  = 110 / 3.6
+)
+
+// GenerateRoadNetwork returns a synthetic road network: a jittered street
+// grid of local roads, faster arterials every few blocks, and highways whose
+// edges jump from exit to exit. Weights are travel times in seconds, the
+// segment length over its class speed times a congestion factor, so they
+// correlate with geometry the way real road weights do. Roads are two-way. Node
+// coordinates (around 0°N 0°E) are attached for the GeoJSON exports.
+func GenerateRoadNetwork(p RoadParams, seed int64) (*Graph, error) {
+	if p.ArterialSpacing == 0 {
+		p.ArterialSpacing = 8
+	}
+	if p.HighwaySpacing == 0 {
+		p.HighwaySpacing = 32
+	}
+	p.HighwaySpacing = max(1, (p.HighwaySpacing+p.ArterialSpacing/2)/p.ArterialSpacing) * p.ArterialSpacing
+	if p.Dropout == 0 {
+		p.Dropout = 0.1
+	}
+	if !(p.Dropout >= 0 && p.Dropout < 1) {
+		return nil, fmt.Errorf("sssp: generate: street dropout %v outside [0, 1)", p.Dropout)
+	}
+	if p.Congestion == (WeightDist{}) {
+		p.Congestion = UniformWeights(0.9, 1.1)
+	}
+	if err := p.Congestion.validate(); err != nil {
+		return nil, err
+	}
+	side := uint32(math.Ceil(math.Sqrt(float64(p.Nodes))))
+	if side < 2 {
+		return nil, fmt.Errorf("sssp: generate: road network needs at least 2 nodes, got %d", p.Nodes)
+	}
+	n := side * side
+	r := rand.New(rand.NewSource(seed))
+	const spacing = 100.0            // metres
+	const degPerMetre = 1 / 111320.0 // near the equator
+	coords := make([]LatLon, n)
+	for v := range coords {
+		x, y := float64(uint32(v)%side), float64(uint32(v)/side)
+		coords[v] = LatLon{
+			Lat: (y + 0.3*(2*r.Float64()-1)) * spacing * degPerMetre,
+			Lon: (x + 0.3*(2*r.Float64()-1)) * spacing * degPerMetre,
+		}
+	}
+	metres := func(u, v uint32) float64 {
+		dLat, dLon := coords[u].Lat-coords[v].Lat, coords[u].Lon-coords[v].Lon
+		return math.Hypot(dLat, dLon) / degPerMetre
+	}
+	type segment struct {
+		u, v  uint32
+		speed float64
+	}
+	var segs []segment
+	degree := make([]uint32, n)
+	add := func(u, v uint32, speed float64) {
+		segs = append(segs, segment{u, v, speed})
+		degree[u]++
+		degree[v]++
+	}
+	// line is the speed of the street along row or column i.
+	line := func(i uint32) float64 {
+		if i%p.ArterialSpacing == 0 {
+			return roadArterialSpeed
+		}
+		return roadLocalSpeed
+	}
+	for y := uint32(0); y < side; y++ {
+		for x := uint32(0); x < side; x++ {
+			v := y*side + x
+			if x+1 < side {
+				add(v, v+1, line(y))
+			}
+			if y+1 < side {
+				add(v, v+side, line(x))
+			}
+		}
+	}
+	// Drop local segments in random order, keeping every node at two or more.
+	kept := make([]segment, 0, len(segs))
+	for _, i := range r.Perm(len(segs)) {
+		s := segs[i]
+		if s.speed == roadLocalSpeed && r.Float64() < p.Dropout && degree[s.u] > 2 && degree[s.v] > 2 {
+			degree[s.u]--
+			degree[s.v]--
+			continue
+		}
+		kept = append(kept, s)
+	}
+	segs = kept
+	// Highways run along every HighwaySpacing-th row and column, linking
+	// consecutive exits directly.
+	for i := uint32(0); i < side; i += p.HighwaySpacing {
+		for a := uint32(0); a+p.ArterialSpacing < side; a += p.ArterialSpacing {
+			b := a + p.ArterialSpacing
+			segs = append(segs, segment{i*side + a, i*side + b, roadHighwaySpeed})
+			segs = append(segs, segment{a*side + i, b*side + i, roadHighwaySpeed})
+		}
+	}
+	edges := make([]Edge, 0, 2*len(segs))
+	for _, s := range segs {
+		w := float32(metres(s.u, s.v) / s.speed * float64(p.Congestion.sample(r)))
+		edges = append(edges, Edge{From: s.u, To: s.v, Weight: w}, Edge{From: s.v, To: s.u, Weight: w})
+	}
+	g, err := FromEdges(n, edges)
+	if err != nil {
+		return nil, err
+	}
+	g.coords = coords
+	return g, nil
+}
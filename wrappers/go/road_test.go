@@ -0,0 +1,38 @@
+package sssp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGenerateRoadNetworkHierarchy(t *testing.T) {
+	g, err := GenerateRoadNetwork(RoadParams{Nodes: 4000}, 11)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	const side = 64
+	if g.NodeCount() != side*side || len(g.Coords()) != side*side {
+		t.Fatalf("%d nodes, %d coordinates", g.NodeCount(), len(g.Coords()))
+	}
+	res, err := g.Run(0, ModeBaseline)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	unreachable := 0
+	for _, d := range res.Dist {
+		if math.IsInf(float64(d), 1) {
+			unreachable++
+		}
+	}
+	if unreachable > side {
+		t.Fatalf("%d nodes unreachable", unreachable)
+	}
+	// The far corner is 12.6 km away on the grid: over 750 s at arterial
+	// speed, so getting there faster takes the highways.
+	if d := res.Dist[side*side-1]; d > 600 || d < 12600/roadHighwaySpeed {
+		t.Fatalf("far corner at %v s", d)
+	}
+	if _, err := res.PathGeoJSON(side*side - 1); err != nil {
+		t.Fatalf("coordinates not usable: %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package sssp
+
+// Components are the strongly connected components of a graph: maximal
+// sets of nodes that can all reach each other. See Graph.Components.
+type Components struct {
+	// Of maps each node to its component. Components are numbered in
+	// topological order: every edge stays within a component or leads to
+	// a later one, so nothing in component i reaches a component before i.
+	Of []uint32
+	// Sizes holds the number of nodes in each component.
+	Sizes []uint32
+	// Condensed has one node per component and an edge a->b, with the
+	// least weight among them, wherever edges lead from a to b. It is
+	// acyclic, with its nodes already in topological order.
+	Condensed *Graph
+}
+
+// Components returns the strongly connected components of g, found with
+// Tarjan's algorithm in O(nodes+edges). They are computed once per change
+// of g and shared, also with concurrent queries; callers must not modify
+// them. Once computed, they also let ShortestPath stop at once when target
+// lies on the wrong side of source, and skip nodes that cannot lead to
+// target.
+func (g *Graph) Components() *Components {
+	if c := g.knownComponents(); c != nil {
+		return c
+	}
+	g.sccMu.Lock()
+	defer g.sccMu.Unlock()
+	if c := g.knownComponents(); c != nil {
+		return c
+	}
+	c := g.tarjan()
+	g.scc.Store(&sccCache{comps: c, version: g.version})
+	return c
+}
+
+// sccCache is Components of a graph at one version.
+type sccCache struct {
+	comps   *Components
+	version uint64
+}
+
+// knownComponents returns the cached components if they are current, or
+// nil. Queries use it to prune without computing them.
+func (g *Graph) knownComponents() *Components {
+	if c := g.scc.Load(); c != nil && c.version == g.version {
+		return c.comps
+	}
+	return nil
+}
+
+// tarjan runs Tarjan's algorithm with an explicit stack, so deep graphs
+// such as long paths cannot overflow the goroutine stack.
+func (g *Graph) tarjan() *Components {
+	n := g.NodeCount()
+	index := make([]uint32, n) // DFS discovery order from 1; 0 is unvisited
+	low := make([]uint32, n)
+	onStack := make([]bool, n)
+	of := make([]uint32, n)
+	var stack []uint32
+	type frame struct{ v, e uint32 }
+	var call []frame
+	counter, count := uint32(0), uint32(0)
+	visit := func(v uint32) {
+		counter++
+		index[v], low[v], onStack[v] = counter, counter, true
+		stack = append(stack, v)
+		call = append(call, frame{v, g.offsets[v]})
+	}
+	for r := uint32(0); r < n; r++ {
+		if index[r] != 0 {
+			continue
+		}
+		visit(r)
+		for len(call) > 0 {
+			f := &call[len(call)-1]
+			v := f.v
+			if f.e < g.offsets[v+1] {
+				w := g.targets[f.e]
+				f.e++
+				if index[w] == 0 {
+					visit(w)
+				} else if onStack[w] {
+					low[v] = min(low[v], index[w])
+				}
+				continue
+			}
+			call = call[:len(call)-1]
+			if len(call) > 0 {
+				p := call[len(call)-1].v
+				low[p] = min(low[p], low[v])
+			}
+			if low[v] != index[v] {
+				continue
+			}
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w], of[w] = false, count
+				if w == v {
+					break
+				}
+			}
+			count++
+		}
+	}
+	// Tarjan completes sinks first; reverse that into topological order.
+	c := &Components{Of: of, Sizes: make([]uint32, count)}
+	for v := range of {
+		of[v] = count - 1 - of[v]
+		c.Sizes[of[v]]++
+	}
+	var edges []Edge
+	for u := uint32(0); u < n; u++ {
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			if a, b := of[u], of[g.targets[e]]; a != b {
+				edges = append(edges, Edge{From: a, To: b, Weight: g.weights[e]})
+			}
+		}
+	}
+	// Every endpoint is below count, so neither call can fail.
+	c.Condensed, _ = FromEdges(count, edges)
+	c.Condensed.MergeDuplicates(KeepMin)
+	return c
+}
+
+// Count returns the number of components.
+func (c *Components) Count() int { return len(c.Sizes) }
+
+// Largest returns the biggest component and its size; ties go to the
+// lowest ID. It is (0, 0) for an empty graph.
+func (c *Components) Largest() (comp, size uint32) {
+	for i, s := range c.Sizes {
+		if s > size {
+			comp, size = uint32(i), s
+		}
+	}
+	return comp, size
+}
+
+// ProvablyUnreachable reports whether the numbering alone shows that v
+// cannot be reached from u: v's component comes before u's. It costs one
+// comparison; false proves reachability only when u and v share a
+// component, and ReachableFrom answers exactly.
+func (c *Components) ProvablyUnreachable(u, v uint32) bool { return c.Of[v] < c.Of[u] }
+
+// ReachableFrom marks the components reachable from node source's,
+// including its own, with one sweep over Condensed in topological order.
+// Nodes in unmarked components are unreachable from source without any
+// search.
+func (c *Components) ReachableFrom(source uint32) []bool {
+	reach := make([]bool, len(c.Sizes))
+	d := c.Condensed
+	start := c.Of[source]
+	reach[start] = true
+	for a := start; a < uint32(len(reach)); a++ {
+		if !reach[a] {
+			continue
+		}
+		for e := d.offsets[a]; e < d.offsets[a+1]; e++ {
+			reach[d.targets[e]] = true
+		}
+	}
+	return reach
+}
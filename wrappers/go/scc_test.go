@@ -0,0 +1,137 @@
+package sssp
+
+import (
+	"sync"
+	"testing"
+)
+
+// reachSets returns, for every node, the set of nodes it reaches.
+func reachSets(g *Graph) [][]bool {
+	n := g.NodeCount()
+	out := make([][]bool, n)
+	for s := uint32(0); s < n; s++ {
+		seen := make([]bool, n)
+		seen[s] = true
+		stack := []uint32{s}
+		for len(stack) > 0 {
+			u := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, v := range g.Neighbors(u) {
+				if !seen[v] {
+					seen[v] = true
+					stack = append(stack, v)
+				}
+			}
+		}
+		out[s] = seen
+	}
+	return out
+}
+
+func TestComponents(t *testing.T) {
+	for seed := int64(1); seed <= 5; seed++ {
+		g := randomGraph(t, 80, 120, seed)
+		c := g.Components()
+		reach := reachSets(g)
+		var total uint32
+		for _, s := range c.Sizes {
+			total += s
+		}
+		if total != g.NodeCount() {
+			t.Fatalf("seed %d: sizes add up to %d", seed, total)
+		}
+		for u := uint32(0); u < g.NodeCount(); u++ {
+			fromU := c.ReachableFrom(u)
+			for v := uint32(0); v < g.NodeCount(); v++ {
+				same := reach[u][v] && reach[v][u]
+				if (c.Of[u] == c.Of[v]) != same {
+					t.Fatalf("seed %d: %d and %d in components %d and %d, mutually reachable %v", seed, u, v, c.Of[u], c.Of[v], same)
+				}
+				if fromU[c.Of[v]] != reach[u][v] {
+					t.Fatalf("seed %d: ReachableFrom(%d) says %v for %d", seed, u, fromU[c.Of[v]], v)
+				}
+				if c.ProvablyUnreachable(u, v) && reach[u][v] {
+					t.Fatalf("seed %d: %d reaches %d, which was ruled out", seed, u, v)
+				}
+			}
+		}
+		for _, e := range c.Condensed.Edges() {
+			if e.From >= e.To {
+				t.Fatalf("seed %d: condensed edge %d->%d against the order", seed, e.From, e.To)
+			}
+		}
+		if g.Components() != c {
+			t.Error("components recomputed without a change")
+		}
+	}
+}
+
+func TestComponentsCondensed(t *testing.T) {
+	// {0,1} -> {2,3} twice, and 4 on its own.
+	g, _ := FromEdges(5, []Edge{{0, 1, 1}, {1, 0, 1}, {2, 3, 1}, {3, 2, 1}, {0, 2, 5}, {1, 3, 2}, {4, 0, 1}})
+	c := g.Components()
+	if c.Count() != 3 {
+		t.Fatalf("%d components, want 3", c.Count())
+	}
+	if comp, size := c.Largest(); size != 2 || comp != c.Of[0] {
+		t.Errorf("Largest() = %d, %d", comp, size)
+	}
+	if w, ok := c.Condensed.EdgeWeight(c.Of[0], c.Of[2]); !ok || w != 2 || c.Condensed.EdgeCount() != 2 {
+		t.Errorf("condensed edges %v", c.Condensed.Edges())
+	}
+	// Deep enough to overflow a recursive search.
+	path := make([]Edge, 200000)
+	for i := range path {
+		path[i] = Edge{uint32(i), uint32(i + 1), 1}
+	}
+	long, _ := FromEdges(uint32(len(path)+1), path)
+	if c := long.Components(); c.Count() != len(path)+1 || c.Of[0] != 0 {
+		t.Errorf("path: %d components, node 0 in %d", c.Count(), c.Of[0])
+	}
+}
+
+func TestShortestPathPruned(t *testing.T) {
+	g := randomGraph(t, 200, 400, 7)
+	var want [][]float32
+	for s := uint32(0); s < 10; s++ {
+		row := make([]float32, g.NodeCount())
+		for v := range row {
+			row[v], _, _ = g.ShortestPath(s, uint32(v))
+		}
+		want = append(want, row)
+	}
+	c := g.Components()
+	for s, row := range want {
+		for v, d := range row {
+			got, path, err := g.ShortestPath(uint32(s), uint32(v))
+			if err != nil || got != d || (d != Unreachable) != (path != nil) {
+				t.Fatalf("%d->%d: got %v %v %v, want %v", s, v, got, path, err, d)
+			}
+		}
+	}
+	if err := g.AddEdges([]Edge{{199, 0, 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if g.Components() == c {
+		t.Error("components not recomputed after AddEdges")
+	}
+}
+
+func TestComponentsConcurrent(t *testing.T) {
+	// Queries prune by the cache while another goroutine fills it; run with
+	// -race.
+	g := randomGraph(t, 300, 600, 8)
+	want, _, _ := g.ShortestPath(0, 150)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			g.Components()
+			if d, _, err := g.ShortestPath(0, 150); err != nil || d != want {
+				t.Errorf("got %v, %v, want %v", d, err, want)
+			}
+		}()
+	}
+	wg.Wait()
+}
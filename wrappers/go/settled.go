@@ -0,0 +1,72 @@
+package sssp
+
+import "fmt"
+
+// SettledFunc receives a node as soon as its distance is final, together
+// with that distance and the node's predecessor on a shortest path (-1 for
+// the source).
+type SettledFunc func(node uint32, dist float32, pred int32)
+
+// RunSettled is Run calling settled for every reachable node the moment the
+// algorithm finalizes it, so callers can act on near nodes before far ones
+// are known. ModeBaseline reports nodes in nondecreasing distance order;
+// the delta-stepping modes report whole buckets of width delta in
+// increasing order, in no particular order within a bucket. Each node is
+// reported exactly once, from the calling goroutine, and the returned
+// Result holds the same distances and predecessors.
+//
+// The native library has no callback entry point, so RunSettled always
+// runs the pure-Go engine: Stats.Version is 0 and ModeStoc uses the fixed
+// delta described for builds without cgo.
+func (g *Graph) RunSettled(source uint32, mode int, settled SettledFunc) (Result, error) {
+	var res Result
+	var hooks *runHooks
+	if settled != nil {
+		hooks = &runHooks{visit: func(u uint32) { settled(u, res.Dist[u], res.Pred[u]) }}
+	}
+	err := g.runGo(source, mode, &res, hooks)
+	return res, err
+}
+
+// runGo runs mode on the pure-Go engine into res, allocating its rows
+// before the engine starts so hooks can read them.
+func (g *Graph) runGo(source uint32, mode int, res *Result, hooks *runHooks) error {
+	n := g.NodeCount()
+	if err := checkGoRun(n, source, mode); err != nil {
+		return err
+	}
+	*res = Result{Dist: make([]float32, n), Pred: make([]int32, n), graph: g}
+	res.Stats = runGoCSR(g.offsets, g.targets, g.weights, source, mode, res.Dist, res.Pred, hooks)
+	return nil
+}
+
+// checkGoRun rejects what the pure-Go engine cannot run.
+func checkGoRun(n, source uint32, mode int) error {
+	if n == 0 {
+		return fmt.Errorf("sssp: graph has no nodes")
+	}
+	if source >= n {
+		return fmt.Errorf("sssp: source %d out of range for %d nodes", source, n)
+	}
+	if mode < ModeBaseline || mode > ModeGPU {
+		return fmt.Errorf("%w: mode %d", ErrUnsupported, mode)
+	}
+	return nil
+}
+
+// runGoCSR is runGo on bare CSR arrays of any offset width and weight
+// type, filling caller-allocated rows after checkGoRun.
+func runGoCSR[O csrOffset, W Weight](off []O, tgt []uint32, wts []W, source uint32, mode int, dist []W, pred []int32, hooks *runHooks) Stats {
+	switch mode {
+	case ModeBaseline:
+		ops := goDijkstra(off, tgt, wts, source, dist, pred, hooks)
+		return Stats{Relaxations: ops.Improvements, Settled: uint32(len(dist)), Ops: ops}
+	case ModeAutotune:
+		return goAutotune(off, tgt, wts, source, AutotuneParams{}, dist, pred, hooks)
+	default:
+		delta := deltaOf[W](avgWeight(wts) * envFloat("SSSP_STOC_DELTA_MULT", 3))
+		c := goDeltaStepping(off, tgt, wts, source, delta, dist, pred, 0, hooks)
+		return Stats{Relaxations: c.relax, LightRelaxations: c.light, HeavyRelaxations: c.heavy,
+			Settled: c.settled, Delta: float32(delta), Fallback: c.overflow || mode == ModeGPU, Ops: c.ops}
+	}
+}
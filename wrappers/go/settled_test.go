@@ -0,0 +1,50 @@
+package sssp
+
+import "testing"
+
+func TestRunSettledReportsEachNodeOnce(t *testing.T) {
+	g, err := GenerateRandomGraph(3000, 4, 5, RandomGraphOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := g.Run(11, ModeBaseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mode := range []int{ModeBaseline, ModeStoc, ModeAutotune} {
+		seen := make([]bool, g.NodeCount())
+		count := 0
+		last := float32(-1)
+		res, err := g.RunSettled(11, mode, func(node uint32, dist float32, pred int32) {
+			if seen[node] {
+				t.Fatalf("mode %d: node %d reported twice", mode, node)
+			}
+			seen[node] = true
+			count++
+			if mode == ModeBaseline && dist < last {
+				t.Fatalf("mode %d: node %d at %v after %v", mode, node, dist, last)
+			}
+			last = dist
+			if dist != ref.Dist[node] {
+				t.Fatalf("mode %d: node %d settled at %v, want %v", mode, node, dist, ref.Dist[node])
+			}
+			if node != 11 && (pred < 0 || mode == ModeBaseline && !seen[pred]) {
+				t.Fatalf("mode %d: node %d reported before its predecessor %d", mode, node, pred)
+			}
+		})
+		if err != nil {
+			t.Fatalf("mode %d: %v", mode, err)
+		}
+		for v, d := range res.Dist {
+			if seen[v] != (d != inf32) {
+				t.Fatalf("mode %d: node %d reported %v with distance %v", mode, v, seen[v], d)
+			}
+		}
+		if count == 0 || res.graph != g {
+			t.Fatalf("mode %d: %d nodes reported", mode, count)
+		}
+	}
+	if _, err := g.RunSettled(g.NodeCount(), ModeBaseline, nil); err == nil {
+		t.Fatalf("out-of-range source accepted")
+	}
+}
@@ -0,0 +1,85 @@
+package sssp
+
+import (
+	"math"
+	"math/bits"
+	"os"
+	"sync"
+
+	"github.com/your-org/optimized-sssp-go/internal/simd"
+)
+
+// simdMinDegree is the degree from which the pure-Go Dijkstra relaxes a
+// node's edges with the vector kernel; below it the setup costs more than
+// it saves.
+const simdMinDegree = 16
+
+// simdEnabled reports whether the vector kernel is used: the CPU supports
+// it and SSSP_SIMD is not "0".
+var simdEnabled = simd.Available && os.Getenv("SSSP_SIMD") != "0"
+
+// simdRelaxer finds the edges of a node that improve on the current
+// distances, eight at a time: it gathers the targets' distances, adds the
+// weights to the node's distance and compares in vector registers, leaving
+// only the improving edges to the scalar loop.
+type simdRelaxer struct {
+	tgt   []uint32
+	wts   []float32
+	dist  []float32
+	masks []uint8
+	cand  []int
+}
+
+// newSIMDRelaxer returns a relaxer for float32 weights when the kernel is
+// enabled, or nil. The kernel's gather takes signed 32-bit indices, so
+// larger graphs stay scalar.
+func newSIMDRelaxer[W Weight](tgt []uint32, wts, dist []W) *simdRelaxer {
+	w, ok := any(wts).([]float32)
+	if !simdEnabled || !ok || len(dist) > math.MaxInt32 {
+		return nil
+	}
+	s, _ := relaxerPool.Get().(*simdRelaxer)
+	if s == nil || !pooling.Load() {
+		s = &simdRelaxer{cand: make([]int, 0, 64)}
+	}
+	s.tgt, s.wts, s.dist = tgt, w, any(dist).([]float32)
+	return s
+}
+
+var relaxerPool sync.Pool
+
+// release hands s back to the pool for another run, letting go of the
+// graph. A nil s is ignored.
+func (s *simdRelaxer) release() {
+	if s == nil || !pooling.Load() {
+		return
+	}
+	s.tgt, s.wts, s.dist = nil, nil, nil
+	relaxerPool.Put(s)
+}
+
+// candidates returns the edges in [lo, hi) with du+weight below their
+// target's distance, in order. Edges are compared against the distances
+// before any of them is applied, so the caller must check again.
+func (s *simdRelaxer) candidates(du float32, lo, hi int) []int {
+	s.cand = s.cand[:0]
+	vec := (hi - lo) &^ 7
+	if vec > 0 {
+		if cap(s.masks) < vec/8 {
+			s.masks = make([]uint8, vec/8)
+		}
+		masks := s.masks[:vec/8]
+		simd.RelaxMasks(du, s.tgt[lo:lo+vec], s.wts[lo:lo+vec], s.dist, masks)
+		for g, m := range masks {
+			for ; m != 0; m &= m - 1 {
+				s.cand = append(s.cand, lo+g*8+bits.TrailingZeros8(m))
+			}
+		}
+	}
+	for e := lo + vec; e < hi; e++ {
+		if du+s.wts[e] < s.dist[s.tgt[e]] {
+			s.cand = append(s.cand, e)
+		}
+	}
+	return s.cand
+}
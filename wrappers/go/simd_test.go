@@ -0,0 +1,31 @@
+package sssp
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSIMDDijkstraMatchesScalar(t *testing.T) {
+	if !simdEnabled {
+		t.Skip("vector kernel not available")
+	}
+	// Dense enough that most nodes take the vector path, with duplicate
+	// edges to the same target inside one batch.
+	g := randomGraph(t, 300, 30000, 3)
+	vec, err := g.RunSettled(0, ModeBaseline, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	simdEnabled = false
+	defer func() { simdEnabled = true }()
+	scalar, err := g.RunSettled(0, ModeBaseline, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(vec.Dist, scalar.Dist) || !slices.Equal(vec.Pred, scalar.Pred) {
+		t.Fatalf("vector and scalar runs differ")
+	}
+	if vec.Stats.Ops != scalar.Stats.Ops {
+		t.Fatalf("ops %+v, scalar %+v", vec.Stats.Ops, scalar.Stats.Ops)
+	}
+}
@@ -0,0 +1,89 @@
+package sssp
+
+import (
+	"fmt"
+	"math"
+)
+
+// SimplifyPath reduces the polyline of path with the Douglas-Peucker
+// algorithm: it keeps the endpoints and, recursively, the node farthest
+// from the line between the kept ones while that node lies more than
+// tolerance metres off it. It returns the node IDs kept, in path order, and
+// their coordinates. Distances use a flat projection around the path's first
+// node, accurate for routes up to a few hundred kilometres. It needs
+// coordinates.
+func (g *Graph) SimplifyPath(path []uint32, tolerance float64) ([]uint32, []LatLon, error) {
+	if g.coords == nil {
+		return nil, nil, fmt.Errorf("sssp: path simplification needs coordinates")
+	}
+	if tolerance < 0 {
+		return nil, nil, fmt.Errorf("sssp: negative simplification tolerance %v", tolerance)
+	}
+	pts := make([][2]float64, len(path))
+	for i, v := range path {
+		if v >= g.NodeCount() {
+			return nil, nil, fmt.Errorf("sssp: path node %d out of range for %d nodes", v, g.NodeCount())
+		}
+		pts[i] = project(g.coords[path[0]], g.coords[v])
+	}
+	keep := make([]bool, len(path))
+	if len(path) > 0 {
+		keep[0], keep[len(path)-1] = true, true
+	}
+	// An explicit stack of spans instead of recursion: 12,000-point routes
+	// would otherwise recurse deeply on nearly straight stretches.
+	stack := [][2]int{{0, len(path) - 1}}
+	for len(stack) > 0 {
+		lo, hi := stack[len(stack)-1][0], stack[len(stack)-1][1]
+		stack = stack[:len(stack)-1]
+		far, farDist := -1, tolerance
+		for i := lo + 1; i < hi; i++ {
+			if d := segmentDistance(pts[i], pts[lo], pts[hi]); d > farDist {
+				far, farDist = i, d
+			}
+		}
+		if far >= 0 {
+			keep[far] = true
+			stack = append(stack, [2]int{lo, far}, [2]int{far, hi})
+		}
+	}
+	var nodes []uint32
+	var line []LatLon
+	for i, v := range path {
+		if keep[i] {
+			nodes = append(nodes, v)
+			line = append(line, g.coords[v])
+		}
+	}
+	return nodes, line, nil
+}
+
+// SimplifiedPath is SimplifyPath on the shortest path to target.
+func (r *Result) SimplifiedPath(target uint32, tolerance float64) ([]uint32, []LatLon, error) {
+	if _, err := r.geoCoords(); err != nil {
+		return nil, nil, err
+	}
+	path := r.Path(target)
+	if path == nil {
+		return nil, nil, fmt.Errorf("sssp: node %d is unreachable", target)
+	}
+	return r.graph.SimplifyPath(path, tolerance)
+}
+
+// project maps c to metres east and north of origin on a plane tangent at
+// origin.
+func project(origin, c LatLon) [2]float64 {
+	const r = 6371008.8
+	rad := math.Pi / 180
+	return [2]float64{(c.Lon - origin.Lon) * rad * r * math.Cos(origin.Lat*rad), (c.Lat - origin.Lat) * rad * r}
+}
+
+// segmentDistance is the distance from p to the segment a-b.
+func segmentDistance(p, a, b [2]float64) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	t := 0.0
+	if l := dx*dx + dy*dy; l > 0 {
+		t = math.Max(0, math.Min(1, ((p[0]-a[0])*dx+(p[1]-a[1])*dy)/l))
+	}
+	return math.Hypot(p[0]-a[0]-t*dx, p[1]-a[1]-t*dy)
+}
@@ -0,0 +1,38 @@
+package sssp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSimplifyPath(t *testing.T) {
+	// A street along the equator with a 5 m wiggle at node 2 and a corner
+	// at node 4 where it turns north.
+	coords := []LatLon{{0, 0}, {0, 0.001}, {0.000045, 0.002}, {0, 0.003}, {0, 0.004}, {0.001, 0.004}}
+	var edges []Edge
+	for u := uint32(0); u < 5; u++ {
+		edges = append(edges, Edge{u, u + 1, 1})
+	}
+	g, _ := FromEdges(6, edges)
+	if _, _, err := g.SimplifyPath([]uint32{0, 1}, 1); err == nil {
+		t.Fatalf("no coordinates accepted")
+	}
+	g.SetCoords(coords)
+	res, err := g.Run(0, ModeBaseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes, line, err := res.SimplifiedPath(5, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(nodes, []uint32{0, 4, 5}) || !reflect.DeepEqual(line, []LatLon{coords[0], coords[4], coords[5]}) {
+		t.Fatalf("10 m tolerance kept %v %v", nodes, line)
+	}
+	if nodes, _, _ := res.SimplifiedPath(5, 3); !reflect.DeepEqual(nodes, []uint32{0, 2, 4, 5}) {
+		t.Fatalf("3 m tolerance kept %v", nodes)
+	}
+	if nodes, _, _ := g.SimplifyPath([]uint32{3}, 1); !reflect.DeepEqual(nodes, []uint32{3}) {
+		t.Fatalf("single node %v", nodes)
+	}
+}
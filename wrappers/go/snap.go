@@ -0,0 +1,140 @@
+package sssp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/your-org/optimized-sssp-go/internal/decompress"
+)
+
+// SNAP (snap.stanford.edu/data) edge lists: '#' comment lines, then one
+// "FromNodeId<TAB>ToNodeId" pair per line, optionally followed by a weight.
+// Ids are kept as-is, so sparse id spaces yield isolated nodes, unless
+// SNAPOptions.IDs numbers them densely.
+
+// SNAPOptions adjusts LoadSNAP to a dataset's conventions.
+type SNAPOptions struct {
+	// OneBased subtracts 1 from every id.
+	OneBased bool
+	// KeepSelfLoops keeps u->u edges; they are dropped by default.
+	KeepSelfLoops bool
+	// Symmetrize adds the reverse of every edge and removes duplicate pairs
+	// (keeping the smaller weight). Use it for "Undirected graph" datasets
+	// that list each edge once.
+	Symmetrize bool
+	// IDs, if set, numbers the ids through it in the order they are first
+	// seen, so a dataset with sparse, large ids gets one node per id that
+	// occurs; ids may then use 64 bits and OneBased is ignored. Reuse one
+	// mapper to load several files over the same nodes.
+	IDs *IDMapper[uint64]
+	// SparseIDs keeps ids as node numbers even when the largest is far
+	// beyond what the edge count justifies (see CheckIDSpan), which LoadSNAP
+	// otherwise rejects.
+	SparseIDs bool
+}
+
+// LoadSNAP reads a SNAP text dataset. Lines with a third column use it as the
+// weight; others get weight 1.
+func LoadSNAP(r io.Reader, opts SNAPOptions) (*Graph, error) {
+	r, err := decompress.Reader(r)
+	if err != nil {
+		return nil, err
+	}
+	sc := bufio.NewScanner(r)
+	var (
+		edges []Edge
+		maxID uint64
+		seen  bool
+	)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || text[0] == '#' {
+			continue
+		}
+		f := strings.Fields(text)
+		if len(f) < 2 {
+			return nil, fmt.Errorf("sssp: snap line %d: expected 'from to [weight]'", line)
+		}
+		bits := 32
+		if opts.IDs != nil {
+			bits = 64
+		}
+		u, err1 := strconv.ParseUint(f[0], 10, bits)
+		v, err2 := strconv.ParseUint(f[1], 10, bits)
+		if err1 != nil || err2 != nil || (opts.IDs == nil && (u == math.MaxUint32 || v == math.MaxUint32)) {
+			return nil, fmt.Errorf("sssp: snap line %d: bad node id", line)
+		}
+		if opts.IDs != nil {
+			u, v = uint64(opts.IDs.ID(u)), uint64(opts.IDs.ID(v))
+		} else if opts.OneBased {
+			if u == 0 || v == 0 {
+				return nil, fmt.Errorf("sssp: snap line %d: node id 0 in a 1-based file", line)
+			}
+			u, v = u-1, v-1
+		}
+		maxID, seen = max(maxID, u, v), true
+		if u == v && !opts.KeepSelfLoops {
+			continue
+		}
+		w := 1.0
+		if len(f) >= 3 {
+			var err error
+			if w, err = strconv.ParseFloat(f[2], 32); err != nil {
+				return nil, fmt.Errorf("sssp: snap line %d: bad weight %q", line, f[2])
+			}
+		}
+		edges = append(edges, Edge{From: uint32(u), To: uint32(v), Weight: float32(w)})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if opts.Symmetrize {
+		edges = symmetrize(edges)
+	}
+	n := uint32(0)
+	switch {
+	case opts.IDs != nil:
+		n = opts.IDs.Len()
+	case seen:
+		if err := CheckIDSpan(maxID, len(edges)); err != nil && !opts.SparseIDs {
+			return nil, fmt.Errorf("%w; set SNAPOptions.IDs to number the ids densely, or SparseIDs to keep them", err)
+		}
+		n = uint32(maxID + 1)
+	}
+	return FromEdges(n, edges)
+}
+
+// symmetrize returns edges plus their reverses with duplicate (from, to)
+// pairs collapsed to the lightest.
+func symmetrize(edges []Edge) []Edge {
+	all := make([]Edge, 0, 2*len(edges))
+	for _, e := range edges {
+		all = append(all, e)
+		if e.From != e.To {
+			all = append(all, Edge{From: e.To, To: e.From, Weight: e.Weight})
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		a, b := all[i], all[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.Weight < b.Weight
+	})
+	out := all[:0]
+	for i, e := range all {
+		if i > 0 && e.From == all[i-1].From && e.To == all[i-1].To {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
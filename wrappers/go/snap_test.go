@@ -0,0 +1,68 @@
+package sssp
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleSNAP = `# Undirected graph: com-example.ungraph.txt
+# Nodes: 4 Edges: 4
+# FromNodeId	ToNodeId
+0	1
+1	0
+1	5
+5	5
+2	5
+`
+
+func TestLoadSNAPDropsSelfLoopsAndKeepsSparseIDs(t *testing.T) {
+	g, err := LoadSNAP(strings.NewReader(sampleSNAP), SNAPOptions{})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if g.NodeCount() != 6 || g.EdgeCount() != 4 {
+		t.Fatalf("got %d nodes %d edges", g.NodeCount(), g.EdgeCount())
+	}
+	g, _ = LoadSNAP(strings.NewReader(sampleSNAP), SNAPOptions{KeepSelfLoops: true})
+	if g.EdgeCount() != 5 {
+		t.Fatalf("self-loop not kept: %d edges", g.EdgeCount())
+	}
+}
+
+func TestLoadSNAPSymmetrizeAndOneBased(t *testing.T) {
+	g, err := LoadSNAP(strings.NewReader(sampleSNAP), SNAPOptions{Symmetrize: true})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	// {0,1} listed both ways collapses to one pair; {1,5} and {2,5} gain reverses.
+	if g.EdgeCount() != 6 {
+		t.Fatalf("got %v", g.Edges())
+	}
+	res, err := g.Run(0, ModeBaseline)
+	if err != nil || res.Dist[2] != 3 {
+		t.Fatalf("run: %v dist %v", err, res.Dist)
+	}
+	g, err = LoadSNAP(strings.NewReader("1 2 0.5\n2 3\n"), SNAPOptions{OneBased: true})
+	if err != nil || g.NodeCount() != 3 || g.Edges()[0] != (Edge{0, 1, 0.5}) {
+		t.Fatalf("one-based: %v %v", err, g)
+	}
+	if _, err := LoadSNAP(strings.NewReader("0 1\n"), SNAPOptions{OneBased: true}); err == nil {
+		t.Fatalf("expected id 0 to be rejected")
+	}
+}
+
+func TestLoadSNAPLargeIDs(t *testing.T) {
+	const in = "0\t2000000000\n"
+	if _, err := LoadSNAP(strings.NewReader(in), SNAPOptions{}); err == nil {
+		t.Fatal("id 2e9 on one edge accepted")
+	}
+	ids := NewIDMapper[uint64]()
+	g, err := LoadSNAP(strings.NewReader(in+"2000000000\t99999999999\n"), SNAPOptions{IDs: ids})
+	if err != nil || g.NodeCount() != 3 || ids.Key(2) != 99999999999 {
+		t.Fatalf("mapped ids: %v, %v", err, g)
+	}
+	g, err = LoadSNAP(strings.NewReader("0\t5000000\n"), SNAPOptions{SparseIDs: true})
+	if err != nil || g.NodeCount() != 5000001 {
+		t.Fatalf("sparse ids: %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+package sssp
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"unsafe"
+
+	"github.com/your-org/optimized-sssp-go/internal/decompress"
+)
+
+// Snapshot layout: a 64-byte header followed by the raw little-endian CSR
+// arrays, each starting on an 8-byte boundary, so a loaded or mapped file is
+// used in place without parsing.
+//
+//	0   magic   "SSSPCSR\x00"
+//	8   version uint32 (1)
+//	12  weight  uint32 (1 = float32)
+//	16  n       uint64
+//	24  m       uint64
+//	32  reserved, zero
+//	64  offsets [n+1]uint32, targets [m]uint32, weights [m]float32
+
+const (
+	snapshotMagic      = "SSSPCSR\x00"
+	snapshotVersion    = 1
+	snapshotWeightF32  = 1
+	snapshotHeaderSize = 64
+)
+
+var nativeLittleEndian = func() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}()
+
+func align8(x uint64) uint64 { return (x + 7) &^ 7 }
+
+// snapshotSections returns the byte ranges of the three arrays.
+func snapshotSections(n, m uint64) (offAt, tgtAt, wtsAt, end uint64) {
+	offAt = snapshotHeaderSize
+	tgtAt = align8(offAt + 4*(n+1))
+	wtsAt = align8(tgtAt + 4*m)
+	end = wtsAt + 4*m
+	return
+}
+
+// SaveSnapshot writes g in the snapshot layout.
+func (g *Graph) SaveSnapshot(w io.Writer) error {
+	n, m := uint64(g.NodeCount()), uint64(g.EdgeCount())
+	offAt, tgtAt, wtsAt, _ := snapshotSections(n, m)
+	bw := bufio.NewWriterSize(w, 1<<20)
+	var hdr [snapshotHeaderSize]byte
+	copy(hdr[:], snapshotMagic)
+	binary.LittleEndian.PutUint32(hdr[8:], snapshotVersion)
+	binary.LittleEndian.PutUint32(hdr[12:], snapshotWeightF32)
+	binary.LittleEndian.PutUint64(hdr[16:], n)
+	binary.LittleEndian.PutUint64(hdr[24:], m)
+	bw.Write(hdr[:])
+	pos := offAt
+	pad := func(to uint64) {
+		for ; pos < to; pos++ {
+			bw.WriteByte(0)
+		}
+	}
+	var b [4]byte
+	for _, v := range g.offsets {
+		binary.LittleEndian.PutUint32(b[:], v)
+		bw.Write(b[:])
+	}
+	pos += 4 * (n + 1)
+	pad(tgtAt)
+	for _, v := range g.targets {
+		binary.LittleEndian.PutUint32(b[:], v)
+		bw.Write(b[:])
+	}
+	pos += 4 * m
+	pad(wtsAt)
+	for _, v := range g.weights {
+		binary.LittleEndian.PutUint32(b[:], math.Float32bits(v))
+		bw.Write(b[:])
+	}
+	return bw.Flush()
+}
+
+// SnapshotFromBytes returns a graph whose arrays alias b. b must stay
+// unmodified while the graph is in use. On big-endian hosts, or if b is
+// misaligned, the arrays are copied instead. A gzip- or zstd-compressed
+// snapshot is decompressed into a new buffer, which the graph then aliases.
+// The arrays are checked as NewGraphCSR checks them, in one pass over b, so
+// a corrupted snapshot fails here rather than in a later run.
+func SnapshotFromBytes(b []byte) (*Graph, error) {
+	return snapshotFromBytes(b, true)
+}
+
+// snapshotFromBytes is SnapshotFromBytes, skipping the pass over offsets
+// and targets unless check is set.
+func snapshotFromBytes(b []byte, check bool) (*Graph, error) {
+	b, err := decompress.Bytes(b)
+	if err != nil {
+		return nil, fmt.Errorf("sssp: snapshot: %w", err)
+	}
+	if len(b) < snapshotHeaderSize || string(b[:8]) != snapshotMagic {
+		return nil, fmt.Errorf("sssp: snapshot: bad magic")
+	}
+	if v := binary.LittleEndian.Uint32(b[8:]); v != snapshotVersion {
+		return nil, fmt.Errorf("sssp: snapshot: unsupported version %d", v)
+	}
+	if k := binary.LittleEndian.Uint32(b[12:]); k != snapshotWeightF32 {
+		return nil, fmt.Errorf("sssp: snapshot: unsupported weight type %d", k)
+	}
+	n, m := binary.LittleEndian.Uint64(b[16:]), binary.LittleEndian.Uint64(b[24:])
+	if n >= math.MaxUint32 || m > math.MaxUint32 {
+		return nil, fmt.Errorf("sssp: snapshot: %d nodes / %d edges exceed 32-bit ids", n, m)
+	}
+	offAt, tgtAt, wtsAt, end := snapshotSections(n, m)
+	if uint64(len(b)) < end {
+		return nil, fmt.Errorf("sssp: snapshot: file is %d bytes, layout needs %d", len(b), end)
+	}
+	g := &Graph{
+		offsets: viewUint32(b[offAt : offAt+4*(n+1)]),
+		targets: viewUint32(b[tgtAt : tgtAt+4*m]),
+		weights: viewFloat32(b[wtsAt : wtsAt+4*m]),
+	}
+	if g.offsets[0] != 0 || uint64(g.offsets[n]) != m {
+		return nil, fmt.Errorf("sssp: snapshot: offsets span %d..%d, want 0..%d", g.offsets[0], g.offsets[n], m)
+	}
+	if check {
+		if err := checkStructure(g.offsets, g.targets); err != nil {
+			return nil, fmt.Errorf("sssp: snapshot: %w", err)
+		}
+	}
+	return g, nil
+}
+
+// OpenSnapshot reads a snapshot file into memory and returns a graph backed
+// by that buffer. The file is checked as in SnapshotFromBytes, so offsets
+// that decrease or targets out of range are reported as an error instead of
+// making queries read out of bounds.
+func OpenSnapshot(path string) (*Graph, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return SnapshotFromBytes(b)
+}
+
+func viewUint32(b []byte) []uint32 {
+	if len(b) == 0 {
+		return []uint32{}
+	}
+	if nativeLittleEndian && uintptr(unsafe.Pointer(&b[0]))%4 == 0 {
+		return unsafe.Slice((*uint32)(unsafe.Pointer(&b[0])), len(b)/4)
+	}
+	out := make([]uint32, len(b)/4)
+	for i := range out {
+		out[i] = binary.LittleEndian.Uint32(b[4*i:])
+	}
+	return out
+}
+
+func viewFloat32(b []byte) []float32 {
+	if len(b) == 0 {
+		return []float32{}
+	}
+	if nativeLittleEndian && uintptr(unsafe.Pointer(&b[0]))%4 == 0 {
+		return unsafe.Slice((*float32)(unsafe.Pointer(&b[0])), len(b)/4)
+	}
+	out := make([]float32, len(b)/4)
+	for i := range out {
+		out[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:]))
+	}
+	return out
+}
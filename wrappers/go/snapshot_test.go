@@ -0,0 +1,81 @@
+package sssp
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"unsafe"
+)
+
+func TestSnapshotRoundTripIsZeroCopy(t *testing.T) {
+	off, tgt, wts := randomCSR(300, 3, 11)
+	g, err := NewGraphCSR(off, tgt, wts)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := g.SaveSnapshot(&buf); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	b := buf.Bytes()
+	snap, err := SnapshotFromBytes(b)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	o2, t2, w2 := snap.CSR()
+	if _, err := NewGraphCSR(o2, t2, w2); err != nil {
+		t.Fatalf("snapshot arrays invalid: %v", err)
+	}
+	if nativeLittleEndian && uintptr(unsafe.Pointer(&b[0]))%4 == 0 {
+		if uintptr(unsafe.Pointer(&o2[0])) != uintptr(unsafe.Pointer(&b[snapshotHeaderSize])) {
+			t.Fatalf("offsets were copied, expected a view into the buffer")
+		}
+	}
+	want, _ := g.Run(0, ModeBaseline)
+	got, err := snap.Run(0, ModeBaseline)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	for v := range want.Dist {
+		if want.Dist[v] != got.Dist[v] {
+			t.Fatalf("dist[%d] = %v, want %v", v, got.Dist[v], want.Dist[v])
+		}
+	}
+	if _, err := SnapshotFromBytes(b[:len(b)-4]); err == nil {
+		t.Fatalf("expected truncated snapshot error")
+	}
+}
+
+func TestOpenSnapshotFileAndEmptyGraph(t *testing.T) {
+	g, _ := FromEdges(0, nil)
+	path := filepath.Join(t.TempDir(), "empty.csr")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := g.SaveSnapshot(f); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	f.Close()
+	snap, err := OpenSnapshot(path)
+	if err != nil || snap.NodeCount() != 0 || snap.EdgeCount() != 0 {
+		t.Fatalf("open: %v", err)
+	}
+}
+
+func TestSnapshotRejectsCorruptArrays(t *testing.T) {
+	g, _ := FromEdges(3, []Edge{{0, 1, 1}, {1, 2, 1}, {2, 0, 1}})
+	var buf bytes.Buffer
+	if err := g.SaveSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+	offAt, tgtAt, _, _ := snapshotSections(3, 3)
+	for name, at := range map[string]uint64{"offset": offAt + 4, "target": tgtAt + 4} {
+		b := bytes.Clone(buf.Bytes())
+		b[at+1] = 0x32 // a middle offset past m, or a target past n
+		if _, err := SnapshotFromBytes(b); err == nil {
+			t.Fatalf("corrupt %s accepted", name)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package sssp
+
+import "fmt"
+
+// GraphSource is the view of a graph the search algorithms need: a node ID
+// bound and the out-edges of one node at a time. Edges may be produced on
+// demand, from a state space, a database or a rule, instead of being
+// stored; RunSource asks for a node's edges only when it scans that node.
+// Graph and Graph64 implement it.
+type GraphSource interface {
+	// NodeCount bounds the node IDs: every node, and every target
+	// ForEachNeighbor yields, is below it. Results hold one entry per ID.
+	NodeCount() uint32
+	// ForEachNeighbor calls fn for each out-edge of u until fn returns
+	// false. Weights must be non-negative.
+	ForEachNeighbor(u uint32, fn func(to uint32, w float32) bool)
+}
+
+// FuncSource adapts a neighbor function to a GraphSource with Nodes node
+// IDs.
+type FuncSource struct {
+	Nodes     uint32
+	Neighbors func(u uint32, fn func(to uint32, w float32) bool)
+}
+
+func (s FuncSource) NodeCount() uint32 { return s.Nodes }
+
+func (s FuncSource) ForEachNeighbor(u uint32, fn func(to uint32, w float32) bool) {
+	s.Neighbors(u, fn)
+}
+
+// RunSource runs mode from source on the pure-Go engine, reading edges
+// only through src. ModeBaseline and ModeStoc (with ModeGPU falling back
+// to it) are supported; ModeStoc uses the fixed delta described for builds
+// without cgo, from the first 1000 weights src yields in node order.
+// ModeAutotune would regenerate every edge once per trial and returns
+// ErrUnsupported. A Graph gives the same distances through Run, faster.
+func RunSource(src GraphSource, source uint32, mode int) (Result, error) {
+	n := src.NodeCount()
+	if err := checkGoRun(n, source, mode); err != nil {
+		return Result{}, err
+	}
+	if mode == ModeAutotune {
+		return Result{}, fmt.Errorf("%w: autotune on a GraphSource", ErrUnsupported)
+	}
+	s := sourceSearch{src: src, n: n, dist: make([]float32, n), pred: make([]int32, n)}
+	res := Result{Dist: s.dist, Pred: s.pred}
+	if mode == ModeBaseline {
+		s.dijkstra(source)
+		res.Stats = Stats{Relaxations: s.ops.Improvements, Settled: n, Ops: s.ops}
+	} else {
+		delta := clampDelta(sourceAvgWeight(src) * envFloat("SSSP_STOC_DELTA_MULT", 3))
+		s.deltaStepping(source, delta)
+		if s.overflow && s.bad == nil {
+			// Past the last bucket, as in goDeltaStepping: redo it exactly.
+			s.dijkstra(source)
+			s.settled = n
+		}
+		res.Stats = Stats{Relaxations: s.ops.Improvements, LightRelaxations: s.light, HeavyRelaxations: s.heavy,
+			Settled: s.settled, Delta: delta, Fallback: s.overflow || mode == ModeGPU, Ops: s.ops}
+	}
+	if s.bad != nil {
+		return Result{}, s.bad
+	}
+	return res, nil
+}
+
+// sourceAvgWeight is avgWeight over the first 1000 weights src yields in
+// node order, the CSR order of a Graph.
+func sourceAvgWeight(src GraphSource) float32 {
+	var s float32
+	var count int
+	for u := uint32(0); u < src.NodeCount() && count < 1000; u++ {
+		src.ForEachNeighbor(u, func(_ uint32, w float32) bool {
+			s += w
+			count++
+			return count < 1000
+		})
+	}
+	if count == 0 || s <= 0 {
+		return 1
+	}
+	return s / float32(count)
+}
+
+// sourceSearch holds the state of one RunSource call. It mirrors
+// goDijkstra and goDeltaStepping, calling ForEachNeighbor where they index
+// the CSR arrays.
+type sourceSearch struct {
+	src     GraphSource
+	n       uint32
+	dist    []float32
+	pred    []int32
+	ops     OpCounters
+	light   uint64
+	heavy   uint64
+	settled uint32
+	bad     error // first out-of-range target; stops the search
+
+	overflow bool // a distance fell past maxBucket; stops delta-stepping
+}
+
+// scan calls relax for each out-edge of u, stopping at an out-of-range
+// target.
+func (s *sourceSearch) scan(u uint32, relax func(v uint32, w float32)) {
+	s.src.ForEachNeighbor(u, func(v uint32, w float32) bool {
+		if v >= s.n {
+			s.bad = fmt.Errorf("sssp: node %d has an edge to %d, source has %d nodes", u, v, s.n)
+			return false
+		}
+		relax(v, w)
+		return true
+	})
+}
+
+func (s *sourceSearch) dijkstra(source uint32) {
+	resetDistPred(source, s.dist, s.pred)
+	h := minHeap[float32]{}
+	h.push(heapItem[float32]{node: source})
+	s.ops.HeapPushes++
+	for s.bad == nil {
+		it, ok := h.pop()
+		if !ok {
+			break
+		}
+		s.ops.HeapPops++
+		if it.dist > s.dist[it.node] {
+			continue
+		}
+		s.ops.FrontierExpansions++
+		s.scan(it.node, func(v uint32, w float32) {
+			s.ops.EdgeRelaxations++
+			if nd := it.dist + w; nd < s.dist[v] {
+				s.dist[v], s.pred[v] = nd, int32(it.node)
+				h.push(heapItem[float32]{node: v, dist: nd})
+				s.ops.HeapPushes++
+				s.ops.Improvements++
+			}
+		})
+	}
+}
+
+func (s *sourceSearch) deltaStepping(source uint32, delta float32) {
+	resetDistPred(source, s.dist, s.pred)
+	inv := 1 / delta
+	bucketOf := make([]int32, s.n)
+	for i := range bucketOf {
+		bucketOf[i] = -1
+	}
+	settled := make([]bool, s.n)
+	buckets := [][]uint32{{source}}
+	bucketOf[source] = 0
+	// relax reports whether dist[v] improved and whether v (re)entered
+	// bucket cur.
+	relax := func(u, v uint32, w float32, cur int) (improved, sameBucket bool) {
+		nd := s.dist[u] + w
+		s.ops.EdgeRelaxations++
+		if nd >= s.dist[v] {
+			return false, false
+		}
+		b, ok := bucketIndex(nd, delta, inv, true, cur, int(s.n))
+		if !ok {
+			s.overflow = true
+			return false, false
+		}
+		s.dist[v], s.pred[v] = nd, int32(u)
+		s.ops.Improvements++
+		for b >= len(buckets) {
+			buckets = append(buckets, nil)
+		}
+		if bucketOf[v] != int32(b) {
+			buckets[b] = append(buckets[b], v)
+			bucketOf[v] = int32(b)
+			return true, b == cur
+		}
+		return true, false
+	}
+	for cur := 0; cur < len(buckets) && s.bad == nil && !s.overflow; cur++ {
+		var lightSet []uint32
+		for repeat := true; repeat && s.bad == nil && !s.overflow; {
+			repeat = false
+			frontier := buckets[cur][:0]
+			for _, u := range buckets[cur] {
+				if bucketOf[u] == int32(cur) {
+					bucketOf[u] = -1
+					frontier = append(frontier, u)
+				}
+			}
+			buckets[cur] = nil
+			s.ops.BucketScans++
+			for _, u := range frontier {
+				s.ops.FrontierExpansions++
+				if !settled[u] {
+					settled[u] = true
+					s.settled++
+					lightSet = append(lightSet, u)
+				}
+				s.scan(u, func(v uint32, w float32) {
+					if w > delta {
+						return
+					}
+					if improved, same := relax(u, v, w, cur); improved {
+						s.light++
+						repeat = repeat || same
+					}
+				})
+			}
+		}
+		for _, u := range lightSet {
+			s.scan(u, func(v uint32, w float32) {
+				if w <= delta {
+					return
+				}
+				if improved, _ := relax(u, v, w, cur); improved {
+					s.heavy++
+				}
+			})
+		}
+	}
+}
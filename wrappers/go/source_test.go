@@ -0,0 +1,57 @@
+package sssp
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRunSourceMatchesGraph(t *testing.T) {
+	// An implicit state space: from u, step to u+1 for 3 or jump to 2u for 5.
+	const n = 2000
+	implicit := FuncSource{Nodes: n, Neighbors: func(u uint32, fn func(uint32, float32) bool) {
+		if u+1 < n && !fn(u+1, 3) {
+			return
+		}
+		if 2*u < n {
+			fn(2*u, 5)
+		}
+	}}
+	var edges []Edge
+	for u := uint32(0); u < n; u++ {
+		implicit.ForEachNeighbor(u, func(v uint32, w float32) bool {
+			edges = append(edges, Edge{u, v, w})
+			return true
+		})
+	}
+	g, err := FromEdges(n, edges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mode := range []int{ModeBaseline, ModeStoc} {
+		want, err := g.RunSettled(1, mode, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, src := range []GraphSource{implicit, g} {
+			got, err := RunSource(src, 1, mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for v := range want.Dist {
+				if got.Dist[v] != want.Dist[v] {
+					t.Fatalf("mode %d %T node %d: %v, want %v", mode, src, v, got.Dist[v], want.Dist[v])
+				}
+			}
+			if p := got.Path(1999); len(p) == 0 || p[0] != 1 {
+				t.Fatalf("mode %d: path %v", mode, p)
+			}
+		}
+	}
+	if _, err := RunSource(implicit, 0, ModeAutotune); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("autotune: %v", err)
+	}
+	bad := FuncSource{Nodes: 2, Neighbors: func(u uint32, fn func(uint32, float32) bool) { fn(7, 1) }}
+	if _, err := RunSource(bad, 0, ModeBaseline); err == nil {
+		t.Fatalf("out-of-range target accepted")
+	}
+}
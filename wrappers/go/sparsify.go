@@ -0,0 +1,130 @@
+package sssp
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Spanner is a sparse subgraph of a graph that keeps every distance within
+// a known factor, as built by Graph.Sparsify.
+type Spanner struct {
+	// Graph has the same nodes, with their IDs, coordinates and
+	// attributes, and a subset of the edges.
+	Graph *Graph
+	// Stretch bounds the distortion: for every pair of nodes, the distance
+	// in Graph is at least the original distance and at most Stretch times
+	// it.
+	Stretch float64
+	// Removed is the number of edges left out.
+	Removed int
+}
+
+// Sparsify builds a greedy t-spanner of g with t = stretch: it visits the
+// edges by increasing weight and keeps u->v of weight w only if the edges
+// kept so far do not already lead from u to v within stretch·w. Every
+// left-out edge is then covered by a path at most stretch times longer, so
+// every distance is too; Dijkstra on it is an approximation for users who
+// accept one, and on dense graphs, where most edges are redundant, a much
+// cheaper one. Each edge costs one search bounded by stretch·w. Stretch 1
+// only drops edges that some other path matches exactly. Self-loops are
+// always dropped, and the guarantee holds up to float32 rounding.
+func (g *Graph) Sparsify(stretch float64) (*Spanner, error) {
+	if !(stretch >= 1) || math.IsInf(stretch, 1) {
+		return nil, fmt.Errorf("sssp: sparsify: stretch %v, want a finite value >= 1", stretch)
+	}
+	n := g.NodeCount()
+	order := make([]uint32, len(g.targets)) // edge IDs by weight
+	from := make([]uint32, len(g.targets))
+	for u := uint32(0); u < n; u++ {
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			order[e], from[e] = e, u
+		}
+	}
+	slices.SortStableFunc(order, func(a, b uint32) int { return cmp.Compare(g.weights[a], g.weights[b]) })
+
+	kept := make([]bool, len(g.targets))
+	adj := make([][]uint32, n) // kept edge IDs by source node
+	dist := make([]float32, n)
+	for v := range dist {
+		dist[v] = Unreachable
+	}
+	var touched []uint32
+	var q minHeap[float32]
+	// within reports whether the kept edges lead from u to v within limit.
+	within := func(u, v uint32, limit float32) bool {
+		for _, t := range touched {
+			dist[t] = Unreachable
+		}
+		touched, q.data = append(touched[:0], u), q.data[:0]
+		dist[u] = 0
+		q.push(heapItem[float32]{node: u})
+		for {
+			it, ok := q.pop()
+			if !ok || it.dist > limit {
+				return false
+			}
+			if it.node == v {
+				return true
+			}
+			if it.dist > dist[it.node] {
+				continue
+			}
+			for _, e := range adj[it.node] {
+				t := g.targets[e]
+				if nd := it.dist + g.weights[e]; nd < dist[t] && nd <= limit {
+					if dist[t] == Unreachable {
+						touched = append(touched, t)
+					}
+					dist[t] = nd
+					q.push(heapItem[float32]{node: t, dist: nd})
+				}
+			}
+		}
+	}
+	removed := 0
+	for _, e := range order {
+		u, v, w := from[e], g.targets[e], g.weights[e]
+		if u == v || within(u, v, float32(stretch*float64(w))) {
+			removed++
+			continue
+		}
+		kept[e] = true
+		adj[u] = append(adj[u], e)
+	}
+	all := make([]uint32, n)
+	same := make([]int32, n)
+	for v := range all {
+		all[v], same[v] = uint32(v), int32(v)
+	}
+	return &Spanner{Graph: g.extract(all, same, kept), Stretch: stretch, Removed: removed}, nil
+}
+
+// Run runs mode on the spanner and reports the result with its guarantee.
+func (s *Spanner) Run(source uint32, mode int) (ApproxResult, error) {
+	r, err := s.Graph.Run(source, mode)
+	return ApproxResult{Result: r, Stretch: s.Stretch}, err
+}
+
+// ApproxResult is a result whose distances may exceed the true ones by a
+// bounded factor, a (1+ε) guarantee with ε = Stretch-1. Each reachable
+// node's true distance lies between Dist/Stretch and Dist, and Dist is the
+// length of a real path, the one Path returns. Reachability is exact.
+type ApproxResult struct {
+	Result
+	Stretch float64
+}
+
+// Epsilon returns ε of the (1+ε) guarantee.
+func (r *ApproxResult) Epsilon() float64 { return r.Stretch - 1 }
+
+// Bounds returns the interval holding node v's true distance: both ends are
+// Unreachable if v is, or out of range.
+func (r *ApproxResult) Bounds(v uint32) (lo, hi float32) {
+	if !r.Reachable(v) {
+		return Unreachable, Unreachable
+	}
+	d := r.Dist[v]
+	return float32(float64(d) / r.Stretch), d
+}
@@ -0,0 +1,59 @@
+package sssp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSparsify(t *testing.T) {
+	g := randomGraph(t, 150, 3000, 4)
+	for _, stretch := range []float64{1, 1.5, 3} {
+		s, err := g.Sparsify(stretch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h := s.Graph
+		if h.NodeCount() != g.NodeCount() || h.EdgeCount()+s.Removed != g.EdgeCount() {
+			t.Fatalf("stretch %v: %d nodes, %d edges + %d removed", stretch, h.NodeCount(), h.EdgeCount(), s.Removed)
+		}
+		if stretch > 1 && s.Removed < g.EdgeCount()/2 {
+			t.Errorf("stretch %v removed only %d of %d edges", stretch, s.Removed, g.EdgeCount())
+		}
+		for _, e := range h.Edges() {
+			if w, ok := g.EdgeWeight(e.From, e.To); !ok || w > e.Weight {
+				t.Fatalf("stretch %v: edge %v not in the graph", stretch, e)
+			}
+		}
+		for src := uint32(0); src < 150; src += 37 {
+			want, err := g.RunSettled(src, ModeBaseline, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := s.Run(src, ModeBaseline)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Epsilon() != stretch-1 {
+				t.Errorf("epsilon %v", got.Epsilon())
+			}
+			for v, d := range want.Dist {
+				lo, hi := got.Bounds(uint32(v))
+				if math.IsInf(float64(d), 1) != math.IsInf(float64(hi), 1) {
+					t.Fatalf("stretch %v: %d->%d reachability differs", stretch, src, v)
+				}
+				if d < lo*(1-1e-5) || d > hi*(1+1e-5) {
+					t.Fatalf("stretch %v: %d->%d true distance %v outside [%v, %v]", stretch, src, v, d, lo, hi)
+				}
+			}
+			// Spanner paths are paths of g of the reported length.
+			if v, err := g.Verify(src, &got.Result, nil, DefaultTolerance); err != nil || !v.OK() {
+				t.Fatalf("stretch %v: verify: %v %v", stretch, v, err)
+			}
+		}
+	}
+	for _, bad := range []float64{0.5, math.NaN(), math.Inf(1)} {
+		if _, err := g.Sparsify(bad); err == nil {
+			t.Errorf("stretch %v accepted", bad)
+		}
+	}
+}
@@ -2,7 +2,10 @@ package sssp
 
 /*
 #cgo LDFLAGS: -L${SRCDIR}/../../implementations/rust/sssp_core/target/release -lsssp_core
+#cgo linux LDFLAGS: -ldl
 #include <stdint.h>
+#include <stdlib.h>
+#include <dlfcn.h>
 
 typedef struct SsspResultInfo {
   uint64_t relaxations;
@@ -21,46 +24,346 @@ int32_t sssp_run_stoc(uint32_t n, const uint32_t* offsets, const uint32_t* targe
 int32_t sssp_run_stoc_autotune(uint32_t n, const uint32_t* offsets, const uint32_t* targets,
                       const float* weights, uint32_t source, float* out_dist,
                       int32_t* out_pred, SsspResultInfo* info);
+float sssp_get_last_delta();
 uint32_t sssp_version();
+
+// Entry points newer than the functions above are resolved at runtime so an
+// older libsssp_core still loads; callers check for NULL and report
+// ErrUnsupported instead of failing at link/load time.
+typedef struct SsspAutotuneParams {
+  const float* candidates;
+  uint32_t candidate_count;
+  uint32_t trial_limit;
+  float    pinned_delta;
+  float    chosen_delta;
+  float    chosen_mult;
+  float    avg_weight;
+  uint32_t trials;
+  uint32_t buckets;
+} SsspAutotuneParams;
+
+typedef int32_t (*sssp_batch_threads_fn)(uint32_t, const uint32_t*, const uint32_t*, const float*,
+                                         const uint32_t*, uint32_t, int32_t, uint32_t,
+                                         float*, int32_t*, SsspResultInfo*);
+typedef int32_t (*sssp_autotune_params_fn)(uint32_t, const uint32_t*, const uint32_t*, const float*,
+                                           uint32_t, float*, int32_t*, SsspAutotuneParams*, SsspResultInfo*);
+typedef uint64_t (*sssp_capabilities_fn)(void);
+typedef uint32_t (*sssp_last_threads_fn)(void);
+typedef int32_t (*sssp_run_fn)(uint32_t, const uint32_t*, const uint32_t*, const float*,
+                               uint32_t, float*, int32_t*, SsspResultInfo*);
+
+typedef struct SsspOpCounters {
+  uint64_t edge_relaxations;
+  uint64_t improvements;
+  uint64_t heap_pushes;
+  uint64_t heap_pops;
+  uint64_t frontier_expansions;
+  uint64_t bucket_scans;
+} SsspOpCounters;
+typedef void (*sssp_op_counters_fn)(SsspOpCounters*);
+
+typedef struct SsspStocParams {
+  float    chosen_delta;
+  uint32_t restarts;
+} SsspStocParams;
+typedef int32_t (*sssp_stoc_params_fn)(uint32_t, const uint32_t*, const uint32_t*, const float*,
+                                       uint32_t, float*, int32_t*, SsspStocParams*, SsspResultInfo*);
+
+static void* sssp_lookup(const char* name) { return dlsym(RTLD_DEFAULT, name); }
+
+// The core keeps op counters per thread, so they are read back on the thread
+// that ran the query, inside the same cgo call. ops_fn may be NULL.
+static void sssp_read_ops(void* ops_fn, int32_t rc, SsspOpCounters* ops) {
+  if (rc == 0 && ops_fn) ((sssp_op_counters_fn)ops_fn)(ops);
+}
+
+// The delta of a stoc run comes back through sssp_run_stoc_params when the
+// library has it (stoc_fn), else from sssp_get_last_delta on the same thread.
+static int32_t sssp_run_mode(int32_t mode, uint32_t n, const uint32_t* offsets, const uint32_t* targets,
+                             const float* weights, uint32_t source, float* out_dist, int32_t* out_pred,
+                             SsspResultInfo* info, void* stoc_fn, float* delta, void* ops_fn, SsspOpCounters* ops) {
+  int32_t rc;
+  SsspStocParams sp = {0};
+  switch (mode) {
+  case 0: rc = sssp_run_baseline(n, offsets, targets, weights, source, out_dist, out_pred, info); break;
+  case 1:
+    if (stoc_fn) {
+      rc = ((sssp_stoc_params_fn)stoc_fn)(n, offsets, targets, weights, source, out_dist, out_pred, &sp, info);
+      *delta = sp.chosen_delta;
+    } else {
+      rc = sssp_run_stoc(n, offsets, targets, weights, source, out_dist, out_pred, info);
+      *delta = sssp_get_last_delta();
+    }
+    break;
+  default: rc = sssp_run_stoc_autotune(n, offsets, targets, weights, source, out_dist, out_pred, info); break;
+  }
+  sssp_read_ops(ops_fn, rc, ops);
+  return rc;
+}
+
+// The worker count comes back from sssp_get_last_threads (threads_fn, may be
+// NULL) on the same thread, like the op counters.
+static int32_t sssp_call_batch_threads(void* fn, uint32_t n, const uint32_t* offsets, const uint32_t* targets,
+                                       const float* weights, const uint32_t* sources, uint32_t k, int32_t mode,
+                                       uint32_t threads, float* out_dist, int32_t* out_pred, SsspResultInfo* info,
+                                       void* ops_fn, SsspOpCounters* ops, void* threads_fn, uint32_t* used) {
+  int32_t rc = ((sssp_batch_threads_fn)fn)(n, offsets, targets, weights, sources, k, mode, threads, out_dist, out_pred, info);
+  sssp_read_ops(ops_fn, rc, ops);
+  if (rc == 0 && threads_fn) *used = ((sssp_last_threads_fn)threads_fn)();
+  return rc;
+}
+static int32_t sssp_call_autotune_params(void* fn, uint32_t n, const uint32_t* offsets, const uint32_t* targets,
+                                         const float* weights, uint32_t source, float* out_dist, int32_t* out_pred,
+                                         SsspAutotuneParams* params, SsspResultInfo* info,
+                                         void* ops_fn, SsspOpCounters* ops) {
+  int32_t rc = ((sssp_autotune_params_fn)fn)(n, offsets, targets, weights, source, out_dist, out_pred, params, info);
+  sssp_read_ops(ops_fn, rc, ops);
+  return rc;
+}
+static uint64_t sssp_call_capabilities(void* fn) { return ((sssp_capabilities_fn)fn)(); }
+static int32_t sssp_call_run(void* fn, uint32_t n, const uint32_t* offsets, const uint32_t* targets,
+                             const float* weights, uint32_t source, float* out_dist, int32_t* out_pred,
+                             SsspResultInfo* info, void* ops_fn, SsspOpCounters* ops) {
+  int32_t rc = ((sssp_run_fn)fn)(n, offsets, targets, weights, source, out_dist, out_pred, info);
+  sssp_read_ops(ops_fn, rc, ops);
+  return rc;
+}
 */
 import "C"
-import "unsafe"
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"unsafe"
+)
 
-// Result holds algorithm outputs.
-type Result struct {
-	Dist  []float32
-	Pred  []int32
-	Stats Stats
+// nativeSymbols holds the optional entry points resolved from the loaded library.
+type nativeSymbols struct {
+	batchThreads   unsafe.Pointer
+	autotuneParams unsafe.Pointer
+	capabilities   unsafe.Pointer
+	runGPU         unsafe.Pointer
+	opCounters     unsafe.Pointer
+	stocParams     unsafe.Pointer
+	lastThreads    unsafe.Pointer
 }
 
-// Stats mirrors the Rust SsspResultInfo.
-type Stats struct {
-	Relaxations      uint64
-	LightRelaxations uint64
-	HeavyRelaxations uint64
-	Settled          uint32
-	ErrorCode        int32
-	Version          uint32
+var (
+	nativesOnce sync.Once
+	nativesTab  nativeSymbols
+)
+
+func natives() *nativeSymbols {
+	nativesOnce.Do(func() {
+		nativesTab.batchThreads = lookup("sssp_run_batch_threads")
+		nativesTab.autotuneParams = lookup("sssp_run_stoc_autotune_params")
+		nativesTab.capabilities = lookup("sssp_capabilities")
+		nativesTab.runGPU = lookup("sssp_run_gpu")
+		nativesTab.opCounters = lookup("sssp_get_last_op_counters")
+		nativesTab.stocParams = lookup("sssp_run_stoc_params")
+		nativesTab.lastThreads = lookup("sssp_get_last_threads")
+	})
+	return &nativesTab
+}
+
+func lookup(name string) unsafe.Pointer {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return C.sssp_lookup(cname)
 }
 
-// Run executes a selected variant: 0 baseline, 1 stoc, 2 autotune.
+// Capabilities reports the feature set of the loaded native library. Libraries
+// predating sssp_capabilities are probed symbol by symbol.
+func Capabilities() Capability {
+	syms := natives()
+	if syms.capabilities != nil {
+		return Capability(C.sssp_call_capabilities(syms.capabilities))
+	}
+	caps := CapBaseline | CapStoc | CapAutotune
+	if syms.autotuneParams != nil {
+		caps |= CapAutotuneParams
+	}
+	if syms.batchThreads != nil {
+		caps |= CapBatch | CapThreads
+	}
+	if syms.opCounters != nil {
+		caps |= CapOpCounters
+	}
+	if syms.stocParams != nil {
+		caps |= CapStocParams
+	}
+	return caps
+}
+
+// Run executes a selected variant: 0 baseline, 1 stoc, 2 autotune, 3 GPU.
+// It fails without running if the array lengths disagree with n (see
+// NewGraphCSR for the layout).
 func Run(n uint32, offsets, targets []uint32, weights []float32, source uint32, mode int) (Result, error) {
+	if err := checkCSR(n, offsets, targets, weights); err != nil {
+		return Result{}, err
+	}
+	if mode == ModeGPU {
+		return runGPU(n, offsets, targets, weights, source)
+	}
 	dist := make([]float32, n)
 	pred := make([]int32, n)
 	var info C.SsspResultInfo
-	var rc C.int32_t
+	var ops C.SsspOpCounters
+	var delta C.float
 	switch mode {
-	case 0:
-		rc = C.sssp_run_baseline(C.uint32_t(n), (*C.uint32_t)(unsafe.Pointer(&offsets[0])), (*C.uint32_t)(unsafe.Pointer(&targets[0])), (*C.float)(unsafe.Pointer(&weights[0])), C.uint32_t(source), (*C.float)(unsafe.Pointer(&dist[0])), (*C.int32_t)(unsafe.Pointer(&pred[0])), &info)
-	case 1:
-		rc = C.sssp_run_stoc(C.uint32_t(n), (*C.uint32_t)(unsafe.Pointer(&offsets[0])), (*C.uint32_t)(unsafe.Pointer(&targets[0])), (*C.float)(unsafe.Pointer(&weights[0])), C.uint32_t(source), (*C.float)(unsafe.Pointer(&dist[0])), (*C.int32_t)(unsafe.Pointer(&pred[0])), &info)
-	case 2:
-		rc = C.sssp_run_stoc_autotune(C.uint32_t(n), (*C.uint32_t)(unsafe.Pointer(&offsets[0])), (*C.uint32_t)(unsafe.Pointer(&targets[0])), (*C.float)(unsafe.Pointer(&weights[0])), C.uint32_t(source), (*C.float)(unsafe.Pointer(&dist[0])), (*C.int32_t)(unsafe.Pointer(&pred[0])), &info)
+	case ModeBaseline, ModeStoc:
+	case ModeAutotune:
+		return RunAutotune(n, offsets, targets, weights, source, AutotuneParams{})
 	default:
-		return Result{}, nil
+		return Result{}, fmt.Errorf("%w: mode %d", ErrUnsupported, mode)
+	}
+	rc := C.sssp_run_mode(C.int32_t(mode), C.uint32_t(n), u32ptr(offsets), u32ptr(targets), f32ptr(weights), C.uint32_t(source), (*C.float)(unsafe.Pointer(&dist[0])), (*C.int32_t)(unsafe.Pointer(&pred[0])), &info, natives().stocParams, &delta, natives().opCounters, &ops)
+	if rc != 0 {
+		return Result{}, fmt.Errorf("sssp: run failed with code %d", int32(rc))
+	}
+	stats := statsFrom(&info, &ops)
+	stats.Delta = float32(delta)
+	return Result{Dist: dist, Pred: pred, Stats: stats}, nil
+}
+
+// RunAutotune executes the autotuned variant with explicit tuner controls and
+// reports the chosen parameters in Stats. Feed Stats.Delta back through
+// PinnedDelta to skip tuning on later runs.
+func RunAutotune(n uint32, offsets, targets []uint32, weights []float32, source uint32, params AutotuneParams) (Result, error) {
+	if err := checkCSR(n, offsets, targets, weights); err != nil {
+		return Result{}, err
+	}
+	dist := make([]float32, n)
+	pred := make([]int32, n)
+	var info C.SsspResultInfo
+	var ops C.SsspOpCounters
+	syms := natives()
+	fn := syms.autotuneParams
+	if fn == nil {
+		// Older cores only have the parameterless entry point.
+		if len(params.Candidates) > 0 || params.TrialLimit > 0 || params.PinnedDelta > 0 {
+			return Result{}, fmt.Errorf("%w: autotune parameters", ErrUnsupported)
+		}
+		rc := C.sssp_run_mode(C.int32_t(ModeAutotune), C.uint32_t(n), u32ptr(offsets), u32ptr(targets), f32ptr(weights), C.uint32_t(source), (*C.float)(unsafe.Pointer(&dist[0])), (*C.int32_t)(unsafe.Pointer(&pred[0])), &info, nil, nil, syms.opCounters, &ops)
+		if rc != 0 {
+			return Result{}, fmt.Errorf("sssp: autotune run failed with code %d", int32(rc))
+		}
+		return Result{Dist: dist, Pred: pred, Stats: statsFrom(&info, &ops)}, nil
+	}
+	cp := C.SsspAutotuneParams{
+		candidate_count: C.uint32_t(len(params.Candidates)),
+		trial_limit:     C.uint32_t(params.TrialLimit),
+		pinned_delta:    C.float(params.PinnedDelta),
+	}
+	if len(params.Candidates) > 0 {
+		// cp is passed by pointer, so the slice it references must be pinned.
+		var pin runtime.Pinner
+		pin.Pin(&params.Candidates[0])
+		defer pin.Unpin()
+		cp.candidates = f32ptr(params.Candidates)
 	}
+	rc := C.sssp_call_autotune_params(fn, C.uint32_t(n), u32ptr(offsets), u32ptr(targets), f32ptr(weights), C.uint32_t(source), (*C.float)(unsafe.Pointer(&dist[0])), (*C.int32_t)(unsafe.Pointer(&pred[0])), &cp, &info, syms.opCounters, &ops)
 	if rc != 0 {
-		return Result{}, nil
+		return Result{}, fmt.Errorf("sssp: autotune run failed with code %d", int32(rc))
+	}
+	stats := statsFrom(&info, &ops)
+	stats.Delta = float32(cp.chosen_delta)
+	stats.DeltaMultiplier = float32(cp.chosen_mult)
+	stats.Buckets = uint32(cp.buckets)
+	stats.AutotuneTrials = uint32(cp.trials)
+	return Result{Dist: dist, Pred: pred, Stats: stats}, nil
+}
+
+// runGPU dispatches to the device backend when the library advertises one and
+// otherwise serves the query with delta-stepping, flagging Stats.Fallback.
+func runGPU(n uint32, offsets, targets []uint32, weights []float32, source uint32) (Result, error) {
+	fn := natives().runGPU
+	if fn == nil || !Capabilities().Has(CapGPU) {
+		res, err := Run(n, offsets, targets, weights, source, ModeStoc)
+		res.Stats.Fallback = err == nil
+		return res, err
+	}
+	dist := make([]float32, n)
+	pred := make([]int32, n)
+	var info C.SsspResultInfo
+	var ops C.SsspOpCounters
+	rc := C.sssp_call_run(fn, C.uint32_t(n), u32ptr(offsets), u32ptr(targets), f32ptr(weights), C.uint32_t(source), (*C.float)(unsafe.Pointer(&dist[0])), (*C.int32_t)(unsafe.Pointer(&pred[0])), &info, natives().opCounters, &ops)
+	if rc != 0 {
+		return Result{}, fmt.Errorf("sssp: gpu run failed with code %d", int32(rc))
+	}
+	return Result{Dist: dist, Pred: pred, Stats: statsFrom(&info, &ops)}, nil
+}
+
+func statsFrom(info *C.SsspResultInfo, ops *C.SsspOpCounters) Stats {
+	return Stats{
+		Relaxations: uint64(info.relaxations), LightRelaxations: uint64(info.light_relaxations), HeavyRelaxations: uint64(info.heavy_relaxations), Settled: uint32(info.settled), ErrorCode: int32(info.error_code), Version: uint32(C.sssp_version()),
+		Ops: OpCounters{
+			EdgeRelaxations: uint64(ops.edge_relaxations), Improvements: uint64(ops.improvements),
+			HeapPushes: uint64(ops.heap_pushes), HeapPops: uint64(ops.heap_pops),
+			FrontierExpansions: uint64(ops.frontier_expansions), BucketScans: uint64(ops.bucket_scans),
+		},
+	}
+}
+
+// RunBatch computes distances from every source in one native call. The core
+// splits the sources across its worker threads (SSSP_THREADS or all cores).
+func RunBatch(n uint32, offsets, targets []uint32, weights []float32, sources []uint32, mode int) (BatchResult, error) {
+	return RunBatchThreads(n, offsets, targets, weights, sources, mode, 0)
+}
+
+// RunBatchThreads is RunBatch with the native worker count capped at threads.
+// threads <= 0 falls back to SSSP_THREADS, then to all available cores. A
+// delta-stepping row past the last bucket fails the batch with code -5; the
+// pure-Go engine instead finishes that row with Dijkstra and sets
+// Stats.Fallback.
+func RunBatchThreads(n uint32, offsets, targets []uint32, weights []float32, sources []uint32, mode int, threads int) (BatchResult, error) {
+	if len(sources) == 0 {
+		return BatchResult{N: n, Sources: sources}, nil
+	}
+	if err := checkBatch(n, offsets, targets, weights, sources); err != nil {
+		return BatchResult{}, err
+	}
+	fn := natives().batchThreads
+	if fn == nil {
+		return BatchResult{}, fmt.Errorf("%w: batched runs", ErrUnsupported)
+	}
+	if threads < 0 {
+		threads = 0
+	}
+	fallback := false
+	if mode == ModeGPU {
+		// Batched device offload is not part of the ABI yet.
+		mode, fallback = ModeStoc, true
+	}
+	dist := make([]float32, len(sources)*int(n))
+	var info C.SsspResultInfo
+	var ops C.SsspOpCounters
+	var used C.uint32_t
+	rc := C.sssp_call_batch_threads(fn, C.uint32_t(n), u32ptr(offsets), u32ptr(targets), f32ptr(weights), u32ptr(sources), C.uint32_t(len(sources)), C.int32_t(mode), C.uint32_t(threads), (*C.float)(unsafe.Pointer(&dist[0])), nil, &info, natives().opCounters, &ops, natives().lastThreads, &used)
+	if rc != 0 {
+		return BatchResult{}, fmt.Errorf("sssp: batch run failed with code %d", int32(rc))
+	}
+	stats := statsFrom(&info, &ops)
+	stats.Fallback = fallback
+	return BatchResult{N: n, Sources: sources, Dist: dist, Stats: stats, Threads: uint32(used)}, nil
+}
+
+// placeholders keep pointer arguments non-nil for empty slices (e.g. edgeless graphs).
+var (
+	placeholderU32 [1]uint32
+	placeholderF32 [1]float32
+)
+
+func u32ptr(s []uint32) *C.uint32_t {
+	if len(s) == 0 {
+		return (*C.uint32_t)(unsafe.Pointer(&placeholderU32[0]))
+	}
+	return (*C.uint32_t)(unsafe.Pointer(&s[0]))
+}
+
+func f32ptr(s []float32) *C.float {
+	if len(s) == 0 {
+		return (*C.float)(unsafe.Pointer(&placeholderF32[0]))
 	}
-	return Result{Dist: dist, Pred: pred, Stats: Stats{Relaxations: uint64(info.relaxations), LightRelaxations: uint64(info.light_relaxations), HeavyRelaxations: uint64(info.heavy_relaxations), Settled: uint32(info.settled), ErrorCode: int32(info.error_code), Version: uint32(C.sssp_version())}}, nil
+	return (*C.float)(unsafe.Pointer(&s[0]))
 }
@@ -1,6 +1,10 @@
 package sssp
 
-import "testing"
+import (
+	"errors"
+	"sync"
+	"testing"
+)
 
 func TestRunBaselineSmall(t *testing.T) {
 	// Simple 3-node chain 0->1->2
@@ -18,3 +22,254 @@ func TestRunBaselineSmall(t *testing.T) {
 		t.Fatalf("expected distance 3 got %v", res.Dist[2])
 	}
 }
+
+func TestRunRejectsMismatchedArrays(t *testing.T) {
+	off := []uint32{0, 1, 2, 2}
+	tgt := []uint32{1, 2}
+	wts := []float32{1, 2}
+	cases := []struct {
+		name     string
+		n        uint32
+		off, tgt []uint32
+		wts      []float32
+	}{
+		{"no nodes", 0, []uint32{0}, nil, nil},
+		{"short offsets", 4, off, tgt, wts},
+		{"long offsets", 2, off, tgt, wts},
+		{"offsets not from 0", 3, []uint32{1, 1, 2, 2}, tgt, wts},
+		{"missing weight", 3, off, tgt, wts[:1]},
+		{"missing target", 3, off, tgt[:1], wts[:1]},
+	}
+	for _, c := range cases {
+		if _, err := Run(c.n, c.off, c.tgt, c.wts, 0, ModeBaseline); err == nil {
+			t.Errorf("Run, %s: no error", c.name)
+		}
+		if _, err := RunAutotune(c.n, c.off, c.tgt, c.wts, 0, AutotuneParams{}); err == nil {
+			t.Errorf("RunAutotune, %s: no error", c.name)
+		}
+		if _, err := RunBatch(c.n, c.off, c.tgt, c.wts, []uint32{0}, ModeBaseline); err == nil {
+			t.Errorf("RunBatch, %s: no error", c.name)
+		}
+	}
+}
+
+func TestRunBatchMatchesRun(t *testing.T) {
+	// 0->1 (1), 0->2 (4), 1->2 (2), 2->3 (1), 3->0 (5)
+	off := []uint32{0, 2, 3, 4, 5}
+	tgt := []uint32{1, 2, 2, 3, 0}
+	wts := []float32{1, 4, 2, 1, 5}
+	sources := []uint32{0, 2, 3}
+	for _, mode := range []int{ModeBaseline, ModeStoc, ModeAutotune} {
+		batch, err := RunBatch(4, off, tgt, wts, sources, mode)
+		if err != nil {
+			t.Fatalf("mode %d: %v", mode, err)
+		}
+		for i, s := range sources {
+			single, _ := Run(4, off, tgt, wts, s, ModeBaseline)
+			row := batch.Row(i)
+			for v := range row {
+				if row[v] != single.Dist[v] {
+					t.Fatalf("mode %d source %d node %d: batch %v run %v", mode, s, v, row[v], single.Dist[v])
+				}
+			}
+		}
+	}
+	if _, err := RunBatch(4, off, tgt, wts, []uint32{9}, ModeBaseline); err == nil {
+		t.Fatalf("expected error for out-of-range source")
+	}
+}
+
+func TestRunAutotuneReportsAndPinsDelta(t *testing.T) {
+	off := []uint32{0, 2, 3, 4, 5}
+	tgt := []uint32{1, 2, 2, 3, 0}
+	wts := []float32{1, 4, 2, 1, 5}
+	tuned, err := RunAutotune(4, off, tgt, wts, 0, AutotuneParams{Candidates: []float32{1, 2}})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if tuned.Stats.Delta <= 0 || tuned.Stats.AutotuneTrials != 2 {
+		t.Fatalf("unexpected tuner report: %+v", tuned.Stats)
+	}
+	pinned, err := RunAutotune(4, off, tgt, wts, 0, AutotuneParams{PinnedDelta: tuned.Stats.Delta})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if pinned.Stats.AutotuneTrials != 0 || pinned.Stats.Delta != tuned.Stats.Delta {
+		t.Fatalf("pinned run retuned: %+v", pinned.Stats)
+	}
+	if pinned.Dist[3] != 4 {
+		t.Fatalf("expected distance 4 got %v", pinned.Dist[3])
+	}
+}
+
+func TestRunStocDeltaPerCall(t *testing.T) {
+	// Same shape, weights a hundredfold apart, so the two deltas differ.
+	off := []uint32{0, 2, 3, 4, 5}
+	tgt := []uint32{1, 2, 2, 3, 0}
+	light := []float32{1, 4, 2, 1, 5}
+	heavy := []float32{100, 400, 200, 100, 500}
+	want := make([]float32, 2)
+	for i, wts := range [][]float32{light, heavy} {
+		res, err := Run(4, off, tgt, wts, 0, ModeStoc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want[i] = res.Stats.Delta
+	}
+	if want[0] <= 0 || want[0] == want[1] {
+		t.Fatalf("deltas %v: want two distinct positive values", want)
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			wts := [][]float32{light, heavy}[i]
+			for r := 0; r < 200; r++ {
+				res, err := Run(4, off, tgt, wts, 0, ModeStoc)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if res.Stats.Delta != want[i] {
+					t.Errorf("graph %d: delta %v, want %v", i, res.Stats.Delta, want[i])
+					return
+				}
+			}
+		}(g % 2)
+	}
+	wg.Wait()
+}
+
+func TestRunBatchThreadsSingleWorker(t *testing.T) {
+	off := []uint32{0, 2, 3, 4, 5}
+	tgt := []uint32{1, 2, 2, 3, 0}
+	wts := []float32{1, 4, 2, 1, 5}
+	sources := []uint32{0, 1, 2, 3, 0}
+	all, err := RunBatch(4, off, tgt, wts, sources, ModeBaseline)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	// The cap is honoured and never exceeds one worker per row; 5 rows over
+	// 4 workers come in chunks of 2, so only 3 run.
+	for _, c := range []struct{ threads, want int }{{1, 1}, {2, 2}, {4, 3}, {16, len(sources)}} {
+		capped, err := RunBatchThreads(4, off, tgt, wts, sources, ModeBaseline, c.threads)
+		if err != nil {
+			t.Fatalf("threads %d: %v", c.threads, err)
+		}
+		if capped.Threads != uint32(c.want) {
+			t.Fatalf("threads %d: ran on %d workers, want %d", c.threads, capped.Threads, c.want)
+		}
+		for i := range capped.Dist {
+			if capped.Dist[i] != all.Dist[i] {
+				t.Fatalf("threads %d entry %d: capped %v uncapped %v", c.threads, i, capped.Dist[i], all.Dist[i])
+			}
+		}
+	}
+}
+
+func TestCapabilitiesAndUnknownMode(t *testing.T) {
+	caps := Capabilities()
+	if !caps.Has(CapBaseline | CapStoc | CapAutotune | CapBatch) {
+		t.Fatalf("core capabilities missing: %b", caps)
+	}
+	off := []uint32{0, 1, 1}
+	tgt := []uint32{1}
+	wts := []float32{1}
+	if _, err := Run(2, off, tgt, wts, 0, 99); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported, got %v", err)
+	}
+}
+
+func TestRunGPUFallsBackWithoutDevice(t *testing.T) {
+	if Capabilities().Has(CapGPU) {
+		t.Skip("library advertises a GPU backend")
+	}
+	off := []uint32{0, 1, 2, 2}
+	tgt := []uint32{1, 2}
+	wts := []float32{1.0, 2.0}
+	res, err := Run(3, off, tgt, wts, 0, ModeGPU)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !res.Stats.Fallback || res.Dist[2] != 3.0 {
+		t.Fatalf("expected fallback result, got %+v dist %v", res.Stats, res.Dist)
+	}
+	batch, err := RunBatch(3, off, tgt, wts, []uint32{0, 1}, ModeGPU)
+	if err != nil || !batch.Stats.Fallback || batch.Row(0)[2] != 3.0 {
+		t.Fatalf("batch: %v, fallback %v", err, batch.Stats.Fallback)
+	}
+	g, _ := NewGraphCSR(off, tgt, wts)
+	if res, err := g.RunSettled(0, ModeGPU, nil); err != nil || !res.Stats.Fallback || res.Dist[2] != 3.0 {
+		t.Fatalf("pure-Go: %v, fallback %v", err, res.Stats.Fallback)
+	}
+}
+
+func TestOpCountersAreConsistent(t *testing.T) {
+	if !Capabilities().Has(CapOpCounters) {
+		t.Skip("library predates op counters")
+	}
+	off, tgt, wts := randomCSR(400, 4, 3)
+	base, err := Run(400, off, tgt, wts, 0, ModeBaseline)
+	if err != nil {
+		t.Fatalf("baseline: %v", err)
+	}
+	ops := base.Stats.Ops
+	var reached, scanned uint64
+	for v, d := range base.Dist {
+		if d != inf32 {
+			reached++
+			scanned += uint64(off[v+1] - off[v])
+		}
+	}
+	// Every reached node is expanded exactly once, scanning all its edges,
+	// and the heap is drained.
+	if ops.FrontierExpansions != reached || ops.EdgeRelaxations != scanned || ops.HeapPops != ops.HeapPushes ||
+		ops.Improvements != base.Stats.Relaxations || ops.HeapPushes != ops.Improvements+1 || ops.BucketScans != 0 {
+		t.Fatalf("baseline ops %+v; reached %d, scanned %d", ops, reached, scanned)
+	}
+	for _, mode := range []int{ModeStoc, ModeAutotune} {
+		res, err := Run(400, off, tgt, wts, 0, mode)
+		if err != nil {
+			t.Fatalf("mode %d: %v", mode, err)
+		}
+		ops := res.Stats.Ops
+		if ops.HeapPushes != 0 || ops.BucketScans == 0 || ops.FrontierExpansions < uint64(res.Stats.Settled) ||
+			ops.Improvements < res.Stats.Relaxations || ops.EdgeRelaxations < ops.Improvements {
+			t.Fatalf("mode %d ops %+v, stats %+v", mode, ops, res.Stats)
+		}
+	}
+	batch, err := RunBatch(400, off, tgt, wts, []uint32{0, 0}, ModeBaseline)
+	if err != nil {
+		t.Fatalf("batch: %v", err)
+	}
+	if batch.Stats.Ops.EdgeRelaxations != 2*scanned {
+		t.Fatalf("batch ops %+v, want twice %d edge relaxations", batch.Stats.Ops, scanned)
+	}
+}
+
+func TestUnreachableSentinelIsUniform(t *testing.T) {
+	// 0->1->2, node 3 isolated.
+	off := []uint32{0, 1, 2, 2, 2}
+	tgt := []uint32{1, 2}
+	wts := []float32{1.0, 2.0}
+	for _, mode := range []int{ModeBaseline, ModeStoc, ModeAutotune, ModeGPU} {
+		res, err := Run(4, off, tgt, wts, 0, mode)
+		if err != nil {
+			t.Fatalf("mode %d: %v", mode, err)
+		}
+		if res.Dist[3] != Unreachable || res.Pred[3] != -1 || res.Reachable(3) || !res.Reachable(2) || res.Reachable(4) {
+			t.Fatalf("mode %d: isolated node has distance %v, pred %d", mode, res.Dist[3], res.Pred[3])
+		}
+		if got := res.ReachableNodes(); len(got) != 3 || got[2] != 2 {
+			t.Fatalf("mode %d: reachable nodes %v", mode, got)
+		}
+		batch, err := RunBatch(4, off, tgt, wts, []uint32{0, 1}, mode)
+		if err != nil {
+			t.Fatalf("mode %d batch: %v", mode, err)
+		}
+		if batch.Row(0)[3] != Unreachable || batch.Row(1)[0] != Unreachable {
+			t.Fatalf("mode %d batch rows %v", mode, batch.Dist)
+		}
+	}
+}
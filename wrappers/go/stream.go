@@ -0,0 +1,123 @@
+package sssp
+
+import (
+	"fmt"
+	"math"
+)
+
+// StreamBuilder assembles a Graph from two passes over the same edge stream
+// without holding an edge list. The first pass counts out-degrees; the second
+// writes each edge straight into its CSR slot. Peak memory is the final
+// arrays plus one uint32 per node, where collecting []Edge for FromEdges
+// needs about 2.5x the final size.
+//
+//	b := NewStreamBuilder(n)
+//	for each edge: b.Count(e)
+//	for each edge: b.Place(e) // the same edges again
+//	g, err := b.Graph()
+//
+// Edges keep their second-pass order within each source node.
+type StreamBuilder struct {
+	fixed   uint32 // declared node count, 0 if taken from the edges
+	placing bool
+	offsets []uint32 // degrees while counting, then CSR offsets
+	cursor  []uint32 // next free slot per node while placing
+	targets []uint32
+	weights []float32
+	m       uint64
+	placed  uint64
+}
+
+// NewStreamBuilder starts a builder for n nodes. Zero means the node count is
+// the largest id seen while counting, plus one. Either way the per-node
+// array grows with the ids seen, so an n read from an untrusted header
+// costs nothing until the edges have been counted.
+func NewStreamBuilder(n uint32) *StreamBuilder {
+	return &StreamBuilder{fixed: n, offsets: make([]uint32, 1)}
+}
+
+// Count records e during the first pass.
+func (b *StreamBuilder) Count(e Edge) error {
+	if b.placing {
+		return fmt.Errorf("sssp: stream: Count after Place")
+	}
+	if hi := uint64(max(e.From, e.To)); hi >= uint64(len(b.offsets)-1) {
+		if b.fixed > 0 && hi >= uint64(b.fixed) {
+			return fmt.Errorf("sssp: stream: edge %d (%d->%d) out of range for %d nodes", b.m, e.From, e.To, b.fixed)
+		}
+		if hi >= math.MaxUint32 {
+			return fmt.Errorf("sssp: stream: node id %d leaves no room for a 32-bit node count", hi)
+		}
+		for uint64(len(b.offsets)-1) <= hi {
+			b.offsets = append(b.offsets, 0)
+		}
+	}
+	if b.m == math.MaxUint32 {
+		return fmt.Errorf("sssp: stream: more than %d edges", uint32(math.MaxUint32))
+	}
+	b.offsets[e.From+1]++
+	b.m++
+	return nil
+}
+
+func (b *StreamBuilder) allocate() {
+	b.placing = true
+	if b.fixed > 0 {
+		b.offsets = append(b.offsets, make([]uint32, int(b.fixed)+1-len(b.offsets))...)
+	}
+	n := len(b.offsets) - 1
+	for u := 0; u < n; u++ {
+		b.offsets[u+1] += b.offsets[u]
+	}
+	b.cursor = make([]uint32, n)
+	copy(b.cursor, b.offsets[:n])
+	b.targets = make([]uint32, b.m)
+	b.weights = make([]float32, b.m)
+}
+
+// Place writes e during the second pass. The first call ends counting.
+func (b *StreamBuilder) Place(e Edge) error {
+	if !b.placing {
+		b.allocate()
+	}
+	n := uint32(len(b.cursor))
+	if e.From >= n || e.To >= n {
+		return fmt.Errorf("sssp: stream: edge %d (%d->%d) was not seen while counting", b.placed, e.From, e.To)
+	}
+	i := b.cursor[e.From]
+	if i == b.offsets[e.From+1] {
+		return fmt.Errorf("sssp: stream: node %d has more edges than were counted", e.From)
+	}
+	b.targets[i], b.weights[i] = e.To, e.Weight
+	b.cursor[e.From]++
+	b.placed++
+	return nil
+}
+
+// Graph finishes the build. It fails if the second pass placed fewer edges
+// than the first counted.
+func (b *StreamBuilder) Graph() (*Graph, error) {
+	if !b.placing {
+		b.allocate()
+	}
+	if b.placed != b.m {
+		return nil, fmt.Errorf("sssp: stream: counted %d edges but placed %d", b.m, b.placed)
+	}
+	b.cursor = nil
+	return &Graph{offsets: b.offsets, targets: b.targets, weights: b.weights}, nil
+}
+
+// BuildStreaming runs pass twice, counting the edges it emits and then
+// placing them, and returns the graph. pass must emit the same edges both
+// times, e.g. by re-reading a file; an error from emit should be returned
+// unchanged.
+func BuildStreaming(n uint32, pass func(emit func(Edge) error) error) (*Graph, error) {
+	b := NewStreamBuilder(n)
+	if err := pass(b.Count); err != nil {
+		return nil, err
+	}
+	if err := pass(b.Place); err != nil {
+		return nil, err
+	}
+	return b.Graph()
+}
@@ -0,0 +1,101 @@
+package sssp
+
+import (
+	"compress/gzip"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildStreamingMatchesFromEdges(t *testing.T) {
+	off, tgt, wts := randomCSR(300, 4, 3)
+	g, _ := NewGraphCSR(off, tgt, wts)
+	edges := g.Edges()
+	rand.New(rand.NewSource(1)).Shuffle(len(edges), func(i, j int) { edges[i], edges[j] = edges[j], edges[i] })
+	want, _ := FromEdges(300, edges)
+
+	for _, n := range []uint32{300, 0} {
+		got, err := BuildStreaming(n, func(emit func(Edge) error) error {
+			for _, e := range edges {
+				if err := emit(e); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("n=%d: %v", n, err)
+		}
+		a, b := got.Edges(), want.Edges()
+		if len(a) != len(b) {
+			t.Fatalf("n=%d: %d edges, want %d", n, len(a), len(b))
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				t.Fatalf("n=%d edge %d: %v, want %v", n, i, a[i], b[i])
+			}
+		}
+	}
+}
+
+func TestStreamBuilderRejectsMismatchedPasses(t *testing.T) {
+	b := NewStreamBuilder(3)
+	b.Count(Edge{From: 0, To: 1})
+	if err := b.Count(Edge{From: 0, To: 3}); err == nil {
+		t.Fatalf("expected out-of-range edge to fail")
+	}
+	if err := b.Place(Edge{From: 0, To: 2}); err != nil {
+		t.Fatalf("place: %v", err)
+	}
+	if err := b.Place(Edge{From: 0, To: 1}); err == nil {
+		t.Fatalf("expected an uncounted edge to fail")
+	}
+	if err := b.Count(Edge{From: 1, To: 2}); err == nil {
+		t.Fatalf("expected Count after Place to fail")
+	}
+
+	b = NewStreamBuilder(0)
+	b.Count(Edge{From: 4, To: 1})
+	if _, err := b.Graph(); err == nil {
+		t.Fatalf("expected missing second pass to fail")
+	}
+}
+
+func TestLoadDIMACSFileStreams(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "g.gr.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	zw := gzip.NewWriter(f)
+	zw.Write([]byte(sampleGR))
+	zw.Close()
+	f.Close()
+
+	g, err := LoadDIMACSFile(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	res, err := g.Run(0, ModeBaseline)
+	if err != nil || g.EdgeCount() != 5 || res.Dist[3] != 4 {
+		t.Fatalf("run: %v dist %v", err, res.Dist)
+	}
+}
+
+func TestStreamBuilderDefersDeclaredNodes(t *testing.T) {
+	// A declared count far beyond the edges costs nothing while counting,
+	// and the out-of-range check still applies.
+	b := NewStreamBuilder(math.MaxUint32 - 1)
+	if err := b.Count(Edge{0, 1, 1}); err != nil {
+		t.Fatal(err)
+	}
+	if len(b.offsets) != 3 {
+		t.Fatalf("per-node array sized %d before any pass ended", len(b.offsets))
+	}
+	b = NewStreamBuilder(2)
+	if err := b.Count(Edge{0, 2, 1}); err == nil {
+		t.Fatal("edge past the declared count accepted")
+	}
+}
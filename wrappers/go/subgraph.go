@@ -0,0 +1,97 @@
+package sssp
+
+import "fmt"
+
+// Subgraph returns the subgraph induced by nodes: node nodes[i] becomes node
+// i, and every edge of g between two of them is kept, in CSR order. The
+// second result maps each node of g to its new ID, -1 for nodes left out.
+// Coordinates and attributes, if any, are carried over. nodes must be in
+// range and distinct.
+func (g *Graph) Subgraph(nodes []uint32) (*Graph, []int32, error) {
+	n := g.NodeCount()
+	newID := make([]int32, n)
+	for i := range newID {
+		newID[i] = -1
+	}
+	for i, u := range nodes {
+		if u >= n {
+			return nil, nil, fmt.Errorf("sssp: subgraph node %d out of range for %d nodes", u, n)
+		}
+		if newID[u] >= 0 {
+			return nil, nil, fmt.Errorf("sssp: subgraph node %d listed twice", u)
+		}
+		newID[u] = int32(i)
+	}
+	return g.extract(nodes, newID, nil), newID, nil
+}
+
+// SubgraphByEdges returns the subgraph of the edges keep selects and the
+// nodes they touch. Those nodes are renumbered in increasing order of their
+// old IDs; the second result maps old IDs to new ones as in Subgraph.
+func (g *Graph) SubgraphByEdges(keep func(Edge) bool) (*Graph, []int32) {
+	kept := make([]bool, len(g.targets))
+	for u := uint32(0); u < g.NodeCount(); u++ {
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			kept[e] = keep(Edge{From: u, To: g.targets[e], Weight: g.weights[e]})
+		}
+	}
+	return g.keepEdges(kept)
+}
+
+// keepEdges is SubgraphByEdges on the edges marked in kept, by ID.
+func (g *Graph) keepEdges(kept []bool) (*Graph, []int32) {
+	n := g.NodeCount()
+	touched := make([]bool, n)
+	for u := uint32(0); u < n; u++ {
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			if kept[e] {
+				touched[u], touched[g.targets[e]] = true, true
+			}
+		}
+	}
+	newID := make([]int32, n)
+	var nodes []uint32
+	for u := range touched {
+		newID[u] = -1
+		if touched[u] {
+			newID[u] = int32(len(nodes))
+			nodes = append(nodes, uint32(u))
+		}
+	}
+	return g.extract(nodes, newID, kept), newID
+}
+
+// extract builds the graph on nodes, renumbered by newID, from the edges
+// between them; a non-nil kept further restricts it to the marked edges.
+func (g *Graph) extract(nodes []uint32, newID []int32, kept []bool) *Graph {
+	offsets := make([]uint32, len(nodes)+1)
+	var targets []uint32
+	var weights []float32
+	var edgeIdx []int32 // old ID of each edge of the subgraph
+	for i, u := range nodes {
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			if v := newID[g.targets[e]]; v >= 0 && (kept == nil || kept[e]) {
+				targets = append(targets, uint32(v))
+				weights = append(weights, g.weights[e])
+				edgeIdx = append(edgeIdx, int32(e))
+			}
+		}
+		offsets[i+1] = uint32(len(targets))
+	}
+	sub := &Graph{offsets: offsets, targets: targets, weights: weights, owned: true}
+	if len(g.nodeAttrs) > 0 {
+		nodeIdx := make([]int32, len(nodes))
+		for i, u := range nodes {
+			nodeIdx[i] = int32(u)
+		}
+		sub.nodeAttrs = gatherAttrs(g.nodeAttrs, nodeIdx)
+	}
+	sub.edgeAttrs = gatherAttrs(g.edgeAttrs, edgeIdx)
+	if g.coords != nil {
+		sub.coords = make([]LatLon, len(nodes))
+		for i, u := range nodes {
+			sub.coords[i] = g.coords[u]
+		}
+	}
+	return sub
+}
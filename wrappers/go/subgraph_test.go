@@ -0,0 +1,52 @@
+package sssp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSubgraph(t *testing.T) {
+	g, err := FromEdges(5, []Edge{{0, 1, 1}, {1, 2, 2}, {2, 0, 3}, {2, 3, 4}, {3, 4, 5}, {4, 2, 6}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	coords := []LatLon{{0, 0}, {1, 1}, {2, 2}, {3, 3}, {4, 4}}
+	if err := g.SetCoords(coords); err != nil {
+		t.Fatal(err)
+	}
+	sub, newID, err := g.Subgraph([]uint32{4, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int32{-1, -1, 1, 2, 0}; !reflect.DeepEqual(newID, want) {
+		t.Fatalf("mapping %v, want %v", newID, want)
+	}
+	want := []Edge{{0, 1, 6}, {1, 2, 4}, {2, 0, 5}}
+	if got := sub.Edges(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("edges %v, want %v", got, want)
+	}
+	if c := sub.Coords(); !reflect.DeepEqual(c, []LatLon{{4, 4}, {2, 2}, {3, 3}}) {
+		t.Fatalf("coords %v", c)
+	}
+	if _, _, err := g.Subgraph([]uint32{1, 1}); err == nil {
+		t.Fatalf("duplicate node accepted")
+	}
+	if _, _, err := g.Subgraph([]uint32{5}); err == nil {
+		t.Fatalf("node out of range accepted")
+	}
+
+	heavy, newID := g.SubgraphByEdges(func(e Edge) bool { return e.Weight >= 4 })
+	if want := []int32{-1, -1, 0, 1, 2}; !reflect.DeepEqual(newID, want) {
+		t.Fatalf("edge-filter mapping %v, want %v", newID, want)
+	}
+	if want := []Edge{{0, 1, 4}, {1, 2, 5}, {2, 0, 6}}; !reflect.DeepEqual(heavy.Edges(), want) {
+		t.Fatalf("edge-filter edges %v, want %v", heavy.Edges(), want)
+	}
+	res, err := heavy.Run(0, ModeBaseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Dist[2] != 9 {
+		t.Fatalf("dist on the subgraph %v", res.Dist)
+	}
+}
@@ -0,0 +1,87 @@
+package sssp
+
+import (
+	"fmt"
+	"time"
+)
+
+// PhaseKind names a step of a traced run.
+type PhaseKind int
+
+const (
+	PhaseHeap  PhaseKind = iota // Dijkstra's heap loop, the whole baseline run
+	PhaseLight                  // one round of light-edge relaxation within a bucket
+	PhaseHeavy                  // heavy-edge relaxation out of a finished bucket
+	PhaseTrial                  // an autotune trial run, truncated after TrialLimit settled nodes
+)
+
+func (k PhaseKind) String() string {
+	switch k {
+	case PhaseHeap:
+		return "heap"
+	case PhaseLight:
+		return "light"
+	case PhaseHeavy:
+		return "heavy"
+	case PhaseTrial:
+		return "trial"
+	}
+	return fmt.Sprintf("PhaseKind(%d)", int(k))
+}
+
+// Phase is one entry of a run's timeline.
+type Phase struct {
+	Kind PhaseKind
+	// Bucket is the delta-stepping bucket index, -1 for heap and trial
+	// phases. Round counts light rounds within the bucket from 0; for a
+	// trial it is the candidate's index.
+	Bucket, Round int
+	Delta         float32       // bucket width in use; 0 for the heap phase
+	Start         time.Duration // since the run began
+	Duration      time.Duration
+
+	// Frontier is the number of nodes the phase scanned: the bucket's
+	// members for a light round, the nodes leaving the bucket for a heavy
+	// pass, and the peak heap size for the heap phase.
+	Frontier    int
+	Settled     int    // nodes first settled during the phase
+	Relaxations uint64 // edges examined
+}
+
+// phaseTrace collects the phases of a run; each end closes the phase that
+// began where the previous one ended.
+type phaseTrace struct {
+	start, last time.Time
+	phases      []Phase
+}
+
+func newPhaseTrace() *phaseTrace {
+	now := time.Now()
+	return &phaseTrace{start: now, last: now}
+}
+
+func (t *phaseTrace) end(p Phase) {
+	now := time.Now()
+	p.Start, p.Duration = t.last.Sub(t.start), now.Sub(t.last)
+	t.last = now
+	t.phases = append(t.phases, p)
+}
+
+// RunTraced is Run recording Result.Phases, a timeline of where the run
+// spent its time: the heap loop for ModeBaseline; every light round and
+// heavy pass of every bucket for ModeStoc and ModeGPU; and for
+// ModeAutotune, each trial before the final run's buckets. Timing each
+// phase costs a clock read per round, so Run leaves Phases nil.
+//
+// Like RunSettled, RunTraced always runs the pure-Go engine; its phases are
+// those of the algorithms above and may differ from the native library's
+// internal steps.
+func (g *Graph) RunTraced(source uint32, mode int) (Result, error) {
+	var res Result
+	hooks := &runHooks{trace: newPhaseTrace()}
+	if err := g.runGo(source, mode, &res, hooks); err != nil {
+		return Result{}, err
+	}
+	res.Phases = hooks.trace.phases
+	return res, nil
+}
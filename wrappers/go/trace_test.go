@@ -0,0 +1,52 @@
+package sssp
+
+import "testing"
+
+func TestRunTracedTimeline(t *testing.T) {
+	g, err := GenerateRandomGraph(3000, 4, 9, RandomGraphOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mode := range []int{ModeBaseline, ModeStoc, ModeAutotune} {
+		res, err := g.RunTraced(0, mode)
+		if err != nil {
+			t.Fatalf("mode %d: %v", mode, err)
+		}
+		if len(res.Phases) == 0 {
+			t.Fatalf("mode %d: no phases", mode)
+		}
+		var relax uint64
+		settled, trials := 0, 0
+		for i, p := range res.Phases {
+			if i > 0 && p.Start != res.Phases[i-1].Start+res.Phases[i-1].Duration {
+				t.Fatalf("mode %d: phase %d starts at %v, previous ends at %v", mode, i, p.Start, res.Phases[i-1].Start+res.Phases[i-1].Duration)
+			}
+			if p.Kind == PhaseTrial {
+				trials++
+				continue
+			}
+			relax += p.Relaxations
+			settled += p.Settled
+		}
+		if relax != res.Stats.Ops.EdgeRelaxations-trialRelaxations(res.Phases) || settled != len(res.ReachableNodes()) {
+			t.Fatalf("mode %d: phases relax %d edges and settle %d nodes, want %d and %d",
+				mode, relax, settled, res.Stats.Ops.EdgeRelaxations, len(res.ReachableNodes()))
+		}
+		if trials != int(res.Stats.AutotuneTrials) {
+			t.Fatalf("mode %d: %d trial phases, %d trials", mode, trials, res.Stats.AutotuneTrials)
+		}
+	}
+	if res, _ := g.Run(0, ModeBaseline); res.Phases != nil {
+		t.Fatalf("Run recorded phases")
+	}
+}
+
+func trialRelaxations(phases []Phase) uint64 {
+	var n uint64
+	for _, p := range phases {
+		if p.Kind == PhaseTrial {
+			n += p.Relaxations
+		}
+	}
+	return n
+}
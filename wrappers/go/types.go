@@ -0,0 +1,174 @@
+package sssp
+
+import (
+	"errors"
+	"math"
+)
+
+// Run modes understood by Run and RunBatch.
+const (
+	ModeBaseline = 0 // binary-heap Dijkstra
+	ModeStoc     = 1 // delta-stepping
+	ModeAutotune = 2 // delta-stepping with autotuned delta
+	ModeGPU      = 3 // device-offloaded relaxation; falls back to ModeStoc without CapGPU
+)
+
+// Unreachable is the distance of a node the source cannot reach, in every
+// Result and BatchResult row, whichever engine produced it: the native
+// library and the pure-Go engine both use +Inf, never a large finite value.
+// It is a variable only because Go has no infinite constants; compare with
+// Result.Reachable or math.IsInf(d, 1) rather than relying on a magnitude.
+var Unreachable = float32(math.Inf(1))
+
+// Result holds algorithm outputs.
+type Result struct {
+	Dist  []float32
+	Pred  []int32
+	Stats Stats
+
+	// Phases is the run's timeline, recorded only by Graph.RunTraced.
+	Phases []Phase
+
+	graph *Graph // set by Graph.Run
+}
+
+// Reachable reports whether node has a finite distance. It is false for a
+// node out of range.
+func (r *Result) Reachable(node uint32) bool {
+	return int(node) < len(r.Dist) && !math.IsInf(float64(r.Dist[node]), 1)
+}
+
+// ReachableNodes returns the nodes with a finite distance, in increasing
+// order, the source included.
+func (r *Result) ReachableNodes() []uint32 {
+	var nodes []uint32
+	for v, d := range r.Dist {
+		if !math.IsInf(float64(d), 1) {
+			nodes = append(nodes, uint32(v))
+		}
+	}
+	return nodes
+}
+
+// Path returns the nodes of the shortest path from the source to target,
+// both included, by following Pred. It is nil if target is unreachable or out
+// of range.
+func (r *Result) Path(target uint32) []uint32 {
+	if !r.Reachable(target) {
+		return nil
+	}
+	return pathFromPred(r.Pred, target)
+}
+
+// pathFromPred follows pred back from target, which must be reachable, and
+// returns the path in source-to-target order.
+func pathFromPred(pred []int32, target uint32) []uint32 {
+	var rev []uint32
+	for v := int32(target); v >= 0; v = pred[v] {
+		if len(rev) > len(pred) || int(v) >= len(pred) {
+			return nil // corrupt predecessor chain
+		}
+		rev = append(rev, uint32(v))
+	}
+	for i, j := 0, len(rev)-1; i < j; i, j = i+1, j-1 {
+		rev[i], rev[j] = rev[j], rev[i]
+	}
+	return rev
+}
+
+// Stats mirrors the Rust SsspResultInfo.
+type Stats struct {
+	Relaxations      uint64
+	LightRelaxations uint64
+	HeavyRelaxations uint64
+	Settled          uint32
+	ErrorCode        int32
+	Version          uint32
+
+	// Delta-stepping parameters the run used (zero for baseline).
+	Delta           float32
+	DeltaMultiplier float32 // autotune only; 0 when the delta was pinned
+	Buckets         uint32  // autotune only: bucket indices spanned by final distances
+	AutotuneTrials  uint32  // autotune only: truncated trial runs executed
+
+	// Fallback is set when the requested mode was served by another
+	// algorithm or backend: ModeGPU without a device-capable library runs
+	// as ModeStoc on the CPU, and the pure-Go delta-stepping runs finish
+	// with Dijkstra when a distance lands past the last bucket they allow
+	// (4n+1024 buckets of width Delta), where the native library fails with
+	// code -5.
+	Fallback bool
+
+	// Ops counts the run's operations the same way in every engine.
+	Ops OpCounters
+}
+
+// OpCounters are operation counts every runner reports on the same terms,
+// native and pure-Go alike, so implementations can be compared op for op.
+// They include all work a run did: adaptive restarts and autotune trial runs
+// count too. Batched runs report the sum over all rows. A native library
+// without CapOpCounters leaves them zero.
+type OpCounters struct {
+	EdgeRelaxations    uint64 // edges whose tentative distance was computed and compared
+	Improvements       uint64 // edge relaxations that lowered a distance
+	HeapPushes         uint64 // priority-queue inserts (Dijkstra only)
+	HeapPops           uint64 // priority-queue removals, stale entries included (Dijkstra only)
+	FrontierExpansions uint64 // nodes taken off the heap or out of a bucket and scanned
+	BucketScans        uint64 // passes over a bucket (delta-stepping light-phase rounds)
+}
+
+// Add accumulates o into c.
+func (c *OpCounters) Add(o OpCounters) {
+	c.EdgeRelaxations += o.EdgeRelaxations
+	c.Improvements += o.Improvements
+	c.HeapPushes += o.HeapPushes
+	c.HeapPops += o.HeapPops
+	c.FrontierExpansions += o.FrontierExpansions
+	c.BucketScans += o.BucketScans
+}
+
+// AutotuneParams controls the autotuner (mode 2). The zero value reproduces
+// the default behaviour (SSSP_STOC_AUTOTUNE_SET / SSSP_STOC_AUTOTUNE_LIMIT or
+// built-in defaults).
+type AutotuneParams struct {
+	Candidates  []float32 // delta multipliers to try, scaled by the sampled average weight
+	TrialLimit  uint32    // nodes settled per trial run
+	PinnedDelta float32   // > 0 skips tuning and runs with this delta
+}
+
+// BatchResult holds the distance matrix of a RunBatch call.
+type BatchResult struct {
+	N       uint32
+	Sources []uint32
+	Dist    []float32 // row-major: Dist[i*N+v] is the distance from Sources[i] to v
+	Stats   Stats     // counters summed over all rows
+	Threads uint32    // worker threads the rows were split across; 0 if the native library does not say
+}
+
+// Row returns the distance row for Sources[i].
+func (b BatchResult) Row(i int) []float32 {
+	return b.Dist[i*int(b.N) : (i+1)*int(b.N)]
+}
+
+// ErrUnsupported reports a feature the loaded native library does not provide.
+var ErrUnsupported = errors.New("sssp: not supported by the loaded native library")
+
+// Capability is a feature bit reported by the native library.
+type Capability uint64
+
+// Capability bits; values match SSSP_CAP_* in the Rust core.
+const (
+	CapBaseline       Capability = 1 << 0
+	CapStoc           Capability = 1 << 1
+	CapAutotune       Capability = 1 << 2
+	CapAutotuneParams Capability = 1 << 3
+	CapBatch          Capability = 1 << 4
+	CapThreads        Capability = 1 << 5
+	CapF64            Capability = 1 << 6
+	CapGPU            Capability = 1 << 7
+	CapOpCounters     Capability = 1 << 8
+	CapStocParams     Capability = 1 << 9 // stoc runs report their delta per call
+)
+
+// Has reports whether every bit of f is set.
+func (c Capability) Has(f Capability) bool { return c&f == f }
@@ -0,0 +1,117 @@
+package sssp
+
+import (
+	"fmt"
+	"math"
+)
+
+// IssueKind classifies an Issue found by Validate.
+type IssueKind uint8
+
+const (
+	NaNWeight      IssueKind = iota // weight is NaN: comparisons fail and distances become garbage
+	InfiniteWeight                  // weight is ±Inf: the native delta heuristics break
+	NegativeWeight                  // weight < 0: Dijkstra and delta-stepping assume non-negative weights
+	DanglingEdge                    // target is not a node of the graph
+	SelfLoop                        // edge u->u: harmless to distances, usually a data error
+	DuplicateEdge                   // another edge with the same endpoints came earlier in u's list
+)
+
+func (k IssueKind) String() string {
+	switch k {
+	case NaNWeight:
+		return "NaN weight"
+	case InfiniteWeight:
+		return "infinite weight"
+	case NegativeWeight:
+		return "negative weight"
+	case DanglingEdge:
+		return "dangling edge"
+	case SelfLoop:
+		return "self-loop"
+	case DuplicateEdge:
+		return "duplicate edge"
+	}
+	return fmt.Sprintf("IssueKind(%d)", k)
+}
+
+// Issue is one problem edge.
+type Issue struct {
+	Kind     IssueKind
+	Index    int // of the edge in the CSR targets and weights
+	From, To uint32
+	Weight   float32
+}
+
+// maxIssues bounds ValidationReport.Issues; the counts stay exact.
+const maxIssues = 1000
+
+// ValidationReport is the outcome of Validate.
+type ValidationReport struct {
+	// Issues in CSR order, at most the first 1000; an edge can have
+	// several. Counts holds the exact number per kind.
+	Issues    []Issue
+	Counts    map[IssueKind]int
+	Truncated bool // more issues than Issues holds
+}
+
+// OK reports whether no issue was found.
+func (r *ValidationReport) OK() bool { return len(r.Counts) == 0 }
+
+// Usable reports whether the algorithms can run on the graph correctly:
+// there are no NaN, infinite or negative weights and no dangling edges.
+// Self-loops and duplicate edges do not change distances.
+func (r *ValidationReport) Usable() bool {
+	return r.Counts[NaNWeight]+r.Counts[InfiniteWeight]+r.Counts[NegativeWeight]+r.Counts[DanglingEdge] == 0
+}
+
+func (r *ValidationReport) add(is Issue) {
+	r.Counts[is.Kind]++
+	if len(r.Issues) < maxIssues {
+		r.Issues = append(r.Issues, is)
+	} else {
+		r.Truncated = true
+	}
+}
+
+// Validate checks every edge of g for NaN, infinite and negative weights,
+// dangling targets, self-loops and duplicate edges, in one pass using a
+// node-sized scratch array. The runs do not check their input, so a graph
+// from an untrusted source should pass Usable first.
+func (g *Graph) Validate() *ValidationReport {
+	r := &ValidationReport{Counts: make(map[IssueKind]int)}
+	n := g.NodeCount()
+	seen := make([]uint32, n) // seen[v] == u+1: u already has an edge to v
+	for u := uint32(0); u < n; u++ {
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			v, w := g.targets[e], g.weights[e]
+			is := Issue{Index: int(e), From: u, To: v, Weight: w}
+			switch f := float64(w); {
+			case math.IsNaN(f):
+				is.Kind = NaNWeight
+				r.add(is)
+			case math.IsInf(f, 0):
+				is.Kind = InfiniteWeight
+				r.add(is)
+			case f < 0:
+				is.Kind = NegativeWeight
+				r.add(is)
+			}
+			if v >= n {
+				is.Kind = DanglingEdge
+				r.add(is)
+				continue
+			}
+			if v == u {
+				is.Kind = SelfLoop
+				r.add(is)
+			}
+			if seen[v] == u+1 {
+				is.Kind = DuplicateEdge
+				r.add(is)
+			}
+			seen[v] = u + 1
+		}
+	}
+	return r
+}
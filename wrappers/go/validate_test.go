@@ -0,0 +1,41 @@
+package sssp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestValidateFindsEachIssue(t *testing.T) {
+	nan, inf := float32(math.NaN()), float32(math.Inf(1))
+	g := &Graph{
+		offsets: []uint32{0, 5, 7, 7},
+		// 0->1 NaN, 0->0 self-loop, 0->1 duplicate, 0->2 negative, 0->7 dangling; 1->2 inf, 1->0 fine
+		targets: []uint32{1, 0, 1, 2, 7, 2, 0},
+		weights: []float32{nan, 1, 2, -1, 1, inf, 3},
+	}
+	r := g.Validate()
+	want := map[IssueKind]int{NaNWeight: 1, SelfLoop: 1, DuplicateEdge: 1, NegativeWeight: 1, DanglingEdge: 1, InfiniteWeight: 1}
+	if len(r.Counts) != len(want) || r.OK() || r.Usable() {
+		t.Fatalf("counts %v", r.Counts)
+	}
+	for k, c := range want {
+		if r.Counts[k] != c {
+			t.Fatalf("%v: %d issues, want %d", k, r.Counts[k], c)
+		}
+	}
+	if is := r.Issues[2]; is.Kind != DuplicateEdge || is.Index != 2 || is.From != 0 || is.To != 1 {
+		t.Fatalf("third issue %+v", is)
+	}
+
+	clean, err := GenerateRandomGraph(1000, 4, 1, RandomGraphOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := clean.Validate(); !r.Usable() || r.Counts[NaNWeight] != 0 {
+		t.Fatalf("generated graph: %v", r.Counts)
+	}
+	loops, _ := FromEdges(2, []Edge{{0, 0, 1}, {0, 1, 1}, {0, 1, 2}})
+	if r := loops.Validate(); !r.Usable() || r.OK() || r.Counts[SelfLoop] != 1 || r.Counts[DuplicateEdge] != 1 {
+		t.Fatalf("self-loop and duplicate: %v", r.Counts)
+	}
+}
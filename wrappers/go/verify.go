@@ -0,0 +1,174 @@
+package sssp
+
+import (
+	"fmt"
+	"math"
+)
+
+// Tolerance bounds the distance error Verify accepts: got agrees with want
+// when |got - want| <= Abs + Rel·|want|. Algorithms summing the same path in
+// a different order round differently, so exact comparison of float32
+// distances is too strict for all but the same algorithm.
+type Tolerance struct {
+	Abs float64
+	Rel float64
+}
+
+// DefaultTolerance accepts float32 rounding on paths of a few thousand edges.
+var DefaultTolerance = Tolerance{Abs: 1e-6, Rel: 1e-4}
+
+// Float64Tolerance is DefaultTolerance for float64 distances, from
+// Graph.Float64 or GraphOf[float64]: the same paths round about 2^29 times
+// less, so a float32 tolerance would hide real errors.
+var Float64Tolerance = Tolerance{Abs: 1e-12, Rel: 1e-10}
+
+func (t Tolerance) agree(want, got float64) bool {
+	return math.Abs(got-want) <= t.Abs+t.Rel*math.Abs(want)
+}
+
+// MismatchKind classifies a Mismatch.
+type MismatchKind uint8
+
+const (
+	// Drift: both results reach the node, at distances differing by more
+	// than the tolerance.
+	Drift MismatchKind = iota
+	// Reachability: one result reaches the node and the other does not.
+	Reachability
+	// Predecessor: the checked result's Pred[v] is not an in-neighbour u
+	// with Dist[v] = Dist[u] + w(u, v), or is set on the source or an
+	// unreachable node, or is missing on a reachable one.
+	Predecessor
+)
+
+func (k MismatchKind) String() string {
+	switch k {
+	case Drift:
+		return "drift"
+	case Reachability:
+		return "reachability"
+	case Predecessor:
+		return "predecessor"
+	}
+	return fmt.Sprintf("MismatchKind(%d)", k)
+}
+
+// Mismatch is one node failing verification.
+type Mismatch struct {
+	Node uint32
+	Kind MismatchKind
+	Want float32 // reference distance; +Inf if unreachable, 0 for Predecessor without a reference
+	Got  float32 // checked distance
+	Pred int32   // checked predecessor
+}
+
+// Verification is the outcome of Verify.
+type Verification struct {
+	// Mismatches in node order; a node can fail both a distance and the
+	// predecessor check.
+	Mismatches   []Mismatch
+	Drift        int     // nodes with Kind Drift
+	Reachability int     // nodes with Kind Reachability
+	Predecessor  int     // nodes with Kind Predecessor
+	MaxDrift     float64 // largest |got - want| over nodes both results reach
+}
+
+// OK reports whether nothing failed.
+func (v *Verification) OK() bool { return len(v.Mismatches) == 0 }
+
+// Verify checks a result of a run on g from source. When want is non-nil it
+// compares got's distances with those reference distances, separating
+// reachability disagreements from numeric drift. It also checks that got's
+// predecessors form shortest-path edges of g: each reached node other than
+// the source has a predecessor u with an edge u->v whose weight accounts
+// for Dist[v] - Dist[u] within tol. A result without predecessors, from
+// QueryOptions.DistancesOnly, is checked on its distances alone.
+func (g *Graph) Verify(source uint32, got *Result, want []float32, tol Tolerance) (*Verification, error) {
+	return verifyRows(g.offsets, g.targets, g.weights, source, got.Dist, got.Pred, want, tol)
+}
+
+// Verify is Graph.Verify for a result of g.Run. Mismatch.Want and Got are
+// rounded to float32; MaxDrift is computed in float64. Pass the
+// tolerance of the precision in use: Float64Tolerance for float64
+// distances, a zero Tolerance for integer weights, whose sums are exact.
+func (g *GraphOf[W]) Verify(source uint32, got *ResultOf[W], want []W, tol Tolerance) (*Verification, error) {
+	return verifyRows(g.offsets, g.targets, g.weights, source, got.Dist, got.Pred, want, tol)
+}
+
+// verifyRows is Verify on bare CSR arrays and result rows.
+func verifyRows[W Weight](off, tgt []uint32, wts []W, source uint32, dist []W, pred []int32, want []W, tol Tolerance) (*Verification, error) {
+	n := uint32(len(off) - 1)
+	if source >= n {
+		return nil, fmt.Errorf("sssp: verify: source %d out of range for %d nodes", source, n)
+	}
+	if len(dist) != int(n) || (pred != nil && len(pred) != int(n)) {
+		return nil, fmt.Errorf("sssp: verify: result has %d distances, %d predecessors for %d nodes",
+			len(dist), len(pred), n)
+	}
+	if want != nil && len(want) != int(n) {
+		return nil, fmt.Errorf("sssp: verify: %d reference distances for %d nodes", len(want), n)
+	}
+	out := &Verification{}
+	add := func(m Mismatch) {
+		out.Mismatches = append(out.Mismatches, m)
+		switch m.Kind {
+		case Drift:
+			out.Drift++
+		case Reachability:
+			out.Reachability++
+		case Predecessor:
+			out.Predecessor++
+		}
+	}
+	unreached := unreachableOf[W]()
+	for v := uint32(0); v < n; v++ {
+		d, p := float64(dist[v]), int32(-1)
+		if pred != nil {
+			p = pred[v]
+		}
+		m := Mismatch{Node: v, Got: float32(dist[v]), Pred: p}
+		if want != nil {
+			m.Want = float32(want[v])
+			w := float64(want[v])
+			switch wInf, dInf := want[v] == unreached, dist[v] == unreached; {
+			case wInf != dInf:
+				m.Kind = Reachability
+				add(m)
+			case !wInf:
+				diff := math.Abs(d - w)
+				out.MaxDrift = math.Max(out.MaxDrift, diff)
+				if !tol.agree(w, d) {
+					m.Kind = Drift
+					add(m)
+				}
+			}
+		}
+		if pred != nil && !predOK(off, tgt, wts, source, v, dist, pred, tol) {
+			m.Kind = Predecessor
+			add(m)
+		}
+	}
+	return out, nil
+}
+
+// predOK checks v's predecessor.
+func predOK[W Weight](off, tgt []uint32, wts []W, source, v uint32, dist []W, pred []int32, tol Tolerance) bool {
+	p := pred[v]
+	d := float64(dist[v])
+	switch {
+	case v == source:
+		return p < 0 && d == 0
+	case dist[v] == unreachableOf[W]():
+		return p < 0
+	case math.IsNaN(d) || p < 0 || int(p) >= len(off)-1:
+		return false
+	}
+	// Sum in W, as the runners do, so a zero tolerance holds exactly.
+	du := dist[p]
+	for e := off[p]; e < off[p+1]; e++ {
+		if tgt[e] == v && tol.agree(d, float64(du+wts[e])) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package sssp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVerifyAcceptsEveryMode(t *testing.T) {
+	g, err := GenerateRandomGraph(2000, 4, 3, RandomGraphOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := g.Run(7, ModeBaseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := g.Verify(7, &ref, ref.Dist, Tolerance{})
+	if err != nil || !v.OK() {
+		t.Fatalf("baseline against itself: %+v, %v", v, err)
+	}
+	if v, _ := g.Verify(7, &ref, nil, DefaultTolerance); !v.OK() {
+		t.Fatalf("baseline predecessors: %+v", v.Mismatches[0])
+	}
+}
+
+func TestVerifyClassifiesMismatches(t *testing.T) {
+	// 0->1 (1), 1->2 (2), 0->3 (4)
+	g, err := FromEdges(5, []Edge{{0, 1, 1}, {1, 2, 2}, {0, 3, 4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := g.Run(0, ModeBaseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inf := float32(math.Inf(1))
+	got := Result{
+		Dist: []float32{0, 1, 3.0001, inf, 7},
+		Pred: []int32{-1, 0, 1, -1, 0},
+	}
+	v, err := g.Verify(0, &got, ref.Dist, Tolerance{Abs: 1e-6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		node uint32
+		kind MismatchKind
+	}{
+		{2, Drift}, {2, Predecessor}, {3, Reachability}, {4, Reachability}, {4, Predecessor},
+	}
+	if len(v.Mismatches) != len(want) {
+		t.Fatalf("mismatches %+v", v.Mismatches)
+	}
+	for i, w := range want {
+		if m := v.Mismatches[i]; m.Node != w.node || m.Kind != w.kind {
+			t.Fatalf("mismatch %d = %+v, want node %d %v", i, m, w.node, w.kind)
+		}
+	}
+	if v.Drift != 1 || v.Reachability != 2 || v.Predecessor != 2 {
+		t.Fatalf("counts %d %d %d", v.Drift, v.Reachability, v.Predecessor)
+	}
+	if math.Abs(v.MaxDrift-1e-4) > 1e-5 {
+		t.Fatalf("max drift %v", v.MaxDrift)
+	}
+	// A looser tolerance absorbs the drift and the predecessor slack.
+	v, _ = g.Verify(0, &got, ref.Dist, Tolerance{Rel: 1e-3})
+	if v.Drift != 0 || v.Predecessor != 1 {
+		t.Fatalf("loose: %+v", v.Mismatches)
+	}
+	if _, err := g.Verify(5, &got, nil, DefaultTolerance); err == nil {
+		t.Fatal("source out of range accepted")
+	}
+}
@@ -0,0 +1,92 @@
+package sssp
+
+import "fmt"
+
+// Voronoi is the partition of a graph's nodes by nearest source, as
+// returned by Graph.Voronoi.
+type Voronoi struct {
+	// Sources are the sources in the order given; cells are numbered by
+	// their index here.
+	Sources []uint32
+	// Dist is each node's distance from its nearest source, Unreachable if
+	// none reaches it.
+	Dist []float32
+	// Owner is the index in Sources of each node's nearest source, -1 if
+	// unreached.
+	Owner []int32
+	// Pred is the predecessor of each node on the path from its source, -1
+	// for sources and unreached nodes.
+	Pred []int32
+	// Boundary lists the IDs of the edges whose endpoints lie in different
+	// cells, in ID order: the borders between territories.
+	Boundary []int
+}
+
+// Voronoi runs one search from all sources at once and assigns every node
+// to the source that reaches it first: a network Voronoi diagram, for
+// territory assignment or catchment areas. A node at the same distance from
+// two sources goes to the one the search settles it from first, which is
+// deterministic for a given graph and source order. Repeated sources keep
+// their first index. It runs on the pure-Go engine; weights must be
+// non-negative.
+func (g *Graph) Voronoi(sources []uint32) (*Voronoi, error) {
+	n := g.NodeCount()
+	vo := &Voronoi{Sources: sources, Dist: make([]float32, n), Owner: make([]int32, n), Pred: make([]int32, n)}
+	for i := range vo.Owner {
+		vo.Dist[i], vo.Pred[i], vo.Owner[i] = Unreachable, -1, -1
+	}
+	q := minHeap[float32]{}
+	for i, s := range sources {
+		if s >= n {
+			return nil, fmt.Errorf("sssp: source %d out of range for %d nodes", s, n)
+		}
+		if vo.Owner[s] < 0 {
+			vo.Dist[s], vo.Owner[s] = 0, int32(i)
+			q.push(heapItem[float32]{node: s})
+		}
+	}
+	for {
+		it, ok := q.pop()
+		if !ok {
+			break
+		}
+		u := it.node
+		if it.dist > vo.Dist[u] {
+			continue
+		}
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			v := g.targets[e]
+			if nd := vo.Dist[u] + g.weights[e]; nd < vo.Dist[v] {
+				vo.Dist[v], vo.Pred[v], vo.Owner[v] = nd, int32(u), vo.Owner[u]
+				q.push(heapItem[float32]{node: v, dist: nd})
+			}
+		}
+	}
+	for u := uint32(0); u < n; u++ {
+		for e := g.offsets[u]; e < g.offsets[u+1]; e++ {
+			if a, b := vo.Owner[u], vo.Owner[g.targets[e]]; a >= 0 && b >= 0 && a != b {
+				vo.Boundary = append(vo.Boundary, int(e))
+			}
+		}
+	}
+	return vo, nil
+}
+
+// Cell returns the nodes owned by source index i, in ID order.
+func (vo *Voronoi) Cell(i int) []uint32 {
+	var cell []uint32
+	for v, o := range vo.Owner {
+		if int(o) == i {
+			cell = append(cell, uint32(v))
+		}
+	}
+	return cell
+}
+
+// Path returns the path from v's source to v, or nil if v is unreached.
+func (vo *Voronoi) Path(v uint32) []uint32 {
+	if int(v) >= len(vo.Owner) || vo.Owner[v] < 0 {
+		return nil
+	}
+	return pathFromPred(vo.Pred, v)
+}
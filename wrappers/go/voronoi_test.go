@@ -0,0 +1,42 @@
+package sssp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVoronoi(t *testing.T) {
+	// A path 0 - 1 - 2 - 3 - 4 - 5 and an isolated node 6.
+	var edges []Edge
+	for u := uint32(0); u < 5; u++ {
+		edges = append(edges, Edge{u, u + 1, 1}, Edge{u + 1, u, 1})
+	}
+	g, _ := FromEdges(7, edges)
+	vo, err := g.Voronoi([]uint32{0, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := vo.Cell(0); !reflect.DeepEqual(got, []uint32{0, 1, 2}) {
+		t.Fatalf("cell 0 %v", got)
+	}
+	if got := vo.Cell(1); !reflect.DeepEqual(got, []uint32{3, 4, 5}) {
+		t.Fatalf("cell 1 %v", got)
+	}
+	if vo.Owner[6] != -1 || vo.Dist[6] != Unreachable || vo.Path(6) != nil {
+		t.Fatalf("isolated node owned by %d", vo.Owner[6])
+	}
+	if got := vo.Path(2); !reflect.DeepEqual(got, []uint32{0, 1, 2}) {
+		t.Fatalf("path %v", got)
+	}
+	var border []Edge
+	for _, id := range vo.Boundary {
+		e, _ := g.EdgeByID(id)
+		border = append(border, e)
+	}
+	if want := []Edge{{2, 3, 1}, {3, 2, 1}}; !reflect.DeepEqual(border, want) {
+		t.Fatalf("boundary %v", border)
+	}
+	if _, err := g.Voronoi([]uint32{7}); err == nil {
+		t.Fatalf("out-of-range source accepted")
+	}
+}
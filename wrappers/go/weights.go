@@ -0,0 +1,105 @@
+package sssp
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// WeightDist is the edge weight distribution of a generator. The zero value
+// is uniform on [1, 10), the range the benchmarks use.
+type WeightDist struct {
+	kind weightKind
+	a, b float64
+}
+
+type weightKind uint8
+
+const (
+	weightDefault weightKind = iota
+	weightUniform
+	weightExponential
+	weightLogNormal
+	weightInt
+	weightConstant
+)
+
+// UniformWeights draws weights uniformly from [lo, hi).
+func UniformWeights(lo, hi float32) WeightDist {
+	return WeightDist{kind: weightUniform, a: float64(lo), b: float64(hi)}
+}
+
+// ExponentialWeights draws weights from an exponential distribution with the
+// given mean: mostly light edges with a long tail of heavy ones.
+func ExponentialWeights(mean float32) WeightDist {
+	return WeightDist{kind: weightExponential, a: float64(mean)}
+}
+
+// LogNormalWeights draws exp(N(mu, sigma²)); large sigma gives heavy skew.
+func LogNormalWeights(mu, sigma float64) WeightDist {
+	return WeightDist{kind: weightLogNormal, a: mu, b: sigma}
+}
+
+// IntWeights draws integers uniformly from [lo, hi], both included, as in
+// the DIMACS challenge instances.
+func IntWeights(lo, hi int) WeightDist {
+	return WeightDist{kind: weightInt, a: float64(lo), b: float64(hi)}
+}
+
+// ConstantWeights gives every edge weight w.
+func ConstantWeights(w float32) WeightDist {
+	return WeightDist{kind: weightConstant, a: float64(w)}
+}
+
+func (d WeightDist) String() string {
+	switch d.kind {
+	case weightDefault:
+		return "uniform[1,10)"
+	case weightUniform:
+		return fmt.Sprintf("uniform[%g,%g)", d.a, d.b)
+	case weightExponential:
+		return fmt.Sprintf("exponential(mean=%g)", d.a)
+	case weightLogNormal:
+		return fmt.Sprintf("lognormal(mu=%g,sigma=%g)", d.a, d.b)
+	case weightInt:
+		return fmt.Sprintf("int[%g,%g]", d.a, d.b)
+	case weightConstant:
+		return fmt.Sprintf("constant(%g)", d.a)
+	}
+	return fmt.Sprintf("WeightDist(%d)", d.kind)
+}
+
+// validate rejects parameters that could give negative or NaN weights.
+func (d WeightDist) validate() error {
+	ok := true
+	switch d.kind {
+	case weightUniform, weightInt:
+		ok = d.a >= 0 && d.b >= d.a
+	case weightExponential:
+		ok = d.a > 0
+	case weightLogNormal:
+		ok = !math.IsNaN(d.a) && !math.IsInf(d.a, 0) && d.b >= 0
+	case weightConstant:
+		ok = d.a >= 0
+	}
+	if !ok {
+		return fmt.Errorf("sssp: generate: invalid weight distribution %v", d)
+	}
+	return nil
+}
+
+func (d WeightDist) sample(r *rand.Rand) float32 {
+	switch d.kind {
+	case weightUniform:
+		return float32(d.a + r.Float64()*(d.b-d.a))
+	case weightExponential:
+		return float32(r.ExpFloat64() * d.a)
+	case weightLogNormal:
+		return float32(math.Exp(d.a + d.b*r.NormFloat64()))
+	case weightInt:
+		return float32(int64(d.a) + r.Int63n(int64(d.b)-int64(d.a)+1))
+	case weightConstant:
+		return float32(d.a)
+	}
+	return 1 + r.Float32()*9
+}
@@ -0,0 +1,46 @@
+package sssp
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestWeightDistributions(t *testing.T) {
+	const samples = 20000
+	cases := []struct {
+		d        WeightDist
+		mean     float64
+		lo, hi   float32
+		integral bool
+	}{
+		{WeightDist{}, 5.5, 1, 10, false},
+		{UniformWeights(2, 4), 3, 2, 4, false},
+		{ExponentialWeights(3), 3, 0, float32(math.Inf(1)), false},
+		{LogNormalWeights(0, 0.5), math.Exp(0.125), 0, float32(math.Inf(1)), false},
+		{IntWeights(1, 4), 2.5, 1, 4, true},
+		{ConstantWeights(7), 7, 7, 7, false},
+	}
+	for _, c := range cases {
+		if err := c.d.validate(); err != nil {
+			t.Fatalf("%v: %v", c.d, err)
+		}
+		r := rand.New(rand.NewSource(1))
+		var sum float64
+		for i := 0; i < samples; i++ {
+			w := c.d.sample(r)
+			if w < c.lo || w > c.hi || (c.integral && w != float32(math.Round(float64(w)))) {
+				t.Fatalf("%v: sample %v out of range", c.d, w)
+			}
+			sum += float64(w)
+		}
+		if mean := sum / samples; math.Abs(mean-c.mean) > 0.05*c.mean {
+			t.Fatalf("%v: mean %v, want %v", c.d, mean, c.mean)
+		}
+	}
+	for _, bad := range []WeightDist{UniformWeights(3, 2), ExponentialWeights(0), IntWeights(-1, 2), LogNormalWeights(0, -1)} {
+		if bad.validate() == nil {
+			t.Fatalf("%v: expected an error", bad)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package sssp
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Weight is the set of edge weight types GraphOf accepts. With an integer
+// type the engine never converts weights or distances to floating point,
+// so sums are exact.
+type Weight interface {
+	~int | ~int32 | ~int64 | ~uint | ~uint32 | ~uint64 | ~float32 | ~float64
+}
+
+// isFloatWeight reports whether W is a floating-point type.
+func isFloatWeight[W Weight]() bool {
+	one := W(1)
+	return one/2 != 0
+}
+
+// unreachableOf is the distance of an unreached node: +Inf for floats, the
+// largest value of W for integers.
+func unreachableOf[W Weight]() W {
+	if isFloatWeight[W]() {
+		inf := math.Inf(1)
+		return W(inf)
+	}
+	t := reflect.TypeFor[W]()
+	switch t.Kind() {
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		m := uint64(1)<<t.Bits() - 1
+		return W(m)
+	default:
+		m := int64(1)<<(t.Bits()-1) - 1
+		return W(m)
+	}
+}
+
+// UnreachableOf returns the distance ResultOf[W] gives a node the source
+// cannot reach: +Inf for float types, the largest value of W for integer
+// types.
+func UnreachableOf[W Weight]() W { return unreachableOf[W]() }
+
+// EdgeOf is an Edge whose weight has type W.
+type EdgeOf[W Weight] struct {
+	From, To uint32
+	Weight   W
+}
+
+// GraphOf is a directed CSR graph with weights of type W, for callers whose
+// weights are integers (or float64) and who want exact distances in that
+// type instead of float32. Weights must be non-negative. It runs on the
+// pure-Go engine; Graph is the float32 graph the native library takes.
+type GraphOf[W Weight] struct {
+	offsets []uint32
+	targets []uint32
+	weights []W
+}
+
+// NewGraphCSROf wraps existing CSR arrays after checking they are
+// consistent, as NewGraphCSR does.
+func NewGraphCSROf[W Weight](offsets, targets []uint32, weights []W) (*GraphOf[W], error) {
+	if len(targets) != len(weights) {
+		return nil, fmt.Errorf("sssp: %d targets and %d weights", len(targets), len(weights))
+	}
+	// The checks do not depend on the weights; borrow NewGraphCSR's.
+	if _, err := NewGraphCSR(offsets, targets, make([]float32, len(targets))); err != nil {
+		return nil, err
+	}
+	return &GraphOf[W]{offsets: offsets, targets: targets, weights: weights}, nil
+}
+
+// FromEdgesOf builds a graph with n nodes, placing edges as FromEdges does.
+func FromEdgesOf[W Weight](n uint32, edges []EdgeOf[W]) (*GraphOf[W], error) {
+	offsets := make([]uint32, n+1)
+	for i, e := range edges {
+		if e.From >= n || e.To >= n {
+			return nil, fmt.Errorf("sssp: edge %d (%d->%d) out of range for %d nodes", i, e.From, e.To, n)
+		}
+		offsets[e.From+1]++
+	}
+	for u := uint32(0); u < n; u++ {
+		offsets[u+1] += offsets[u]
+	}
+	targets := make([]uint32, len(edges))
+	weights := make([]W, len(edges))
+	next := make([]uint32, n)
+	copy(next, offsets[:n])
+	for _, e := range edges {
+		i := next[e.From]
+		targets[i], weights[i] = e.To, e.Weight
+		next[e.From]++
+	}
+	return &GraphOf[W]{offsets: offsets, targets: targets, weights: weights}, nil
+}
+
+// Float64 returns g as a GraphOf[float64] sharing its offsets and targets,
+// with the weights widened into a new array. Its Run sums and stores
+// distances in float64: 8 bytes a node instead of 4 for Dist, and 4 more
+// an edge for the weights, in exchange for paths whose rounding error no
+// longer grows past float32's 24-bit mantissa. Weight updates made through
+// g later do not show through.
+func (g *Graph) Float64() *GraphOf[float64] {
+	weights := make([]float64, len(g.weights))
+	for i, w := range g.weights {
+		weights[i] = float64(w)
+	}
+	return &GraphOf[float64]{offsets: g.offsets, targets: g.targets, weights: weights}
+}
+
+// NodeCount returns the number of nodes.
+func (g *GraphOf[W]) NodeCount() uint32 { return uint32(len(g.offsets) - 1) }
+
+// EdgeCount returns the number of directed edges.
+func (g *GraphOf[W]) EdgeCount() int { return len(g.targets) }
+
+// CSR exposes the underlying arrays. Callers must not modify them.
+func (g *GraphOf[W]) CSR() (offsets, targets []uint32, weights []W) {
+	return g.offsets, g.targets, g.weights
+}
+
+// ResultOf is a Result with distances of type W. Unreachable nodes have
+// distance UnreachableOf[W]() and predecessor -1.
+type ResultOf[W Weight] struct {
+	Dist  []W
+	Pred  []int32
+	Stats Stats
+}
+
+// Reachable reports whether node was reached. It is false for a node out
+// of range.
+func (r *ResultOf[W]) Reachable(node uint32) bool {
+	return int(node) < len(r.Dist) && r.Dist[node] != unreachableOf[W]()
+}
+
+// Path returns the nodes of the shortest path from the source to target,
+// both included, or nil if target is unreachable or out of range.
+func (r *ResultOf[W]) Path(target uint32) []uint32 {
+	if !r.Reachable(target) {
+		return nil
+	}
+	return pathFromPred(r.Pred, target)
+}
+
+// Run executes mode from source on the pure-Go engine with W arithmetic
+// throughout. A path whose length overflows an integer W is treated as
+// missing. ModeStoc buckets by a delta of W: the average weight times
+// SSSP_STOC_DELTA_MULT (default 3), at least 1 for integers. Stats.Delta
+// reports it as a float32.
+func (g *GraphOf[W]) Run(source uint32, mode int) (ResultOf[W], error) {
+	n := g.NodeCount()
+	if err := checkGoRun(n, source, mode); err != nil {
+		return ResultOf[W]{}, err
+	}
+	res := ResultOf[W]{Dist: make([]W, n), Pred: make([]int32, n)}
+	res.Stats = runGoCSR(g.offsets, g.targets, g.weights, source, mode, res.Dist, res.Pred, nil)
+	return res, nil
+}
@@ -0,0 +1,116 @@
+package sssp
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestGraphOfIntegerWeightsAreExact(t *testing.T) {
+	// float32 cannot tell 2^40+1 from 2^40; int64 distances must.
+	big := int64(1)<<40 + 1
+	g, err := FromEdgesOf(4, []EdgeOf[int64]{{0, 1, big}, {1, 2, 1}, {0, 2, 2 * big}, {2, 3, 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mode := range []int{ModeBaseline, ModeStoc, ModeAutotune} {
+		res, err := g.Run(0, mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []int64{0, big, big + 1, big + 4}; !reflect.DeepEqual(res.Dist, want) {
+			t.Fatalf("mode %d: dist %v, want %v", mode, res.Dist, want)
+		}
+		if p := res.Path(3); !reflect.DeepEqual(p, []uint32{0, 1, 2, 3}) {
+			t.Fatalf("mode %d: path %v", mode, p)
+		}
+	}
+}
+
+func TestGraphOfMatchesFloat32Engine(t *testing.T) {
+	off, tgt, wts := randomCSR(300, 4, 5)
+	ints := make([]uint32, len(wts))
+	for i, w := range wts {
+		ints[i] = uint32(w)
+		wts[i] = float32(ints[i])
+	}
+	g, err := NewGraphCSROf(off, tgt, ints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := (&Graph{offsets: off, targets: tgt, weights: wts}).RunSettled(0, ModeBaseline, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mode := range []int{ModeBaseline, ModeStoc} {
+		got, err := g.Run(0, mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for v, d := range got.Dist {
+			if got.Reachable(uint32(v)) != want.Reachable(uint32(v)) || (got.Reachable(uint32(v)) && float32(d) != want.Dist[v]) {
+				t.Fatalf("mode %d node %d: %d, want %v", mode, v, d, want.Dist[v])
+			}
+		}
+	}
+}
+
+func TestGraphOfOverflowIsUnreachable(t *testing.T) {
+	g, err := FromEdgesOf(3, []EdgeOf[uint32]{{0, 1, math.MaxUint32 - 5}, {1, 2, 10}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := g.Run(0, ModeBaseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Reachable(1) || res.Reachable(2) || res.Dist[2] != UnreachableOf[uint32]() || res.Pred[2] != -1 {
+		t.Fatalf("dist %v pred %v", res.Dist, res.Pred)
+	}
+	if u := UnreachableOf[int32](); u != math.MaxInt32 {
+		t.Fatalf("int32 unreachable %d", u)
+	}
+	if u := UnreachableOf[float64](); !math.IsInf(u, 1) {
+		t.Fatalf("float64 unreachable %v", u)
+	}
+}
+
+func TestFloat64Precision(t *testing.T) {
+	// A chain of 20000 edges of 0.1: float32 sums drift past float64's
+	// tolerance, float64 sums match the exact prefix sums.
+	const n = 20001
+	edges := make([]Edge, n-1)
+	want := make([]float64, n)
+	for i := range edges {
+		edges[i] = Edge{uint32(i), uint32(i + 1), 0.1}
+		want[i+1] = want[i] + float64(float32(0.1))
+	}
+	g, err := FromEdges(n, edges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wide := g.Float64()
+	for _, mode := range []int{ModeBaseline, ModeStoc, ModeAutotune} {
+		res, err := wide.Run(0, mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v, err := wide.Verify(0, &res, want, Tolerance{}); err != nil || !v.OK() {
+			t.Fatalf("mode %d: float64 run: %+v, %v", mode, v, err)
+		}
+	}
+	narrow, err := g.Run(0, ModeBaseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := ResultOf[float64]{Dist: make([]float64, n), Pred: narrow.Pred}
+	for v, d := range narrow.Dist {
+		got.Dist[v] = float64(d)
+	}
+	if v, _ := wide.Verify(0, &got, want, Float64Tolerance); v.Drift == 0 {
+		t.Fatal("float32 distances pass the float64 tolerance")
+	}
+	if v, _ := g.Verify(0, &narrow, nil, DefaultTolerance); !v.OK() {
+		t.Fatalf("float32 run: %+v", v.Mismatches[0])
+	}
+}